@@ -6,22 +6,43 @@
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
+// @securityDefinitions.apikey PortalAuth
+// @in header
+// @name Authorization
+// @description Client portal access token, as "Bearer <token>"
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/hutamy/go-invoice-backend/config"
 	_ "github.com/hutamy/go-invoice-backend/docs"
+	"github.com/hutamy/go-invoice-backend/internal/adapter/mailer"
+	"github.com/hutamy/go-invoice-backend/internal/adapter/notifier"
+	"github.com/hutamy/go-invoice-backend/internal/adapter/payment"
+	"github.com/hutamy/go-invoice-backend/internal/adapter/pdf"
 	pgrepo "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres"
 	"github.com/hutamy/go-invoice-backend/internal/adapter/security"
+	"github.com/hutamy/go-invoice-backend/internal/adapter/storage"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
 	ht "github.com/hutamy/go-invoice-backend/internal/transport/http"
 	"github.com/hutamy/go-invoice-backend/internal/transport/http/handlers"
+	"github.com/hutamy/go-invoice-backend/internal/transport/scheduler"
 	authuc "github.com/hutamy/go-invoice-backend/internal/usecase/auth"
 	clientuc "github.com/hutamy/go-invoice-backend/internal/usecase/client"
+	emailtemplateuc "github.com/hutamy/go-invoice-backend/internal/usecase/emailtemplate"
 	invoiceuc "github.com/hutamy/go-invoice-backend/internal/usecase/invoice"
+	latefeeuc "github.com/hutamy/go-invoice-backend/internal/usecase/latefee"
+	paymentuc "github.com/hutamy/go-invoice-backend/internal/usecase/payment"
+	portaluc "github.com/hutamy/go-invoice-backend/internal/usecase/portal"
+	reconciliationuc "github.com/hutamy/go-invoice-backend/internal/usecase/reconciliation"
+	reminderuc "github.com/hutamy/go-invoice-backend/internal/usecase/reminder"
+	taxinvoiceuc "github.com/hutamy/go-invoice-backend/internal/usecase/taxinvoice"
+	mw "github.com/hutamy/go-invoice-backend/middleware"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
@@ -49,6 +70,14 @@ func main() {
 	// Register custom validator
 	e.Validator = &CustomValidator{validator: validator.New()}
 
+	// Behind a proxy every request comes from the proxy's address, so the
+	// client's is taken from the header it sets.
+	if cfg.TrustProxy {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	} else {
+		e.IPExtractor = echo.ExtractIPDirect()
+	}
+
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
@@ -62,28 +91,142 @@ func main() {
 	authRepo := pgrepo.NewAuthRepository(db)
 	clientRepo := pgrepo.NewClientRepository(db)
 	invoiceRepo := pgrepo.NewInvoiceRepository(db)
+	lateFeeRepo := pgrepo.NewLateFeeRepository(db)
+	reminderRepo := pgrepo.NewReminderRepository(db)
+	templateRepo := pgrepo.NewEmailTemplateRepository(db)
+	shareLinkRepo := pgrepo.NewShareLinkRepository(db)
+	brandingRepo := pgrepo.NewInvoiceBrandingRepository(db)
+	portalRepo := pgrepo.NewPortalRepository(db)
+	paymentRepo := pgrepo.NewPaymentRepository(db)
+	reconciliationRepo := pgrepo.NewReconciliationRepository(db)
+	pdfJobRepo := pgrepo.NewPDFJobRepository(db)
+	taxInvoiceRepo := pgrepo.NewTaxInvoiceRepository(db)
 
 	// Security adapters
 	hasher := security.NewBcryptHasher()
 	tokens := security.NewJWTTokenService()
+	signer := security.NewHMACSigner()
+	portalTokens := security.NewPortalTokenService()
+
+	// Delivery adapters
+	var mail ports.Mailer
+	notif := notifier.NewLogNotifier()
+	if cfg.SMTPHost != "" {
+		mail = mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+		notif = notifier.NewEmailNotifier(mail)
+	}
+
+	// PDF rendering
+	var renderer ports.PDFRenderer
+	switch cfg.PDFRenderer {
+	case "native":
+		renderer = pdf.NewNativeRenderer()
+	case "chrome":
+		renderer = pdf.NewChromeRenderer(pdf.ChromeOptions{
+			PoolSize:       cfg.ChromePoolSize,
+			RenderTimeout:  cfg.PDFRenderTimeout,
+			QueueTimeout:   cfg.PDFQueueTimeout,
+			HealthInterval: cfg.ChromeHealthCheck,
+		})
+	default:
+		log.Fatalf("unknown PDF_RENDERER %q, expected chrome or native", cfg.PDFRenderer)
+	}
+
+	// Rendered PDFs storage
+	var blobs ports.BlobStore
+	switch cfg.PDFStorage {
+	case "filesystem":
+		blobs = storage.NewFilesystemStore(cfg.PDFStorageDir)
+	case "s3":
+		var err error
+		blobs, err = storage.NewS3Store(storage.S3Options{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			PathStyle:       cfg.S3PathStyle,
+		})
+		if err != nil {
+			log.Fatalf("invalid S3 storage config: %v", err)
+		}
+	default:
+		log.Fatalf("unknown PDF_STORAGE %q, expected filesystem or s3", cfg.PDFStorage)
+	}
+
+	// Payment gateways; the fake one can mark invoices paid on request, so it
+	// is only registered when chosen explicitly.
+	var gateways []ports.PaymentGateway
+	if cfg.XenditSecretKey != "" {
+		gateways = append(gateways, payment.NewXenditGateway(cfg.XenditSecretKey, cfg.XenditCallbackToken))
+	}
+
+	if cfg.MidtransServerKey != "" {
+		gateways = append(gateways, payment.NewMidtransGateway(cfg.MidtransServerKey, cfg.MidtransProduction))
+	}
+
+	if cfg.PaymentProvider == "fake" {
+		if cfg.FakeGatewaySecret == "" {
+			log.Fatal("FAKE_GATEWAY_SECRET is required with PAYMENT_PROVIDER=fake")
+		}
+
+		gateways = append(gateways, payment.NewFakeGateway(cfg.FakeGatewaySecret, cfg.PublicBaseURL))
+	}
 
 	// Wire use cases
 	authUC := authuc.NewUseCase(authRepo, clientRepo, invoiceRepo, hasher, tokens)
 	clientUC := clientuc.NewUseCase(clientRepo)
-	invoiceUC := invoiceuc.NewUseCase(invoiceRepo, clientRepo, authRepo)
+	invoiceUC := invoiceuc.NewUseCase(invoiceRepo, clientRepo, authRepo, templateRepo, shareLinkRepo, brandingRepo, paymentRepo, mail, signer, renderer, pdfJobRepo, blobs)
+	lateFeeUC := latefeeuc.NewUseCase(lateFeeRepo, invoiceRepo, clientRepo)
+	reminderUC := reminderuc.NewUseCase(reminderRepo, invoiceRepo, authRepo, templateRepo, paymentRepo, notif)
+	emailTemplateUC := emailtemplateuc.NewUseCase(templateRepo, invoiceRepo, authRepo, paymentRepo, mail)
+	portalUC := portaluc.NewUseCase(portalRepo, clientRepo, invoiceRepo, authRepo, mail, portalTokens, cfg.PortalLoginURL, cfg.MagicLinkTTL, cfg.PortalTokenTTL)
+	paymentUC := paymentuc.NewUseCase(paymentRepo, invoiceRepo, gateways, cfg.PaymentProvider, cfg.PaymentSuccessURL)
+	reconciliationUC := reconciliationuc.NewUseCase(reconciliationRepo, invoiceRepo)
+	taxInvoiceUC := taxinvoiceuc.NewUseCase(taxInvoiceRepo, invoiceRepo, authRepo)
 
 	// Handlers
 	authHandler := handlers.NewAuthHandler(authUC)
 	clientHandler := handlers.NewClientHandler(clientUC)
 	invoiceHandler := handlers.NewInvoiceHandler(invoiceUC)
+	lateFeeHandler := handlers.NewLateFeeHandler(lateFeeUC)
+	reminderHandler := handlers.NewReminderHandler(reminderUC)
+	emailTemplateHandler := handlers.NewEmailTemplateHandler(emailTemplateUC)
+	if cfg.MagicLinksPerEmail < 1 || cfg.MagicLinksPerIP < 1 {
+		log.Fatal("MAGIC_LINKS_PER_EMAIL and MAGIC_LINKS_PER_IP must be at least 1")
+	}
+
+	portalHandler := handlers.NewPortalHandler(portalUC, invoiceUC, mw.NewRateLimitStore(cfg.MagicLinksPerEmail))
+	paymentHandler := handlers.NewPaymentHandler(paymentUC)
+	reconciliationHandler := handlers.NewReconciliationHandler(reconciliationUC)
+	taxInvoiceHandler := handlers.NewTaxInvoiceHandler(taxInvoiceUC)
 
 	// Register routes
 	ht.RegisterRoutes(e, ht.RouterDeps{
-		Auth:    authHandler,
-		Client:  clientHandler,
-		Invoice: invoiceHandler,
+		Auth:            authHandler,
+		Client:          clientHandler,
+		Invoice:         invoiceHandler,
+		LateFee:         lateFeeHandler,
+		Reminder:        reminderHandler,
+		EmailTemplate:   emailTemplateHandler,
+		Portal:          portalHandler,
+		Payment:         paymentHandler,
+		Reconciliation:  reconciliationHandler,
+		TaxInvoice:      taxInvoiceHandler,
+		MagicLinksPerIP: cfg.MagicLinksPerIP,
+		FakePayments:    cfg.PaymentProvider == "fake",
 	})
 
+	// Background jobs
+	retention := time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour
+	scheduler.New(
+		scheduler.PurgeTrashJob(invoiceUC, clientUC, retention, cfg.PurgeInterval),
+		scheduler.MarkOverdueJob(invoiceUC, cfg.OverdueInterval),
+		scheduler.LateFeeJob(lateFeeUC, cfg.LateFeeInterval),
+		scheduler.ReminderJob(reminderUC, cfg.ReminderInterval),
+	).Start(context.Background())
+	invoiceUC.RunPDFWorkers(context.Background(), cfg.PDFWorkers)
+
 	log.Printf("Starting server on port: %d", cfg.Port)
 	e.Logger.Fatal(e.Start(fmt.Sprintf("0.0.0.0:%d", cfg.Port)))
 }
@@ -15,11 +15,51 @@ import (
 )
 
 type Config struct {
-	Port        int    `env:"PORT" envDefault:"8080"`
-	JwtSecret   string `env:"JWT_SECRET"`
-	DatabaseURL string `env:"DATABASE_URL"`
-	Schema      string `env:"SCHEMA" envDefault:"public"`
-	SkipMigrate bool   `env:"SKIP_MIGRATE" envDefault:"false"` // Add option to skip migration
+	Port                int           `env:"PORT" envDefault:"8080"`
+	JwtSecret           string        `env:"JWT_SECRET"`
+	DatabaseURL         string        `env:"DATABASE_URL"`
+	Schema              string        `env:"SCHEMA" envDefault:"public"`
+	SkipMigrate         bool          `env:"SKIP_MIGRATE" envDefault:"false"` // Add option to skip migration
+	TrashRetentionDays  int           `env:"TRASH_RETENTION_DAYS" envDefault:"30"`
+	PurgeInterval       time.Duration `env:"PURGE_INTERVAL" envDefault:"24h"` // 0 disables the purge job
+	OverdueInterval     time.Duration `env:"OVERDUE_INTERVAL" envDefault:"1h"`
+	LateFeeInterval     time.Duration `env:"LATE_FEE_INTERVAL" envDefault:"1h"`
+	ReminderInterval    time.Duration `env:"REMINDER_INTERVAL" envDefault:"1h"`
+	SMTPHost            string        `env:"SMTP_HOST"` // empty disables email delivery
+	SMTPPort            int           `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername        string        `env:"SMTP_USERNAME"`
+	SMTPPassword        string        `env:"SMTP_PASSWORD"`
+	SMTPFrom            string        `env:"SMTP_FROM"`
+	ShareLinkSecret     string        `env:"SHARE_LINK_SECRET"`   // defaults to JWT_SECRET
+	PortalTokenSecret   string        `env:"PORTAL_TOKEN_SECRET"` // defaults to a key derived from JWT_SECRET
+	PortalLoginURL      string        `env:"PORTAL_LOGIN_URL" envDefault:"http://localhost:3000/portal/login"`
+	PortalTokenTTL      time.Duration `env:"PORTAL_TOKEN_TTL" envDefault:"24h"`
+	MagicLinkTTL        time.Duration `env:"MAGIC_LINK_TTL" envDefault:"15m"`
+	MagicLinksPerEmail  int           `env:"MAGIC_LINKS_PER_EMAIL" envDefault:"5"` // an hour
+	MagicLinksPerIP     int           `env:"MAGIC_LINKS_PER_IP" envDefault:"20"`   // an hour
+	TrustProxy          bool          `env:"TRUST_PROXY" envDefault:"false"`       // take client IPs from X-Forwarded-For
+	PaymentProvider     string        `env:"PAYMENT_PROVIDER"`                     // xendit, midtrans or fake; empty disables payment links
+	XenditSecretKey     string        `env:"XENDIT_SECRET_KEY"`
+	XenditCallbackToken string        `env:"XENDIT_CALLBACK_TOKEN"`
+	MidtransServerKey   string        `env:"MIDTRANS_SERVER_KEY"`
+	MidtransProduction  bool          `env:"MIDTRANS_PRODUCTION" envDefault:"false"`
+	FakeGatewaySecret   string        `env:"FAKE_GATEWAY_SECRET"` // required with PAYMENT_PROVIDER=fake
+	PublicBaseURL       string        `env:"PUBLIC_BASE_URL" envDefault:"http://localhost:8080"`
+	PaymentSuccessURL   string        `env:"PAYMENT_SUCCESS_URL"`
+	PDFRenderer         string        `env:"PDF_RENDERER" envDefault:"chrome"` // chrome, or native for containers without Chrome
+	ChromePoolSize      int           `env:"CHROME_POOL_SIZE" envDefault:"2"`
+	PDFRenderTimeout    time.Duration `env:"PDF_RENDER_TIMEOUT" envDefault:"30s"`
+	PDFQueueTimeout     time.Duration `env:"PDF_QUEUE_TIMEOUT" envDefault:"30s"`
+	ChromeHealthCheck   time.Duration `env:"CHROME_HEALTH_INTERVAL" envDefault:"1m"` // 0 disables health checks
+	PDFWorkers          int           `env:"PDF_WORKERS" envDefault:"2"`
+	PDFStorage          string        `env:"PDF_STORAGE" envDefault:"filesystem"` // filesystem or s3
+	PDFStorageDir       string        `env:"PDF_STORAGE_DIR" envDefault:"data/pdfs"`
+	S3Endpoint          string        `env:"S3_ENDPOINT"`
+	S3Region            string        `env:"S3_REGION" envDefault:"us-east-1"`
+	S3Bucket            string        `env:"S3_BUCKET"`
+	S3AccessKeyID       string        `env:"S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey   string        `env:"S3_SECRET_ACCESS_KEY"`
+	S3PathStyle         bool          `env:"S3_PATH_STYLE" envDefault:"false"` // true for MinIO and most self-hosted services
 }
 
 var (
@@ -98,6 +138,22 @@ func migrate(db *gorm.DB, schemaName string) {
 		&pmodel.Client{},
 		&pmodel.Invoice{},
 		&pmodel.InvoiceItem{},
+		&pmodel.LateFeePolicy{},
+		&pmodel.LateFee{},
+		&pmodel.ReminderRule{},
+		&pmodel.InvoiceReminder{},
+		&pmodel.InvoiceDelivery{},
+		&pmodel.EmailTemplate{},
+		&pmodel.InvoiceBranding{},
+		&pmodel.InvoiceShareLink{},
+		&pmodel.PortalMagicLink{},
+		&pmodel.Payment{},
+		&pmodel.PaymentLink{},
+		&pmodel.PaymentEvent{},
+		&pmodel.BankTransaction{},
+		&pmodel.ReconciliationMatch{},
+		&pmodel.PDFJob{},
+		&pmodel.TaxSerialRange{},
 	}
 
 	for _, model := range models {
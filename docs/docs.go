@@ -15,54 +15,46 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/v1/protected/auth/refresh-token": {
-            "post": {
-                "description": "Refresh access token with refresh token",
-                "consumes": [
-                    "application/json"
-                ],
+        "/health/pdf": {
+            "get": {
+                "description": "Report the PDF renderer's browser pool, queue wait and render duration metrics",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
-                ],
-                "summary": "Refresh Token",
-                "parameters": [
-                    {
-                        "description": "Refresh Token Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.refreshTokenRequest"
-                        }
-                    }
+                    "Health"
                 ],
+                "summary": "PDF Renderer Health",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.GenericResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/response.GenericResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.PDFRendererStats"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/v1/protected/clients": {
+        "/v1/portal/invoices": {
             "get": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "PortalAuth": []
                     }
                 ],
-                "description": "Get all clients",
+                "description": "List the client's sent, overdue and paid invoices",
                 "consumes": [
                     "application/json"
                 ],
@@ -70,26 +62,19 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Client"
+                    "Portal"
                 ],
-                "summary": "Get All Clients",
+                "summary": "Portal Invoices",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page Size",
-                        "name": "page_size",
-                        "in": "query"
-                    },
-                    {
+                        "enum": [
+                            "SENT",
+                            "OVERDUE",
+                            "PAID"
+                        ],
                         "type": "string",
-                        "description": "Search",
-                        "name": "search",
+                        "description": "Filter by status",
+                        "name": "status",
                         "in": "query"
                     }
                 ],
@@ -97,7 +82,22 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.GenericResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.Invoice"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -107,14 +107,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/v1/portal/invoices/{id}": {
+            "get": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "PortalAuth": []
                     }
                 ],
-                "description": "Create a new client",
+                "description": "Get one of the client's invoices or quotes",
                 "consumes": [
                     "application/json"
                 ],
@@ -122,29 +124,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Client"
+                    "Portal"
                 ],
-                "summary": "Create Client",
+                "summary": "Portal Invoice",
                 "parameters": [
                     {
-                        "description": "Client Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.clientRequest"
-                        }
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.GenericResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.Invoice"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/response.GenericResponse"
                         }
@@ -152,28 +164,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/clients/{id}": {
+        "/v1/portal/invoices/{id}/pdf": {
             "get": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "PortalAuth": []
                     }
                 ],
-                "description": "Get client by id",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Download one of the client's invoices or quotes as a PDF",
                 "produces": [
-                    "application/json"
+                    "application/pdf"
                 ],
                 "tags": [
-                    "Client"
+                    "Portal"
                 ],
-                "summary": "Get Client By ID",
+                "summary": "Portal Invoice PDF",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Client ID",
+                        "description": "Invoice ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -183,24 +192,26 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.GenericResponse"
+                            "type": "file"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/response.GenericResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/v1/portal/me": {
+            "get": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "PortalAuth": []
                     }
                 ],
-                "description": "Update client by id",
+                "description": "Get the signed in client",
                 "consumes": [
                     "application/json"
                 ],
@@ -208,32 +219,75 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Client"
+                    "Portal"
                 ],
-                "summary": "Update Client",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Client ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                "summary": "Portal Client",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.Client"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
-                    {
-                        "description": "Client Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/handlers.clientRequest"
+                            "$ref": "#/definitions/response.GenericResponse"
                         }
                     }
+                }
+            }
+        },
+        "/v1/portal/quotes": {
+            "get": {
+                "security": [
+                    {
+                        "PortalAuth": []
+                    }
+                ],
+                "description": "List the client's quotes awaiting acceptance",
+                "consumes": [
+                    "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portal"
+                ],
+                "summary": "Portal Quotes",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.GenericResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.Invoice"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -243,14 +297,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/portal/quotes/{id}/accept": {
+            "post": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "PortalAuth": []
                     }
                 ],
-                "description": "Delete client by id",
+                "description": "Accept a quote, which turns it into a draft invoice",
                 "consumes": [
                     "application/json"
                 ],
@@ -258,13 +314,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Client"
+                    "Portal"
                 ],
-                "summary": "Delete Client",
+                "summary": "Accept Quote",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Client ID",
+                        "description": "Quote ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -274,7 +330,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.GenericResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.Invoice"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -286,14 +354,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/invoices": {
+        "/v1/portal/statement": {
             "get": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "PortalAuth": []
                     }
                 ],
-                "description": "List invoices by user id",
+                "description": "Get a statement of the client's invoices issued in a period, by default the last 12 months",
                 "consumes": [
                     "application/json"
                 ],
@@ -301,26 +369,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Invoice"
+                    "Portal"
                 ],
-                "summary": "List Invoices By User ID",
+                "summary": "Portal Statement",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page Size",
-                        "name": "page_size",
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "from",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Status",
-                        "name": "status",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "to",
                         "in": "query"
                     }
                 ],
@@ -328,7 +390,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.GenericResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.Statement"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -338,14 +412,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/protected/auth/refresh-token": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Create a new invoice",
+                "description": "Refresh access token with refresh token",
                 "consumes": [
                     "application/json"
                 ],
@@ -353,23 +424,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Invoice"
+                    "Auth"
                 ],
-                "summary": "Create Invoice",
+                "summary": "Refresh Token",
                 "parameters": [
                     {
-                        "description": "Invoice Request",
+                        "description": "Refresh Token Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.invoiceReq"
+                            "$ref": "#/definitions/handlers.refreshTokenRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/response.GenericResponse"
                         }
@@ -383,14 +454,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/invoices/summary": {
+        "/v1/protected/bank/matches": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Invoice summary",
+                "description": "List suggested matches between bank transactions and invoices, best first for each transaction",
                 "consumes": [
                     "application/json"
                 ],
@@ -398,14 +469,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Invoice"
+                    "Reconciliation"
+                ],
+                "summary": "List Reconciliation Matches",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "SUGGESTED (default), ACCEPTED or REJECTED",
+                        "name": "status",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Invoice Summary",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.GenericResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.ReconciliationMatch"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -417,14 +511,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/invoices/{id}": {
-            "get": {
+        "/v1/protected/bank/matches/{id}/accept": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get invoice by id",
+                "description": "Record the matched transaction as a bank transfer payment of the invoice. The invoice is marked PAID\nonce its payments cover the total, and the transaction's other suggestions are rejected.",
                 "consumes": [
                     "application/json"
                 ],
@@ -432,13 +526,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Invoice"
+                    "Reconciliation"
                 ],
-                "summary": "Get Invoice By ID",
+                "summary": "Accept Reconciliation Match",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Invoice ID",
+                        "description": "Match ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -448,7 +542,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.GenericResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.Payment"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -458,14 +564,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/v1/protected/bank/matches/{id}/reject": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update invoice by id",
+                "description": "Reject a suggested match; the pair is not suggested again",
                 "consumes": [
                     "application/json"
                 ],
@@ -473,25 +581,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Invoice"
+                    "Reconciliation"
                 ],
-                "summary": "Update Invoice",
+                "summary": "Reject Reconciliation Match",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Invoice ID",
+                        "description": "Match ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Invoice Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.invoiceReq"
-                        }
                     }
                 ],
                 "responses": {
@@ -508,14 +607,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/v1/protected/bank/reconcile": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete invoice by id",
+                "description": "Suggest matches for all unmatched transactions against the open invoices, e.g. after new invoices\nwere sent. Pairs suggested before are not suggested again.",
                 "consumes": [
                     "application/json"
                 ],
@@ -523,18 +624,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Invoice"
-                ],
-                "summary": "Delete Invoice",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Invoice ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Reconciliation"
                 ],
+                "summary": "Reconcile Bank Transactions",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -551,38 +643,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/invoices/{id}/pdf": {
+        "/v1/protected/bank/statements": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Download invoice pdf",
+                "description": "Import a bank statement and suggest which open invoices its incoming transfers pay. Accepts CSV\n(columns date, amount or credit/debit, description, reference, counterparty, currency; day-first\ndates), SWIFT MT940 and ISO 20022 camt.053. Outgoing entries and entries imported before are skipped.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Invoice"
+                    "Reconciliation"
                 ],
-                "summary": "Download Invoice PDF",
+                "summary": "Import Bank Statement",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Invoice ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "file",
+                        "description": "Bank statement",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv, mt940 or camt053, detected from the file when omitted",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.GenericResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.BankImportReport"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -594,14 +704,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/invoices/{id}/status": {
-            "patch": {
+        "/v1/protected/bank/transactions": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update invoice status by id",
+                "description": "List imported incoming bank transactions",
                 "consumes": [
                     "application/json"
                 ],
@@ -609,32 +719,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Invoice"
+                    "Reconciliation"
                 ],
-                "summary": "Update Invoice Status",
+                "summary": "List Bank Transactions",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Invoice ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Invoice Status Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.statusReq"
-                        }
+                        "type": "string",
+                        "description": "UNMATCHED or MATCHED",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.GenericResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.BankTransaction"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -646,14 +761,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/me": {
+        "/v1/protected/clients": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get current user",
+                "description": "Get all clients",
                 "consumes": [
                     "application/json"
                 ],
@@ -661,9 +776,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Client"
+                ],
+                "summary": "Get All Clients",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search",
+                        "name": "search",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Me",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -678,16 +813,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/protected/me/banking": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update current user banking",
+                "description": "Create a new client",
                 "consumes": [
                     "application/json"
                 ],
@@ -695,23 +828,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Client"
                 ],
-                "summary": "Update Banking",
+                "summary": "Create Client",
                 "parameters": [
                     {
-                        "description": "Update Banking Request",
+                        "description": "Client Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.updateBankingRequest"
+                            "$ref": "#/definitions/handlers.clientRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/response.GenericResponse"
                         }
@@ -725,34 +858,104 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/me/change-password": {
+        "/v1/protected/clients/import": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Change current user password",
+                "description": "Import clients from a CSV file. The first row must be a header; columns are matched to\nname, email, phone and address case-insensitively unless overridden by the mapping field,\ne.g. {\"name\":\"Company\",\"email\":\"E-mail\"}. Rows whose email already exists are skipped.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Client"
                 ],
-                "summary": "Change Password",
+                "summary": "Import Clients",
                 "parameters": [
                     {
-                        "description": "Update Password Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "type": "file",
+                        "description": "CSV file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON object mapping client fields to CSV headers",
+                        "name": "mapping",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate only, do not create clients",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.updatePasswordRequest"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.ClientImportReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
                         }
                     }
+                }
+            }
+        },
+        "/v1/protected/clients/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List deleted clients that can still be restored",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "List Trashed Clients",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
@@ -770,14 +973,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/protected/me/deactivate": {
-            "post": {
+        "/v1/protected/clients/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deactivate current user",
+                "description": "Get client by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -785,9 +988,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Client"
+                ],
+                "summary": "Get Client By ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Deactivate User",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -802,16 +1014,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/protected/me/profile": {
+            },
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update current user profile",
+                "description": "Update client by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -819,17 +1029,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Client"
                 ],
-                "summary": "Update Profile",
+                "summary": "Update Client",
                 "parameters": [
                     {
-                        "description": "Update Profile Request",
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Client Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.updateProfileRequest"
+                            "$ref": "#/definitions/handlers.clientRequest"
                         }
                     }
                 ],
@@ -847,11 +1064,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/public/auth/sign-in": {
-            "post": {
-                "description": "Sign in with email and password",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete client by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -859,18 +1079,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Client"
                 ],
-                "summary": "Sign In",
+                "summary": "Delete Client",
                 "parameters": [
                     {
-                        "description": "Sign In Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.signInRequest"
-                        }
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -889,9 +1107,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/public/auth/sign-up": {
-            "post": {
-                "description": "Register a new user",
+        "/v1/protected/clients/{id}/late-fee-policy": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the late fee policy overriding the default for a client",
                 "consumes": [
                     "application/json"
                 ],
@@ -899,25 +1122,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Late Fee"
                 ],
-                "summary": "Sign Up",
+                "summary": "Get Client Late Fee Policy",
                 "parameters": [
                     {
-                        "description": "Sign Up Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.signUpRequest"
-                        }
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.GenericResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.LateFeePolicy"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -927,11 +1160,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/public/invoices/generate-pdf": {
-            "post": {
-                "description": "Generate public invoice",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create or replace the late fee policy overriding the default for a client",
                 "consumes": [
                     "application/json"
                 ],
@@ -939,17 +1175,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Invoice"
+                    "Late Fee"
                 ],
-                "summary": "Generate Public Invoice",
+                "summary": "Save Client Late Fee Policy",
                 "parameters": [
                     {
-                        "description": "Invoice Public Request",
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Late Fee Policy Request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.invoicePublicReq"
+                            "$ref": "#/definitions/handlers.lateFeePolicyRequest"
                         }
                     }
                 ],
@@ -957,7 +1200,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.GenericResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.LateFeePolicy"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -967,10 +1222,4512 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a client's late fee policy so the default applies again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Late Fee"
+                ],
+                "summary": "Delete Client Late Fee Policy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/clients/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore a deleted client by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Restore Client",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List invoices by user id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "List Invoices By User ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new invoice",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Create Invoice",
+                "parameters": [
+                    {
+                        "description": "Invoice Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.invoiceReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Apply an action to several invoices at once. Actions: status (requires status), delete,\nduplicate and export_pdf. export_pdf responds with a zip archive of the PDFs plus a\nmanifest.csv of their totals and errors; the other actions respond with a result per invoice.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/zip"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Bulk Invoice Actions",
+                "parameters": [
+                    {
+                        "description": "Bulk Invoice Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.bulkInvoiceReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.InvoiceBulkResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Render every invoice matching the filters and stream back a zip archive with one PDF per\ninvoice, named after its number, and a manifest.csv listing each invoice's totals with a\nfinal row of sums. Invoices that fail to render are left out and reported in the manifest.\nAt most 1000 invoices can be exported at once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Export Invoice PDFs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Issued on or after (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Issued on or before (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invoice status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "client_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Import invoices exported from another tool. The file is CSV or JSON (an array of objects) with\none line item per row; rows sharing an invoice_number form one invoice. Columns: invoice_number,\nissue_date, due_date, status, notes, tax_rate, delivery_fee, client_name, client_email,\nclient_phone, client_address, item_description, item_quantity, item_unit_price.\nClients are matched by email (or name) and created when missing. Invoices whose number already\nexists are skipped. Each invoice is stored in its own transaction.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Import Invoices",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or JSON export",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv or json, detected from the file extension when omitted",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.InvoiceImportReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/summary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invoice summary",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Invoice Summary",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List deleted invoices that can still be restored",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "List Trashed Invoices",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get invoice by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Get Invoice By ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update invoice by id. The invoice keeps its status, which only changes through the status endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Update Invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Invoice Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.invoiceReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete invoice by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Delete Invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the emails sent, or attempted, for an invoice",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "List Invoice Deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.InvoiceDelivery"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/duplicate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Copy an invoice into a new draft with a freshly allocated invoice number. The copy is issued\non issue_date (today when omitted) and keeps the original gap between issue and due date.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Duplicate Invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Duplicate Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.duplicateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/late-fees": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the late fees charged on an invoice",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Late Fee"
+                ],
+                "summary": "List Invoice Late Fees",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.LateFee"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/payment-links": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the payment gateway checkouts created for an invoice",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "List Payment Links",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.PaymentLink"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a payment gateway checkout for the outstanding amount of a SENT or OVERDUE invoice.\nThe invoice is marked PAID automatically once the gateway reports the payment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Create Payment Link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment Link Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.paymentLinkReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.PaymentLink"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/payments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the payments recorded against an invoice",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "List Invoice Payments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.Payment"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/pdf": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download invoice pdf. With format=pdfa-3 the PDF is a PDF/A-3 archive embedding the invoice\nas Factur-X (EN 16931) XML, for e-invoicing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Download Invoice PDF",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pdf (default) or pdfa-3",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/pdf-jobs": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queue the invoice PDF to be rendered in the background. Poll the returned job with\nGET /v1/protected/pdf-jobs/{id} and download it once it is DONE. A job already queued\nfor the invoice is returned instead of a new one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Queue Invoice PDF",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.PDFJob"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/qris": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download a QRIS code, as PNG, that pays the outstanding amount of the invoice.\nRequires a QRIS payload set with PUT /v1/protected/me/qris.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Invoice QRIS Code",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/reminders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the reminders sent, or attempted, for an invoice",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reminder"
+                ],
+                "summary": "List Invoice Reminders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.InvoiceReminder"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Opt an invoice out of, or back into, automatic reminders",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reminder"
+                ],
+                "summary": "Toggle Invoice Reminders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Invoice Reminders Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.invoiceRemindersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore a deleted invoice by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Restore Invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/send": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Email the invoice PDF to the client and mark a draft invoice as SENT. Subject and body are\nGo templates with {{.ClientName}}, {{.InvoiceNumber}}, {{.Total}}, {{.IssueDate}}, {{.DueDate}},\n{{.SenderName}} and {{.SenderEmail}}; empty values use the default wording.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Send Invoice",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Send Invoice Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.sendInvoiceReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.InvoiceDelivery"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/share-links": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the share links of an invoice, including expired and revoked ones",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "List Invoice Share Links",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.InvoiceShareLink"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a link to view the invoice without logging in at /v1/public/invoices/view/{token}",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Create Invoice Share Link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Share Link Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.shareLinkReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.InvoiceShareLink"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/share-links/{linkId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke a share link so its token stops working",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Revoke Invoice Share Link",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Share Link ID",
+                        "name": "linkId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/status": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update invoice status by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Update Invoice Status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Invoice Status Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.statusReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/tax-serial": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Give an issued invoice with PPN its tax invoice serial (NSFP), the next one left in the serial\nranges. Requires an NPWP set with PUT /v1/protected/me/tax-id. An invoice keeps its serial, so\nassigning again returns the same one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tax Invoice"
+                ],
+                "summary": "Assign Tax Invoice Serial",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.Invoice"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/invoices/{id}/ubl": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download the invoice as a UBL 2.1 Peppol BIS Billing 3.0 e-invoice. Invoices breaking the\nEN 16931 or Peppol business rules are rejected, naming the rules broken.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Download Invoice UBL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Me",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me/banking": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update current user banking",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Update Banking",
+                "parameters": [
+                    {
+                        "description": "Update Banking Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updateBankingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me/change-password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change current user password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Change Password",
+                "parameters": [
+                    {
+                        "description": "Update Password Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updatePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me/deactivate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deactivate current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Deactivate User",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me/email-templates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the email templates for every kind (INVOICE_SENT, REMINDER, RECEIPT, QUOTE), with the\ndefault wording where no custom template is saved",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Email Template"
+                ],
+                "summary": "List Email Templates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.EmailTemplate"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me/email-templates/{kind}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the email template of a kind",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Email Template"
+                ],
+                "summary": "Get Email Template",
+                "parameters": [
+                    {
+                        "enum": [
+                            "INVOICE_SENT",
+                            "REMINDER",
+                            "RECEIPT",
+                            "QUOTE"
+                        ],
+                        "type": "string",
+                        "description": "Template kind",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.EmailTemplate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Save a custom email template. subject and body are Go text templates, html_body an optional Go\nHTML template whose values are escaped automatically. Variables: {{.ClientName}},\n{{.InvoiceNumber}}, {{.Total}}, {{.IssueDate}}, {{.DueDate}}, {{.DueStatus}}, {{.PayLink}},\n{{.SenderName}} and {{.SenderEmail}}.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Email Template"
+                ],
+                "summary": "Save Email Template",
+                "parameters": [
+                    {
+                        "enum": [
+                            "INVOICE_SENT",
+                            "REMINDER",
+                            "RECEIPT",
+                            "QUOTE"
+                        ],
+                        "type": "string",
+                        "description": "Template kind",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Email Template Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.emailTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.EmailTemplate"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a custom email template so the default wording applies again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Email Template"
+                ],
+                "summary": "Reset Email Template",
+                "parameters": [
+                    {
+                        "enum": [
+                            "INVOICE_SENT",
+                            "REMINDER",
+                            "RECEIPT",
+                            "QUOTE"
+                        ],
+                        "type": "string",
+                        "description": "Template kind",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me/email-templates/{kind}/preview": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Render an email template with sample data, or with an invoice when invoice_id is given.\nFields left empty are taken from the saved template.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Email Template"
+                ],
+                "summary": "Preview Email Template",
+                "parameters": [
+                    {
+                        "enum": [
+                            "INVOICE_SENT",
+                            "REMINDER",
+                            "RECEIPT",
+                            "QUOTE"
+                        ],
+                        "type": "string",
+                        "description": "Template kind",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Email Template Preview Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.emailTemplatePreviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.RenderedEmail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me/email-templates/{kind}/test": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Email a template rendered with sample data to the signed in user. Fields left empty are taken\nfrom the saved template.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Email Template"
+                ],
+                "summary": "Send Test Email",
+                "parameters": [
+                    {
+                        "enum": [
+                            "INVOICE_SENT",
+                            "REMINDER",
+                            "RECEIPT",
+                            "QUOTE"
+                        ],
+                        "type": "string",
+                        "description": "Template kind",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Email Template Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.emailTemplatePreviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.RenderedEmail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me/invoice-branding": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the layout, template and branding used for the user's invoices, or the defaults when\nnone are saved",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice Branding"
+                ],
+                "summary": "Get Invoice Branding",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.InvoiceBranding"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Save the look of the user's invoices. layout is one of classic, modern, compact or custom; custom\nrenders template, a Go HTML template whose values are escaped automatically. Variables:\n{{.InvoiceNumber}}, {{.Status}}, {{.IssueDate}}, {{.DueDate}}, {{.PaymentTerms}}, {{.Currency}},\n{{.Sender.Name}}, {{.Sender.Address}}, {{.Sender.Email}}, {{.Sender.Phone}}, the same under\n{{.Client}}, {{range .Items}} with {{.Description}}, {{.Quantity}}, {{.UnitPrice}} and {{.Total}},\n{{.Subtotal}}, {{.TaxRate}}, {{.Tax}}, {{.DeliveryFee}}, {{.Total}}, {{.Notes}}, {{.Bank.Name}},\n{{.Bank.AccountName}}, {{.Bank.AccountNumber}}, {{.QRISImage}}, {{.Logo}}, {{.AccentColor}},\n{{.Font}} and {{.FooterText}}. The settings are rejected unless a sample invoice renders.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice Branding"
+                ],
+                "summary": "Save Invoice Branding",
+                "parameters": [
+                    {
+                        "description": "Invoice Branding Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.invoiceBrandingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.InvoiceBranding"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete the saved invoice branding so the default look applies again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice Branding"
+                ],
+                "summary": "Reset Invoice Branding",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me/invoice-branding/preview": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Render a sample invoice, or the invoice given by invoice_id, as HTML with the given branding",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Invoice Branding"
+                ],
+                "summary": "Preview Invoice Branding",
+                "parameters": [
+                    {
+                        "description": "Invoice Branding Preview Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.invoiceBrandingPreviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Invoice HTML",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me/late-fee-policy": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the default late fee policy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Late Fee"
+                ],
+                "summary": "Get Late Fee Policy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.LateFeePolicy"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create or replace the default late fee policy. Fees are charged on OVERDUE invoices once the\ngrace period has passed: a fixed or percentage fee first, then monthly interest, never more\nthan cap in total. LINE_ITEM adds fees to the invoice, FEE_INVOICE issues a linked invoice.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Late Fee"
+                ],
+                "summary": "Save Late Fee Policy",
+                "parameters": [
+                    {
+                        "description": "Late Fee Policy Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.lateFeePolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.LateFeePolicy"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete the default late fee policy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Late Fee"
+                ],
+                "summary": "Delete Late Fee Policy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me/payment-terms": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the default payment terms used to compute invoice due dates",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Update Payment Terms",
+                "parameters": [
+                    {
+                        "description": "Update Payment Terms Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updatePaymentTermsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me/profile": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update current user profile",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Update Profile",
+                "parameters": [
+                    {
+                        "description": "Update Profile Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me/qris": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store the static QRIS merchant payload, the decoded text of the QRIS sticker. Invoices then carry\na dynamic QRIS code for their amount.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Update QRIS",
+                "parameters": [
+                    {
+                        "description": "Update QRIS Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updateQRISRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me/reminders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the reminder steps sent for unpaid invoices, in days relative to the due date",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reminder"
+                ],
+                "summary": "List Reminder Schedule",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.ReminderRule"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the reminder schedule, e.g. [-3, 0, 7, 14] reminds 3 days before the due date, on it,\nand 7 and 14 days after. Reminders go to SENT and OVERDUE invoices; an empty list turns them off.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reminder"
+                ],
+                "summary": "Replace Reminder Schedule",
+                "parameters": [
+                    {
+                        "description": "Reminder Schedule Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.reminderRulesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.ReminderRule"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/me/tax-id": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Store the NPWP and NITKU of a PKP (VAT-registered) user, needed to assign tax invoice serials\nand export e-Faktur.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Update Tax ID",
+                "parameters": [
+                    {
+                        "description": "Update Tax ID Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updateTaxIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/pdf-jobs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the status of a background PDF job: QUEUED, RUNNING, DONE, FAILED, or EXPIRED\nwhen the invoice changed after the PDF was rendered.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Get PDF Job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "PDF Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.PDFJob"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/pdf-jobs/{id}/download": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download the PDF rendered by a finished job. Fails when the invoice changed since.",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Download PDF Job",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "PDF Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/tax-invoices/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Export the tax invoices issued in a month, for reporting to DJP. csv is the import CSV of the\ne-Faktur desktop application, xml the bulk tax invoice import of Coretax. Both carry the DPP,\nPPN and PPnBM of every item; delivery fees are not subject to PPN and are left out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Tax Invoice"
+                ],
+                "summary": "Export e-Faktur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tax period (YYYY-MM)",
+                        "name": "period",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv (default) or xml",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/tax-invoices/serial-ranges": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the tax invoice serial (NSFP) ranges DJP allocated, with the next serial of each",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tax Invoice"
+                ],
+                "summary": "List Tax Serial Ranges",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/entity.TaxSerialRange"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a range of tax invoice serials (NSFP) allocated by DJP. Serials are assigned to invoices in\norder, starting from the lowest range.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tax Invoice"
+                ],
+                "summary": "Add Tax Serial Range",
+                "parameters": [
+                    {
+                        "description": "Tax Serial Range Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.taxSerialRangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.TaxSerialRange"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/tax-invoices/serial-ranges/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a tax invoice serial range none of whose serials have been assigned",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tax Invoice"
+                ],
+                "summary": "Delete Tax Serial Range",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Range ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/public/auth/sign-in": {
+            "post": {
+                "description": "Sign in with email and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Sign In",
+                "parameters": [
+                    {
+                        "description": "Sign In Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.signInRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/public/auth/sign-up": {
+            "post": {
+                "description": "Register a new user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Sign Up",
+                "parameters": [
+                    {
+                        "description": "Sign Up Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.signUpRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/public/invoices/generate-pdf": {
+            "post": {
+                "description": "Generate public invoice",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "Generate Public Invoice",
+                "parameters": [
+                    {
+                        "description": "Invoice Public Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.invoicePublicReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/public/invoices/view/{token}": {
+            "get": {
+                "description": "View an invoice through a share link without logging in, as JSON, an HTML page or a PDF.\nEvery request counts as a view.",
+                "produces": [
+                    "application/json",
+                    "text/html",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Invoice"
+                ],
+                "summary": "View Shared Invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share link token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "html",
+                            "pdf"
+                        ],
+                        "type": "string",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.Invoice"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/public/payments/fake/{reference}/pay": {
+            "post": {
+                "description": "Pay a fake gateway checkout in full, for development. Only available with PAYMENT_PROVIDER=fake.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Simulate Payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment link reference",
+                        "name": "reference",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/public/payments/webhooks/{provider}": {
+            "post": {
+                "description": "Receive a payment notification from a gateway. The signature is verified against the\nprovider's credentials and events already processed are acknowledged without being applied again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Payment Gateway Webhook",
+                "parameters": [
+                    {
+                        "enum": [
+                            "xendit",
+                            "midtrans",
+                            "fake"
+                        ],
+                        "type": "string",
+                        "description": "Payment provider",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/public/portal/login": {
+            "post": {
+                "description": "Exchange the token of a portal sign-in link for a portal access token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portal"
+                ],
+                "summary": "Portal Login",
+                "parameters": [
+                    {
+                        "description": "Portal Login Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.portalLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.GenericResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/entity.PortalSession"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/public/portal/magic-link": {
+            "post": {
+                "description": "Email a single use sign-in link for the client portal to the given address. The response is\nthe same whether or not the address belongs to a client.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portal"
+                ],
+                "summary": "Request Portal Link",
+                "parameters": [
+                    {
+                        "description": "Magic Link Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.magicLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/response.GenericResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entity.BankImportReport": {
+            "type": "object",
+            "properties": {
+                "debits": {
+                    "description": "outgoing entries, which are not imported",
+                    "type": "integer"
+                },
+                "duplicates": {
+                    "type": "integer"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "suggestions": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.BankTransaction": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "bank_reference": {
+                    "type": "string"
+                },
+                "booked_at": {
+                    "type": "string"
+                },
+                "counterparty": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imported_at": {
+                    "type": "string"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "payment_id": {
+                    "type": "integer"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.BankTransactionStatus"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.BankTransactionStatus": {
+            "type": "string",
+            "enum": [
+                "UNMATCHED",
+                "MATCHED"
+            ],
+            "x-enum-varnames": [
+                "BankTransactionUnmatched",
+                "BankTransactionMatched"
+            ]
+        },
+        "entity.Client": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "language": {
+                    "description": "invoice language, empty for English",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "nitku": {
+                    "type": "string"
+                },
+                "npwp": {
+                    "description": "16-digit taxpayer number, empty for buyers without one",
+                    "type": "string"
+                },
+                "payment_terms": {
+                    "description": "empty inherits the user's default",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.PaymentTerms"
+                        }
+                    ]
+                },
+                "payment_terms_days": {
+                    "type": "integer"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ClientImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ClientImportResult"
+                    }
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ClientImportResult": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.ImportRowStatus"
+                }
+            }
+        },
+        "entity.DurationStats": {
+            "type": "object",
+            "properties": {
+                "avg_ms": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "last_ms": {
+                    "type": "number"
+                },
+                "max_ms": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.EmailTemplate": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "custom": {
+                    "type": "boolean"
+                },
+                "html_body": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "$ref": "#/definitions/entity.EmailTemplateKind"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.EmailTemplateKind": {
+            "type": "string",
+            "enum": [
+                "INVOICE_SENT",
+                "REMINDER",
+                "RECEIPT",
+                "QUOTE"
+            ],
+            "x-enum-varnames": [
+                "EmailTemplateInvoiceSent",
+                "EmailTemplateReminder",
+                "EmailTemplateReceipt",
+                "EmailTemplateQuote"
+            ]
+        },
+        "entity.ImportRowStatus": {
+            "type": "string",
+            "enum": [
+                "VALID",
+                "CREATED",
+                "SKIPPED",
+                "FAILED"
+            ],
+            "x-enum-varnames": [
+                "ImportRowStatusValid",
+                "ImportRowStatusCreated",
+                "ImportRowStatusSkipped",
+                "ImportRowStatusFailed"
+            ]
+        },
+        "entity.Invoice": {
+            "type": "object",
+            "properties": {
+                "amount_in_words": {
+                    "type": "boolean"
+                },
+                "amount_paid": {
+                    "type": "number"
+                },
+                "client": {
+                    "$ref": "#/definitions/entity.Client"
+                },
+                "client_address": {
+                    "type": "string"
+                },
+                "client_email": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "client_name": {
+                    "type": "string"
+                },
+                "client_phone": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "delivery_fee": {
+                    "type": "number"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "fee_for_invoice_id": {
+                    "type": "integer"
+                },
+                "first_viewed_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_number": {
+                    "type": "string"
+                },
+                "issue_date": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.InvoiceItem"
+                    }
+                },
+                "language": {
+                    "description": "empty uses the client's language",
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "payment_terms": {
+                    "$ref": "#/definitions/entity.PaymentTerms"
+                },
+                "payment_terms_days": {
+                    "type": "integer"
+                },
+                "quote_accepted_at": {
+                    "type": "string"
+                },
+                "reminders_disabled": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "tax": {
+                    "type": "number"
+                },
+                "tax_rate": {
+                    "type": "number"
+                },
+                "tax_serial": {
+                    "description": "NSFP of the invoice's faktur pajak, once assigned",
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "description": "Relationship",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "view_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.InvoiceBranding": {
+            "type": "object",
+            "properties": {
+                "accent_color": {
+                    "type": "string"
+                },
+                "custom": {
+                    "type": "boolean"
+                },
+                "font": {
+                    "type": "string"
+                },
+                "footer_text": {
+                    "type": "string"
+                },
+                "layout": {
+                    "$ref": "#/definitions/entity.InvoiceLayout"
+                },
+                "logo": {
+                    "type": "string"
+                },
+                "template": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.InvoiceBulkResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "new_invoice_id": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "entity.InvoiceDelivery": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "recipient": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.InvoiceImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "invoices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.InvoiceImportResult"
+                    }
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.InvoiceImportResult": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "invoice_number": {
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.ImportRowStatus"
+                }
+            }
+        },
+        "entity.InvoiceItem": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "unit_price": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.InvoiceLayout": {
+            "type": "string",
+            "enum": [
+                "classic",
+                "modern",
+                "compact",
+                "custom"
+            ],
+            "x-enum-varnames": [
+                "InvoiceLayoutClassic",
+                "InvoiceLayoutModern",
+                "InvoiceLayoutCompact",
+                "InvoiceLayoutCustom"
+            ]
+        },
+        "entity.InvoiceReminder": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "offset_days": {
+                    "type": "integer"
+                },
+                "recipient": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.InvoiceShareLink": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.LateFee": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "applied_at": {
+                    "type": "string"
+                },
+                "base_amount": {
+                    "type": "number"
+                },
+                "fee_invoice_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "invoice_item_id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "$ref": "#/definitions/entity.LateFeeKind"
+                },
+                "period": {
+                    "type": "integer"
+                },
+                "policy_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.LateFeeKind": {
+            "type": "string",
+            "enum": [
+                "INITIAL",
+                "INTEREST"
+            ],
+            "x-enum-varnames": [
+                "LateFeeKindInitial",
+                "LateFeeKindInterest"
+            ]
+        },
+        "entity.LateFeeMode": {
+            "type": "string",
+            "enum": [
+                "LINE_ITEM",
+                "FEE_INVOICE"
+            ],
+            "x-enum-varnames": [
+                "LateFeeModeLineItem",
+                "LateFeeModeFeeInvoice"
+            ]
+        },
+        "entity.LateFeePolicy": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "cap": {
+                    "type": "number"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "grace_days": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "$ref": "#/definitions/entity.LateFeeMode"
+                },
+                "monthly_interest_rate": {
+                    "type": "number"
+                },
+                "type": {
+                    "$ref": "#/definitions/entity.LateFeeType"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.LateFeeType": {
+            "type": "string",
+            "enum": [
+                "FIXED",
+                "PERCENTAGE"
+            ],
+            "x-enum-varnames": [
+                "LateFeeTypeFixed",
+                "LateFeeTypePercentage"
+            ]
+        },
+        "entity.MatchStatus": {
+            "type": "string",
+            "enum": [
+                "SUGGESTED",
+                "ACCEPTED",
+                "REJECTED"
+            ],
+            "x-enum-varnames": [
+                "MatchSuggested",
+                "MatchAccepted",
+                "MatchRejected"
+            ]
+        },
+        "entity.PDFJob": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "cached": {
+                    "description": "the PDF had already been rendered",
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.PDFJobStatus"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.PDFJobStatus": {
+            "type": "string",
+            "enum": [
+                "QUEUED",
+                "RUNNING",
+                "DONE",
+                "FAILED",
+                "EXPIRED"
+            ],
+            "x-enum-varnames": [
+                "PDFJobQueued",
+                "PDFJobRunning",
+                "PDFJobDone",
+                "PDFJobFailed",
+                "PDFJobExpired"
+            ]
+        },
+        "entity.PDFRendererStats": {
+            "type": "object",
+            "properties": {
+                "canceled": {
+                    "type": "integer"
+                },
+                "failures": {
+                    "type": "integer"
+                },
+                "idle": {
+                    "type": "integer"
+                },
+                "pool_size": {
+                    "type": "integer"
+                },
+                "queue_wait": {
+                    "$ref": "#/definitions/entity.DurationStats"
+                },
+                "render_duration": {
+                    "$ref": "#/definitions/entity.DurationStats"
+                },
+                "renderer": {
+                    "type": "string"
+                },
+                "renders": {
+                    "type": "integer"
+                },
+                "restarts": {
+                    "type": "integer"
+                },
+                "running": {
+                    "type": "integer"
+                },
+                "timeouts": {
+                    "type": "integer"
+                },
+                "waiting": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.Payment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "method": {
+                    "$ref": "#/definitions/entity.PaymentMethod"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.PaymentLink": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.PaymentLinkStatus"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.PaymentLinkStatus": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "PAID",
+                "EXPIRED"
+            ],
+            "x-enum-varnames": [
+                "PaymentLinkStatusPending",
+                "PaymentLinkStatusPaid",
+                "PaymentLinkStatusExpired"
+            ]
+        },
+        "entity.PaymentMethod": {
+            "type": "string",
+            "enum": [
+                "GATEWAY",
+                "BANK_TRANSFER"
+            ],
+            "x-enum-varnames": [
+                "PaymentMethodGateway",
+                "PaymentMethodBankTransfer"
+            ]
+        },
+        "entity.PaymentTerms": {
+            "type": "string",
+            "enum": [
+                "DUE_ON_RECEIPT",
+                "NET_7",
+                "NET_14",
+                "NET_30",
+                "END_OF_MONTH",
+                "CUSTOM"
+            ],
+            "x-enum-varnames": [
+                "PaymentTermsDueOnReceipt",
+                "PaymentTermsNet7",
+                "PaymentTermsNet14",
+                "PaymentTermsNet30",
+                "PaymentTermsEndOfMonth",
+                "PaymentTermsCustom"
+            ]
+        },
+        "entity.PortalSession": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "client": {
+                    "$ref": "#/definitions/entity.Client"
+                },
+                "expires_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ReconciliationMatch": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invoice_id": {
+                    "type": "integer"
+                },
+                "invoice_number": {
+                    "type": "string"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "score": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/entity.MatchStatus"
+                },
+                "transaction": {
+                    "$ref": "#/definitions/entity.BankTransaction"
+                },
+                "transaction_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ReminderRule": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "offset_days": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.RenderedEmail": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "html_body": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Statement": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "invoiced": {
+                    "type": "number"
+                },
+                "invoices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Invoice"
+                    }
+                },
+                "outstanding": {
+                    "type": "number"
+                },
+                "paid": {
+                    "type": "number"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.TaxSerialRange": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "first": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last": {
+                    "type": "integer"
+                },
+                "next": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.User": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "bank_account_name": {
+                    "type": "string"
+                },
+                "bank_account_number": {
+                    "type": "string"
+                },
+                "bank_name": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "nitku": {
+                    "description": "22-digit place of business number, empty without an NPWP",
+                    "type": "string"
+                },
+                "npwp": {
+                    "description": "16-digit taxpayer number, set when the user is PKP",
+                    "type": "string"
+                },
+                "payment_terms": {
+                    "$ref": "#/definitions/entity.PaymentTerms"
+                },
+                "payment_terms_days": {
+                    "type": "integer"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "qris_payload": {
+                    "description": "static merchant QRIS payload, empty when not set",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.bulkInvoiceReq": {
+            "type": "object",
+            "required": [
+                "action",
+                "ids"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "status",
+                        "delete",
+                        "duplicate",
+                        "export_pdf"
+                    ]
+                },
+                "ids": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "QUOTE",
+                        "DRAFT",
+                        "SENT",
+                        "OVERDUE",
+                        "PAID"
+                    ]
+                }
             }
-        }
-    },
-    "definitions": {
+        },
         "handlers.clientRequest": {
             "type": "object",
             "required": [
@@ -986,14 +5743,177 @@ const docTemplate = `{
                 "email": {
                     "type": "string"
                 },
+                "language": {
+                    "description": "language of the client's invoices",
+                    "type": "string",
+                    "enum": [
+                        "en",
+                        "id"
+                    ]
+                },
                 "name": {
                     "type": "string"
                 },
+                "nitku": {
+                    "description": "22 digits, empty for the head office",
+                    "type": "string"
+                },
+                "npwp": {
+                    "description": "15 or 16 digits, for buyers with one",
+                    "type": "string"
+                },
+                "payment_terms": {
+                    "type": "string",
+                    "enum": [
+                        "DUE_ON_RECEIPT",
+                        "NET_7",
+                        "NET_14",
+                        "NET_30",
+                        "END_OF_MONTH",
+                        "CUSTOM"
+                    ]
+                },
+                "payment_terms_days": {
+                    "type": "integer",
+                    "minimum": 1
+                },
                 "phone": {
                     "type": "string"
                 }
             }
         },
+        "handlers.duplicateReq": {
+            "type": "object",
+            "properties": {
+                "issue_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.emailTemplatePreviewRequest": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "html_body": {
+                    "type": "string",
+                    "maxLength": 50000
+                },
+                "invoice_id": {
+                    "description": "render with this invoice instead of sample data",
+                    "type": "integer"
+                },
+                "subject": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "handlers.emailTemplateRequest": {
+            "type": "object",
+            "required": [
+                "body",
+                "subject"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "html_body": {
+                    "type": "string",
+                    "maxLength": 50000
+                },
+                "subject": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "handlers.invoiceBrandingPreviewRequest": {
+            "type": "object",
+            "properties": {
+                "accent_color": {
+                    "type": "string"
+                },
+                "font": {
+                    "type": "string",
+                    "enum": [
+                        "Inter",
+                        "Helvetica",
+                        "Georgia",
+                        "Roboto",
+                        "Courier"
+                    ]
+                },
+                "footer_text": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "invoice_id": {
+                    "description": "render this invoice instead of a sample",
+                    "type": "integer"
+                },
+                "layout": {
+                    "type": "string",
+                    "enum": [
+                        "classic",
+                        "modern",
+                        "compact",
+                        "custom"
+                    ]
+                },
+                "logo": {
+                    "description": "data URI of a PNG or JPEG image, at most 256 KB",
+                    "type": "string"
+                },
+                "template": {
+                    "type": "string",
+                    "maxLength": 65536
+                }
+            }
+        },
+        "handlers.invoiceBrandingRequest": {
+            "type": "object",
+            "properties": {
+                "accent_color": {
+                    "type": "string"
+                },
+                "font": {
+                    "type": "string",
+                    "enum": [
+                        "Inter",
+                        "Helvetica",
+                        "Georgia",
+                        "Roboto",
+                        "Courier"
+                    ]
+                },
+                "footer_text": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "layout": {
+                    "type": "string",
+                    "enum": [
+                        "classic",
+                        "modern",
+                        "compact",
+                        "custom"
+                    ]
+                },
+                "logo": {
+                    "description": "data URI of a PNG or JPEG image, at most 256 KB",
+                    "type": "string"
+                },
+                "template": {
+                    "type": "string",
+                    "maxLength": 65536
+                }
+            }
+        },
         "handlers.invoiceItemReq": {
             "type": "object",
             "required": [
@@ -1005,6 +5925,10 @@ const docTemplate = `{
                 "description": {
                     "type": "string"
                 },
+                "id": {
+                    "description": "of a fetched item sent back; late fee items are kept as they are",
+                    "type": "integer"
+                },
                 "quantity": {
                     "type": "integer",
                     "minimum": 1
@@ -1018,13 +5942,15 @@ const docTemplate = `{
         "handlers.invoicePublicReq": {
             "type": "object",
             "required": [
-                "due_date",
                 "invoice_number",
                 "issue_date",
                 "recipient",
                 "sender"
             ],
             "properties": {
+                "amount_in_words": {
+                    "type": "boolean"
+                },
                 "delivery_fee": {
                     "type": "number"
                 },
@@ -1043,9 +5969,31 @@ const docTemplate = `{
                         "$ref": "#/definitions/handlers.invoiceItemReq"
                     }
                 },
+                "language": {
+                    "type": "string",
+                    "enum": [
+                        "en",
+                        "id"
+                    ]
+                },
                 "notes": {
                     "type": "string"
                 },
+                "payment_terms": {
+                    "type": "string",
+                    "enum": [
+                        "DUE_ON_RECEIPT",
+                        "NET_7",
+                        "NET_14",
+                        "NET_30",
+                        "END_OF_MONTH",
+                        "CUSTOM"
+                    ]
+                },
+                "payment_terms_days": {
+                    "type": "integer",
+                    "minimum": 1
+                },
                 "recipient": {
                     "$ref": "#/definitions/handlers.senderRecipientRequest"
                 },
@@ -1057,15 +6005,29 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.invoiceRemindersRequest": {
+            "type": "object",
+            "required": [
+                "enabled"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
         "handlers.invoiceReq": {
             "type": "object",
             "required": [
-                "due_date",
                 "invoice_number",
                 "issue_date",
                 "items"
             ],
             "properties": {
+                "amount_in_words": {
+                    "description": "print the total spelled out",
+                    "type": "boolean"
+                },
                 "client_address": {
                     "type": "string"
                 },
@@ -1085,6 +6047,7 @@ const docTemplate = `{
                     "type": "number"
                 },
                 "due_date": {
+                    "description": "derived from the payment terms when omitted",
                     "type": "string"
                 },
                 "invoice_number": {
@@ -1099,14 +6062,117 @@ const docTemplate = `{
                         "$ref": "#/definitions/handlers.invoiceItemReq"
                     }
                 },
+                "language": {
+                    "description": "empty uses the client's language",
+                    "type": "string",
+                    "enum": [
+                        "en",
+                        "id"
+                    ]
+                },
                 "notes": {
                     "type": "string"
                 },
+                "payment_terms": {
+                    "type": "string",
+                    "enum": [
+                        "DUE_ON_RECEIPT",
+                        "NET_7",
+                        "NET_14",
+                        "NET_30",
+                        "END_OF_MONTH",
+                        "CUSTOM"
+                    ]
+                },
+                "payment_terms_days": {
+                    "type": "integer",
+                    "minimum": 1
+                },
                 "tax_rate": {
                     "type": "number"
                 }
             }
         },
+        "handlers.lateFeePolicyRequest": {
+            "type": "object",
+            "required": [
+                "mode",
+                "type"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "cap": {
+                    "description": "maximum total of all fees, 0 for no cap",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "grace_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "LINE_ITEM",
+                        "FEE_INVOICE"
+                    ]
+                },
+                "monthly_interest_rate": {
+                    "description": "percent of the invoice per overdue month",
+                    "type": "number",
+                    "minimum": 0
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "FIXED",
+                        "PERCENTAGE"
+                    ]
+                }
+            }
+        },
+        "handlers.magicLinkRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.paymentLinkReq": {
+            "type": "object",
+            "properties": {
+                "provider": {
+                    "description": "defaults to PAYMENT_PROVIDER",
+                    "type": "string",
+                    "enum": [
+                        "xendit",
+                        "midtrans",
+                        "fake"
+                    ]
+                }
+            }
+        },
+        "handlers.portalLoginRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "handlers.refreshTokenRequest": {
             "type": "object",
             "required": [
@@ -1118,6 +6184,36 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.reminderRulesRequest": {
+            "type": "object",
+            "properties": {
+                "offsets": {
+                    "description": "days relative to the due date, negative for before it",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handlers.sendInvoiceReq": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "maxLength": 10000
+                },
+                "subject": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "to": {
+                    "description": "defaults to the client email",
+                    "type": "string"
+                }
+            }
+        },
         "handlers.senderRecipientRequest": {
             "type": "object",
             "required": [
@@ -1169,6 +6265,21 @@ const docTemplate = `{
                 },
                 "phone": {
                     "type": "string"
+                },
+                "qris_payload": {
+                    "description": "optional static QRIS payload to print a QRIS code for the total",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.shareLinkReq": {
+            "type": "object",
+            "properties": {
+                "expires_in_days": {
+                    "description": "0 for a link that never expires",
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 0
                 }
             }
         },
@@ -1236,13 +6347,31 @@ const docTemplate = `{
                 "status": {
                     "type": "string",
                     "enum": [
+                        "QUOTE",
                         "DRAFT",
                         "SENT",
+                        "OVERDUE",
                         "PAID"
                     ]
                 }
             }
         },
+        "handlers.taxSerialRangeRequest": {
+            "type": "object",
+            "required": [
+                "first",
+                "last"
+            ],
+            "properties": {
+                "first": {
+                    "description": "e.g. 010-26.00000001, punctuation optional",
+                    "type": "string"
+                },
+                "last": {
+                    "type": "string"
+                }
+            }
+        },
         "handlers.updateBankingRequest": {
             "type": "object",
             "required": [
@@ -1277,6 +6406,29 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.updatePaymentTermsRequest": {
+            "type": "object",
+            "required": [
+                "payment_terms"
+            ],
+            "properties": {
+                "payment_terms": {
+                    "type": "string",
+                    "enum": [
+                        "DUE_ON_RECEIPT",
+                        "NET_7",
+                        "NET_14",
+                        "NET_30",
+                        "END_OF_MONTH",
+                        "CUSTOM"
+                    ]
+                },
+                "payment_terms_days": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
         "handlers.updateProfileRequest": {
             "type": "object",
             "required": [
@@ -1300,6 +6452,28 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.updateQRISRequest": {
+            "type": "object",
+            "properties": {
+                "payload": {
+                    "description": "decoded contents of the static QRIS sticker; empty removes it",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.updateTaxIDRequest": {
+            "type": "object",
+            "properties": {
+                "nitku": {
+                    "description": "22 digits, empty for the head office",
+                    "type": "string"
+                },
+                "npwp": {
+                    "description": "15 or 16 digits, punctuation allowed; empty removes the tax identity",
+                    "type": "string"
+                }
+            }
+        },
         "response.GenericResponse": {
             "type": "object",
             "properties": {
@@ -1318,6 +6492,12 @@ const docTemplate = `{
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
+        },
+        "PortalAuth": {
+            "description": "Client portal access token, as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,266 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+// SMTPMailer delivers mail through an SMTP server. STARTTLS is used when the
+// server offers it, and authentication only when a username is configured,
+// so a local SMTP sink such as MailHog works with just a host and port.
+type SMTPMailer struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPMailer(host string, port int, username, password, from string) ports.Mailer {
+	return &SMTPMailer{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg entity.Mail) error {
+	if len(msg.To) == 0 {
+		return errors.New("mail has no recipients")
+	}
+
+	if msg.From == "" {
+		msg.From = m.from
+	}
+
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender: %w", err)
+	}
+
+	to := make([]string, 0, len(msg.To))
+	for _, addr := range msg.To {
+		parsed, err := mail.ParseAddress(addr)
+		if err != nil {
+			return fmt.Errorf("invalid recipient %q: %w", addr, err)
+		}
+
+		to = append(to, parsed.Address)
+	}
+
+	body, err := buildMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(m.host, strconv.Itoa(m.port)))
+	if err != nil {
+		return err
+	}
+
+	// Closing the connection aborts the session when ctx ends first, so a
+	// canceled send is never delivered after all.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := m.deliver(conn, auth, from.Address, to, body); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// deliver runs the session smtp.SendMail would over conn.
+func (m *SMTPMailer) deliver(conn net.Conn, auth smtp.Auth, from string, to []string, body []byte) error {
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+
+	if auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support authentication")
+		}
+
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// buildMessage renders msg as a multipart/mixed MIME message with the body
+// as its first part and one base64 part per attachment.
+func buildMessage(msg entity.Mail) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	headers := []string{
+		"From: " + msg.From,
+		"To: " + strings.Join(msg.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		fmt.Sprintf("Content-Type: multipart/mixed; boundary=%q", mw.Boundary()),
+	}
+	if msg.ReplyTo != "" {
+		headers = append(headers, "Reply-To: "+msg.ReplyTo)
+	}
+
+	var head bytes.Buffer
+	for _, h := range headers {
+		head.WriteString(h + "\r\n")
+	}
+	head.WriteString("\r\n")
+
+	if err := writeBody(mw, msg); err != nil {
+		return nil, err
+	}
+
+	for _, a := range msg.Attachments {
+		contentType := a.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {contentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := writeBase64(part, a.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	return append(head.Bytes(), buf.Bytes()...), nil
+}
+
+// writeBody writes the plain text body, nested in a multipart/alternative
+// part with the HTML body when msg has one.
+func writeBody(mw *multipart.Writer, msg entity.Mail) error {
+	if msg.HTMLBody == "" {
+		return writeTextPart(mw, "text/plain; charset=utf-8", msg.Body)
+	}
+
+	var buf bytes.Buffer
+	alt := multipart.NewWriter(&buf)
+	if err := writeTextPart(alt, "text/plain; charset=utf-8", msg.Body); err != nil {
+		return err
+	}
+
+	if err := writeTextPart(alt, "text/html; charset=utf-8", msg.HTMLBody); err != nil {
+		return err
+	}
+
+	if err := alt.Close(); err != nil {
+		return err
+	}
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {fmt.Sprintf("multipart/alternative; boundary=%q", alt.Boundary())},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = part.Write(buf.Bytes())
+	return err
+}
+
+func writeTextPart(mw *multipart.Writer, contentType, text string) error {
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return err
+	}
+
+	return writeBase64(part, []byte(text))
+}
+
+// writeBase64 writes data base64 encoded in lines of 76 characters as
+// required by RFC 2045.
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		if _, err := w.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+			return err
+		}
+
+		encoded = encoded[76:]
+	}
+
+	_, err := w.Write([]byte(encoded + "\r\n"))
+	return err
+}
@@ -0,0 +1,296 @@
+package mailer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+// envelope is a message an smtpSink received.
+type envelope struct {
+	from string
+	to   []string
+	data []byte
+}
+
+// smtpSink is an SMTP server that accepts every message, without STARTTLS
+// or authentication. With stall set it never greets clients and instead
+// reports when they hang up.
+type smtpSink struct {
+	ln       net.Listener
+	stall    bool
+	messages chan envelope
+	hangups  chan struct{}
+}
+
+func newSMTPSink(t *testing.T, stall bool) *smtpSink {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &smtpSink{ln: ln, stall: stall, messages: make(chan envelope, 1), hangups: make(chan struct{}, 1)}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *smtpSink) mailer() *SMTPMailer {
+	addr := s.ln.Addr().(*net.TCPAddr)
+	return NewSMTPMailer(addr.IP.String(), addr.Port, "", "", "Studio Hutamy <billing@hutamy.id>").(*SMTPMailer)
+}
+
+func (s *smtpSink) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	if s.stall {
+		io.Copy(io.Discard, r)
+		s.hangups <- struct{}{}
+		return
+	}
+
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 sink ESMTP")
+	var env envelope
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		cmd := strings.TrimRight(line, "\r\n")
+		switch verb := strings.ToUpper(strings.SplitN(cmd, " ", 2)[0]); {
+		case verb == "EHLO" || verb == "HELO":
+			reply("250-sink")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(strings.ToUpper(cmd), "MAIL FROM:"):
+			env.from = pathOf(cmd)
+			reply("250 OK")
+		case strings.HasPrefix(strings.ToUpper(cmd), "RCPT TO:"):
+			env.to = append(env.to, pathOf(cmd))
+			reply("250 OK")
+		case verb == "DATA":
+			reply("354 end with <CRLF>.<CRLF>")
+			var data bytes.Buffer
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+
+				if line == ".\r\n" {
+					break
+				}
+
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+
+			env.data = data.Bytes()
+			s.messages <- env
+			reply("250 queued")
+		case verb == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+// pathOf returns the address of a MAIL or RCPT command, without the
+// parameters after it.
+func pathOf(cmd string) string {
+	_, rest, _ := strings.Cut(cmd, "<")
+	addr, _, _ := strings.Cut(rest, ">")
+	return addr
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	sink := newSMTPSink(t, false)
+	pdf := make([]byte, 300)
+	for i := range pdf {
+		pdf[i] = byte(i)
+	}
+
+	err := sink.mailer().Send(context.Background(), entity.Mail{
+		To:       []string{"PT Pembeli <ap@pembeli.co.id>", "finance@pembeli.co.id"},
+		ReplyTo:  "owner@hutamy.id",
+		Subject:  "Invoice INV-0042 — Rp 1.443.000",
+		Body:     "Dear PT Pembeli,\n\nPlease find the invoice attached.",
+		HTMLBody: "<p>Dear PT Pembeli,</p>",
+		Attachments: []entity.MailAttachment{
+			{Filename: "Invoice INV-0042.pdf", ContentType: "application/pdf", Data: pdf},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	env := <-sink.messages
+	if env.from != "billing@hutamy.id" || strings.Join(env.to, ",") != "ap@pembeli.co.id,finance@pembeli.co.id" {
+		t.Errorf("envelope from %s to %v", env.from, env.to)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(env.data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "Invoice INV-0042 — Rp 1.443.000" {
+		t.Errorf("subject %q, %v", subject, err)
+	}
+
+	for name, want := range map[string]string{
+		"From":         "Studio Hutamy <billing@hutamy.id>",
+		"To":           "PT Pembeli <ap@pembeli.co.id>, finance@pembeli.co.id",
+		"Reply-To":     "owner@hutamy.id",
+		"MIME-Version": "1.0",
+	} {
+		if got := msg.Header.Get(name); got != want {
+			t.Errorf("%s: %q, want %q", name, got, want)
+		}
+	}
+
+	if _, err := msg.Header.Date(); err != nil {
+		t.Errorf("Date: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type %q, %v", msg.Header.Get("Content-Type"), err)
+	}
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	body, err := parts.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mediaType, params, _ = mime.ParseMediaType(body.Header.Get("Content-Type"))
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("body is %s, want multipart/alternative", mediaType)
+	}
+
+	alt := multipart.NewReader(body, params["boundary"])
+	for _, want := range []struct{ contentType, text string }{
+		{"text/plain; charset=utf-8", "Dear PT Pembeli,\n\nPlease find the invoice attached."},
+		{"text/html; charset=utf-8", "<p>Dear PT Pembeli,</p>"},
+	} {
+		part, err := alt.NextPart()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := part.Header.Get("Content-Type"); got != want.contentType {
+			t.Errorf("part is %s, want %s", got, want.contentType)
+		}
+
+		if text := decodeBase64Part(t, part); string(text) != want.text {
+			t.Errorf("%s part %q, want %q", want.contentType, text, want.text)
+		}
+	}
+
+	attachment, err := parts.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if attachment.FileName() != "Invoice INV-0042.pdf" || attachment.Header.Get("Content-Type") != "application/pdf" {
+		t.Errorf("attachment %q of type %s", attachment.FileName(), attachment.Header.Get("Content-Type"))
+	}
+
+	if data := decodeBase64Part(t, attachment); !bytes.Equal(data, pdf) {
+		t.Error("attachment differs from the data sent")
+	}
+
+	if _, err := parts.NextPart(); err != io.EOF {
+		t.Errorf("unexpected part after the attachment: %v", err)
+	}
+}
+
+// decodeBase64Part checks the part is base64 in lines of at most 76
+// characters, as RFC 2045 requires, and returns its content.
+func decodeBase64Part(t *testing.T, part *multipart.Part) []byte {
+	t.Helper()
+	if enc := part.Header.Get("Content-Transfer-Encoding"); enc != "base64" {
+		t.Errorf("transfer encoding %q", enc)
+	}
+
+	raw, err := io.ReadAll(part)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(string(raw), "\r\n"), "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("base64 line of %d characters", len(line))
+		}
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(raw), "\r\n", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func TestSMTPMailerSendCanceled(t *testing.T) {
+	sink := newSMTPSink(t, true)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := sink.mailer().Send(ctx, entity.Mail{To: []string{"ap@pembeli.co.id"}, Subject: "Invoice"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Send: %v, want the context's error", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Send returned after %s", elapsed)
+	}
+
+	// The session is aborted rather than left to deliver the mail later.
+	select {
+	case <-sink.hangups:
+	case <-time.After(2 * time.Second):
+		t.Error("connection was left open")
+	}
+}
+
+func TestSMTPMailerRejectsBadAddresses(t *testing.T) {
+	m := NewSMTPMailer("127.0.0.1", 1, "", "", "billing@hutamy.id")
+	for _, msg := range []entity.Mail{
+		{},
+		{To: []string{"not an address"}},
+		{To: []string{"ap@pembeli.co.id"}, From: "bad\r\nBcc: x@y.z"},
+	} {
+		if err := m.Send(context.Background(), msg); err == nil {
+			t.Errorf("Send(%+v) succeeded", msg)
+		}
+	}
+}
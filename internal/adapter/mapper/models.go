@@ -1,8 +1,12 @@
 package mapper
 
 import (
+	"strings"
+	"time"
+
 	pmodel "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
 	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"gorm.io/gorm"
 )
 
 func UserToModel(u *entity.User) *pmodel.User {
@@ -20,6 +24,12 @@ func UserToModel(u *entity.User) *pmodel.User {
 		BankName:          u.BankName,
 		BankAccountName:   u.BankAccountName,
 		BankAccountNumber: u.BankAccountNumber,
+		QRISPayload:       u.QRISPayload,
+		NPWP:              u.NPWP,
+		NITKU:             u.NITKU,
+
+		PaymentTerms:     string(u.PaymentTerms),
+		PaymentTermsDays: u.PaymentTermsDays,
 	}
 }
 
@@ -38,7 +48,13 @@ func UserFromModel(m *pmodel.User) *entity.User {
 		BankName:          m.BankName,
 		BankAccountName:   m.BankAccountName,
 		BankAccountNumber: m.BankAccountNumber,
-		IsDeleted:         m.DeletedAt.Valid,
+		QRISPayload:       m.QRISPayload,
+		NPWP:              m.NPWP,
+		NITKU:             m.NITKU,
+
+		PaymentTerms:     entity.PaymentTerms(m.PaymentTerms),
+		PaymentTermsDays: m.PaymentTermsDays,
+		IsDeleted:        m.DeletedAt.Valid,
 	}
 }
 
@@ -48,12 +64,17 @@ func ClientToModel(c *entity.Client) *pmodel.Client {
 	}
 
 	return &pmodel.Client{
-		ID:      c.ID,
-		UserID:  c.UserID,
-		Name:    c.Name,
-		Email:   c.Email,
-		Address: c.Address,
-		Phone:   c.Phone,
+		ID:               c.ID,
+		UserID:           c.UserID,
+		Name:             c.Name,
+		Email:            c.Email,
+		Address:          c.Address,
+		Phone:            c.Phone,
+		PaymentTerms:     string(c.PaymentTerms),
+		PaymentTermsDays: c.PaymentTermsDays,
+		Language:         c.Language,
+		NPWP:             c.NPWP,
+		NITKU:            c.NITKU,
 	}
 }
 
@@ -63,12 +84,18 @@ func ClientFromModel(m *pmodel.Client) *entity.Client {
 	}
 
 	return &entity.Client{
-		ID:      m.ID,
-		UserID:  m.UserID,
-		Name:    m.Name,
-		Email:   m.Email,
-		Address: m.Address,
-		Phone:   m.Phone,
+		ID:               m.ID,
+		UserID:           m.UserID,
+		Name:             m.Name,
+		Email:            m.Email,
+		Address:          m.Address,
+		Phone:            m.Phone,
+		PaymentTerms:     entity.PaymentTerms(m.PaymentTerms),
+		PaymentTermsDays: m.PaymentTermsDays,
+		Language:         m.Language,
+		NPWP:             m.NPWP,
+		NITKU:            m.NITKU,
+		DeletedAt:        deletedAtFromModel(m.DeletedAt),
 	}
 }
 
@@ -106,20 +133,31 @@ func InvoiceToModel(inv *entity.Invoice) *pmodel.Invoice {
 	}
 
 	m := &pmodel.Invoice{
-		ID:            inv.ID,
-		UserID:        inv.UserID,
-		ClientID:      inv.ClientID,
-		ClientName:    inv.ClientName,
-		ClientEmail:   inv.ClientEmail,
-		ClientAddress: inv.ClientAddress,
-		ClientPhone:   inv.ClientPhone,
-		InvoiceNumber: inv.InvoiceNumber,
-		IssueDate:     inv.IssueDate,
-		DueDate:       inv.DueDate,
-		Status:        string(inv.Status),
-		Notes:         inv.Notes,
-		TaxRate:       inv.TaxRate,
-		DeliveryFee:   inv.DeliveryFee,
+		ID:                inv.ID,
+		UserID:            inv.UserID,
+		ClientID:          inv.ClientID,
+		ClientName:        inv.ClientName,
+		ClientEmail:       inv.ClientEmail,
+		ClientAddress:     inv.ClientAddress,
+		ClientPhone:       inv.ClientPhone,
+		InvoiceNumber:     inv.InvoiceNumber,
+		IssueDate:         inv.IssueDate,
+		DueDate:           inv.DueDate,
+		PaymentTerms:      string(inv.PaymentTerms),
+		PaymentTermsDays:  inv.PaymentTermsDays,
+		Status:            string(inv.Status),
+		Notes:             inv.Notes,
+		TaxRate:           inv.TaxRate,
+		DeliveryFee:       inv.DeliveryFee,
+		FeeForInvoiceID:   inv.FeeForInvoiceID,
+		RemindersDisabled: inv.RemindersDisabled,
+		FirstViewedAt:     inv.FirstViewedAt,
+		ViewCount:         inv.ViewCount,
+		QuoteAcceptedAt:   inv.QuoteAcceptedAt,
+		Language:          inv.Language,
+		AmountInWords:     inv.AmountInWords,
+		TaxSerial:         uint64(inv.TaxSerial),
+		AmountPaid:        inv.AmountPaid,
 	}
 
 	m.Items = make([]pmodel.InvoiceItem, 0, len(inv.Items))
@@ -141,30 +179,43 @@ func InvoiceFromModel(m *pmodel.Invoice) *entity.Invoice {
 	}
 
 	inv := &entity.Invoice{
-		ID:            m.ID,
-		UserID:        m.UserID,
-		ClientID:      m.ClientID,
-		ClientName:    m.ClientName,
-		ClientEmail:   m.ClientEmail,
-		ClientAddress: m.ClientAddress,
-		ClientPhone:   m.ClientPhone,
-		InvoiceNumber: m.InvoiceNumber,
-		IssueDate:     m.IssueDate,
-		DueDate:       m.DueDate,
-		Status:        string(m.Status),
-		Notes:         m.Notes,
-		Subtotal:      m.Subtotal,
-		Tax:           m.Tax,
-		TaxRate:       m.TaxRate,
-		DeliveryFee:   m.DeliveryFee,
-		Total:         m.Total,
-	}
-
-	if m.ClientID != nil {
+		ID:                m.ID,
+		UserID:            m.UserID,
+		ClientID:          m.ClientID,
+		ClientName:        m.ClientName,
+		ClientEmail:       m.ClientEmail,
+		ClientAddress:     m.ClientAddress,
+		ClientPhone:       m.ClientPhone,
+		InvoiceNumber:     m.InvoiceNumber,
+		IssueDate:         m.IssueDate,
+		DueDate:           m.DueDate,
+		PaymentTerms:      entity.PaymentTerms(m.PaymentTerms),
+		PaymentTermsDays:  m.PaymentTermsDays,
+		Status:            string(m.Status),
+		Notes:             m.Notes,
+		Subtotal:          m.Subtotal,
+		Tax:               m.Tax,
+		TaxRate:           m.TaxRate,
+		DeliveryFee:       m.DeliveryFee,
+		Total:             m.Total,
+		FeeForInvoiceID:   m.FeeForInvoiceID,
+		RemindersDisabled: m.RemindersDisabled,
+		FirstViewedAt:     m.FirstViewedAt,
+		ViewCount:         m.ViewCount,
+		QuoteAcceptedAt:   m.QuoteAcceptedAt,
+		Language:          m.Language,
+		AmountInWords:     m.AmountInWords,
+		TaxSerial:         entity.TaxSerial(m.TaxSerial),
+		AmountPaid:        m.AmountPaid,
+		DeletedAt:         deletedAtFromModel(m.DeletedAt),
+	}
+
+	if m.ClientID != nil && m.Client != nil {
 		inv.ClientName = &m.Client.Name
 		inv.ClientEmail = &m.Client.Email
 		inv.ClientAddress = &m.Client.Address
 		inv.ClientPhone = &m.Client.Phone
+		inv.Client = *ClientFromModel(m.Client)
 	}
 
 	inv.Items = make([]entity.InvoiceItem, 0, len(m.Items))
@@ -174,3 +225,536 @@ func InvoiceFromModel(m *pmodel.Invoice) *entity.Invoice {
 
 	return inv
 }
+
+func LateFeePolicyToModel(p *entity.LateFeePolicy) *pmodel.LateFeePolicy {
+	if p == nil {
+		return nil
+	}
+
+	return &pmodel.LateFeePolicy{
+		ID:                  p.ID,
+		UserID:              p.UserID,
+		ClientID:            p.ClientID,
+		Enabled:             p.Enabled,
+		Type:                string(p.Type),
+		Amount:              p.Amount,
+		GraceDays:           p.GraceDays,
+		MonthlyInterestRate: p.MonthlyInterestRate,
+		Cap:                 p.Cap,
+		Mode:                string(p.Mode),
+	}
+}
+
+func LateFeePolicyFromModel(m *pmodel.LateFeePolicy) *entity.LateFeePolicy {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.LateFeePolicy{
+		ID:                  m.ID,
+		UserID:              m.UserID,
+		ClientID:            m.ClientID,
+		Enabled:             m.Enabled,
+		Type:                entity.LateFeeType(m.Type),
+		Amount:              m.Amount,
+		GraceDays:           m.GraceDays,
+		MonthlyInterestRate: m.MonthlyInterestRate,
+		Cap:                 m.Cap,
+		Mode:                entity.LateFeeMode(m.Mode),
+	}
+}
+
+func LateFeeToModel(f *entity.LateFee) *pmodel.LateFee {
+	if f == nil {
+		return nil
+	}
+
+	return &pmodel.LateFee{
+		ID:            f.ID,
+		UserID:        f.UserID,
+		InvoiceID:     f.InvoiceID,
+		PolicyID:      f.PolicyID,
+		Kind:          string(f.Kind),
+		Period:        f.Period,
+		BaseAmount:    f.BaseAmount,
+		Amount:        f.Amount,
+		InvoiceItemID: f.InvoiceItemID,
+		FeeInvoiceID:  f.FeeInvoiceID,
+		AppliedAt:     f.AppliedAt,
+	}
+}
+
+func LateFeeFromModel(m *pmodel.LateFee) *entity.LateFee {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.LateFee{
+		ID:            m.ID,
+		UserID:        m.UserID,
+		InvoiceID:     m.InvoiceID,
+		PolicyID:      m.PolicyID,
+		Kind:          entity.LateFeeKind(m.Kind),
+		Period:        m.Period,
+		BaseAmount:    m.BaseAmount,
+		Amount:        m.Amount,
+		InvoiceItemID: m.InvoiceItemID,
+		FeeInvoiceID:  m.FeeInvoiceID,
+		AppliedAt:     m.AppliedAt,
+	}
+}
+
+func ReminderRuleFromModel(m *pmodel.ReminderRule) *entity.ReminderRule {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.ReminderRule{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		OffsetDays: m.OffsetDays,
+	}
+}
+
+func InvoiceReminderToModel(r *entity.InvoiceReminder) *pmodel.InvoiceReminder {
+	if r == nil {
+		return nil
+	}
+
+	return &pmodel.InvoiceReminder{
+		ID:         r.ID,
+		UserID:     r.UserID,
+		InvoiceID:  r.InvoiceID,
+		OffsetDays: r.OffsetDays,
+		Recipient:  r.Recipient,
+		SentAt:     r.SentAt,
+		Error:      r.Error,
+	}
+}
+
+func InvoiceReminderFromModel(m *pmodel.InvoiceReminder) *entity.InvoiceReminder {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.InvoiceReminder{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		InvoiceID:  m.InvoiceID,
+		OffsetDays: m.OffsetDays,
+		Recipient:  m.Recipient,
+		SentAt:     m.SentAt,
+		Error:      m.Error,
+	}
+}
+
+func InvoiceDeliveryToModel(d *entity.InvoiceDelivery) *pmodel.InvoiceDelivery {
+	if d == nil {
+		return nil
+	}
+
+	return &pmodel.InvoiceDelivery{
+		ID:        d.ID,
+		UserID:    d.UserID,
+		InvoiceID: d.InvoiceID,
+		Recipient: d.Recipient,
+		Subject:   d.Subject,
+		SentAt:    d.SentAt,
+		Error:     d.Error,
+	}
+}
+
+func InvoiceDeliveryFromModel(m *pmodel.InvoiceDelivery) *entity.InvoiceDelivery {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.InvoiceDelivery{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		InvoiceID: m.InvoiceID,
+		Recipient: m.Recipient,
+		Subject:   m.Subject,
+		SentAt:    m.SentAt,
+		Error:     m.Error,
+	}
+}
+
+func EmailTemplateToModel(t *entity.EmailTemplate) *pmodel.EmailTemplate {
+	if t == nil {
+		return nil
+	}
+
+	return &pmodel.EmailTemplate{
+		ID:       t.ID,
+		UserID:   t.UserID,
+		Kind:     string(t.Kind),
+		Subject:  t.Subject,
+		Body:     t.Body,
+		HTMLBody: t.HTMLBody,
+	}
+}
+
+func EmailTemplateFromModel(m *pmodel.EmailTemplate) *entity.EmailTemplate {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.EmailTemplate{
+		ID:       m.ID,
+		UserID:   m.UserID,
+		Kind:     entity.EmailTemplateKind(m.Kind),
+		Subject:  m.Subject,
+		Body:     m.Body,
+		HTMLBody: m.HTMLBody,
+		Custom:   true,
+	}
+}
+
+func InvoiceBrandingToModel(b *entity.InvoiceBranding) *pmodel.InvoiceBranding {
+	if b == nil {
+		return nil
+	}
+
+	return &pmodel.InvoiceBranding{
+		UserID:      b.UserID,
+		Layout:      string(b.Layout),
+		Template:    b.Template,
+		AccentColor: b.AccentColor,
+		Font:        b.Font,
+		FooterText:  b.FooterText,
+		Logo:        b.Logo,
+	}
+}
+
+func InvoiceBrandingFromModel(m *pmodel.InvoiceBranding) *entity.InvoiceBranding {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.InvoiceBranding{
+		UserID:      m.UserID,
+		Layout:      entity.InvoiceLayout(m.Layout),
+		Template:    m.Template,
+		AccentColor: m.AccentColor,
+		Font:        m.Font,
+		FooterText:  m.FooterText,
+		Logo:        m.Logo,
+		Custom:      true,
+	}
+}
+
+func InvoiceShareLinkToModel(l *entity.InvoiceShareLink) *pmodel.InvoiceShareLink {
+	if l == nil {
+		return nil
+	}
+
+	return &pmodel.InvoiceShareLink{
+		ID:        l.ID,
+		UserID:    l.UserID,
+		InvoiceID: l.InvoiceID,
+		Nonce:     l.Nonce,
+		ExpiresAt: l.ExpiresAt,
+		RevokedAt: l.RevokedAt,
+		CreatedAt: l.CreatedAt,
+	}
+}
+
+func InvoiceShareLinkFromModel(m *pmodel.InvoiceShareLink) *entity.InvoiceShareLink {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.InvoiceShareLink{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		InvoiceID: m.InvoiceID,
+		Nonce:     m.Nonce,
+		ExpiresAt: m.ExpiresAt,
+		RevokedAt: m.RevokedAt,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func PortalMagicLinkToModel(l *entity.PortalMagicLink) *pmodel.PortalMagicLink {
+	if l == nil {
+		return nil
+	}
+
+	return &pmodel.PortalMagicLink{
+		ID:        l.ID,
+		UserID:    l.UserID,
+		ClientID:  l.ClientID,
+		TokenHash: l.TokenHash,
+		ExpiresAt: l.ExpiresAt,
+		UsedAt:    l.UsedAt,
+	}
+}
+
+func PortalMagicLinkFromModel(m *pmodel.PortalMagicLink) *entity.PortalMagicLink {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.PortalMagicLink{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		ClientID:  m.ClientID,
+		TokenHash: m.TokenHash,
+		ExpiresAt: m.ExpiresAt,
+		UsedAt:    m.UsedAt,
+	}
+}
+
+func PaymentToModel(p *entity.Payment) *pmodel.Payment {
+	if p == nil {
+		return nil
+	}
+
+	return &pmodel.Payment{
+		ID:        p.ID,
+		UserID:    p.UserID,
+		InvoiceID: p.InvoiceID,
+		Amount:    p.Amount,
+		Method:    string(p.Method),
+		Provider:  p.Provider,
+		Reference: p.Reference,
+		PaidAt:    p.PaidAt,
+	}
+}
+
+func PaymentFromModel(m *pmodel.Payment) *entity.Payment {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.Payment{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		InvoiceID: m.InvoiceID,
+		Amount:    m.Amount,
+		Method:    entity.PaymentMethod(m.Method),
+		Provider:  m.Provider,
+		Reference: m.Reference,
+		PaidAt:    m.PaidAt,
+	}
+}
+
+func PaymentLinkToModel(l *entity.PaymentLink) *pmodel.PaymentLink {
+	if l == nil {
+		return nil
+	}
+
+	return &pmodel.PaymentLink{
+		ID:         l.ID,
+		UserID:     l.UserID,
+		InvoiceID:  l.InvoiceID,
+		Provider:   l.Provider,
+		Reference:  l.Reference,
+		ExternalID: l.ExternalID,
+		URL:        l.URL,
+		Amount:     l.Amount,
+		Status:     string(l.Status),
+		ExpiresAt:  l.ExpiresAt,
+		CreatedAt:  l.CreatedAt,
+	}
+}
+
+func PaymentLinkFromModel(m *pmodel.PaymentLink) *entity.PaymentLink {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.PaymentLink{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		InvoiceID:  m.InvoiceID,
+		Provider:   m.Provider,
+		Reference:  m.Reference,
+		ExternalID: m.ExternalID,
+		URL:        m.URL,
+		Amount:     m.Amount,
+		Status:     entity.PaymentLinkStatus(m.Status),
+		ExpiresAt:  m.ExpiresAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+func PaymentEventToModel(e *entity.PaymentEvent) *pmodel.PaymentEvent {
+	if e == nil {
+		return nil
+	}
+
+	return &pmodel.PaymentEvent{
+		Provider:  e.Provider,
+		EventID:   e.EventID,
+		Reference: e.Reference,
+		Status:    string(e.Status),
+		Payload:   e.Payload,
+	}
+}
+
+func BankTransactionToModel(t *entity.BankTransaction) *pmodel.BankTransaction {
+	if t == nil {
+		return nil
+	}
+
+	return &pmodel.BankTransaction{
+		ID:            t.ID,
+		UserID:        t.UserID,
+		BookedAt:      t.BookedAt,
+		Amount:        t.Amount,
+		Currency:      t.Currency,
+		Reference:     t.Reference,
+		Description:   t.Description,
+		Counterparty:  t.Counterparty,
+		BankReference: t.BankReference,
+		Status:        string(t.Status),
+		InvoiceID:     t.InvoiceID,
+		PaymentID:     t.PaymentID,
+		Fingerprint:   t.Fingerprint,
+	}
+}
+
+func BankTransactionFromModel(m *pmodel.BankTransaction) *entity.BankTransaction {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.BankTransaction{
+		ID:            m.ID,
+		UserID:        m.UserID,
+		BookedAt:      m.BookedAt,
+		Amount:        m.Amount,
+		Currency:      m.Currency,
+		Reference:     m.Reference,
+		Description:   m.Description,
+		Counterparty:  m.Counterparty,
+		BankReference: m.BankReference,
+		Status:        entity.BankTransactionStatus(m.Status),
+		InvoiceID:     m.InvoiceID,
+		PaymentID:     m.PaymentID,
+		Fingerprint:   m.Fingerprint,
+		ImportedAt:    m.CreatedAt,
+	}
+}
+
+func ReconciliationMatchToModel(r *entity.ReconciliationMatch) *pmodel.ReconciliationMatch {
+	if r == nil {
+		return nil
+	}
+
+	return &pmodel.ReconciliationMatch{
+		ID:            r.ID,
+		UserID:        r.UserID,
+		TransactionID: r.TransactionID,
+		InvoiceID:     r.InvoiceID,
+		InvoiceNumber: r.InvoiceNumber,
+		Score:         r.Score,
+		Reasons:       strings.Join(r.Reasons, "; "),
+		Status:        string(r.Status),
+		DecidedAt:     r.DecidedAt,
+	}
+}
+
+func ReconciliationMatchFromModel(m *pmodel.ReconciliationMatch) *entity.ReconciliationMatch {
+	if m == nil {
+		return nil
+	}
+
+	var reasons []string
+	if m.Reasons != "" {
+		reasons = strings.Split(m.Reasons, "; ")
+	}
+
+	return &entity.ReconciliationMatch{
+		ID:            m.ID,
+		UserID:        m.UserID,
+		TransactionID: m.TransactionID,
+		InvoiceID:     m.InvoiceID,
+		InvoiceNumber: m.InvoiceNumber,
+		Score:         m.Score,
+		Reasons:       reasons,
+		Status:        entity.MatchStatus(m.Status),
+		CreatedAt:     m.CreatedAt,
+		DecidedAt:     m.DecidedAt,
+	}
+}
+
+func PDFJobToModel(j *entity.PDFJob) *pmodel.PDFJob {
+	if j == nil {
+		return nil
+	}
+
+	return &pmodel.PDFJob{
+		ID:         j.ID,
+		UserID:     j.UserID,
+		InvoiceID:  j.InvoiceID,
+		Status:     string(j.Status),
+		BlobKey:    j.BlobKey,
+		Cached:     j.Cached,
+		Attempts:   j.Attempts,
+		Error:      j.Error,
+		CreatedAt:  j.CreatedAt,
+		StartedAt:  j.StartedAt,
+		FinishedAt: j.FinishedAt,
+	}
+}
+
+func PDFJobFromModel(m *pmodel.PDFJob) *entity.PDFJob {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.PDFJob{
+		ID:         m.ID,
+		UserID:     m.UserID,
+		InvoiceID:  m.InvoiceID,
+		Status:     entity.PDFJobStatus(m.Status),
+		BlobKey:    m.BlobKey,
+		Cached:     m.Cached,
+		Attempts:   m.Attempts,
+		Error:      m.Error,
+		CreatedAt:  m.CreatedAt,
+		StartedAt:  m.StartedAt,
+		FinishedAt: m.FinishedAt,
+	}
+}
+
+func deletedAtFromModel(d gorm.DeletedAt) *time.Time {
+	if !d.Valid {
+		return nil
+	}
+
+	t := d.Time
+	return &t
+}
+
+func TaxSerialRangeToModel(r *entity.TaxSerialRange) *pmodel.TaxSerialRange {
+	if r == nil {
+		return nil
+	}
+
+	return &pmodel.TaxSerialRange{
+		ID:     r.ID,
+		UserID: r.UserID,
+		First:  uint64(r.First),
+		Last:   uint64(r.Last),
+		Next:   uint64(r.Next),
+	}
+}
+
+func TaxSerialRangeFromModel(m *pmodel.TaxSerialRange) *entity.TaxSerialRange {
+	if m == nil {
+		return nil
+	}
+
+	return &entity.TaxSerialRange{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		First:     entity.TaxSerial(m.First),
+		Last:      entity.TaxSerial(m.Last),
+		Next:      entity.TaxSerial(m.Next),
+		CreatedAt: m.CreatedAt,
+	}
+}
@@ -0,0 +1,28 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+// EmailNotifier delivers notifications as plain text email.
+type EmailNotifier struct {
+	mailer ports.Mailer
+}
+
+func NewEmailNotifier(mailer ports.Mailer) ports.Notifier {
+	return &EmailNotifier{
+		mailer: mailer,
+	}
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, notification entity.Notification) error {
+	return n.mailer.Send(ctx, entity.Mail{
+		To:       []string{notification.To},
+		Subject:  notification.Subject,
+		Body:     notification.Body,
+		HTMLBody: notification.HTMLBody,
+	})
+}
@@ -0,0 +1,22 @@
+package notifier
+
+import (
+	"context"
+	"log"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+// LogNotifier writes notifications to the application log instead of
+// delivering them. It is used when no delivery channel is configured.
+type LogNotifier struct{}
+
+func NewLogNotifier() ports.Notifier {
+	return &LogNotifier{}
+}
+
+func (LogNotifier) Notify(_ context.Context, n entity.Notification) error {
+	log.Printf("Notification to %s: %s", n.To, n.Subject)
+	return nil
+}
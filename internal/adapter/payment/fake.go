@@ -0,0 +1,120 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+const fakeSignatureHeader = "X-Fake-Signature"
+
+// FakeGateway is an offline payment gateway for development and tests. Its
+// links point at the simulator route, and it signs the webhooks it
+// simulates with an HMAC of the body just as a real gateway would.
+type FakeGateway struct {
+	secret  string
+	baseURL string
+}
+
+// NewFakeGateway returns a fake gateway whose checkout links start with
+// baseURL, the public URL of this API.
+func NewFakeGateway(secret, baseURL string) *FakeGateway {
+	return &FakeGateway{
+		secret:  secret,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+var _ ports.PaymentSimulator = (*FakeGateway)(nil)
+
+func (g *FakeGateway) Name() string {
+	return "fake"
+}
+
+type fakeWebhook struct {
+	EventID       string    `json:"event_id"`
+	Reference     string    `json:"reference"`
+	TransactionID string    `json:"transaction_id"`
+	Status        string    `json:"status"`
+	Amount        float64   `json:"amount"`
+	PaidAt        time.Time `json:"paid_at"`
+}
+
+func (g *FakeGateway) CreatePaymentLink(_ context.Context, req entity.PaymentLinkRequest) (*entity.PaymentLink, error) {
+	expiresAt := time.Now().Add(24 * time.Hour)
+	return &entity.PaymentLink{
+		Provider:   g.Name(),
+		Reference:  req.Reference,
+		ExternalID: "fake_" + randomHex(8),
+		URL:        g.baseURL + "/v1/public/payments/fake/" + url.PathEscape(req.Reference) + "/pay",
+		Amount:     req.Amount,
+		Status:     entity.PaymentLinkStatusPending,
+		ExpiresAt:  &expiresAt,
+	}, nil
+}
+
+func (g *FakeGateway) ParseWebhook(header http.Header, body []byte) (*entity.PaymentEvent, error) {
+	sig, err := hex.DecodeString(header.Get(fakeSignatureHeader))
+	if err != nil || !hmac.Equal(sig, g.sign(body)) {
+		return nil, errors.New("invalid signature")
+	}
+
+	var w fakeWebhook
+	if err := json.Unmarshal(body, &w); err != nil {
+		return nil, err
+	}
+
+	return &entity.PaymentEvent{
+		Provider:      g.Name(),
+		EventID:       w.EventID,
+		Reference:     w.Reference,
+		TransactionID: w.TransactionID,
+		Status:        entity.PaymentEventStatus(w.Status),
+		Amount:        w.Amount,
+		PaidAt:        w.PaidAt,
+		Payload:       string(body),
+	}, nil
+}
+
+// SimulatePayment returns the signed webhook the gateway would send when
+// amount is paid on the link with reference.
+func (g *FakeGateway) SimulatePayment(reference string, amount float64) (http.Header, []byte, error) {
+	body, err := json.Marshal(fakeWebhook{
+		EventID:       "evt_" + randomHex(8),
+		Reference:     reference,
+		TransactionID: "txn_" + randomHex(8),
+		Status:        string(entity.PaymentEventPaid),
+		Amount:        amount,
+		PaidAt:        time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := http.Header{}
+	header.Set(fakeSignatureHeader, hex.EncodeToString(g.sign(body)))
+	return header, body, nil
+}
+
+func (g *FakeGateway) sign(body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(g.secret))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,46 @@
+package payment
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+func TestFakeGatewayVerifiesSignatures(t *testing.T) {
+	g := NewFakeGateway("secret", "http://localhost:8080")
+	header, body, err := g.SimulatePayment("inv-1-1", 150000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event, err := g.ParseWebhook(header, body)
+	if err != nil {
+		t.Fatalf("rejected its own webhook: %v", err)
+	}
+
+	if event.Reference != "inv-1-1" || event.Amount != 150000 || event.EventID == "" {
+		t.Errorf("got event %+v", event)
+	}
+
+	tests := []struct {
+		name   string
+		header http.Header
+		body   []byte
+	}{
+		{"no signature", http.Header{}, body},
+		{"signature not hex", http.Header{fakeSignatureHeader: {"not-hex"}}, body},
+		{"tampered body", header, bytes.Replace(body, []byte("150000"), []byte("1500000"), 1)},
+		{"signed with another secret", func() http.Header {
+			h, _, _ := NewFakeGateway("other", "").SimulatePayment("inv-1-1", 150000)
+			return h
+		}(), body},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := g.ParseWebhook(tt.header, tt.body); err == nil {
+				t.Fatal("accepted a webhook with a bad signature")
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package payment
+
+import (
+	"context"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+const (
+	midtransSandboxURL    = "https://app.sandbox.midtrans.com/snap/v1/transactions"
+	midtransProductionURL = "https://app.midtrans.com/snap/v1/transactions"
+)
+
+// jakarta is the zone Midtrans reports transaction times in.
+var jakarta = time.FixedZone("WIB", 7*60*60)
+
+// MidtransGateway creates Midtrans Snap transactions as payment links and
+// verifies HTTP notifications by their SHA-512 signature key.
+type MidtransGateway struct {
+	serverKey  string
+	production bool
+}
+
+func NewMidtransGateway(serverKey string, production bool) ports.PaymentGateway {
+	return &MidtransGateway{
+		serverKey:  serverKey,
+		production: production,
+	}
+}
+
+func (g *MidtransGateway) Name() string {
+	return "midtrans"
+}
+
+type midtransSnapRequest struct {
+	TransactionDetails struct {
+		OrderID     string `json:"order_id"`
+		GrossAmount int64  `json:"gross_amount"`
+	} `json:"transaction_details"`
+	CustomerDetails struct {
+		FirstName string `json:"first_name,omitempty"`
+		Email     string `json:"email,omitempty"`
+	} `json:"customer_details"`
+	Callbacks struct {
+		Finish string `json:"finish,omitempty"`
+	} `json:"callbacks"`
+}
+
+type midtransSnapResponse struct {
+	Token       string `json:"token"`
+	RedirectURL string `json:"redirect_url"`
+}
+
+type midtransNotification struct {
+	TransactionID     string `json:"transaction_id"`
+	OrderID           string `json:"order_id"`
+	StatusCode        string `json:"status_code"`
+	GrossAmount       string `json:"gross_amount"`
+	SignatureKey      string `json:"signature_key"`
+	TransactionStatus string `json:"transaction_status"`
+	FraudStatus       string `json:"fraud_status"`
+	SettlementTime    string `json:"settlement_time"`
+	TransactionTime   string `json:"transaction_time"`
+}
+
+func (g *MidtransGateway) CreatePaymentLink(ctx context.Context, req entity.PaymentLinkRequest) (*entity.PaymentLink, error) {
+	// Rupiah amounts have no minor unit at Midtrans.
+	amount := math.Round(req.Amount)
+
+	var body midtransSnapRequest
+	body.TransactionDetails.OrderID = req.Reference
+	body.TransactionDetails.GrossAmount = int64(amount)
+	body.CustomerDetails.FirstName = req.CustomerName
+	body.CustomerDetails.Email = req.CustomerEmail
+	body.Callbacks.Finish = req.SuccessURL
+
+	url := midtransSandboxURL
+	if g.production {
+		url = midtransProductionURL
+	}
+
+	var out midtransSnapResponse
+	if err := postJSON(ctx, url, g.serverKey, body, &out); err != nil {
+		return nil, err
+	}
+
+	return &entity.PaymentLink{
+		Provider:   g.Name(),
+		Reference:  req.Reference,
+		ExternalID: out.Token,
+		URL:        out.RedirectURL,
+		Amount:     amount,
+		Status:     entity.PaymentLinkStatusPending,
+	}, nil
+}
+
+func (g *MidtransGateway) ParseWebhook(_ http.Header, body []byte) (*entity.PaymentEvent, error) {
+	var n midtransNotification
+	if err := json.Unmarshal(body, &n); err != nil {
+		return nil, err
+	}
+
+	sum := sha512.Sum512([]byte(n.OrderID + n.StatusCode + n.GrossAmount + g.serverKey))
+	expected := hex.EncodeToString(sum[:])
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(n.SignatureKey)) != 1 {
+		return nil, errors.New("invalid signature")
+	}
+
+	amount, err := strconv.ParseFloat(n.GrossAmount, 64)
+	if err != nil {
+		return nil, errors.New("invalid gross_amount")
+	}
+
+	event := &entity.PaymentEvent{
+		Provider:      g.Name(),
+		EventID:       n.TransactionID + ":" + n.TransactionStatus,
+		Reference:     n.OrderID,
+		TransactionID: n.TransactionID,
+		Amount:        amount,
+		Payload:       string(body),
+	}
+
+	switch n.TransactionStatus {
+	case "settlement":
+		event.Status = entity.PaymentEventPaid
+	case "capture":
+		event.Status = entity.PaymentEventPending
+		if n.FraudStatus == "accept" {
+			event.Status = entity.PaymentEventPaid
+		}
+	case "deny", "cancel", "failure":
+		event.Status = entity.PaymentEventFailed
+	case "expire":
+		event.Status = entity.PaymentEventExpired
+	default:
+		event.Status = entity.PaymentEventPending
+	}
+
+	paidAt := n.SettlementTime
+	if paidAt == "" {
+		paidAt = n.TransactionTime
+	}
+
+	if t, err := time.ParseInLocation(time.DateTime, paidAt, jakarta); err == nil {
+		event.PaidAt = t
+	}
+
+	return event, nil
+}
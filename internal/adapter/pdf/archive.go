@@ -0,0 +1,113 @@
+package pdf
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-fonts/liberation/liberationsansbold"
+	"github.com/go-fonts/liberation/liberationsansregular"
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/pkg/pdf"
+)
+
+// facturXFileName is the name Factur-X readers look for the XML under.
+const facturXFileName = "factur-x.xml"
+
+// archivalFonts are embedded in PDF/A documents in place of Helvetica.
+// Liberation Sans shares its metrics, so the layout does not change.
+var archivalFonts = sync.OnceValues(func() (map[pdf.Font]*pdf.TrueTypeFont, error) {
+	regular, err := pdf.ParseTrueType(liberationsansregular.TTF)
+	if err != nil {
+		return nil, err
+	}
+
+	bold, err := pdf.ParseTrueType(liberationsansbold.TTF)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[pdf.Font]*pdf.TrueTypeFont{
+		pdf.Helvetica:     regular,
+		pdf.HelveticaBold: bold,
+	}, nil
+})
+
+// archive makes doc a PDF/A-3 Factur-X invoice embedding the XML of a.
+func archive(doc *pdf.Document, a *entity.InvoiceArchive) error {
+	fonts, err := archivalFonts()
+	if err != nil {
+		return fmt.Errorf("load archival fonts: %w", err)
+	}
+
+	// The XML of the lighter profiles only complements the PDF; from
+	// BASIC up it is a full alternative to it.
+	relationship := "Alternative"
+	if a.Profile == "MINIMUM" || a.Profile == "BASIC WL" {
+		relationship = "Data"
+	}
+
+	doc.Archival = &pdf.Archival{
+		Fonts:    fonts,
+		Metadata: fmt.Sprintf(facturXMetadata, facturXFileName, a.Profile),
+	}
+	doc.Attachments = append(doc.Attachments, pdf.Attachment{
+		Name:         facturXFileName,
+		Description:  "Factur-X invoice",
+		MimeType:     "text/xml",
+		Relationship: relationship,
+		Data:         a.XML,
+		ModTime:      time.Now(),
+	})
+
+	return nil
+}
+
+// facturXMetadata is the XMP Factur-X requires, with the PDF/A extension
+// schema declaring its properties. It takes the file name and profile.
+const facturXMetadata = `<rdf:Description rdf:about="" xmlns:fx="urn:factur-x:pdfa:CrossIndustryDocument:invoice:1p0#">
+<fx:DocumentType>INVOICE</fx:DocumentType>
+<fx:DocumentFileName>%s</fx:DocumentFileName>
+<fx:Version>1.0</fx:Version>
+<fx:ConformanceLevel>%s</fx:ConformanceLevel>
+</rdf:Description>
+<rdf:Description rdf:about="" xmlns:pdfaExtension="http://www.aiim.org/pdfa/ns/extension/" xmlns:pdfaSchema="http://www.aiim.org/pdfa/ns/schema#" xmlns:pdfaProperty="http://www.aiim.org/pdfa/ns/property#">
+<pdfaExtension:schemas>
+<rdf:Bag>
+<rdf:li rdf:parseType="Resource">
+<pdfaSchema:schema>Factur-X PDFA Extension Schema</pdfaSchema:schema>
+<pdfaSchema:namespaceURI>urn:factur-x:pdfa:CrossIndustryDocument:invoice:1p0#</pdfaSchema:namespaceURI>
+<pdfaSchema:prefix>fx</pdfaSchema:prefix>
+<pdfaSchema:property>
+<rdf:Seq>
+<rdf:li rdf:parseType="Resource">
+<pdfaProperty:name>DocumentFileName</pdfaProperty:name>
+<pdfaProperty:valueType>Text</pdfaProperty:valueType>
+<pdfaProperty:category>external</pdfaProperty:category>
+<pdfaProperty:description>The name of the embedded XML document</pdfaProperty:description>
+</rdf:li>
+<rdf:li rdf:parseType="Resource">
+<pdfaProperty:name>DocumentType</pdfaProperty:name>
+<pdfaProperty:valueType>Text</pdfaProperty:valueType>
+<pdfaProperty:category>external</pdfaProperty:category>
+<pdfaProperty:description>The type of the hybrid document in capital letters, e.g. INVOICE or ORDER</pdfaProperty:description>
+</rdf:li>
+<rdf:li rdf:parseType="Resource">
+<pdfaProperty:name>Version</pdfaProperty:name>
+<pdfaProperty:valueType>Text</pdfaProperty:valueType>
+<pdfaProperty:category>external</pdfaProperty:category>
+<pdfaProperty:description>The actual version of the standard applying to the embedded XML document</pdfaProperty:description>
+</rdf:li>
+<rdf:li rdf:parseType="Resource">
+<pdfaProperty:name>ConformanceLevel</pdfaProperty:name>
+<pdfaProperty:valueType>Text</pdfaProperty:valueType>
+<pdfaProperty:category>external</pdfaProperty:category>
+<pdfaProperty:description>The conformance level of the embedded XML document</pdfaProperty:description>
+</rdf:li>
+</rdf:Seq>
+</pdfaSchema:property>
+</rdf:li>
+</rdf:Bag>
+</pdfaExtension:schemas>
+</rdf:Description>
+`
@@ -0,0 +1,375 @@
+package pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+const (
+	// healthTimeout bounds how long a browser may take to answer a health
+	// check before it is considered hung and restarted.
+	healthTimeout = 5 * time.Second
+	// startTimeout bounds how long Chrome may take to launch.
+	startTimeout = 30 * time.Second
+)
+
+var (
+	ErrRenderTimeout = errors.New("pdf render timed out")
+	ErrRendererBusy  = errors.New("pdf renderer is busy, try again later")
+)
+
+// ChromeOptions configures the Chrome renderer's browser pool. Zero
+// timeouts mean no limit and a zero HealthInterval disables health checks.
+type ChromeOptions struct {
+	PoolSize       int
+	RenderTimeout  time.Duration
+	QueueTimeout   time.Duration
+	HealthInterval time.Duration
+}
+
+// ChromeRenderer prints the HTML invoice with headless Chrome, which must be
+// installed where the service runs. It keeps a fixed pool of browsers, each
+// started on first use, so concurrent downloads wait for a free browser
+// rather than each launching their own. Every render runs in a fresh tab
+// that is closed on timeout or when the request is canceled, and browsers
+// that stop answering are restarted. Chrome cannot write PDF/A, so archival
+// documents are drawn by the native renderer instead.
+type ChromeRenderer struct {
+	opts     ChromeOptions
+	browsers chan *browser
+	archiver ports.PDFRenderer
+
+	// launch starts a browser and returns its context, and ping checks that
+	// a running one still answers. Tests replace them to run without Chrome.
+	launch       func(ctx context.Context) (context.Context, context.CancelFunc, error)
+	ping         func(ctx context.Context) error
+	startTimeout time.Duration
+
+	running   atomic.Int64
+	waiting   atomic.Int64
+	renders   atomic.Int64
+	failures  atomic.Int64
+	timeouts  atomic.Int64
+	canceled  atomic.Int64
+	restarts  atomic.Int64
+	queueWait durations
+	duration  durations
+}
+
+// browser is one pooled Chrome process; ctx is nil while it is not running.
+type browser struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func NewChromeRenderer(opts ChromeOptions) ports.PDFRenderer {
+	return newChromeRenderer(opts, launchChrome, pingChrome)
+}
+
+func newChromeRenderer(
+	opts ChromeOptions,
+	launch func(ctx context.Context) (context.Context, context.CancelFunc, error),
+	ping func(ctx context.Context) error,
+) *ChromeRenderer {
+	if opts.PoolSize < 1 {
+		opts.PoolSize = 1
+	}
+
+	r := &ChromeRenderer{
+		opts:         opts,
+		browsers:     make(chan *browser, opts.PoolSize),
+		archiver:     NewNativeRenderer(),
+		launch:       launch,
+		ping:         ping,
+		startTimeout: startTimeout,
+	}
+	for range opts.PoolSize {
+		r.browsers <- &browser{}
+	}
+
+	if opts.HealthInterval > 0 {
+		go r.checkHealth()
+	}
+
+	return r
+}
+
+func (r *ChromeRenderer) Render(ctx context.Context, doc *entity.InvoiceDocument) ([]byte, error) {
+	if doc.Archive != nil {
+		return r.archiver.Render(ctx, doc)
+	}
+
+	b, err := r.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { r.browsers <- b }()
+
+	if b.ctx == nil {
+		if err := r.start(ctx, b); err != nil {
+			if ctx.Err() != nil {
+				r.canceled.Add(1)
+				return nil, ctx.Err()
+			}
+
+			r.failures.Add(1)
+			return nil, fmt.Errorf("start chrome: %w", err)
+		}
+	}
+
+	start := time.Now()
+	pdfBuf, err := r.print(ctx, b, doc.HTML)
+	r.duration.observe(time.Since(start))
+	r.renders.Add(1)
+	if err == nil {
+		return pdfBuf, nil
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		r.canceled.Add(1)
+		return nil, ctx.Err()
+	case errors.Is(err, ErrRenderTimeout):
+		r.timeouts.Add(1)
+	default:
+		r.failures.Add(1)
+	}
+
+	// A hung or failed render may have taken the browser down with it.
+	if !r.alive(b) {
+		r.restart(b)
+	}
+
+	return nil, err
+}
+
+func (r *ChromeRenderer) Stats() entity.PDFRendererStats {
+	return entity.PDFRendererStats{
+		Renderer:       "chrome",
+		PoolSize:       r.opts.PoolSize,
+		Running:        int(r.running.Load()),
+		Idle:           len(r.browsers),
+		Waiting:        r.waiting.Load(),
+		Renders:        r.renders.Load(),
+		Failures:       r.failures.Load(),
+		Timeouts:       r.timeouts.Load(),
+		Canceled:       r.canceled.Load(),
+		Restarts:       r.restarts.Load(),
+		QueueWait:      r.queueWait.stats(),
+		RenderDuration: r.duration.stats(),
+	}
+}
+
+// acquire waits for a free browser, giving up when ctx is done or the queue
+// timeout passes.
+func (r *ChromeRenderer) acquire(ctx context.Context) (*browser, error) {
+	if err := ctx.Err(); err != nil {
+		r.canceled.Add(1)
+		return nil, err
+	}
+
+	r.waiting.Add(1)
+	defer r.waiting.Add(-1)
+
+	var timeout <-chan time.Time
+	if r.opts.QueueTimeout > 0 {
+		timer := time.NewTimer(r.opts.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	start := time.Now()
+	select {
+	case b := <-r.browsers:
+		r.queueWait.observe(time.Since(start))
+		return b, nil
+	case <-ctx.Done():
+		r.canceled.Add(1)
+		return nil, ctx.Err()
+	case <-timeout:
+		r.timeouts.Add(1)
+		return nil, ErrRendererBusy
+	}
+}
+
+// print renders html to PDF in a new tab of b.
+func (r *ChromeRenderer) print(ctx context.Context, b *browser, html string) ([]byte, error) {
+	tabCtx, closeTab := chromedp.NewContext(b.ctx)
+	defer closeTab()
+
+	var cancel context.CancelFunc
+	if r.opts.RenderTimeout > 0 {
+		tabCtx, cancel = context.WithTimeout(tabCtx, r.opts.RenderTimeout)
+	} else {
+		tabCtx, cancel = context.WithCancel(tabCtx)
+	}
+	defer cancel()
+
+	// The tab lives under the browser's context, so tie it to the request.
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	var pdfBuf []byte
+	err := chromedp.Run(tabCtx,
+		chromedp.Navigate("about:blank"),
+		chromedp.ActionFunc(sandbox),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			tree, err := page.GetFrameTree().Do(ctx)
+			if err != nil {
+				return err
+			}
+
+			return page.SetDocumentContent(tree.Frame.ID, html).Do(ctx)
+		}),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			pdfBuf, _, err = page.PrintToPDF().WithPrintBackground(true).Do(ctx)
+			return err
+		}),
+	)
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(tabCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s", ErrRenderTimeout, r.opts.RenderTimeout)
+		}
+		return nil, err
+	}
+
+	return pdfBuf, nil
+}
+
+// blockedURLs are the schemes a document may not load anything from; the
+// invoice's images are inline data URIs.
+var blockedURLs = []string{"http://*", "https://*", "ws://*", "wss://*", "ftp://*", "file://*"}
+
+// sandbox stops the tab from running scripts or reaching the network, since
+// users can write their own invoice templates.
+func sandbox(ctx context.Context) error {
+	if err := emulation.SetScriptExecutionDisabled(true).Do(ctx); err != nil {
+		return err
+	}
+
+	if err := network.Enable().Do(ctx); err != nil {
+		return err
+	}
+
+	return network.SetBlockedURLs(blockedURLs).Do(ctx)
+}
+
+// start launches the Chrome process for b, giving up after the start
+// timeout or when ctx is done.
+func (r *ChromeRenderer) start(ctx context.Context, b *browser) error {
+	ctx, cancel := context.WithTimeout(ctx, r.startTimeout)
+	defer cancel()
+
+	browserCtx, cancelBrowser, err := r.launch(ctx)
+	if err != nil {
+		return err
+	}
+
+	b.ctx, b.cancel = browserCtx, cancelBrowser
+	r.running.Add(1)
+	return nil
+}
+
+// launchChrome starts a headless Chrome process. The process outlives ctx,
+// which only bounds the launch itself.
+func launchChrome(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), chromedp.DefaultExecAllocatorOptions[:]...)
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+	cancel := func() {
+		cancelBrowser()
+		cancelAlloc()
+	}
+
+	// The first Run on a context launches the browser. Killing it when ctx
+	// ends makes that Run return.
+	stop := context.AfterFunc(ctx, cancel)
+	err := chromedp.Run(browserCtx)
+	if !stop() {
+		return nil, nil, ctx.Err()
+	}
+
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	return browserCtx, cancel, nil
+}
+
+// pingChrome asks the browser of ctx for its targets.
+func pingChrome(ctx context.Context) error {
+	_, err := chromedp.Targets(ctx)
+	return err
+}
+
+// stop kills the Chrome process for b, if it is running.
+func (r *ChromeRenderer) stop(b *browser) {
+	if b.ctx == nil {
+		return
+	}
+
+	b.cancel()
+	b.ctx, b.cancel = nil, nil
+	r.running.Add(-1)
+}
+
+// restart replaces b's Chrome process. When the new one fails to start, b
+// is left stopped and the next render tries again.
+func (r *ChromeRenderer) restart(b *browser) {
+	r.stop(b)
+	r.restarts.Add(1)
+	if err := r.start(context.Background(), b); err != nil {
+		log.Printf("pdf: restart chrome: %v", err)
+	}
+}
+
+// alive reports whether b's browser still answers over the DevTools protocol.
+func (r *ChromeRenderer) alive(b *browser) bool {
+	if b.ctx == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(b.ctx, healthTimeout)
+	defer cancel()
+
+	return r.ping(ctx) == nil
+}
+
+// checkHealth periodically checks the idle browsers one at a time and
+// restarts the ones that crashed or hung. Browsers busy rendering are
+// checked after their render instead.
+func (r *ChromeRenderer) checkHealth() {
+	ticker := time.NewTicker(r.opts.HealthInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		for range r.opts.PoolSize {
+			var b *browser
+			select {
+			case b = <-r.browsers:
+			default:
+			}
+			if b == nil {
+				break
+			}
+
+			if b.ctx != nil && !r.alive(b) {
+				log.Printf("pdf: chrome stopped responding, restarting")
+				r.restart(b)
+			}
+			r.browsers <- b
+		}
+	}
+}
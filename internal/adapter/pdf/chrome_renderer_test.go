@@ -0,0 +1,255 @@
+package pdf
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type browserKey struct{}
+
+// fakeChrome launches browsers that are plain contexts numbered from 1.
+// Browsers listed in dead fail their health check, and while hang is set
+// launches never finish on their own.
+type fakeChrome struct {
+	mu       sync.Mutex
+	hang     bool
+	launches int
+	dead     map[int]bool
+	browsers []context.Context
+}
+
+func (f *fakeChrome) launch(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	f.mu.Lock()
+	hang := f.hang
+	f.launches++
+	n := f.launches
+	f.mu.Unlock()
+
+	if hang {
+		<-ctx.Done()
+		return nil, nil, ctx.Err()
+	}
+
+	browserCtx, cancel := context.WithCancel(context.WithValue(context.Background(), browserKey{}, n))
+	f.mu.Lock()
+	f.browsers = append(f.browsers, browserCtx)
+	f.mu.Unlock()
+	return browserCtx, cancel, nil
+}
+
+func (f *fakeChrome) ping(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.dead[ctx.Value(browserKey{}).(int)] {
+		return errors.New("connection closed")
+	}
+
+	return nil
+}
+
+func (f *fakeChrome) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.launches
+}
+
+// waitFor polls cond until it holds, failing the test after a second.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestChromeAcquireWaitsForFreeBrowser(t *testing.T) {
+	chrome := &fakeChrome{}
+	r := newChromeRenderer(ChromeOptions{PoolSize: 2}, chrome.launch, chrome.ping)
+
+	first, err := r.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	if _, err := r.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	if stats := r.Stats(); stats.Idle != 0 {
+		t.Errorf("idle = %d with the pool taken, want 0", stats.Idle)
+	}
+
+	got := make(chan *browser)
+	go func() {
+		b, err := r.acquire(context.Background())
+		if err != nil {
+			t.Errorf("acquire: %v", err)
+		}
+
+		got <- b
+	}()
+
+	waitFor(t, "a waiting render", func() bool { return r.Stats().Waiting == 1 })
+	select {
+	case <-got:
+		t.Fatal("acquired a browser from an empty pool")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	r.browsers <- first
+	if b := <-got; b != first {
+		t.Error("did not get the released browser")
+	}
+
+	if stats := r.Stats(); stats.Waiting != 0 || stats.QueueWait.Count != 3 {
+		t.Errorf("waiting %d, queue waits %d; want 0 and 3", stats.Waiting, stats.QueueWait.Count)
+	}
+}
+
+func TestChromeQueueTimeout(t *testing.T) {
+	chrome := &fakeChrome{}
+	r := newChromeRenderer(ChromeOptions{PoolSize: 1, QueueTimeout: 20 * time.Millisecond}, chrome.launch, chrome.ping)
+	b, err := r.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer func() { r.browsers <- b }()
+
+	start := time.Now()
+	if _, err := r.Render(context.Background(), sampleInvoice(1)); !errors.Is(err, ErrRendererBusy) {
+		t.Fatalf("Render: %v, want %v", err, ErrRendererBusy)
+	}
+
+	if waited := time.Since(start); waited < 20*time.Millisecond {
+		t.Errorf("gave up after %s, before the queue timeout", waited)
+	}
+
+	if stats := r.Stats(); stats.Timeouts != 1 || stats.Waiting != 0 {
+		t.Errorf("timeouts %d, waiting %d; want 1 and 0", stats.Timeouts, stats.Waiting)
+	}
+}
+
+func TestChromeAcquireCanceled(t *testing.T) {
+	chrome := &fakeChrome{}
+	r := newChromeRenderer(ChromeOptions{PoolSize: 1}, chrome.launch, chrome.ping)
+	b, err := r.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer func() { r.browsers <- b }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := r.acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("acquire: %v, want %v", err, context.Canceled)
+	}
+
+	if _, err := r.acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("acquire with a canceled context: %v, want %v", err, context.Canceled)
+	}
+
+	if stats := r.Stats(); stats.Canceled != 2 || stats.Timeouts != 0 {
+		t.Errorf("canceled %d, timeouts %d; want 2 and 0", stats.Canceled, stats.Timeouts)
+	}
+}
+
+func TestChromeStartTimeout(t *testing.T) {
+	chrome := &fakeChrome{hang: true}
+	r := newChromeRenderer(ChromeOptions{PoolSize: 1}, chrome.launch, chrome.ping)
+	r.startTimeout = 20 * time.Millisecond
+
+	start := time.Now()
+	_, err := r.Render(context.Background(), sampleInvoice(1))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Render: %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("launch took %s despite the start timeout", waited)
+	}
+
+	stats := r.Stats()
+	if stats.Failures != 1 || stats.Running != 0 || stats.Idle != 1 {
+		t.Errorf("failures %d, running %d, idle %d; want 1, 0 and 1", stats.Failures, stats.Running, stats.Idle)
+	}
+
+	// The browser went back to the pool stopped, so the next render
+	// launches it again.
+	b, err := r.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	if b.ctx != nil {
+		t.Error("browser that failed to start has a context")
+	}
+	r.browsers <- b
+}
+
+func TestChromeStartCanceled(t *testing.T) {
+	chrome := &fakeChrome{hang: true}
+	r := newChromeRenderer(ChromeOptions{PoolSize: 1}, chrome.launch, chrome.ping)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := r.Render(ctx, sampleInvoice(1)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Render: %v, want %v", err, context.Canceled)
+	}
+
+	if stats := r.Stats(); stats.Canceled != 1 || stats.Failures != 0 || stats.Idle != 1 {
+		t.Errorf("canceled %d, failures %d, idle %d; want 1, 0 and 1", stats.Canceled, stats.Failures, stats.Idle)
+	}
+}
+
+func TestChromeHealthCheckRestartsDeadBrowser(t *testing.T) {
+	chrome := &fakeChrome{dead: map[int]bool{}}
+	r := newChromeRenderer(ChromeOptions{PoolSize: 2, HealthInterval: 5 * time.Millisecond}, chrome.launch, chrome.ping)
+
+	// Start both browsers, then let the first one crash.
+	var held []*browser
+	for range 2 {
+		b, err := r.acquire(context.Background())
+		if err != nil {
+			t.Fatalf("acquire: %v", err)
+		}
+
+		if err := r.start(context.Background(), b); err != nil {
+			t.Fatalf("start: %v", err)
+		}
+
+		held = append(held, b)
+	}
+
+	chrome.mu.Lock()
+	chrome.dead[1] = true
+	chrome.mu.Unlock()
+	for _, b := range held {
+		r.browsers <- b
+	}
+
+	waitFor(t, "the restart", func() bool { return r.Stats().Restarts == 1 })
+	time.Sleep(30 * time.Millisecond)
+
+	stats := r.Stats()
+	if stats.Restarts != 1 || stats.Running != 2 || chrome.count() != 3 {
+		t.Errorf("restarts %d, running %d, launches %d; want 1, 2 and 3", stats.Restarts, stats.Running, chrome.count())
+	}
+
+	chrome.mu.Lock()
+	crashed, healthy := chrome.browsers[0], chrome.browsers[1]
+	chrome.mu.Unlock()
+	if crashed.Err() == nil {
+		t.Error("crashed browser was not stopped")
+	}
+
+	if healthy.Err() != nil {
+		t.Error("healthy browser was stopped")
+	}
+}
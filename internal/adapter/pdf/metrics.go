@@ -0,0 +1,49 @@
+package pdf
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+// durations accumulates timings for the stats endpoint.
+type durations struct {
+	mu    sync.Mutex
+	count int64
+	total time.Duration
+	max   time.Duration
+	last  time.Duration
+}
+
+func (d *durations) observe(v time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.count++
+	d.total += v
+	d.last = v
+	if v > d.max {
+		d.max = v
+	}
+}
+
+func (d *durations) stats() entity.DurationStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s := entity.DurationStats{
+		Count:  d.count,
+		MaxMs:  milliseconds(d.max),
+		LastMs: milliseconds(d.last),
+	}
+	if d.count > 0 {
+		s.AvgMs = milliseconds(d.total / time.Duration(d.count))
+	}
+
+	return s
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,440 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	"github.com/hutamy/go-invoice-backend/pkg/i18n"
+	"github.com/hutamy/go-invoice-backend/pkg/pdf"
+	"github.com/hutamy/go-invoice-backend/pkg/qrcode"
+)
+
+// Layout of the native renderer, in points. Sizes follow the HTML template
+// at Chrome's 0.75pt per CSS pixel on its default Letter paper.
+const (
+	pageMargin    = 48.0
+	contentWidth  = pdf.LetterWidth - 2*pageMargin
+	bodySize      = 10.5
+	lineHeight    = bodySize * 1.6
+	totalsWidth   = 240.0
+	qrisSize      = 120.0
+	logoMaxWidth  = 150.0
+	logoMaxHeight = 48.0
+)
+
+var (
+	colorTitle  = pdf.Hex("#111827")
+	colorText   = pdf.Hex("#1f2937")
+	colorMuted  = pdf.Hex("#4b5563")
+	colorFaint  = pdf.Hex("#6b7280")
+	colorNotes  = pdf.Hex("#374151")
+	colorHeadBg = pdf.Hex("#f9fafb")
+	colorBorder = pdf.Hex("#e5e7eb")
+	colorRule   = pdf.Hex("#f3f4f6")
+	colorTotal  = pdf.Hex("#d1d5db")
+	colorBankBg = pdf.Hex("#f3f4f6")
+	colorDark   = pdf.Hex("#000000")
+)
+
+// NativeRenderer lays out the invoice design directly in Go, without a
+// browser, so PDFs can be produced in minimal containers and in tests. It
+// ignores the document's HTML and always draws the classic layout, with the
+// branding's accent color, logo and footer but in Helvetica. Archival
+// documents are written as PDF/A-3 with the structured invoice embedded.
+type NativeRenderer struct {
+	renders  atomic.Int64
+	failures atomic.Int64
+	duration durations
+}
+
+func NewNativeRenderer() ports.PDFRenderer {
+	return &NativeRenderer{}
+}
+
+func (r *NativeRenderer) Render(ctx context.Context, doc *entity.InvoiceDocument) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	out, err := r.render(doc)
+	r.duration.observe(time.Since(start))
+	r.renders.Add(1)
+	if err != nil {
+		r.failures.Add(1)
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (r *NativeRenderer) Stats() entity.PDFRendererStats {
+	return entity.PDFRendererStats{
+		Renderer:       "native",
+		Renders:        r.renders.Load(),
+		Failures:       r.failures.Load(),
+		RenderDuration: r.duration.stats(),
+	}
+}
+
+func (r *NativeRenderer) render(doc *entity.InvoiceDocument) ([]byte, error) {
+	l, err := newLayout(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	l.header()
+	l.parties()
+	l.items()
+	l.totals()
+	l.notes()
+	if err := l.bankDetails(); err != nil {
+		return nil, err
+	}
+
+	l.footer()
+	return l.doc.Bytes()
+}
+
+// layout tracks the page being written and the vertical position on it.
+type layout struct {
+	src    *entity.InvoiceDocument
+	doc    *pdf.Document
+	page   *pdf.Page
+	y      float64
+	loc    *i18n.Locale
+	accent pdf.Color
+	logo   *pdf.Image
+}
+
+func newLayout(src *entity.InvoiceDocument) (*layout, error) {
+	loc := i18n.For(src.Language)
+	doc := pdf.New(pdf.LetterWidth, pdf.LetterHeight)
+	doc.Title = loc.T("Invoice") + " " + src.Invoice.InvoiceNumber
+	doc.Author = src.Sender.Name
+	if src.Archive != nil {
+		if err := archive(doc, src.Archive); err != nil {
+			return nil, err
+		}
+	}
+
+	l := &layout{
+		src:    src,
+		doc:    doc,
+		loc:    loc,
+		accent: colorTitle,
+	}
+	if src.Branding.AccentColor != "" {
+		l.accent = pdf.Hex(src.Branding.AccentColor)
+	}
+
+	if src.Branding.Logo != "" {
+		data, _, err := src.Branding.LogoImage()
+		if err != nil {
+			return nil, err
+		}
+
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decode logo: %w", err)
+		}
+
+		l.logo = doc.AddImage(img)
+	}
+
+	l.newPage()
+	return l, nil
+}
+
+func (l *layout) newPage() {
+	l.page = l.doc.AddPage()
+	l.y = pageMargin
+}
+
+// ensure starts a new page unless h more points fit on the current one.
+func (l *layout) ensure(h float64) {
+	if l.y+h > pdf.LetterHeight-pageMargin {
+		l.newPage()
+	}
+}
+
+func (l *layout) money(v float64) string {
+	return l.loc.Money(v)
+}
+
+func (l *layout) textRight(right, y float64, font pdf.Font, size float64, color pdf.Color, s string) {
+	l.page.Text(right-pdf.TextWidth(font, size, s), y, font, size, color, s)
+}
+
+func (l *layout) header() {
+	inv := l.src.Invoice
+	logoHeight := 0.0
+	if l.logo != nil {
+		w, h := l.logo.Size()
+		scale := min(logoMaxWidth/float64(w), logoMaxHeight/float64(h))
+		logoHeight = float64(h)*scale + 12
+		l.page.Image(l.logo, pageMargin, l.y, float64(w)*scale, float64(h)*scale)
+	}
+
+	top := l.y + logoHeight
+	l.page.Text(pageMargin, top+27, pdf.HelveticaBold, 27, l.accent, l.loc.T("INVOICE"))
+	l.page.Text(pageMargin, top+27+18, pdf.Helvetica, bodySize, colorFaint, inv.InvoiceNumber)
+
+	right := pageMargin + contentWidth
+	dates := []string{
+		l.loc.T("Issue Date:") + " " + l.loc.Date(inv.IssueDate),
+		l.loc.T("Due Date:") + " " + l.loc.Date(inv.DueDate),
+	}
+	if text := inv.PaymentTerms.Localized(l.loc.T, inv.PaymentTermsDays); text != "" {
+		dates = append(dates, l.loc.T("Payment Terms:")+" "+text)
+	}
+
+	for i, d := range dates {
+		l.textRight(right, l.y+bodySize+float64(i)*lineHeight, pdf.Helvetica, bodySize, colorMuted, d)
+	}
+
+	l.y += max(logoHeight+27+18, float64(len(dates))*lineHeight) + 36
+}
+
+func (l *layout) parties() {
+	colWidth := (contentWidth - 36) / 2
+	sender := l.src.Sender
+	client := l.src.Client
+	columns := []struct {
+		title string
+		lines []string
+	}{
+		{l.loc.T("FROM"), []string{sender.Name, sender.Address, sender.Email, sender.Phone}},
+		{l.loc.T("TO"), []string{client.Name, client.Address, client.Email, client.Phone}},
+	}
+
+	bottom := l.y
+	for i, col := range columns {
+		x := pageMargin + float64(i)*(colWidth+36)
+		y := l.y + bodySize
+		l.page.Text(x, y, pdf.HelveticaBold, bodySize, colorMuted, col.title)
+		y += 12 + lineHeight
+		for _, text := range col.lines {
+			for _, line := range pdf.Wrap(pdf.Helvetica, bodySize, colWidth, text) {
+				l.page.Text(x, y, pdf.Helvetica, bodySize, colorText, line)
+				y += lineHeight
+			}
+		}
+
+		bottom = max(bottom, y)
+	}
+
+	l.y = bottom + 24
+}
+
+// items draws the line item table, repeating its header on new pages.
+func (l *layout) items() {
+	widths := []float64{contentWidth * 0.45, contentWidth * 0.15, contentWidth * 0.2, contentWidth * 0.2}
+	headers := []string{l.loc.T("Description"), l.loc.T("Quantity"), l.loc.T("Unit Price"), l.loc.T("Total")}
+	const pad = 6.0
+
+	drawHeader := func() {
+		l.page.Rect(pageMargin, l.y, contentWidth, 27, colorHeadBg)
+		x := pageMargin
+		for i, h := range headers {
+			if i == len(headers)-1 {
+				l.textRight(x+widths[i]-pad, l.y+17.5, pdf.HelveticaBold, bodySize, colorText, h)
+			} else {
+				l.page.Text(x+pad, l.y+17.5, pdf.HelveticaBold, bodySize, colorText, h)
+			}
+
+			x += widths[i]
+		}
+
+		l.y += 27
+		l.page.Line(pageMargin, l.y, pageMargin+contentWidth, l.y, 1.5, colorBorder)
+	}
+
+	l.ensure(27 + 2*lineHeight)
+	drawHeader()
+	for i, it := range l.src.Invoice.Items {
+		desc := pdf.Wrap(pdf.Helvetica, bodySize, widths[0]-2*pad, it.Description)
+		height := float64(len(desc))*lineHeight + 16
+		if l.y+height > pdf.LetterHeight-pageMargin {
+			l.newPage()
+			drawHeader()
+		}
+
+		y := l.y + 8 + bodySize + 2
+		for j, line := range desc {
+			l.page.Text(pageMargin+pad, y+float64(j)*lineHeight, pdf.Helvetica, bodySize, colorText, line)
+		}
+
+		x := pageMargin + widths[0]
+		l.page.Text(x+pad, y, pdf.Helvetica, bodySize, colorText, fmt.Sprintf("%d", it.Quantity))
+		x += widths[1]
+		l.page.Text(x+pad, y, pdf.Helvetica, bodySize, colorText, l.money(it.UnitPrice))
+		x += widths[2]
+		l.textRight(x+widths[3]-pad, y, pdf.Helvetica, bodySize, colorText, l.money(it.Total))
+
+		l.y += height
+		if i < len(l.src.Invoice.Items)-1 {
+			l.page.Line(pageMargin, l.y, pageMargin+contentWidth, l.y, 0.75, colorRule)
+		}
+	}
+
+	l.y += 24
+}
+
+func (l *layout) totals() {
+	inv := l.src.Invoice
+	rows := [][2]string{
+		{l.loc.T("Subtotal:"), l.money(inv.Subtotal)},
+		{l.loc.T("Tax (%s%%):", l.loc.Number(inv.TaxRate)), l.money(inv.Tax)},
+	}
+	if inv.DeliveryFee > 0 {
+		rows = append(rows, [2]string{l.loc.T("Delivery Fee:"), l.money(inv.DeliveryFee)})
+	}
+
+	var words []string
+	if inv.AmountInWords {
+		words = pdf.Wrap(pdf.Helvetica, 9, totalsWidth, l.loc.T("Amount in words:")+" "+l.loc.Words(inv.Total))
+	}
+
+	l.ensure(float64(len(rows))*21 + 36 + float64(len(words))*12)
+	left := pageMargin + contentWidth - totalsWidth
+	right := pageMargin + contentWidth
+	for _, row := range rows {
+		l.page.Text(left, l.y+bodySize+6, pdf.Helvetica, bodySize, colorMuted, row[0])
+		l.textRight(right, l.y+bodySize+6, pdf.Helvetica, bodySize, colorText, row[1])
+		l.y += 21
+	}
+
+	l.page.Line(left, l.y, right, l.y, 0.75, colorTotal)
+	l.page.Text(left, l.y+12+9, pdf.HelveticaBold, 12, l.accent, l.loc.T("Total:"))
+	l.textRight(right, l.y+12+9, pdf.HelveticaBold, 12, l.accent, l.money(inv.Total))
+	l.y += 33
+	for _, line := range words {
+		l.page.Text(left, l.y+9, pdf.Helvetica, 9, colorMuted, line)
+		l.y += 12
+	}
+
+	l.y += 24
+}
+
+func (l *layout) notes() {
+	if notes := strings.TrimSpace(l.src.Invoice.Notes); notes != "" {
+		label := l.loc.T("Terms:") + " "
+		indent := pdf.TextWidth(pdf.HelveticaBold, bodySize, label)
+		lines := pdf.Wrap(pdf.Helvetica, bodySize, contentWidth-indent, notes)
+		l.ensure(lineHeight)
+		l.page.Text(pageMargin, l.y+bodySize, pdf.HelveticaBold, bodySize, colorNotes, label)
+		for _, line := range lines {
+			l.ensure(lineHeight)
+			l.page.Text(pageMargin+indent, l.y+bodySize, pdf.Helvetica, bodySize, colorNotes, line)
+			l.y += lineHeight
+		}
+
+		l.y += 12
+	}
+
+	l.ensure(lineHeight)
+	bold := l.loc.T("Thank you")
+	l.page.Text(pageMargin, l.y+bodySize, pdf.HelveticaBold, bodySize, colorNotes, bold)
+	l.page.Text(pageMargin+pdf.TextWidth(pdf.HelveticaBold, bodySize, bold), l.y+bodySize, pdf.Helvetica, bodySize, colorNotes, " "+l.loc.T("for your business!"))
+	l.y += lineHeight + 24
+}
+
+// bankDetails draws the shaded payment box with the bank account and, when
+// the invoice has one, its QRIS code on the right.
+func (l *layout) bankDetails() error {
+	const pad = 18.0
+	sender := l.src.Sender
+	rows := [][2]string{
+		{l.loc.T("Bank Name:"), sender.BankName},
+		{l.loc.T("Account Name:"), sender.BankAccountName},
+		{l.loc.T("Account Number:"), sender.BankAccountNumber},
+	}
+
+	var code *qrcode.Code
+	if l.src.QRISPayload != "" {
+		var err error
+		code, err = qrcode.Encode([]byte(l.src.QRISPayload))
+		if err != nil {
+			return err
+		}
+	}
+
+	height := 2*pad + 12 + float64(len(rows))*lineHeight + 6
+	if code != nil {
+		height = max(height, 2*pad+qrisSize+16)
+	}
+
+	l.ensure(height)
+	top := l.y
+	l.page.Rect(pageMargin, top, contentWidth, height, colorBankBg)
+	l.page.Text(pageMargin+pad, top+pad+bodySize, pdf.HelveticaBold, bodySize, colorMuted, l.loc.T("BANK ACCOUNT DETAILS"))
+	y := top + pad + bodySize + 12 + lineHeight
+	for _, row := range rows {
+		l.page.Text(pageMargin+pad, y, pdf.HelveticaBold, bodySize, colorNotes, row[0])
+		l.page.Text(pageMargin+pad+112, y, pdf.Helvetica, bodySize, colorNotes, row[1])
+		y += lineHeight
+	}
+
+	if code != nil {
+		x := pageMargin + contentWidth - pad - qrisSize
+		l.drawQR(code, x, top+pad, qrisSize)
+		caption := l.loc.T("Scan to pay with QRIS")
+		l.page.Text(x+(qrisSize-pdf.TextWidth(pdf.Helvetica, 9, caption))/2, top+pad+qrisSize+11, pdf.Helvetica, 9, colorMuted, caption)
+	}
+
+	l.y = top + height
+	return nil
+}
+
+// footer draws the branding's footer text centered under a rule.
+func (l *layout) footer() {
+	text := strings.TrimSpace(l.src.Branding.FooterText)
+	if text == "" {
+		return
+	}
+
+	const size = 9.0
+	lines := pdf.Wrap(pdf.Helvetica, size, contentWidth, text)
+	l.y += 24
+	l.ensure(12 + size*1.6)
+	l.page.Line(pageMargin, l.y, pageMargin+contentWidth, l.y, 0.75, colorBorder)
+	l.y += 12
+	for _, line := range lines {
+		l.ensure(size * 1.6)
+		l.page.Text(pageMargin+(contentWidth-pdf.TextWidth(pdf.Helvetica, size, line))/2, l.y+size, pdf.Helvetica, size, colorFaint, line)
+		l.y += size * 1.6
+	}
+}
+
+// drawQR draws code as vector modules on a white square with a quiet zone,
+// merging the dark modules of each row into runs.
+func (l *layout) drawQR(code *qrcode.Code, x, y, size float64) {
+	const quiet = 2
+	module := size / float64(code.Size+2*quiet)
+	l.page.Rect(x, y, size, size, pdf.Color{R: 255, G: 255, B: 255})
+	for row := 0; row < code.Size; row++ {
+		for col := 0; col < code.Size; {
+			if !code.Modules[row][col] {
+				col++
+				continue
+			}
+
+			start := col
+			for col < code.Size && code.Modules[row][col] {
+				col++
+			}
+
+			l.page.Rect(x+float64(start+quiet)*module, y+float64(row+quiet)*module, float64(col-start)*module, module, colorDark)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+var pageCount = regexp.MustCompile(`/Type /Pages /Kids \[[^\]]*\] /Count (\d+)`)
+
+// sampleInvoice returns a document with n items, taxed at 11%.
+func sampleInvoice(n int) *entity.InvoiceDocument {
+	issued := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	inv := entity.Invoice{
+		InvoiceNumber: "INV-0042",
+		Status:        string(entity.InvoiceStatusSent),
+		IssueDate:     issued,
+		DueDate:       issued.AddDate(0, 0, 30),
+		TaxRate:       11,
+		Notes:         "Payment within 30 days.\nThank you.",
+	}
+	for i := range n {
+		inv.Items = append(inv.Items, entity.InvoiceItem{
+			Description: fmt.Sprintf("Consulting, week %d", i+1),
+			Quantity:    1,
+			UnitPrice:   250000,
+			Total:       250000,
+		})
+		inv.Subtotal += 250000
+	}
+
+	inv.Tax = inv.Subtotal * inv.TaxRate / 100
+	inv.Total = inv.Subtotal + inv.Tax
+	return &entity.InvoiceDocument{
+		Invoice: inv,
+		Sender: entity.User{
+			Name:              "Studio Hutamy",
+			Email:             "billing@hutamy.id",
+			Address:           "Jl. Sudirman 1\nJakarta",
+			BankName:          "BCA",
+			BankAccountName:   "Studio Hutamy",
+			BankAccountNumber: "1234567890",
+		},
+		Client: entity.Client{
+			Name:    "PT Pembeli",
+			Email:   "ap@pembeli.co.id",
+			Address: "Jl. Asia Afrika 8\nBandung",
+		},
+	}
+}
+
+func TestNativeRendererPages(t *testing.T) {
+	// Below the header and parties the first page holds 13 single-line
+	// items, and each page after it 20 under the repeated table header.
+	tests := []struct {
+		items int
+		pages int
+	}{
+		{2, 1},
+		{13, 2}, // a full first page pushes the totals over
+		{40, 3},
+		{100, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d items", tt.items), func(t *testing.T) {
+			data, err := NewNativeRenderer().Render(context.Background(), sampleInvoice(tt.items))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.HasPrefix(data, []byte("%PDF-")) {
+				t.Fatalf("output starts with %q", data[:min(len(data), 8)])
+			}
+
+			if !bytes.HasSuffix(bytes.TrimSpace(data), []byte("%%EOF")) {
+				t.Error("output does not end with the end-of-file marker")
+			}
+
+			m := pageCount.FindSubmatch(data)
+			if m == nil {
+				t.Fatal("no page tree")
+			}
+
+			if got, _ := strconv.Atoi(string(m[1])); got != tt.pages {
+				t.Errorf("got %d pages, want %d", got, tt.pages)
+			}
+
+			if got := bytes.Count(data, []byte("<< /Type /Page /Parent")); got != tt.pages {
+				t.Errorf("got %d page objects, want %d", got, tt.pages)
+			}
+		})
+	}
+}
+
+func TestNativeRendererStats(t *testing.T) {
+	r := NewNativeRenderer().(*NativeRenderer)
+	if _, err := r.Render(context.Background(), sampleInvoice(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.Render(ctx, sampleInvoice(1)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Render with a canceled context: %v", err)
+	}
+
+	if s := r.Stats(); s.Renderer != "native" || s.Renders != 1 || s.Failures != 0 {
+		t.Errorf("got stats %+v", s)
+	}
+}
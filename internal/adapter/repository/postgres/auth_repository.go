@@ -80,6 +80,32 @@ func (r *AuthRepository) UpdateUserBanking(userID uint, update entity.User) erro
 		Updates(updates).Error
 }
 
+func (r *AuthRepository) UpdateUserQRIS(userID uint, payload string) error {
+	return r.db.Model(&pmodel.User{}).
+		Where("id = ?", userID).
+		Update("qris_payload", payload).Error
+}
+
+func (r *AuthRepository) UpdateUserTaxID(userID uint, npwp, nitku string) error {
+	updates := map[string]any{
+		"npwp":  npwp,
+		"nitku": nitku,
+	}
+	return r.db.Model(&pmodel.User{}).
+		Where("id = ?", userID).
+		Updates(updates).Error
+}
+
+func (r *AuthRepository) UpdatePaymentTerms(userID uint, terms entity.PaymentTerms, days int) error {
+	updates := map[string]any{
+		"payment_terms":      string(terms),
+		"payment_terms_days": days,
+	}
+	return r.db.Model(&pmodel.User{}).
+		Where("id = ?", userID).
+		Updates(updates).Error
+}
+
 func (r *AuthRepository) DeleteUser(id uint) error {
 	res := r.db.Delete(&pmodel.User{}, id)
 	if res.Error != nil {
@@ -105,6 +131,9 @@ func (r *AuthRepository) RestoreUser(user *entity.User) error {
 		"bank_name":           m.BankName,
 		"bank_account_name":   m.BankAccountName,
 		"bank_account_number": m.BankAccountNumber,
+		"qris_payload":        m.QRISPayload,
+		"npwp":                m.NPWP,
+		"nitku":               m.NITKU,
 	}
 
 	res := r.db.Unscoped().Model(&pmodel.User{}).
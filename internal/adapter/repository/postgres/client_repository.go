@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"errors"
+	"time"
 
 	"github.com/hutamy/go-invoice-backend/internal/adapter/mapper"
 	"github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
@@ -74,12 +75,66 @@ func (r *ClientRepository) ListByUser(userID uint, page int, pageSize int, searc
 	return out, total, nil
 }
 
+func (r *ClientRepository) ListEmailsByUser(userID uint) ([]string, error) {
+	var emails []string
+	if err := r.db.Model(&model.Client{}).
+		Where("user_id = ?", userID).
+		Pluck("email", &emails).Error; err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+// ListByEmail returns the clients of every user with the given email.
+func (r *ClientRepository) ListByEmail(email string) ([]entity.Client, error) {
+	var rows []model.Client
+	if err := r.db.Where("LOWER(email) = LOWER(?)", email).
+		Order("id ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.Client, 0, len(rows))
+	for i := range rows {
+		out = append(out, *mapper.ClientFromModel(&rows[i]))
+	}
+
+	return out, nil
+}
+
+func (r *ClientRepository) FindMatch(userID uint, email, name string) (*entity.Client, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if email != "" {
+		query = query.Where("LOWER(email) = LOWER(?)", email)
+	} else {
+		query = query.Where("LOWER(name) = LOWER(?)", name)
+	}
+
+	var m model.Client
+	err := query.Order("id ASC").First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.ClientFromModel(&m), nil
+}
+
 func (r *ClientRepository) Update(update entity.Client) error {
 	updates := map[string]any{
-		"name":    update.Name,
-		"email":   update.Email,
-		"phone":   update.Phone,
-		"address": update.Address,
+		"name":               update.Name,
+		"email":              update.Email,
+		"phone":              update.Phone,
+		"address":            update.Address,
+		"payment_terms":      string(update.PaymentTerms),
+		"payment_terms_days": update.PaymentTermsDays,
+		"language":           update.Language,
+		"npwp":               update.NPWP,
+		"nitku":              update.NITKU,
 	}
 	res := r.db.Model(&model.Client{}).
 		Where("id = ? AND user_id = ?", update.ID, update.UserID).
@@ -97,8 +152,7 @@ func (r *ClientRepository) Update(update entity.Client) error {
 }
 
 func (r *ClientRepository) Delete(id, userID uint) error {
-	res := r.db.Unscoped().
-		Where("id = ? AND user_id = ?", id, userID).
+	res := r.db.Where("id = ? AND user_id = ?", id, userID).
 		Delete(&model.Client{})
 
 	if res.Error != nil {
@@ -112,25 +166,85 @@ func (r *ClientRepository) Delete(id, userID uint) error {
 	return nil
 }
 
-func (r *ClientRepository) SoftDeleteByUserID(userID uint) error {
-	res := r.db.Where("user_id = ?", userID).
-		Delete(&model.Client{})
+func (r *ClientRepository) ListTrashedByUser(userID uint, page int, pageSize int) ([]entity.Client, int64, error) {
+	offset := (page - 1) * pageSize
+	query := r.db.Unscoped().Model(&model.Client{}).
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID)
 
-	if res.Error != nil {
-		return res.Error
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
 
-	return nil
+	var rows []model.Client
+	if err := query.Order("deleted_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	out := make([]entity.Client, 0, len(rows))
+	for i := range rows {
+		if e := mapper.ClientFromModel(&rows[i]); e != nil {
+			out = append(out, *e)
+		}
+	}
+
+	return out, total, nil
 }
 
-func (r *ClientRepository) RestoreByUserID(userID uint) error {
+func (r *ClientRepository) Restore(id, userID uint) error {
 	res := r.db.Unscoped().Model(&model.Client{}).
-		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).
 		Update("deleted_at", nil)
 
 	if res.Error != nil {
 		return res.Error
 	}
 
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
 	return nil
 }
+
+// PurgeDeletedBefore permanently removes clients trashed before the cutoff.
+// Clients of deactivated users are kept so the account can still be
+// restored, as are clients still referenced by an invoice.
+func (r *ClientRepository) PurgeDeletedBefore(before time.Time) (int64, error) {
+	activeUsers := r.db.Model(&model.User{}).Select("id")
+	referenced := r.db.Unscoped().Model(&model.Invoice{}).
+		Select("client_id").
+		Where("client_id IS NOT NULL")
+
+	res := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Where("user_id IN (?)", activeUsers).
+		Where("id NOT IN (?)", referenced).
+		Delete(&model.Client{})
+
+	return res.RowsAffected, res.Error
+}
+
+// SoftDeleteByUserID trashes the user's clients on deactivation, marking
+// them so that RestoreByUserID brings back only those and not the ones the
+// user had trashed before.
+func (r *ClientRepository) SoftDeleteByUserID(userID uint) error {
+	return r.db.Model(&model.Client{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]any{
+			"deleted_at":        time.Now(),
+			"deleted_with_user": true,
+		}).Error
+}
+
+func (r *ClientRepository) RestoreByUserID(userID uint) error {
+	return r.db.Unscoped().Model(&model.Client{}).
+		Where("user_id = ? AND deleted_with_user", userID).
+		Updates(map[string]any{
+			"deleted_at":        nil,
+			"deleted_with_user": false,
+		}).Error
+}
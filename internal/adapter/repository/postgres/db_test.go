@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	pmodel "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// testDB returns a database with the tables of models in a schema of its
+// own, dropped when the test ends. Repository tests need Postgres, so they
+// are skipped unless TEST_DATABASE_URL is set.
+func testDB(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	schemaName := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	db, err := gorm.Open(postgres.New(postgres.Config{
+		DSN:                  url,
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+		NamingStrategy: schema.NamingStrategy{
+			TablePrefix: schemaName + ".",
+		},
+	})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	if err := db.Exec("CREATE SCHEMA " + schemaName).Error; err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.Exec("DROP SCHEMA " + schemaName + " CASCADE")
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return db
+}
+
+// testInvoice creates a user with a sent invoice over total.
+func testInvoice(t *testing.T, db *gorm.DB, total float64) pmodel.Invoice {
+	t.Helper()
+	user := pmodel.User{Name: "Studio Hutamy", Email: fmt.Sprintf("user%d@hutamy.id", time.Now().UnixNano()), Password: "x"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+
+	now := time.Now()
+	inv := pmodel.Invoice{
+		UserID:        user.ID,
+		InvoiceNumber: "INV-0001",
+		IssueDate:     now,
+		DueDate:       now.AddDate(0, 0, 30),
+		Status:        "sent",
+		Subtotal:      total,
+		Total:         total,
+	}
+	if err := db.Create(&inv).Error; err != nil {
+		t.Fatalf("create invoice: %v", err)
+	}
+
+	return inv
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/hutamy/go-invoice-backend/internal/adapter/mapper"
+	pmodel "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	"gorm.io/gorm"
+)
+
+type EmailTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailTemplateRepository(db *gorm.DB) ports.EmailTemplateRepository {
+	return &EmailTemplateRepository{
+		db: db,
+	}
+}
+
+func (r *EmailTemplateRepository) Get(userID uint, kind entity.EmailTemplateKind) (*entity.EmailTemplate, error) {
+	var m pmodel.EmailTemplate
+	err := r.db.Where("user_id = ? AND kind = ?", userID, kind).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.EmailTemplateFromModel(&m), nil
+}
+
+func (r *EmailTemplateRepository) ListByUser(userID uint) ([]entity.EmailTemplate, error) {
+	var rows []pmodel.EmailTemplate
+	if err := r.db.Where("user_id = ?", userID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.EmailTemplate, 0, len(rows))
+	for i := range rows {
+		out = append(out, *mapper.EmailTemplateFromModel(&rows[i]))
+	}
+
+	return out, nil
+}
+
+// Save creates or replaces the user's template of the same kind.
+func (r *EmailTemplateRepository) Save(template *entity.EmailTemplate) error {
+	existing, err := r.Get(template.UserID, template.Kind)
+	if err != nil {
+		return err
+	}
+
+	m := mapper.EmailTemplateToModel(template)
+	if existing != nil {
+		m.ID = existing.ID
+	}
+
+	if err := r.db.Save(m).Error; err != nil {
+		return err
+	}
+
+	template.ID = m.ID
+	template.Custom = true
+	return nil
+}
+
+func (r *EmailTemplateRepository) Delete(userID uint, kind entity.EmailTemplateKind) error {
+	res := r.db.Where("user_id = ? AND kind = ?", userID, kind).Delete(&pmodel.EmailTemplate{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/hutamy/go-invoice-backend/internal/adapter/mapper"
+	pmodel "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	"gorm.io/gorm"
+)
+
+type InvoiceBrandingRepository struct {
+	db *gorm.DB
+}
+
+func NewInvoiceBrandingRepository(db *gorm.DB) ports.InvoiceBrandingRepository {
+	return &InvoiceBrandingRepository{
+		db: db,
+	}
+}
+
+func (r *InvoiceBrandingRepository) Get(userID uint) (*entity.InvoiceBranding, error) {
+	var m pmodel.InvoiceBranding
+	err := r.db.Where("user_id = ?", userID).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.InvoiceBrandingFromModel(&m), nil
+}
+
+// Save creates or replaces the user's branding.
+func (r *InvoiceBrandingRepository) Save(branding *entity.InvoiceBranding) error {
+	var existing pmodel.InvoiceBranding
+	err := r.db.Select("id").Where("user_id = ?", branding.UserID).First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	m := mapper.InvoiceBrandingToModel(branding)
+	m.ID = existing.ID
+	if err := r.db.Save(m).Error; err != nil {
+		return err
+	}
+
+	branding.Custom = true
+	return nil
+}
+
+func (r *InvoiceBrandingRepository) Delete(userID uint) error {
+	res := r.db.Where("user_id = ?", userID).Delete(&pmodel.InvoiceBranding{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
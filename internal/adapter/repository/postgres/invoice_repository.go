@@ -2,12 +2,14 @@ package postgres
 
 import (
 	"errors"
+	"time"
 
 	"github.com/hutamy/go-invoice-backend/internal/adapter/mapper"
 	pmodel "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
 	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
 	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type InvoiceRepository struct {
@@ -22,7 +24,52 @@ func NewInvoiceRepository(db *gorm.DB) ports.InvoiceRepository {
 
 func (r *InvoiceRepository) Create(inv *entity.Invoice) error {
 	m := mapper.InvoiceToModel(inv)
-	return r.db.Create(m).Error
+	if err := r.db.Create(m).Error; err != nil {
+		return err
+	}
+
+	inv.ID = m.ID
+	inv.Subtotal = m.Subtotal
+	inv.Tax = m.Tax
+	inv.Total = m.Total
+	return nil
+}
+
+// CreateWithClient stores the invoice in a single transaction, creating the
+// client first when it has no ID yet.
+func (r *InvoiceRepository) CreateWithClient(inv *entity.Invoice, client *entity.Client) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if client != nil {
+			if client.ID == 0 {
+				cm := mapper.ClientToModel(client)
+				if err := tx.Create(cm).Error; err != nil {
+					return err
+				}
+				client.ID = cm.ID
+			}
+
+			inv.ClientID = &client.ID
+		}
+
+		m := mapper.InvoiceToModel(inv)
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+
+		inv.ID = m.ID
+		return nil
+	})
+}
+
+func (r *InvoiceRepository) ExistsByNumber(userID uint, invoiceNumber string) (bool, error) {
+	var count int64
+	if err := r.db.Unscoped().Model(&pmodel.Invoice{}).
+		Where("user_id = ? AND invoice_number = ?", userID, invoiceNumber).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
 }
 
 func (r *InvoiceRepository) GetByID(id, userID uint) (*entity.Invoice, error) {
@@ -40,7 +87,7 @@ func (r *InvoiceRepository) GetByID(id, userID uint) (*entity.Invoice, error) {
 
 	if m.ClientID != nil {
 		var client pmodel.Client
-		err = r.db.Where("id = ?", *m.ClientID).First(&client).Error
+		err = r.db.Unscoped().Where("id = ?", *m.ClientID).First(&client).Error
 		if err != nil {
 			return nil, err
 		}
@@ -80,7 +127,7 @@ func (r *InvoiceRepository) ListByUser(userID uint, page int, pageSize int, stat
 	for _, inv := range rows {
 		if inv.ClientID != nil {
 			var client pmodel.Client
-			err := r.db.Where("id = ?", *inv.ClientID).First(&client).Error
+			err := r.db.Unscoped().Where("id = ?", *inv.ClientID).First(&client).Error
 			if err != nil {
 				return nil, 0, err
 			}
@@ -97,25 +144,100 @@ func (r *InvoiceRepository) ListByUser(userID uint, page int, pageSize int, stat
 	return out, total, nil
 }
 
+// Update replaces the invoice and its items. Items added by a late fee are
+// not the client's to edit: they are kept whatever the update holds, and
+// the totals are recalculated to include them.
 func (r *InvoiceRepository) Update(update entity.Invoice) error {
 	m := mapper.InvoiceToModel(&update)
 
-	if err := r.db.Unscoped().
-		Where("invoice_id = ?", m.ID).
-		Delete(&pmodel.InvoiceItem{}).Error; err != nil {
-		return err
-	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		owned := tx.Model(&pmodel.Invoice{}).
+			Select("id").
+			Where("id = ? AND user_id = ?", m.ID, m.UserID)
 
-	for _, item := range update.Items {
-		item.InvoiceID = m.ID
-		if err := r.db.Create(&item).Error; err != nil {
+		var feeItemIDs []uint
+		if err := tx.Model(&pmodel.LateFee{}).
+			Where("invoice_id = ? AND user_id = ? AND invoice_item_id IS NOT NULL", m.ID, m.UserID).
+			Pluck("invoice_item_id", &feeItemIDs).Error; err != nil {
 			return err
 		}
-	}
 
-	res := r.db.Model(&pmodel.Invoice{}).
-		Where("id = ?", m.ID).
-		Updates(m)
+		del := tx.Unscoped().Where("invoice_id IN (?)", owned)
+		if len(feeItemIDs) > 0 {
+			del = del.Where("id NOT IN ?", feeItemIDs)
+		}
+
+		if err := del.Delete(&pmodel.InvoiceItem{}).Error; err != nil {
+			return err
+		}
+
+		var feeItems []pmodel.InvoiceItem
+		if len(feeItemIDs) > 0 {
+			if err := tx.Where("invoice_id IN (?) AND id IN ?", owned, feeItemIDs).
+				Find(&feeItems).Error; err != nil {
+				return err
+			}
+		}
+
+		isFeeItem := make(map[uint]bool, len(feeItemIDs))
+		for _, id := range feeItemIDs {
+			isFeeItem[id] = true
+		}
+
+		res := tx.Model(&pmodel.Invoice{}).
+			Where("id = ? AND user_id = ?", m.ID, m.UserID).
+			Omit(clause.Associations).
+			Updates(m)
+
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		m.Subtotal = 0
+		for _, item := range m.Items {
+			// A client sending back the invoice it fetched includes the fee
+			// items, which are already in place.
+			if isFeeItem[item.ID] {
+				continue
+			}
+
+			item.ID = 0
+			item.InvoiceID = m.ID
+			if err := tx.Create(&item).Error; err != nil {
+				return err
+			}
+
+			m.Subtotal += item.Total
+		}
+
+		for _, item := range feeItems {
+			m.Subtotal += item.Total
+		}
+
+		m.Tax = m.Subtotal * m.TaxRate / 100
+		m.Total = m.Subtotal + m.Tax + m.DeliveryFee
+
+		// Updates skips zero values, so these are written separately to allow
+		// going back to the client's language or dropping the words line.
+		return tx.Model(&pmodel.Invoice{}).
+			Where("id = ? AND user_id = ?", m.ID, m.UserID).
+			Updates(map[string]any{
+				"subtotal":        m.Subtotal,
+				"tax":             m.Tax,
+				"total":           m.Total,
+				"language":        m.Language,
+				"amount_in_words": m.AmountInWords,
+			}).Error
+	})
+}
+
+func (r *InvoiceRepository) Delete(id, userID uint) error {
+	res := r.db.Where("id = ? AND user_id = ?", id, userID).
+		Delete(&pmodel.Invoice{})
 
 	if res.Error != nil {
 		return res.Error
@@ -128,16 +250,39 @@ func (r *InvoiceRepository) Update(update entity.Invoice) error {
 	return nil
 }
 
-func (r *InvoiceRepository) Delete(id, userID uint) error {
-	if err := r.db.Unscoped().
-		Where("invoice_id = ?", id).
-		Delete(&pmodel.InvoiceItem{}).Error; err != nil {
-		return err
+func (r *InvoiceRepository) ListTrashedByUser(userID uint, page int, pageSize int) ([]entity.Invoice, int64, error) {
+	offset := (page - 1) * pageSize
+	query := r.db.Unscoped().Model(&pmodel.Invoice{}).
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
 
-	res := r.db.Unscoped().
-		Where("id = ? AND user_id = ?", id, userID).
-		Delete(&pmodel.Invoice{})
+	var rows []pmodel.Invoice
+	if err := query.Preload("Client", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("deleted_at DESC").
+		Limit(pageSize).
+		Offset(offset).
+		Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	out := make([]entity.Invoice, 0, len(rows))
+	for i := range rows {
+		if e := mapper.InvoiceFromModel(&rows[i]); e != nil {
+			out = append(out, *e)
+		}
+	}
+
+	return out, total, nil
+}
+
+func (r *InvoiceRepository) Restore(id, userID uint) error {
+	res := r.db.Unscoped().Model(&pmodel.Invoice{}).
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).
+		Update("deleted_at", nil)
 
 	if res.Error != nil {
 		return res.Error
@@ -150,34 +295,244 @@ func (r *InvoiceRepository) Delete(id, userID uint) error {
 	return nil
 }
 
+// PurgeDeletedBefore permanently removes invoices trashed before the cutoff,
+// along with their items and the records kept about them. Invoices of
+// deactivated users are kept so the account can still be restored, and so
+// are invoices a late fee invoice still refers to.
+func (r *InvoiceRepository) PurgeDeletedBefore(before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		activeUsers := tx.Model(&pmodel.User{}).Select("id")
+		feeFor := tx.Unscoped().Model(&pmodel.Invoice{}).
+			Select("fee_for_invoice_id").
+			Where("fee_for_invoice_id IS NOT NULL").
+			Where("deleted_at IS NULL OR deleted_at >= ?", before)
+		expired := tx.Unscoped().Model(&pmodel.Invoice{}).
+			Select("id").
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Where("user_id IN (?)", activeUsers).
+			Where("id NOT IN (?)", feeFor)
+
+		var ids []uint
+		if err := expired.Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			return nil
+		}
+
+		for _, dependent := range []interface{}{
+			&pmodel.InvoiceItem{},
+			&pmodel.LateFee{},
+			&pmodel.InvoiceReminder{},
+			&pmodel.InvoiceDelivery{},
+			&pmodel.InvoiceShareLink{},
+			&pmodel.PaymentLink{},
+			&pmodel.Payment{},
+			&pmodel.PDFJob{},
+			&pmodel.ReconciliationMatch{},
+		} {
+			if err := tx.Where("invoice_id IN ?", ids).Delete(dependent).Error; err != nil {
+				return err
+			}
+		}
+
+		// Late fees billed on a separate invoice stay recorded against the
+		// original, so they are not charged again.
+		if err := tx.Model(&pmodel.LateFee{}).
+			Where("fee_invoice_id IN ?", ids).
+			Update("fee_invoice_id", nil).Error; err != nil {
+			return err
+		}
+
+		// Bank transactions are the user's statement lines, so they are kept
+		// and can be matched again.
+		if err := tx.Model(&pmodel.BankTransaction{}).
+			Where("invoice_id IN ?", ids).
+			Updates(map[string]any{
+				"invoice_id": nil,
+				"payment_id": nil,
+				"status":     entity.BankTransactionUnmatched,
+			}).Error; err != nil {
+			return err
+		}
+
+		res := tx.Unscoped().Where("id IN ?", ids).Delete(&pmodel.Invoice{})
+		purged = res.RowsAffected
+		return res.Error
+	})
+
+	return purged, err
+}
+
+// SoftDeleteByUserID trashes the user's invoices on deactivation, marking
+// them so that RestoreByUserID brings back only those and not the ones the
+// user had trashed before.
 func (r *InvoiceRepository) SoftDeleteByUserID(userID uint) error {
-	res := r.db.Where("user_id = ?", userID).
-		Delete(&pmodel.Invoice{})
+	return r.db.Model(&pmodel.Invoice{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]any{
+			"deleted_at":        time.Now(),
+			"deleted_with_user": true,
+		}).Error
+}
+
+func (r *InvoiceRepository) RestoreByUserID(userID uint) error {
+	return r.db.Unscoped().Model(&pmodel.Invoice{}).
+		Where("user_id = ? AND deleted_with_user", userID).
+		Updates(map[string]any{
+			"deleted_at":        nil,
+			"deleted_with_user": false,
+		}).Error
+}
+
+func (r *InvoiceRepository) UpdateStatus(id uint, userID uint, status entity.InvoiceStatus) error {
+	res := r.db.Model(&pmodel.Invoice{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Updates(map[string]interface{}{"status": status})
 
 	if res.Error != nil {
 		return res.Error
 	}
 
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
 	return nil
 }
 
-func (r *InvoiceRepository) RestoreByUserID(userID uint) error {
-	res := r.db.Unscoped().Model(&pmodel.Invoice{}).
-		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
-		Update("deleted_at", nil)
+// ListByStatus returns the invoices of all users in the given status, for
+// use by background jobs.
+func (r *InvoiceRepository) ListByStatus(status entity.InvoiceStatus) ([]entity.Invoice, error) {
+	var rows []pmodel.Invoice
+	if err := r.db.Where("status = ?", status).
+		Preload("Items").
+		Preload("Client", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("id ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.Invoice, 0, len(rows))
+	for i := range rows {
+		if e := mapper.InvoiceFromModel(&rows[i]); e != nil {
+			out = append(out, *e)
+		}
+	}
+
+	return out, nil
+}
+
+// MarkOverdue moves sent invoices whose due date is before asOf to OVERDUE.
+func (r *InvoiceRepository) MarkOverdue(asOf time.Time) (int64, error) {
+	res := r.db.Model(&pmodel.Invoice{}).
+		Where("status = ? AND due_date < ?", entity.InvoiceStatusSent, asOf).
+		Updates(map[string]interface{}{"status": entity.InvoiceStatusOverdue})
+
+	return res.RowsAffected, res.Error
+}
+
+func (r *InvoiceRepository) SetRemindersDisabled(id, userID uint, disabled bool) error {
+	res := r.db.Model(&pmodel.Invoice{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("reminders_disabled", disabled)
 
 	if res.Error != nil {
 		return res.Error
 	}
 
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
 	return nil
 }
 
-func (r *InvoiceRepository) UpdateStatus(id uint, userID uint, status entity.InvoiceStatus) error {
-	res := r.db.Model(&pmodel.Invoice{}).
-		Where("id = ? AND user_id = ?", id, userID).
-		Updates(map[string]interface{}{"status": status})
+func (r *InvoiceRepository) ListForExport(userID uint, filter entity.InvoiceExportFilter, limit int) ([]entity.Invoice, error) {
+	q := r.db.Where("user_id = ?", userID)
+	if !filter.From.IsZero() {
+		q = q.Where("issue_date >= ?", filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		q = q.Where("issue_date < ?", filter.To.AddDate(0, 0, 1))
+	}
+
+	if filter.Status != "" {
+		q = q.Where("status = ?", string(filter.Status))
+	}
+
+	if filter.ClientID != nil {
+		q = q.Where("client_id = ?", *filter.ClientID)
+	}
+
+	var rows []pmodel.Invoice
+	if err := q.Preload("Client", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("issue_date, id").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
 
+	out := make([]entity.Invoice, 0, len(rows))
+	for i := range rows {
+		if e := mapper.InvoiceFromModel(&rows[i]); e != nil {
+			out = append(out, *e)
+		}
+	}
+
+	return out, nil
+}
+
+func (r *InvoiceRepository) ListByClient(userID, clientID uint, statuses []entity.InvoiceStatus) ([]entity.Invoice, error) {
+	var rows []pmodel.Invoice
+	if err := r.db.Where("user_id = ? AND client_id = ? AND status IN ?", userID, clientID, statuses).
+		Preload("Items").
+		Preload("Client", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("issue_date DESC, id DESC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.Invoice, 0, len(rows))
+	for i := range rows {
+		if e := mapper.InvoiceFromModel(&rows[i]); e != nil {
+			out = append(out, *e)
+		}
+	}
+
+	return out, nil
+}
+
+func (r *InvoiceRepository) ListByStatuses(userID uint, statuses []entity.InvoiceStatus) ([]entity.Invoice, error) {
+	var rows []pmodel.Invoice
+	if err := r.db.Where("user_id = ? AND status IN ?", userID, statuses).
+		Order("due_date ASC, id ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.Invoice, 0, len(rows))
+	for i := range rows {
+		if e := mapper.InvoiceFromModel(&rows[i]); e != nil {
+			out = append(out, *e)
+		}
+	}
+
+	return out, nil
+}
+
+// AcceptQuote turns a quote into a draft invoice, recording when it was
+// accepted. It fails when the invoice is no longer a quote.
+func (r *InvoiceRepository) AcceptQuote(id uint, at time.Time) error {
+	res := r.db.Model(&pmodel.Invoice{}).
+		Where("id = ? AND status = ?", id, entity.InvoiceStatusQuote).
+		Updates(map[string]interface{}{
+			"status":            entity.InvoiceStatusDraft,
+			"quote_accepted_at": at,
+		})
 	if res.Error != nil {
 		return res.Error
 	}
@@ -189,6 +544,43 @@ func (r *InvoiceRepository) UpdateStatus(id uint, userID uint, status entity.Inv
 	return nil
 }
 
+// RecordView counts a view of the invoice through a share link, keeping the
+// time of the first one.
+func (r *InvoiceRepository) RecordView(id uint, at time.Time) error {
+	return r.db.Model(&pmodel.Invoice{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"view_count":      gorm.Expr("view_count + 1"),
+			"first_viewed_at": gorm.Expr("COALESCE(first_viewed_at, ?)", at),
+		}).Error
+}
+
+func (r *InvoiceRepository) RecordDelivery(delivery *entity.InvoiceDelivery) error {
+	m := mapper.InvoiceDeliveryToModel(delivery)
+	if err := r.db.Create(m).Error; err != nil {
+		return err
+	}
+
+	delivery.ID = m.ID
+	return nil
+}
+
+func (r *InvoiceRepository) ListDeliveries(invoiceID uint) ([]entity.InvoiceDelivery, error) {
+	var rows []pmodel.InvoiceDelivery
+	if err := r.db.Where("invoice_id = ?", invoiceID).
+		Order("sent_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.InvoiceDelivery, 0, len(rows))
+	for i := range rows {
+		out = append(out, *mapper.InvoiceDeliveryFromModel(&rows[i]))
+	}
+
+	return out, nil
+}
+
 func (r *InvoiceRepository) Summary(userID uint, status string) (float64, error) {
 	var total float64
 	cond := "user_id = ?"
@@ -196,6 +588,10 @@ func (r *InvoiceRepository) Summary(userID uint, status string) (float64, error)
 	if status != "" {
 		cond += " AND status = ?"
 		args = append(args, status)
+	} else {
+		// Quotes are not revenue until accepted.
+		cond += " AND status <> ?"
+		args = append(args, entity.InvoiceStatusQuote)
 	}
 
 	if err := r.db.Model(&pmodel.Invoice{}).
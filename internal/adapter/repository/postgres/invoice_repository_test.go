@@ -0,0 +1,235 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	pmodel "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"gorm.io/gorm"
+)
+
+func TestUpdateKeepsLateFeeItems(t *testing.T) {
+	db := testDB(t, &pmodel.User{}, &pmodel.Invoice{}, &pmodel.InvoiceItem{}, &pmodel.LateFee{})
+	inv := testInvoice(t, db, 0)
+	repo := NewInvoiceRepository(db)
+
+	update := entity.Invoice{
+		ID:            inv.ID,
+		UserID:        inv.UserID,
+		InvoiceNumber: inv.InvoiceNumber,
+		IssueDate:     inv.IssueDate,
+		DueDate:       inv.DueDate,
+		Status:        inv.Status,
+		TaxRate:       10,
+		Items:         []entity.InvoiceItem{{Description: "Consulting", Quantity: 2, UnitPrice: 50000}},
+	}
+	if err := repo.Update(update); err != nil {
+		t.Fatal(err)
+	}
+
+	fee := &entity.LateFee{
+		UserID:     inv.UserID,
+		InvoiceID:  inv.ID,
+		PolicyID:   1,
+		Kind:       entity.LateFeeKindInitial,
+		Period:     1,
+		BaseAmount: 110000,
+		Amount:     25000,
+		AppliedAt:  time.Now(),
+	}
+	if err := NewLateFeeRepository(db).ApplyAsLineItem(fee, "Late fee"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both an edit that leaves the fee out and one that sends back the
+	// fetched items, fee included, end with one fee item.
+	got, err := repo.GetByID(inv.ID, inv.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, items := range [][]entity.InvoiceItem{
+		{{Description: "Consulting", Quantity: 3, UnitPrice: 50000}},
+		append(got.Items, entity.InvoiceItem{Description: "Travel", Quantity: 1, UnitPrice: 0}),
+	} {
+		update.Items = items
+		if err := repo.Update(update); err != nil {
+			t.Fatal(err)
+		}
+
+		var stored []pmodel.InvoiceItem
+		if err := db.Where("invoice_id = ?", inv.ID).Find(&stored).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		fees, subtotal := 0, 0.0
+		for _, it := range stored {
+			if it.ID == *fee.InvoiceItemID {
+				fees++
+			}
+
+			subtotal += it.Total
+		}
+
+		if fees != 1 {
+			t.Errorf("%d late fee items after the update, want 1", fees)
+		}
+
+		var m pmodel.Invoice
+		if err := db.First(&m, inv.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		if m.Subtotal != subtotal || m.Tax != subtotal*10/100 || m.Total != subtotal+subtotal*10/100 {
+			t.Errorf("totals %v/%v/%v, items add up to %v", m.Subtotal, m.Tax, m.Total, subtotal)
+		}
+	}
+}
+
+func TestUpdateLeavesOtherUsersInvoices(t *testing.T) {
+	db := testDB(t, &pmodel.User{}, &pmodel.Invoice{}, &pmodel.InvoiceItem{}, &pmodel.LateFee{})
+	inv := testInvoice(t, db, 0)
+	item := pmodel.InvoiceItem{InvoiceID: inv.ID, Description: "Consulting", Quantity: 1, UnitPrice: 100000, Total: 100000}
+	if err := db.Create(&item).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	err := NewInvoiceRepository(db).Update(entity.Invoice{
+		ID:            inv.ID,
+		UserID:        inv.UserID + 1,
+		InvoiceNumber: "INV-9999",
+		IssueDate:     inv.IssueDate,
+		DueDate:       inv.DueDate,
+		Items:         []entity.InvoiceItem{{Description: "Nothing", Quantity: 1, UnitPrice: 1}},
+	})
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Update by another user: %v, want not found", err)
+	}
+
+	var items []pmodel.InvoiceItem
+	if err := db.Where("invoice_id = ?", inv.ID).Find(&items).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 1 || items[0].ID != item.ID {
+		t.Errorf("items are now %+v", items)
+	}
+
+	var got pmodel.Invoice
+	if err := db.First(&got, inv.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if got.InvoiceNumber != inv.InvoiceNumber {
+		t.Errorf("invoice number changed to %s", got.InvoiceNumber)
+	}
+}
+
+// trashedInvoice creates an invoice of the same user as inv, trashed at
+// deletedAt.
+func trashedInvoice(t *testing.T, db *gorm.DB, inv pmodel.Invoice, deletedAt time.Time) pmodel.Invoice {
+	t.Helper()
+	inv.ID = 0
+	inv.DeletedAt = gorm.DeletedAt{Time: deletedAt, Valid: !deletedAt.IsZero()}
+	if err := db.Create(&inv).Error; err != nil {
+		t.Fatalf("create invoice: %v", err)
+	}
+
+	return inv
+}
+
+func TestPurgeDeletedBeforeRemovesDependents(t *testing.T) {
+	db := testDB(t, &pmodel.User{}, &pmodel.Invoice{}, &pmodel.InvoiceItem{}, &pmodel.LateFee{},
+		&pmodel.InvoiceReminder{}, &pmodel.InvoiceDelivery{}, &pmodel.InvoiceShareLink{}, &pmodel.Payment{},
+		&pmodel.PaymentLink{}, &pmodel.PDFJob{}, &pmodel.BankTransaction{}, &pmodel.ReconciliationMatch{})
+	live := testInvoice(t, db, 100000)
+	longAgo := time.Now().AddDate(0, -2, 0)
+	purged := trashedInvoice(t, db, live, longAgo)
+	billed := trashedInvoice(t, db, live, longAgo)
+
+	feeInvoice := live
+	feeInvoice.FeeForInvoiceID = &billed.ID
+	feeInvoice = trashedInvoice(t, db, feeInvoice, time.Time{})
+
+	now := time.Now()
+	for _, row := range []interface{}{
+		&pmodel.InvoiceItem{InvoiceID: purged.ID, Description: "Consulting", Quantity: 1},
+		&pmodel.InvoiceReminder{UserID: live.UserID, InvoiceID: purged.ID, Recipient: "ap@pembeli.co.id", SentAt: now},
+		&pmodel.InvoiceDelivery{UserID: live.UserID, InvoiceID: purged.ID, Recipient: "ap@pembeli.co.id", SentAt: now},
+		&pmodel.InvoiceShareLink{UserID: live.UserID, InvoiceID: purged.ID, Nonce: "n"},
+		&pmodel.Payment{UserID: live.UserID, InvoiceID: purged.ID, Amount: 1, Method: "bank_transfer", PaidAt: now},
+		&pmodel.PaymentLink{UserID: live.UserID, InvoiceID: purged.ID, Provider: "fake", Reference: "r", Amount: 1, Status: "PENDING"},
+		&pmodel.PDFJob{UserID: live.UserID, InvoiceID: purged.ID, Status: "done"},
+		&pmodel.ReconciliationMatch{UserID: live.UserID, TransactionID: 1, InvoiceID: purged.ID, Status: "SUGGESTED"},
+		&pmodel.LateFee{UserID: live.UserID, InvoiceID: purged.ID, PolicyID: 1, Kind: "INITIAL", Period: 1, AppliedAt: now},
+	} {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatalf("create %T: %v", row, err)
+		}
+	}
+
+	txn := pmodel.BankTransaction{UserID: live.UserID, BookedAt: now, Amount: 1, Status: "MATCHED", InvoiceID: &purged.ID, Fingerprint: "f"}
+	if err := db.Create(&txn).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := NewInvoiceRepository(db).PurgeDeletedBefore(time.Now().AddDate(0, -1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 1 {
+		t.Errorf("purged %d invoices, want 1", n)
+	}
+
+	var ids []uint
+	db.Unscoped().Model(&pmodel.Invoice{}).Order("id").Pluck("id", &ids)
+	if len(ids) != 3 || ids[0] != live.ID || ids[1] != billed.ID || ids[2] != feeInvoice.ID {
+		t.Errorf("invoices left %v, want %d, %d and %d", ids, live.ID, billed.ID, feeInvoice.ID)
+	}
+
+	for _, dependent := range []interface{}{
+		&pmodel.InvoiceItem{}, &pmodel.InvoiceReminder{}, &pmodel.InvoiceDelivery{}, &pmodel.InvoiceShareLink{},
+		&pmodel.Payment{}, &pmodel.PaymentLink{}, &pmodel.PDFJob{}, &pmodel.ReconciliationMatch{}, &pmodel.LateFee{},
+	} {
+		var count int64
+		db.Model(dependent).Where("invoice_id = ?", purged.ID).Count(&count)
+		if count != 0 {
+			t.Errorf("%d %T rows left", count, dependent)
+		}
+	}
+
+	db.First(&txn, txn.ID)
+	if txn.InvoiceID != nil || txn.Status != string(entity.BankTransactionUnmatched) {
+		t.Errorf("bank transaction still matched to invoice %v, %s", txn.InvoiceID, txn.Status)
+	}
+}
+
+func TestRestoreByUserIDRestoresOnlyDeactivation(t *testing.T) {
+	db := testDB(t, &pmodel.User{}, &pmodel.Invoice{}, &pmodel.InvoiceItem{})
+	live := testInvoice(t, db, 100000)
+	trashed := trashedInvoice(t, db, live, time.Now().AddDate(0, 0, -1))
+	repo := NewInvoiceRepository(db)
+
+	if err := repo.SoftDeleteByUserID(live.UserID); err != nil {
+		t.Fatal(err)
+	}
+
+	var count int64
+	db.Model(&pmodel.Invoice{}).Where("user_id = ?", live.UserID).Count(&count)
+	if count != 0 {
+		t.Fatalf("%d invoices left after deactivation", count)
+	}
+
+	if err := repo.RestoreByUserID(live.UserID); err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []uint
+	db.Model(&pmodel.Invoice{}).Where("user_id = ?", live.UserID).Pluck("id", &ids)
+	if len(ids) != 1 || ids[0] != live.ID {
+		t.Errorf("restored %v, want only %d; %d was trashed before", ids, live.ID, trashed.ID)
+	}
+}
@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/hutamy/go-invoice-backend/internal/adapter/mapper"
+	pmodel "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	"gorm.io/gorm"
+)
+
+type LateFeeRepository struct {
+	db *gorm.DB
+}
+
+func NewLateFeeRepository(db *gorm.DB) ports.LateFeeRepository {
+	return &LateFeeRepository{
+		db: db,
+	}
+}
+
+func (r *LateFeeRepository) policyScope(db *gorm.DB, userID uint, clientID *uint) *gorm.DB {
+	db = db.Where("user_id = ?", userID)
+	if clientID == nil {
+		return db.Where("client_id IS NULL")
+	}
+
+	return db.Where("client_id = ?", *clientID)
+}
+
+func (r *LateFeeRepository) GetPolicy(userID uint, clientID *uint) (*entity.LateFeePolicy, error) {
+	var m pmodel.LateFeePolicy
+	err := r.policyScope(r.db, userID, clientID).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.LateFeePolicyFromModel(&m), nil
+}
+
+// SavePolicy creates or replaces the policy for the user/client pair.
+func (r *LateFeeRepository) SavePolicy(policy *entity.LateFeePolicy) error {
+	existing, err := r.GetPolicy(policy.UserID, policy.ClientID)
+	if err != nil {
+		return err
+	}
+
+	m := mapper.LateFeePolicyToModel(policy)
+	if existing != nil {
+		m.ID = existing.ID
+	}
+
+	if err := r.db.Save(m).Error; err != nil {
+		return err
+	}
+
+	policy.ID = m.ID
+	return nil
+}
+
+func (r *LateFeeRepository) DeletePolicy(userID uint, clientID *uint) error {
+	res := r.policyScope(r.db, userID, clientID).Delete(&pmodel.LateFeePolicy{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *LateFeeRepository) ListByInvoice(invoiceID uint) ([]entity.LateFee, error) {
+	var rows []pmodel.LateFee
+	if err := r.db.Where("invoice_id = ?", invoiceID).
+		Order("period ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.LateFee, 0, len(rows))
+	for i := range rows {
+		out = append(out, *mapper.LateFeeFromModel(&rows[i]))
+	}
+
+	return out, nil
+}
+
+// ApplyAsLineItem adds the fee to its invoice as a line item, recalculates
+// the invoice totals and records the fee, all in one transaction.
+func (r *LateFeeRepository) ApplyAsLineItem(fee *entity.LateFee, description string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var inv pmodel.Invoice
+		if err := tx.Preload("Items").First(&inv, fee.InvoiceID).Error; err != nil {
+			return err
+		}
+
+		item := pmodel.InvoiceItem{
+			InvoiceID:   inv.ID,
+			Description: description,
+			Quantity:    1,
+			UnitPrice:   fee.Amount,
+			Total:       fee.Amount,
+		}
+		if err := tx.Create(&item).Error; err != nil {
+			return err
+		}
+
+		subtotal := item.Total
+		for _, it := range inv.Items {
+			subtotal += it.Total
+		}
+
+		tax := subtotal * inv.TaxRate / 100
+		if err := tx.Model(&pmodel.Invoice{}).
+			Where("id = ?", inv.ID).
+			Updates(map[string]interface{}{
+				"subtotal": subtotal,
+				"tax":      tax,
+				"total":    subtotal + tax + inv.DeliveryFee,
+			}).Error; err != nil {
+			return err
+		}
+
+		fee.InvoiceItemID = &item.ID
+		m := mapper.LateFeeToModel(fee)
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+
+		fee.ID = m.ID
+		return nil
+	})
+}
+
+// ApplyAsInvoice stores feeInvoice and records the fee against the original
+// invoice in one transaction.
+func (r *LateFeeRepository) ApplyAsInvoice(fee *entity.LateFee, feeInvoice *entity.Invoice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		im := mapper.InvoiceToModel(feeInvoice)
+		if err := tx.Create(im).Error; err != nil {
+			return err
+		}
+
+		feeInvoice.ID = im.ID
+		fee.FeeInvoiceID = &im.ID
+		m := mapper.LateFeeToModel(fee)
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+
+		fee.ID = m.ID
+		return nil
+	})
+}
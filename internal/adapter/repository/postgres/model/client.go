@@ -7,13 +7,19 @@ import (
 )
 
 type Client struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	UserID    uint           `json:"user_id" gorm:"not null;index"`
-	Name      string         `json:"name" gorm:"not null;"`
-	Email     string         `json:"email"`
-	Phone     string         `json:"phone"`
-	Address   string         `json:"address"`
-	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index" swaggerignore:"true"`
+	ID               uint           `json:"id" gorm:"primaryKey"`
+	UserID           uint           `json:"user_id" gorm:"not null;index"`
+	Name             string         `json:"name" gorm:"not null;"`
+	Email            string         `json:"email"`
+	Phone            string         `json:"phone"`
+	Address          string         `json:"address"`
+	PaymentTerms     string         `json:"payment_terms"`
+	PaymentTermsDays int            `json:"payment_terms_days" gorm:"not null;default:0"`
+	Language         string         `json:"language" gorm:"not null;default:''"`
+	NPWP             string         `json:"npwp" gorm:"not null;default:''"`
+	NITKU            string         `json:"nitku" gorm:"not null;default:''"`
+	CreatedAt        time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index" swaggerignore:"true"`
+	DeletedWithUser  bool           `json:"-" gorm:"not null;default:false" swaggerignore:"true"` // trashed by deactivating the account
 }
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type EmailTemplate struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_email_template_user_kind"`
+	Kind      string    `json:"kind" gorm:"not null;uniqueIndex:idx_email_template_user_kind"`
+	Subject   string    `json:"subject" gorm:"not null"`
+	Body      string    `json:"body" gorm:"type:text;not null"`
+	HTMLBody  string    `json:"html_body" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -7,27 +7,39 @@ import (
 )
 
 type Invoice struct {
-	ID            uint           `json:"id" gorm:"primaryKey"`
-	UserID        uint           `json:"user_id" gorm:"not null;index"`
-	ClientID      *uint          `json:"client_id" gorm:"index"`
-	ClientName    *string        `json:"client_name"`
-	ClientEmail   *string        `json:"client_email"`
-	ClientAddress *string        `json:"client_address"`
-	ClientPhone   *string        `json:"client_phone"`
-	InvoiceNumber string         `json:"invoice_number" gorm:"not null"`
-	IssueDate     time.Time      `json:"issue_date" gorm:"not null"`
-	DueDate       time.Time      `json:"due_date" gorm:"not null"`
-	Status        string         `json:"status" gorm:"not null;default:'draft'"`
-	Notes         string         `json:"notes" gorm:"type:text"`
-	Subtotal      float64        `json:"subtotal" gorm:"not null;default:0"`
-	Tax           float64        `json:"tax" gorm:"not null;default:0"`
-	TaxRate       float64        `json:"tax_rate" gorm:"not null;default:0"`
-	DeliveryFee   float64        `json:"delivery_fee"`
-	Total         float64        `json:"total" gorm:"not null;default:0"`
-	Items         []InvoiceItem  `json:"items" gorm:"foreignKey:InvoiceID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	CreatedAt     time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt     time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index" swaggerignore:"true"`
+	ID                uint           `json:"id" gorm:"primaryKey"`
+	UserID            uint           `json:"user_id" gorm:"not null;index"`
+	ClientID          *uint          `json:"client_id" gorm:"index"`
+	ClientName        *string        `json:"client_name"`
+	ClientEmail       *string        `json:"client_email"`
+	ClientAddress     *string        `json:"client_address"`
+	ClientPhone       *string        `json:"client_phone"`
+	InvoiceNumber     string         `json:"invoice_number" gorm:"not null"`
+	IssueDate         time.Time      `json:"issue_date" gorm:"not null"`
+	DueDate           time.Time      `json:"due_date" gorm:"not null"`
+	PaymentTerms      string         `json:"payment_terms"`
+	PaymentTermsDays  int            `json:"payment_terms_days" gorm:"not null;default:0"`
+	Status            string         `json:"status" gorm:"not null;default:'draft'"`
+	Notes             string         `json:"notes" gorm:"type:text"`
+	Subtotal          float64        `json:"subtotal" gorm:"not null;default:0"`
+	Tax               float64        `json:"tax" gorm:"not null;default:0"`
+	TaxRate           float64        `json:"tax_rate" gorm:"not null;default:0"`
+	DeliveryFee       float64        `json:"delivery_fee"`
+	Total             float64        `json:"total" gorm:"not null;default:0"`
+	AmountPaid        float64        `json:"amount_paid" gorm:"not null;default:0"`
+	FeeForInvoiceID   *uint          `json:"fee_for_invoice_id" gorm:"index"`
+	RemindersDisabled bool           `json:"reminders_disabled" gorm:"not null;default:false"`
+	FirstViewedAt     *time.Time     `json:"first_viewed_at"`
+	ViewCount         int            `json:"view_count" gorm:"not null;default:0"`
+	QuoteAcceptedAt   *time.Time     `json:"quote_accepted_at"`
+	Language          string         `json:"language" gorm:"not null;default:''"`
+	AmountInWords     bool           `json:"amount_in_words" gorm:"not null;default:false"`
+	TaxSerial         uint64         `json:"tax_serial" gorm:"not null;default:0;index"`
+	Items             []InvoiceItem  `json:"items" gorm:"foreignKey:InvoiceID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	CreatedAt         time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index" swaggerignore:"true"`
+	DeletedWithUser   bool           `json:"-" gorm:"not null;default:false" swaggerignore:"true"` // trashed by deactivating the account
 
 	// Relationship
 	User   User    `json:"user" gorm:"foreignKey:UserID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
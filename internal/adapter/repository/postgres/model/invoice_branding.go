@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type InvoiceBranding struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"not null;uniqueIndex"`
+	Layout      string    `json:"layout" gorm:"not null"`
+	Template    string    `json:"template" gorm:"type:text"`
+	AccentColor string    `json:"accent_color" gorm:"not null"`
+	Font        string    `json:"font" gorm:"not null"`
+	FooterText  string    `json:"footer_text" gorm:"type:text"`
+	Logo        string    `json:"logo" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
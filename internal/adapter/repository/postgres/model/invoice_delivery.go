@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+type InvoiceDelivery struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	InvoiceID uint      `json:"invoice_id" gorm:"not null;index"`
+	Recipient string    `json:"recipient" gorm:"not null"`
+	Subject   string    `json:"subject"`
+	SentAt    time.Time `json:"sent_at" gorm:"not null"`
+	Error     string    `json:"error" gorm:"type:text"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LateFeePolicy struct {
+	ID                  uint           `json:"id" gorm:"primaryKey"`
+	UserID              uint           `json:"user_id" gorm:"not null;index"`
+	ClientID            *uint          `json:"client_id" gorm:"index"`
+	Enabled             bool           `json:"enabled" gorm:"not null;default:true"`
+	Type                string         `json:"type" gorm:"not null"`
+	Amount              float64        `json:"amount" gorm:"not null;default:0"`
+	GraceDays           int            `json:"grace_days" gorm:"not null;default:0"`
+	MonthlyInterestRate float64        `json:"monthly_interest_rate" gorm:"not null;default:0"`
+	Cap                 float64        `json:"cap" gorm:"not null;default:0"`
+	Mode                string         `json:"mode" gorm:"not null"`
+	CreatedAt           time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt           time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt           gorm.DeletedAt `json:"-" gorm:"index" swaggerignore:"true"`
+}
+
+type LateFee struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	UserID        uint      `json:"user_id" gorm:"not null;index"`
+	InvoiceID     uint      `json:"invoice_id" gorm:"not null;uniqueIndex:idx_late_fee_invoice_period"`
+	PolicyID      uint      `json:"policy_id" gorm:"not null"`
+	Kind          string    `json:"kind" gorm:"not null"`
+	Period        int       `json:"period" gorm:"not null;uniqueIndex:idx_late_fee_invoice_period"`
+	BaseAmount    float64   `json:"base_amount" gorm:"not null"`
+	Amount        float64   `json:"amount" gorm:"not null"`
+	InvoiceItemID *uint     `json:"invoice_item_id"`
+	FeeInvoiceID  *uint     `json:"fee_invoice_id"`
+	AppliedAt     time.Time `json:"applied_at" gorm:"not null"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+type Payment struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	InvoiceID uint      `json:"invoice_id" gorm:"not null;index"`
+	Amount    float64   `json:"amount" gorm:"not null"`
+	Method    string    `json:"method" gorm:"not null"`
+	Provider  string    `json:"provider"`
+	Reference string    `json:"reference"`
+	PaidAt    time.Time `json:"paid_at" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+type PaymentLink struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"not null;index"`
+	InvoiceID  uint       `json:"invoice_id" gorm:"not null;index"`
+	Provider   string     `json:"provider" gorm:"not null;uniqueIndex:idx_payment_link_provider_reference"`
+	Reference  string     `json:"reference" gorm:"not null;uniqueIndex:idx_payment_link_provider_reference"`
+	ExternalID string     `json:"external_id"`
+	URL        string     `json:"url" gorm:"type:text"`
+	Amount     float64    `json:"amount" gorm:"not null"`
+	Status     string     `json:"status" gorm:"not null"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+type PaymentEvent struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Provider   string    `json:"provider" gorm:"not null;uniqueIndex:idx_payment_event_provider_event"`
+	EventID    string    `json:"event_id" gorm:"not null;uniqueIndex:idx_payment_event_provider_event"`
+	Reference  string    `json:"reference" gorm:"index"`
+	Status     string    `json:"status" gorm:"not null"`
+	Payload    string    `json:"payload" gorm:"type:text"`
+	ReceivedAt time.Time `json:"received_at" gorm:"autoCreateTime"`
+}
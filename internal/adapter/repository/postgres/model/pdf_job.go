@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type PDFJob struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"not null;index"`
+	InvoiceID  uint       `json:"invoice_id" gorm:"not null;index"`
+	Status     string     `json:"status" gorm:"not null;index"`
+	BlobKey    string     `json:"blob_key"`
+	Cached     bool       `json:"cached" gorm:"not null;default:false"`
+	Attempts   int        `json:"attempts" gorm:"not null;default:0"`
+	Error      string     `json:"error" gorm:"type:text"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+type BankTransaction struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	UserID        uint      `json:"user_id" gorm:"not null;index;uniqueIndex:idx_bank_transaction_user_fingerprint"`
+	BookedAt      time.Time `json:"booked_at" gorm:"not null"`
+	Amount        float64   `json:"amount" gorm:"not null"`
+	Currency      string    `json:"currency"`
+	Reference     string    `json:"reference"`
+	Description   string    `json:"description" gorm:"type:text"`
+	Counterparty  string    `json:"counterparty"`
+	BankReference string    `json:"bank_reference"`
+	Status        string    `json:"status" gorm:"not null;index"`
+	InvoiceID     *uint     `json:"invoice_id"`
+	PaymentID     *uint     `json:"payment_id"`
+	Fingerprint   string    `json:"-" gorm:"not null;uniqueIndex:idx_bank_transaction_user_fingerprint"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+type ReconciliationMatch struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	UserID        uint       `json:"user_id" gorm:"not null;index"`
+	TransactionID uint       `json:"transaction_id" gorm:"not null;uniqueIndex:idx_reconciliation_match_transaction_invoice"`
+	InvoiceID     uint       `json:"invoice_id" gorm:"not null;uniqueIndex:idx_reconciliation_match_transaction_invoice"`
+	InvoiceNumber string     `json:"invoice_number"`
+	Score         int        `json:"score" gorm:"not null"`
+	Reasons       string     `json:"reasons"`
+	Status        string     `json:"status" gorm:"not null;index"`
+	DecidedAt     *time.Time `json:"decided_at"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+type ReminderRule struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	UserID     uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_reminder_rule_user_offset"`
+	OffsetDays int       `json:"offset_days" gorm:"not null;uniqueIndex:idx_reminder_rule_user_offset"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+type InvoiceReminder struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	UserID     uint      `json:"user_id" gorm:"not null;index"`
+	InvoiceID  uint      `json:"invoice_id" gorm:"not null;index"`
+	OffsetDays int       `json:"offset_days" gorm:"not null"`
+	Recipient  string    `json:"recipient"`
+	SentAt     time.Time `json:"sent_at" gorm:"not null"`
+	Error      string    `json:"error" gorm:"type:text"`
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+type InvoiceShareLink struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	InvoiceID uint       `json:"invoice_id" gorm:"not null;index"`
+	Nonce     string     `json:"-" gorm:"not null"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+type TaxSerialRange struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	First     uint64    `json:"first" gorm:"not null"`
+	Last      uint64    `json:"last" gorm:"not null"`
+	Next      uint64    `json:"next" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
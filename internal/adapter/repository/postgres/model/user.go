@@ -7,16 +7,22 @@ import (
 )
 
 type User struct {
-	ID                uint           `json:"id" gorm:"primaryKey"`
-	Name              string         `json:"name" gorm:"not null"`
-	Email             string         `json:"email" gorm:"not null;uniqueIndex"`
-	Password          string         `json:"-" gorm:"not null"`
-	Address           string         `json:"address"`
-	Phone             string         `json:"phone"`
-	BankName          string         `json:"bank_name"`
-	BankAccountName   string         `json:"bank_account_name"`
-	BankAccountNumber string         `json:"bank_account_number"`
-	CreatedAt         time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt         time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index" swaggerignore:"true"`
+	ID                uint   `json:"id" gorm:"primaryKey"`
+	Name              string `json:"name" gorm:"not null"`
+	Email             string `json:"email" gorm:"not null;uniqueIndex"`
+	Password          string `json:"-" gorm:"not null"`
+	Address           string `json:"address"`
+	Phone             string `json:"phone"`
+	BankName          string `json:"bank_name"`
+	BankAccountName   string `json:"bank_account_name"`
+	BankAccountNumber string `json:"bank_account_number"`
+	QRISPayload       string `json:"qris_payload"`
+	NPWP              string `json:"npwp" gorm:"not null;default:''"`
+	NITKU             string `json:"nitku" gorm:"not null;default:''"`
+
+	PaymentTerms     string         `json:"payment_terms" gorm:"not null;default:'NET_30'"`
+	PaymentTermsDays int            `json:"payment_terms_days" gorm:"not null;default:0"`
+	CreatedAt        time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt        time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index" swaggerignore:"true"`
 }
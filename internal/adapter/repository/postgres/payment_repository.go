@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/hutamy/go-invoice-backend/internal/adapter/mapper"
+	pmodel "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PaymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) ports.PaymentRepository {
+	return &PaymentRepository{
+		db: db,
+	}
+}
+
+func (r *PaymentRepository) CreateLink(link *entity.PaymentLink) error {
+	m := mapper.PaymentLinkToModel(link)
+	if err := r.db.Create(m).Error; err != nil {
+		return err
+	}
+
+	link.ID = m.ID
+	link.CreatedAt = m.CreatedAt
+	return nil
+}
+
+func (r *PaymentRepository) GetLinkByReference(provider, reference string) (*entity.PaymentLink, error) {
+	var m pmodel.PaymentLink
+	err := r.db.Where("provider = ? AND reference = ?", provider, reference).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.PaymentLinkFromModel(&m), nil
+}
+
+func (r *PaymentRepository) ListLinks(invoiceID uint) ([]entity.PaymentLink, error) {
+	var rows []pmodel.PaymentLink
+	if err := r.db.Where("invoice_id = ?", invoiceID).
+		Order("created_at DESC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.PaymentLink, 0, len(rows))
+	for i := range rows {
+		out = append(out, *mapper.PaymentLinkFromModel(&rows[i]))
+	}
+
+	return out, nil
+}
+
+func (r *PaymentRepository) ListPayments(invoiceID uint) ([]entity.Payment, error) {
+	var rows []pmodel.Payment
+	if err := r.db.Where("invoice_id = ?", invoiceID).
+		Order("paid_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.Payment, 0, len(rows))
+	for i := range rows {
+		out = append(out, *mapper.PaymentFromModel(&rows[i]))
+	}
+
+	return out, nil
+}
+
+// RecordPayment stores the payment and updates the amount paid on its
+// invoice, marking the invoice PAID once it is covered, in one transaction.
+func (r *PaymentRepository) RecordPayment(payment *entity.Payment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return recordPayment(tx, payment)
+	})
+}
+
+// ApplyEvent records a gateway event once. Redeliveries of an event already
+// recorded return false and change nothing. With a payment, the payment is
+// recorded and link marked paid in the same transaction. A link already
+// paid is left as it is and records no further payment, since gateways
+// report one payment in several events, such as Midtrans capture and
+// settlement.
+func (r *PaymentRepository) ApplyEvent(event *entity.PaymentEvent, link *entity.PaymentLink, payment *entity.Payment) (bool, error) {
+	applied := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(mapper.PaymentEventToModel(event))
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		applied = true
+		if link != nil && link.Status != "" {
+			res := tx.Model(&pmodel.PaymentLink{}).
+				Where("id = ? AND status <> ?", link.ID, entity.PaymentLinkStatusPaid).
+				Update("status", link.Status)
+			if res.Error != nil {
+				return res.Error
+			}
+
+			if res.RowsAffected == 0 {
+				return nil
+			}
+		}
+
+		if payment == nil {
+			return nil
+		}
+
+		return recordPayment(tx, payment)
+	})
+
+	return applied, err
+}
+
+func recordPayment(tx *gorm.DB, payment *entity.Payment) error {
+	var inv pmodel.Invoice
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ?", payment.InvoiceID, payment.UserID).
+		First(&inv).Error; err != nil {
+		return err
+	}
+
+	m := mapper.PaymentToModel(payment)
+	if err := tx.Create(m).Error; err != nil {
+		return err
+	}
+
+	payment.ID = m.ID
+	updates := map[string]interface{}{
+		"amount_paid": inv.AmountPaid + payment.Amount,
+	}
+
+	// Allow for rounding in amounts received from gateways and banks.
+	if inv.AmountPaid+payment.Amount >= inv.Total-0.005 {
+		updates["status"] = entity.InvoiceStatusPaid
+	}
+
+	return tx.Model(&pmodel.Invoice{}).
+		Where("id = ?", inv.ID).
+		Updates(updates).Error
+}
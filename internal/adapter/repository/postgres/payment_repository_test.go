@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	pmodel "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+func TestApplyEventIgnoresRedeliveredEvents(t *testing.T) {
+	db := testDB(t, &pmodel.User{}, &pmodel.Invoice{}, &pmodel.InvoiceItem{}, &pmodel.Payment{}, &pmodel.PaymentLink{}, &pmodel.PaymentEvent{})
+	inv := testInvoice(t, db, 150000)
+	repo := NewPaymentRepository(db)
+
+	link := &entity.PaymentLink{
+		UserID:    inv.UserID,
+		InvoiceID: inv.ID,
+		Provider:  "fake",
+		Reference: "inv-1-1",
+		Amount:    150000,
+		Status:    entity.PaymentLinkStatusPending,
+	}
+	if err := repo.CreateLink(link); err != nil {
+		t.Fatal(err)
+	}
+
+	// A partial payment, so applying it twice would also flip the status.
+	deliver := func() bool {
+		t.Helper()
+		update := *link
+		update.Status = entity.PaymentLinkStatusPaid
+		applied, err := repo.ApplyEvent(&entity.PaymentEvent{
+			Provider:      "fake",
+			EventID:       "evt_1",
+			Reference:     link.Reference,
+			TransactionID: "txn_1",
+			Status:        entity.PaymentEventPaid,
+			Amount:        100000,
+		}, &update, &entity.Payment{
+			UserID:    inv.UserID,
+			InvoiceID: inv.ID,
+			Amount:    100000,
+			Method:    entity.PaymentMethodGateway,
+			Provider:  "fake",
+			Reference: "txn_1",
+			PaidAt:    time.Now(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return applied
+	}
+
+	if !deliver() {
+		t.Fatal("first delivery was not applied")
+	}
+
+	if deliver() {
+		t.Fatal("redelivered event was applied again")
+	}
+
+	payments, err := repo.ListPayments(inv.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(payments) != 1 {
+		t.Errorf("got %d payments, want 1", len(payments))
+	}
+
+	var got pmodel.Invoice
+	if err := db.First(&got, inv.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if got.AmountPaid != 100000 || got.Status != "sent" {
+		t.Errorf("invoice paid %.2f and %s, want 100000.00 and sent", got.AmountPaid, got.Status)
+	}
+
+	var events int64
+	db.Model(&pmodel.PaymentEvent{}).Count(&events)
+	if events != 1 {
+		t.Errorf("got %d events, want 1", events)
+	}
+}
+
+func TestApplyEventRecordsOnePaymentPerLink(t *testing.T) {
+	db := testDB(t, &pmodel.User{}, &pmodel.Invoice{}, &pmodel.InvoiceItem{}, &pmodel.Payment{}, &pmodel.PaymentLink{}, &pmodel.PaymentEvent{})
+	inv := testInvoice(t, db, 150000)
+	repo := NewPaymentRepository(db)
+
+	link := &entity.PaymentLink{
+		UserID:    inv.UserID,
+		InvoiceID: inv.ID,
+		Provider:  "midtrans",
+		Reference: "inv-1-1",
+		Amount:    150000,
+		Status:    entity.PaymentLinkStatusPending,
+	}
+	if err := repo.CreateLink(link); err != nil {
+		t.Fatal(err)
+	}
+
+	// Midtrans reports a card payment as capture and then settlement, two
+	// events for the one payment.
+	for _, status := range []string{"capture", "settlement"} {
+		update := *link
+		update.Status = entity.PaymentLinkStatusPaid
+		applied, err := repo.ApplyEvent(&entity.PaymentEvent{
+			Provider:      "midtrans",
+			EventID:       "txn_1:" + status,
+			Reference:     link.Reference,
+			TransactionID: "txn_1",
+			Status:        entity.PaymentEventPaid,
+			Amount:        150000,
+		}, &update, &entity.Payment{
+			UserID:    inv.UserID,
+			InvoiceID: inv.ID,
+			Amount:    150000,
+			Method:    entity.PaymentMethodGateway,
+			Provider:  "midtrans",
+			Reference: "txn_1",
+			PaidAt:    time.Now(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !applied {
+			t.Errorf("%s event was not recorded", status)
+		}
+	}
+
+	payments, err := repo.ListPayments(inv.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(payments) != 1 {
+		t.Errorf("got %d payments, want 1", len(payments))
+	}
+
+	var got pmodel.Invoice
+	if err := db.First(&got, inv.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if got.AmountPaid != 150000 || got.Status != string(entity.InvoiceStatusPaid) {
+		t.Errorf("invoice paid %.2f and %s, want 150000.00 and PAID", got.AmountPaid, got.Status)
+	}
+}
@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/adapter/mapper"
+	pmodel "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PDFJobRepository struct {
+	db *gorm.DB
+}
+
+func NewPDFJobRepository(db *gorm.DB) ports.PDFJobRepository {
+	return &PDFJobRepository{
+		db: db,
+	}
+}
+
+func (r *PDFJobRepository) Create(job *entity.PDFJob) error {
+	m := mapper.PDFJobToModel(job)
+	if err := r.db.Create(m).Error; err != nil {
+		return err
+	}
+
+	job.ID = m.ID
+	job.CreatedAt = m.CreatedAt
+	return nil
+}
+
+func (r *PDFJobRepository) GetByID(id, userID uint) (*entity.PDFJob, error) {
+	var m pmodel.PDFJob
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.PDFJobFromModel(&m), nil
+}
+
+func (r *PDFJobRepository) GetActive(invoiceID, userID uint) (*entity.PDFJob, error) {
+	var m pmodel.PDFJob
+	err := r.db.Where("invoice_id = ? AND user_id = ? AND status IN ?", invoiceID, userID,
+		[]string{string(entity.PDFJobQueued), string(entity.PDFJobRunning)}).
+		Order("id DESC").
+		First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.PDFJobFromModel(&m), nil
+}
+
+// ClaimNext locks the job it picks with SKIP LOCKED, so several workers, in
+// this process or others, never claim the same job.
+func (r *PDFJobRepository) ClaimNext(staleBefore time.Time) (*entity.PDFJob, error) {
+	var claimed *entity.PDFJob
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var m pmodel.PDFJob
+		res := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND started_at < ?)",
+				string(entity.PDFJobQueued), string(entity.PDFJobRunning), staleBefore).
+			Order("id").
+			Limit(1).
+			Find(&m)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+
+		now := time.Now()
+		m.Status = string(entity.PDFJobRunning)
+		m.StartedAt = &now
+		m.Attempts++
+		if err := tx.Save(&m).Error; err != nil {
+			return err
+		}
+
+		claimed = mapper.PDFJobFromModel(&m)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+func (r *PDFJobRepository) Finish(job entity.PDFJob) error {
+	res := r.db.Model(&pmodel.PDFJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]any{
+			"status":      string(job.Status),
+			"blob_key":    job.BlobKey,
+			"cached":      job.Cached,
+			"error":       job.Error,
+			"finished_at": job.FinishedAt,
+		})
+
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *PDFJobRepository) ExpireByInvoice(invoiceID, userID uint) error {
+	q := r.db.Model(&pmodel.PDFJob{}).
+		Where("user_id = ? AND status = ?", userID, string(entity.PDFJobDone))
+	if invoiceID != 0 {
+		q = q.Where("invoice_id = ?", invoiceID)
+	}
+
+	return q.Update("status", string(entity.PDFJobExpired)).Error
+}
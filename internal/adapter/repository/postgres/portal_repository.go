@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/adapter/mapper"
+	pmodel "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	"gorm.io/gorm"
+)
+
+type PortalRepository struct {
+	db *gorm.DB
+}
+
+func NewPortalRepository(db *gorm.DB) ports.PortalRepository {
+	return &PortalRepository{
+		db: db,
+	}
+}
+
+func (r *PortalRepository) CreateMagicLink(link *entity.PortalMagicLink) error {
+	m := mapper.PortalMagicLinkToModel(link)
+	if err := r.db.Create(m).Error; err != nil {
+		return err
+	}
+
+	link.ID = m.ID
+	return nil
+}
+
+// ConsumeMagicLink marks the unused, unexpired link with tokenHash as used
+// and returns it, or nil when there is no such link. The update is
+// conditional so a link cannot be used twice.
+func (r *PortalRepository) ConsumeMagicLink(tokenHash string, at time.Time) (*entity.PortalMagicLink, error) {
+	var m pmodel.PortalMagicLink
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, at).
+			First(&m).Error; err != nil {
+			return err
+		}
+
+		res := tx.Model(&pmodel.PortalMagicLink{}).
+			Where("id = ? AND used_at IS NULL", m.ID).
+			Update("used_at", at)
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		m.UsedAt = &at
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.PortalMagicLinkFromModel(&m), nil
+}
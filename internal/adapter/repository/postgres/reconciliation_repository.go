@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/adapter/mapper"
+	pmodel "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReconciliationRepository struct {
+	db *gorm.DB
+}
+
+func NewReconciliationRepository(db *gorm.DB) ports.ReconciliationRepository {
+	return &ReconciliationRepository{
+		db: db,
+	}
+}
+
+// ImportTransactions stores the transactions not imported before, going by
+// their fingerprint, and returns the ones it stored.
+func (r *ReconciliationRepository) ImportTransactions(txs []entity.BankTransaction) ([]entity.BankTransaction, error) {
+	var imported []entity.BankTransaction
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range txs {
+			m := mapper.BankTransactionToModel(&txs[i])
+			res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(m)
+			if res.Error != nil {
+				return res.Error
+			}
+
+			if res.RowsAffected == 0 {
+				continue
+			}
+
+			imported = append(imported, *mapper.BankTransactionFromModel(m))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return imported, nil
+}
+
+func (r *ReconciliationRepository) ListTransactions(userID uint, status entity.BankTransactionStatus) ([]entity.BankTransaction, error) {
+	q := r.db.Where("user_id = ?", userID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+
+	var rows []pmodel.BankTransaction
+	if err := q.Order("booked_at DESC, id DESC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.BankTransaction, 0, len(rows))
+	for i := range rows {
+		out = append(out, *mapper.BankTransactionFromModel(&rows[i]))
+	}
+
+	return out, nil
+}
+
+func (r *ReconciliationRepository) GetTransaction(id, userID uint) (*entity.BankTransaction, error) {
+	var m pmodel.BankTransaction
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.BankTransactionFromModel(&m), nil
+}
+
+// SaveMatches stores new suggestions and returns how many were stored.
+// Pairs suggested before, including rejected ones, are not suggested again.
+func (r *ReconciliationRepository) SaveMatches(matches []entity.ReconciliationMatch) (int, error) {
+	saved := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range matches {
+			m := mapper.ReconciliationMatchToModel(&matches[i])
+			res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(m)
+			if res.Error != nil {
+				return res.Error
+			}
+
+			saved += int(res.RowsAffected)
+		}
+
+		return nil
+	})
+
+	return saved, err
+}
+
+// ListMatches returns the user's matches, best first, with their
+// transactions attached.
+func (r *ReconciliationRepository) ListMatches(userID uint, status entity.MatchStatus) ([]entity.ReconciliationMatch, error) {
+	q := r.db.Where("user_id = ?", userID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+
+	var rows []pmodel.ReconciliationMatch
+	if err := q.Order("transaction_id DESC, score DESC, id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(rows))
+	for _, m := range rows {
+		ids = append(ids, m.TransactionID)
+	}
+
+	var txRows []pmodel.BankTransaction
+	if len(ids) > 0 {
+		if err := r.db.Where("id IN ?", ids).Find(&txRows).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	txs := make(map[uint]*entity.BankTransaction, len(txRows))
+	for i := range txRows {
+		txs[txRows[i].ID] = mapper.BankTransactionFromModel(&txRows[i])
+	}
+
+	out := make([]entity.ReconciliationMatch, 0, len(rows))
+	for i := range rows {
+		m := mapper.ReconciliationMatchFromModel(&rows[i])
+		m.Transaction = txs[m.TransactionID]
+		out = append(out, *m)
+	}
+
+	return out, nil
+}
+
+func (r *ReconciliationRepository) GetMatch(id, userID uint) (*entity.ReconciliationMatch, error) {
+	var m pmodel.ReconciliationMatch
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.ReconciliationMatchFromModel(&m), nil
+}
+
+// AcceptMatch records payment for the match, marks its transaction matched
+// and rejects the other suggestions for the transaction, in one
+// transaction. It fails with gorm.ErrRecordNotFound when the match was
+// already decided or its transaction already matched.
+func (r *ReconciliationRepository) AcceptMatch(match *entity.ReconciliationMatch, payment *entity.Payment, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&pmodel.ReconciliationMatch{}).
+			Where("id = ? AND status = ?", match.ID, entity.MatchSuggested).
+			Updates(map[string]any{
+				"status":     entity.MatchAccepted,
+				"decided_at": at,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := recordPayment(tx, payment); err != nil {
+			return err
+		}
+
+		res = tx.Model(&pmodel.BankTransaction{}).
+			Where("id = ? AND status = ?", match.TransactionID, entity.BankTransactionUnmatched).
+			Updates(map[string]any{
+				"status":     entity.BankTransactionMatched,
+				"invoice_id": match.InvoiceID,
+				"payment_id": payment.ID,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Model(&pmodel.ReconciliationMatch{}).
+			Where("transaction_id = ? AND status = ?", match.TransactionID, entity.MatchSuggested).
+			Updates(map[string]any{
+				"status":     entity.MatchRejected,
+				"decided_at": at,
+			}).Error
+	})
+}
+
+func (r *ReconciliationRepository) RejectMatch(id, userID uint, at time.Time) error {
+	res := r.db.Model(&pmodel.ReconciliationMatch{}).
+		Where("id = ? AND user_id = ? AND status = ?", id, userID, entity.MatchSuggested).
+		Updates(map[string]any{
+			"status":     entity.MatchRejected,
+			"decided_at": at,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"github.com/hutamy/go-invoice-backend/internal/adapter/mapper"
+	pmodel "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	"gorm.io/gorm"
+)
+
+type ReminderRepository struct {
+	db *gorm.DB
+}
+
+func NewReminderRepository(db *gorm.DB) ports.ReminderRepository {
+	return &ReminderRepository{
+		db: db,
+	}
+}
+
+func (r *ReminderRepository) ListRules(userID uint) ([]entity.ReminderRule, error) {
+	var rows []pmodel.ReminderRule
+	if err := r.db.Where("user_id = ?", userID).
+		Order("offset_days ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.ReminderRule, 0, len(rows))
+	for i := range rows {
+		out = append(out, *mapper.ReminderRuleFromModel(&rows[i]))
+	}
+
+	return out, nil
+}
+
+// ReplaceRules swaps the user's whole reminder sequence for offsets in one
+// transaction.
+func (r *ReminderRepository) ReplaceRules(userID uint, offsets []int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&pmodel.ReminderRule{}).Error; err != nil {
+			return err
+		}
+
+		if len(offsets) == 0 {
+			return nil
+		}
+
+		rows := make([]pmodel.ReminderRule, 0, len(offsets))
+		for _, offset := range offsets {
+			rows = append(rows, pmodel.ReminderRule{UserID: userID, OffsetDays: offset})
+		}
+
+		return tx.Create(&rows).Error
+	})
+}
+
+func (r *ReminderRepository) ListByInvoice(invoiceID uint) ([]entity.InvoiceReminder, error) {
+	var rows []pmodel.InvoiceReminder
+	if err := r.db.Where("invoice_id = ?", invoiceID).
+		Order("sent_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.InvoiceReminder, 0, len(rows))
+	for i := range rows {
+		out = append(out, *mapper.InvoiceReminderFromModel(&rows[i]))
+	}
+
+	return out, nil
+}
+
+func (r *ReminderRepository) Record(reminder *entity.InvoiceReminder) error {
+	m := mapper.InvoiceReminderToModel(reminder)
+	if err := r.db.Create(m).Error; err != nil {
+		return err
+	}
+
+	reminder.ID = m.ID
+	return nil
+}
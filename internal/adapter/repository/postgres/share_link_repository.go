@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/adapter/mapper"
+	pmodel "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	"gorm.io/gorm"
+)
+
+type ShareLinkRepository struct {
+	db *gorm.DB
+}
+
+func NewShareLinkRepository(db *gorm.DB) ports.ShareLinkRepository {
+	return &ShareLinkRepository{
+		db: db,
+	}
+}
+
+func (r *ShareLinkRepository) Create(link *entity.InvoiceShareLink) error {
+	m := mapper.InvoiceShareLinkToModel(link)
+	if err := r.db.Create(m).Error; err != nil {
+		return err
+	}
+
+	link.ID = m.ID
+	link.CreatedAt = m.CreatedAt
+	return nil
+}
+
+func (r *ShareLinkRepository) GetByID(id uint) (*entity.InvoiceShareLink, error) {
+	var m pmodel.InvoiceShareLink
+	err := r.db.First(&m, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.InvoiceShareLinkFromModel(&m), nil
+}
+
+func (r *ShareLinkRepository) ListByInvoice(invoiceID uint) ([]entity.InvoiceShareLink, error) {
+	var rows []pmodel.InvoiceShareLink
+	if err := r.db.Where("invoice_id = ?", invoiceID).
+		Order("created_at DESC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.InvoiceShareLink, 0, len(rows))
+	for i := range rows {
+		out = append(out, *mapper.InvoiceShareLinkFromModel(&rows[i]))
+	}
+
+	return out, nil
+}
+
+func (r *ShareLinkRepository) Revoke(id, invoiceID uint, at time.Time) error {
+	res := r.db.Model(&pmodel.InvoiceShareLink{}).
+		Where("id = ? AND invoice_id = ? AND revoked_at IS NULL", id, invoiceID).
+		Update("revoked_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/adapter/mapper"
+	pmodel "github.com/hutamy/go-invoice-backend/internal/adapter/repository/postgres/model"
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TaxInvoiceRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxInvoiceRepository(db *gorm.DB) ports.TaxInvoiceRepository {
+	return &TaxInvoiceRepository{
+		db: db,
+	}
+}
+
+func (r *TaxInvoiceRepository) CreateSerialRange(rng *entity.TaxSerialRange) error {
+	m := mapper.TaxSerialRangeToModel(rng)
+	if err := r.db.Create(m).Error; err != nil {
+		return err
+	}
+
+	rng.ID = m.ID
+	rng.CreatedAt = m.CreatedAt
+	return nil
+}
+
+func (r *TaxInvoiceRepository) ListSerialRanges(userID uint) ([]entity.TaxSerialRange, error) {
+	var rows []pmodel.TaxSerialRange
+	if err := r.db.Where("user_id = ?", userID).
+		Order("first").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.TaxSerialRange, 0, len(rows))
+	for i := range rows {
+		out = append(out, *mapper.TaxSerialRangeFromModel(&rows[i]))
+	}
+
+	return out, nil
+}
+
+// DeleteUnusedSerialRange deletes a range none of whose serials have been
+// assigned, as assigned serials are reported to DJP.
+func (r *TaxInvoiceRepository) DeleteUnusedSerialRange(id, userID uint) error {
+	res := r.db.Where("id = ? AND user_id = ? AND next = first", id, userID).
+		Delete(&pmodel.TaxSerialRange{})
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// AssignSerial gives the invoice the next serial of the user's lowest range
+// with serials left, and returns the serial the invoice already has if any.
+// Both rows are locked so that concurrent assignments never share a serial.
+func (r *TaxInvoiceRepository) AssignSerial(invoiceID, userID uint) (entity.TaxSerial, error) {
+	var serial entity.TaxSerial
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var inv pmodel.Invoice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", invoiceID, userID).
+			First(&inv).Error; err != nil {
+			return err
+		}
+
+		if inv.TaxSerial != 0 {
+			serial = entity.TaxSerial(inv.TaxSerial)
+			return nil
+		}
+
+		var rng pmodel.TaxSerialRange
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND next <= last", userID).
+			Order("first").
+			First(&rng).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.ErrNoTaxSerial
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&pmodel.TaxSerialRange{}).
+			Where("id = ?", rng.ID).
+			Update("next", rng.Next+1).Error; err != nil {
+			return err
+		}
+
+		serial = entity.TaxSerial(rng.Next)
+		return tx.Model(&pmodel.Invoice{}).
+			Where("id = ?", inv.ID).
+			Update("tax_serial", rng.Next).Error
+	})
+
+	return serial, err
+}
+
+// ListByIssueDate returns the user's invoices with a tax invoice serial issued
+// from from up to, not including, to, with their items and clients.
+func (r *TaxInvoiceRepository) ListByIssueDate(userID uint, from, to time.Time) ([]entity.Invoice, error) {
+	var rows []pmodel.Invoice
+	if err := r.db.Where("user_id = ? AND tax_serial <> 0 AND issue_date >= ? AND issue_date < ?", userID, from, to).
+		Preload("Items").
+		Preload("Client", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("tax_serial").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.Invoice, 0, len(rows))
+	for i := range rows {
+		if e := mapper.InvoiceFromModel(&rows[i]); e != nil {
+			out = append(out, *e)
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,59 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/hutamy/go-invoice-backend/config"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+var errInvalidSignature = errors.New("invalid token")
+
+// HMACSigner signs payloads with HMAC-SHA256 into tokens of the form
+// base64url(payload).base64url(mac).
+type HMACSigner struct{}
+
+func NewHMACSigner() ports.Signer {
+	return &HMACSigner{}
+}
+
+func (HMACSigner) Sign(payload string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(payload)) + "." + enc.EncodeToString(mac([]byte(payload)))
+}
+
+func (HMACSigner) Verify(token string) (string, error) {
+	enc := base64.RawURLEncoding
+	rawPayload, rawMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", errInvalidSignature
+	}
+
+	payload, err := enc.DecodeString(rawPayload)
+	if err != nil {
+		return "", errInvalidSignature
+	}
+
+	sig, err := enc.DecodeString(rawMAC)
+	if err != nil || !hmac.Equal(sig, mac(payload)) {
+		return "", errInvalidSignature
+	}
+
+	return string(payload), nil
+}
+
+func mac(payload []byte) []byte {
+	cfg := config.GetConfig()
+	secret := cfg.ShareLinkSecret
+	if secret == "" {
+		secret = cfg.JwtSecret
+	}
+
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(payload)
+	return h.Sum(nil)
+}
@@ -0,0 +1,68 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hutamy/go-invoice-backend/config"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+const portalTokenType = "portal"
+
+var errInvalidPortalToken = errors.New("invalid token")
+
+type PortalTokenService struct{}
+
+func NewPortalTokenService() ports.PortalTokenService {
+	return &PortalTokenService{}
+}
+
+func (PortalTokenService) Generate(clientID, userID uint, ttl time.Duration) (string, error) {
+	claims := jwt.MapClaims{
+		"typ":       portalTokenType,
+		"client_id": clientID,
+		"user_id":   userID,
+		"exp":       time.Now().Add(ttl).Unix(),
+		"iat":       time.Now().Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(portalSecret())
+}
+
+func (PortalTokenService) Parse(token string) (uint, uint, error) {
+	t, err := jwt.Parse(token, func(tok *jwt.Token) (interface{}, error) {
+		if _, ok := tok.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrTokenUnverifiable
+		}
+		return portalSecret(), nil
+	})
+	if err != nil || !t.Valid {
+		return 0, 0, errInvalidPortalToken
+	}
+
+	claims := t.Claims.(jwt.MapClaims)
+	clientID, okClient := claims["client_id"].(float64)
+	userID, okUser := claims["user_id"].(float64)
+	if claims["typ"] != portalTokenType || !okClient || !okUser {
+		return 0, 0, errInvalidPortalToken
+	}
+
+	return uint(clientID), uint(userID), nil
+}
+
+// portalSecret returns PORTAL_TOKEN_SECRET, or a key derived from the JWT
+// secret so portal tokens never verify as user tokens.
+func portalSecret() []byte {
+	cfg := config.GetConfig()
+	if cfg.PortalTokenSecret != "" {
+		return []byte(cfg.PortalTokenSecret)
+	}
+
+	h := hmac.New(sha256.New, []byte(cfg.JwtSecret))
+	h.Write([]byte("client-portal"))
+	return h.Sum(nil)
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFilesystemStore(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	store := NewFilesystemStore(root)
+
+	if err := store.Put(ctx, "pdfs/1/INV-0001.pdf", []byte("%PDF-1.7"), "application/pdf"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	data, err := store.Get(ctx, "pdfs/1/INV-0001.pdf")
+	if err != nil || string(data) != "%PDF-1.7" {
+		t.Fatalf("Get: %q, %v", data, err)
+	}
+
+	// Put replaces the blob and leaves no temporary files behind.
+	if err := store.Put(ctx, "pdfs/1/INV-0001.pdf", []byte("%PDF-2.0"), "application/pdf"); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, "pdfs", "1"))
+	if err != nil || len(entries) != 1 {
+		t.Errorf("directory holds %d entries, %v; want 1", len(entries), err)
+	}
+
+	if data, _ := store.Get(ctx, "pdfs/1/INV-0001.pdf"); string(data) != "%PDF-2.0" {
+		t.Errorf("Get after replacing: %q", data)
+	}
+
+	if ok, err := store.Exists(ctx, "pdfs/1/INV-0001.pdf"); !ok || err != nil {
+		t.Errorf("Exists: %v, %v", ok, err)
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		data, err := store.Get(ctx, "pdfs/missing.pdf")
+		if data != nil || err != nil {
+			t.Errorf("Get: %q, %v; want nil, nil", data, err)
+		}
+
+		if ok, err := store.Exists(ctx, "pdfs/missing.pdf"); ok || err != nil {
+			t.Errorf("Exists: %v, %v; want false, nil", ok, err)
+		}
+	})
+
+	t.Run("delete prefix", func(t *testing.T) {
+		for _, k := range []string{"pdfs/2/a.pdf", "pdfs/2/b/c.pdf", "pdfs/20/a.pdf"} {
+			if err := store.Put(ctx, k, []byte(k), "application/pdf"); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := store.DeletePrefix(ctx, "pdfs/2/"); err != nil {
+			t.Fatalf("DeletePrefix: %v", err)
+		}
+
+		for k, want := range map[string]bool{"pdfs/2/a.pdf": false, "pdfs/2/b/c.pdf": false, "pdfs/20/a.pdf": true, "pdfs/1/INV-0001.pdf": true} {
+			if ok, _ := store.Exists(ctx, k); ok != want {
+				t.Errorf("%s exists: %v, want %v", k, ok, want)
+			}
+		}
+
+		if err := store.DeletePrefix(ctx, "pdfs"); err == nil {
+			t.Error("DeletePrefix accepted a prefix without a trailing slash")
+		}
+	})
+
+	t.Run("keys outside the root", func(t *testing.T) {
+		for _, key := range []string{"", "/etc/passwd", "../secret", "pdfs/../../secret", "pdfs//a.pdf", "pdfs/./a.pdf", `pdfs\a.pdf`} {
+			if err := store.Put(ctx, key, []byte("x"), "text/plain"); err == nil {
+				t.Errorf("Put(%q) succeeded", key)
+			}
+
+			if _, err := store.Get(ctx, key); err == nil {
+				t.Errorf("Get(%q) succeeded", key)
+			}
+		}
+
+		if err := store.DeletePrefix(ctx, "../"); err == nil {
+			t.Error("DeletePrefix(\"../\") succeeded")
+		}
+
+		if _, err := os.Stat(filepath.Join(filepath.Dir(root), "secret")); !os.IsNotExist(err) {
+			t.Error("a blob was written outside the root")
+		}
+	})
+}
@@ -1,10 +1,18 @@
 package entity
 
+import "time"
+
 type Client struct {
-	ID      uint   `json:"id"`
-	UserID  uint   `json:"user_id"`
-	Name    string `json:"name"`
-	Email   string `json:"email"`
-	Phone   string `json:"phone"`
-	Address string `json:"address"`
+	ID               uint         `json:"id"`
+	UserID           uint         `json:"user_id"`
+	Name             string       `json:"name"`
+	Email            string       `json:"email"`
+	Phone            string       `json:"phone"`
+	Address          string       `json:"address"`
+	PaymentTerms     PaymentTerms `json:"payment_terms"` // empty inherits the user's default
+	PaymentTermsDays int          `json:"payment_terms_days"`
+	Language         string       `json:"language"` // invoice language, empty for English
+	NPWP             string       `json:"npwp"`     // 16-digit taxpayer number, empty for buyers without one
+	NITKU            string       `json:"nitku"`
+	DeletedAt        *time.Time   `json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,34 @@
+package entity
+
+type ImportRowStatus string
+
+const (
+	ImportRowStatusValid   ImportRowStatus = "VALID"
+	ImportRowStatusCreated ImportRowStatus = "CREATED"
+	ImportRowStatusSkipped ImportRowStatus = "SKIPPED"
+	ImportRowStatusFailed  ImportRowStatus = "FAILED"
+)
+
+type ClientImportRow struct {
+	Row    int
+	Client Client
+	Error  string
+}
+
+type ClientImportResult struct {
+	Row    int             `json:"row"`
+	Name   string          `json:"name"`
+	Email  string          `json:"email"`
+	Status ImportRowStatus `json:"status"`
+	Error  string          `json:"error,omitempty"`
+}
+
+type ClientImportReport struct {
+	DryRun  bool                 `json:"dry_run"`
+	Total   int                  `json:"total"`
+	Created int                  `json:"created"`
+	Valid   int                  `json:"valid"`
+	Skipped int                  `json:"skipped"`
+	Failed  int                  `json:"failed"`
+	Rows    []ClientImportResult `json:"rows"`
+}
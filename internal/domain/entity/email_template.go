@@ -0,0 +1,244 @@
+package entity
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+type EmailTemplateKind string
+
+const (
+	EmailTemplateInvoiceSent EmailTemplateKind = "INVOICE_SENT"
+	EmailTemplateReminder    EmailTemplateKind = "REMINDER"
+	EmailTemplateReceipt     EmailTemplateKind = "RECEIPT"
+	EmailTemplateQuote       EmailTemplateKind = "QUOTE"
+)
+
+var EmailTemplateKinds = []EmailTemplateKind{
+	EmailTemplateInvoiceSent,
+	EmailTemplateReminder,
+	EmailTemplateReceipt,
+	EmailTemplateQuote,
+}
+
+func (k EmailTemplateKind) IsValid() bool {
+	for _, kind := range EmailTemplateKinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EmailTemplate is a user's wording for one kind of email. Subject and Body
+// are text/template strings and HTMLBody an optional html/template string,
+// all executed with EmailTemplateData. Values in HTMLBody are escaped for
+// their HTML context.
+type EmailTemplate struct {
+	ID       uint              `json:"id,omitempty"`
+	UserID   uint              `json:"user_id,omitempty"`
+	Kind     EmailTemplateKind `json:"kind"`
+	Subject  string            `json:"subject"`
+	Body     string            `json:"body"`
+	HTMLBody string            `json:"html_body"`
+	Custom   bool              `json:"custom"`
+}
+
+// EmailTemplateData holds the variables available to email templates.
+type EmailTemplateData struct {
+	ClientName    string
+	InvoiceNumber string
+	Total         string
+	IssueDate     string
+	DueDate       string
+	DueStatus     string // e.g. "is due in 3 days", "is due today", "is 7 days overdue"
+	PayLink       string // empty until the invoice can be paid online
+	SenderName    string
+	SenderEmail   string
+}
+
+type RenderedEmail struct {
+	Subject  string `json:"subject"`
+	Body     string `json:"body"`
+	HTMLBody string `json:"html_body"`
+}
+
+var defaultEmailTemplates = map[EmailTemplateKind]EmailTemplate{
+	EmailTemplateInvoiceSent: {
+		Subject: "Invoice {{.InvoiceNumber}} from {{.SenderName}}",
+		Body: `Hi {{.ClientName}},
+
+Please find attached invoice {{.InvoiceNumber}} for {{.Total}}, due on {{.DueDate}}.
+{{if .PayLink}}
+You can pay online at {{.PayLink}}
+{{end}}
+Thank you,
+{{.SenderName}}`,
+	},
+	EmailTemplateReminder: {
+		Subject: "Reminder: invoice {{.InvoiceNumber}} {{.DueStatus}}",
+		Body: `Hi {{.ClientName}},
+
+This is a friendly reminder that invoice {{.InvoiceNumber}} for {{.Total}} {{.DueStatus}}. It was due on {{.DueDate}}.
+{{if .PayLink}}
+You can pay online at {{.PayLink}}
+{{end}}
+Thank you,
+{{.SenderName}}`,
+	},
+	EmailTemplateReceipt: {
+		Subject: "Receipt for invoice {{.InvoiceNumber}}",
+		Body: `Hi {{.ClientName}},
+
+Thank you for your payment of {{.Total}} for invoice {{.InvoiceNumber}}. The paid invoice is attached for your records.
+
+{{.SenderName}}`,
+	},
+	EmailTemplateQuote: {
+		Subject: "Quote {{.InvoiceNumber}} from {{.SenderName}}",
+		Body: `Hi {{.ClientName}},
+
+Please find attached quote {{.InvoiceNumber}} for {{.Total}}, valid until {{.DueDate}}.
+
+Kind regards,
+{{.SenderName}}`,
+	},
+}
+
+// DefaultEmailTemplate returns the built-in wording for kind.
+func DefaultEmailTemplate(kind EmailTemplateKind) EmailTemplate {
+	t := defaultEmailTemplates[kind]
+	t.Kind = kind
+	return t
+}
+
+// Render executes the template with data. An empty HTMLBody renders an
+// empty HTML body, and the email is sent as plain text only.
+func (t EmailTemplate) Render(data EmailTemplateData) (RenderedEmail, error) {
+	var out RenderedEmail
+	var err error
+	if out.Subject, err = executeText("subject", t.Subject, data); err != nil {
+		return out, err
+	}
+
+	// Headers cannot span lines.
+	out.Subject = strings.Join(strings.Fields(out.Subject), " ")
+
+	if out.Body, err = executeText("body", t.Body, data); err != nil {
+		return out, err
+	}
+
+	if strings.TrimSpace(t.HTMLBody) == "" {
+		return out, nil
+	}
+
+	tmpl, err := htmltemplate.New("html_body").Parse(t.HTMLBody)
+	if err != nil {
+		return out, fmt.Errorf("invalid html_body template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return out, fmt.Errorf("invalid html_body template: %w", err)
+	}
+
+	out.HTMLBody = buf.String()
+	return out, nil
+}
+
+func executeText(name, text string, data EmailTemplateData) (string, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", name, err)
+	}
+
+	return buf.String(), nil
+}
+
+// NewEmailTemplateData returns the template variables for an invoice sent by
+// user, with DueStatus relative to now and PayLink the newest of links that
+// can still be paid.
+func NewEmailTemplateData(invoice *Invoice, user *User, links []PaymentLink, now time.Time) EmailTemplateData {
+	p := message.NewPrinter(language.English)
+	data := EmailTemplateData{
+		InvoiceNumber: invoice.InvoiceNumber,
+		Total:         p.Sprintf("%.2f", invoice.Total),
+		IssueDate:     invoice.IssueDate.Format("02 Jan 2006"),
+		DueDate:       invoice.DueDate.Format("02 Jan 2006"),
+		DueStatus:     DueStatus(invoice.DueDate, now),
+		SenderName:    user.Name,
+		SenderEmail:   user.Email,
+	}
+	if invoice.ClientName != nil {
+		data.ClientName = *invoice.ClientName
+	}
+
+	var newest *PaymentLink
+	for i, link := range links {
+		if link.Status != PaymentLinkStatusPending || link.URL == "" {
+			continue
+		}
+
+		if link.ExpiresAt != nil && !link.ExpiresAt.After(now) {
+			continue
+		}
+
+		if newest == nil || link.CreatedAt.After(newest.CreatedAt) {
+			newest = &links[i]
+		}
+	}
+
+	if newest != nil {
+		data.PayLink = newest.URL
+	}
+
+	return data
+}
+
+// SampleEmailTemplateData returns made-up variables for previews.
+func SampleEmailTemplateData(user *User) EmailTemplateData {
+	now := time.Now()
+	return EmailTemplateData{
+		ClientName:    "Jane Doe",
+		InvoiceNumber: "INV-0001",
+		Total:         "1,250.00",
+		IssueDate:     now.Format("02 Jan 2006"),
+		DueDate:       now.AddDate(0, 0, 30).Format("02 Jan 2006"),
+		DueStatus:     "is due in 30 days",
+		PayLink:       "https://example.com/pay/INV-0001",
+		SenderName:    user.Name,
+		SenderEmail:   user.Email,
+	}
+}
+
+// DueStatus describes dueDate relative to the day of now.
+func DueStatus(dueDate, now time.Time) string {
+	today := now.UTC().Truncate(24 * time.Hour)
+	due := dueDate.UTC().Truncate(24 * time.Hour)
+	days := int(due.Sub(today).Hours() / 24)
+	switch {
+	case days == 1:
+		return "is due tomorrow"
+	case days > 1:
+		return fmt.Sprintf("is due in %d days", days)
+	case days == 0:
+		return "is due today"
+	case days == -1:
+		return "is 1 day overdue"
+	default:
+		return fmt.Sprintf("is %d days overdue", -days)
+	}
+}
@@ -1,41 +1,77 @@
 package entity
 
-import (
-	"time"
-
-	"gorm.io/gorm"
-)
+import "time"
 
 type InvoiceStatus string
 
 const (
-	InvoiceStatusDraft InvoiceStatus = "DRAFT"
-	InvoiceStatusSent  InvoiceStatus = "SENT"
-	InvoiceStatusPaid  InvoiceStatus = "PAID"
+	InvoiceStatusDraft   InvoiceStatus = "DRAFT"
+	InvoiceStatusSent    InvoiceStatus = "SENT"
+	InvoiceStatusOverdue InvoiceStatus = "OVERDUE"
+	InvoiceStatusPaid    InvoiceStatus = "PAID"
+	// InvoiceStatusQuote marks a quote awaiting the client's acceptance, after
+	// which it becomes a draft invoice.
+	InvoiceStatusQuote InvoiceStatus = "QUOTE"
 )
 
+var invoiceStatusTransitions = map[InvoiceStatus][]InvoiceStatus{
+	InvoiceStatusQuote:   {InvoiceStatusDraft},
+	InvoiceStatusDraft:   {InvoiceStatusQuote, InvoiceStatusSent, InvoiceStatusPaid},
+	InvoiceStatusSent:    {InvoiceStatusDraft, InvoiceStatusOverdue, InvoiceStatusPaid},
+	InvoiceStatusOverdue: {InvoiceStatusSent, InvoiceStatusPaid},
+	InvoiceStatusPaid:    {InvoiceStatusSent},
+}
+
+// CanTransitionTo reports whether an invoice in status s may be moved to next.
+func (s InvoiceStatus) CanTransitionTo(next InvoiceStatus) bool {
+	for _, allowed := range invoiceStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsValid reports whether s is one of the known invoice statuses.
+func (s InvoiceStatus) IsValid() bool {
+	_, ok := invoiceStatusTransitions[s]
+	return ok
+}
+
 type Invoice struct {
-	ID            uint           `json:"id"`
-	UserID        uint           `json:"user_id"`
-	ClientID      *uint          `json:"client_id"`
-	ClientName    *string        `json:"client_name"`
-	ClientEmail   *string        `json:"client_email"`
-	ClientAddress *string        `json:"client_address"`
-	ClientPhone   *string        `json:"client_phone"`
-	InvoiceNumber string         `json:"invoice_number"`
-	IssueDate     time.Time      `json:"issue_date"`
-	DueDate       time.Time      `json:"due_date"`
-	Status        string         `json:"status"`
-	Notes         string         `json:"notes"`
-	Subtotal      float64        `json:"subtotal"`
-	Tax           float64        `json:"tax"`
-	TaxRate       float64        `json:"tax_rate"`
-	DeliveryFee   float64        `json:"delivery_fee"`
-	Total         float64        `json:"total"`
-	Items         []InvoiceItem  `json:"items"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	DeletedAt     gorm.DeletedAt `json:"-"`
+	ID                uint          `json:"id"`
+	UserID            uint          `json:"user_id"`
+	ClientID          *uint         `json:"client_id"`
+	ClientName        *string       `json:"client_name"`
+	ClientEmail       *string       `json:"client_email"`
+	ClientAddress     *string       `json:"client_address"`
+	ClientPhone       *string       `json:"client_phone"`
+	InvoiceNumber     string        `json:"invoice_number"`
+	IssueDate         time.Time     `json:"issue_date"`
+	DueDate           time.Time     `json:"due_date"`
+	PaymentTerms      PaymentTerms  `json:"payment_terms"`
+	PaymentTermsDays  int           `json:"payment_terms_days"`
+	Status            string        `json:"status"`
+	Notes             string        `json:"notes"`
+	Subtotal          float64       `json:"subtotal"`
+	Tax               float64       `json:"tax"`
+	TaxRate           float64       `json:"tax_rate"`
+	DeliveryFee       float64       `json:"delivery_fee"`
+	Total             float64       `json:"total"`
+	AmountPaid        float64       `json:"amount_paid"`
+	FeeForInvoiceID   *uint         `json:"fee_for_invoice_id,omitempty"`
+	RemindersDisabled bool          `json:"reminders_disabled"`
+	FirstViewedAt     *time.Time    `json:"first_viewed_at,omitempty"`
+	ViewCount         int           `json:"view_count"`
+	QuoteAcceptedAt   *time.Time    `json:"quote_accepted_at,omitempty"`
+	Language          string        `json:"language"` // empty uses the client's language
+	AmountInWords     bool          `json:"amount_in_words"`
+	TaxSerial         TaxSerial     `json:"tax_serial,omitempty"` // NSFP of the invoice's faktur pajak, once assigned
+	Items             []InvoiceItem `json:"items"`
+	CreatedAt         time.Time     `json:"created_at"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+	DeletedAt         *time.Time    `json:"deleted_at,omitempty"`
 
 	// Relationship
 	User   User
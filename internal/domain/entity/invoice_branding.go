@@ -0,0 +1,227 @@
+package entity
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"regexp"
+	"strings"
+)
+
+type InvoiceLayout string
+
+const (
+	InvoiceLayoutClassic InvoiceLayout = "classic"
+	InvoiceLayoutModern  InvoiceLayout = "modern"
+	InvoiceLayoutCompact InvoiceLayout = "compact"
+	InvoiceLayoutCustom  InvoiceLayout = "custom"
+)
+
+var InvoiceLayouts = []InvoiceLayout{
+	InvoiceLayoutClassic,
+	InvoiceLayoutModern,
+	InvoiceLayoutCompact,
+	InvoiceLayoutCustom,
+}
+
+func (l InvoiceLayout) IsValid() bool {
+	for _, layout := range InvoiceLayouts {
+		if l == layout {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InvoiceFonts maps the fonts users can pick to their CSS font stacks.
+var InvoiceFonts = map[string]string{
+	"Inter":     `"Inter", "Segoe UI", sans-serif`,
+	"Helvetica": `"Helvetica Neue", Helvetica, Arial, sans-serif`,
+	"Georgia":   `Georgia, "Times New Roman", serif`,
+	"Roboto":    `"Roboto", "Segoe UI", sans-serif`,
+	"Courier":   `"Courier New", Courier, monospace`,
+}
+
+// Limits on branding settings, to keep them cheap to store and render.
+const (
+	MaxInvoiceTemplateSize = 64 << 10
+	MaxInvoiceLogoSize     = 256 << 10
+	MaxInvoiceFooterLength = 500
+)
+
+var accentColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// InvoiceBranding is how a user's invoices look. Layout picks one of the
+// built-in designs, or "custom" to render Template, an html/template string
+// executed with InvoiceTemplateData. Logo is a data URI of a PNG or JPEG
+// image.
+type InvoiceBranding struct {
+	UserID      uint          `json:"user_id,omitempty"`
+	Layout      InvoiceLayout `json:"layout"`
+	Template    string        `json:"template"`
+	AccentColor string        `json:"accent_color"`
+	Font        string        `json:"font"`
+	FooterText  string        `json:"footer_text"`
+	Logo        string        `json:"logo"`
+	Custom      bool          `json:"custom"`
+}
+
+// DefaultInvoiceBranding returns the look of invoices for users who have not
+// set their own.
+func DefaultInvoiceBranding() InvoiceBranding {
+	return InvoiceBranding{
+		Layout:      InvoiceLayoutClassic,
+		AccentColor: "#111827",
+		Font:        "Inter",
+	}
+}
+
+// Validate checks the settings, except that a custom template renders,
+// which needs an invoice to render.
+func (b InvoiceBranding) Validate() error {
+	if !b.Layout.IsValid() {
+		return fmt.Errorf("unknown layout %q", b.Layout)
+	}
+
+	if b.Layout == InvoiceLayoutCustom && strings.TrimSpace(b.Template) == "" {
+		return errors.New("template is required for the custom layout")
+	}
+
+	if len(b.Template) > MaxInvoiceTemplateSize {
+		return fmt.Errorf("template must be at most %d bytes", MaxInvoiceTemplateSize)
+	}
+
+	if !accentColorPattern.MatchString(b.AccentColor) {
+		return errors.New("accent_color must be a hex color such as #1d4ed8")
+	}
+
+	if _, ok := InvoiceFonts[b.Font]; !ok {
+		return fmt.Errorf("unknown font %q", b.Font)
+	}
+
+	if len([]rune(b.FooterText)) > MaxInvoiceFooterLength {
+		return fmt.Errorf("footer_text must be at most %d characters", MaxInvoiceFooterLength)
+	}
+
+	if b.Logo != "" {
+		if _, _, err := b.LogoImage(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LogoImage decodes the logo data URI into its image bytes and MIME type.
+func (b InvoiceBranding) LogoImage() ([]byte, string, error) {
+	rest, ok := strings.CutPrefix(b.Logo, "data:")
+	if !ok {
+		return nil, "", errors.New("logo must be a data URI")
+	}
+
+	mime, encoded, ok := strings.Cut(rest, ";base64,")
+	if !ok || (mime != "image/png" && mime != "image/jpeg") {
+		return nil, "", errors.New("logo must be a base64 encoded PNG or JPEG image")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, "", errors.New("logo is not valid base64")
+	}
+
+	if len(data) > MaxInvoiceLogoSize {
+		return nil, "", fmt.Errorf("logo must be at most %d KB", MaxInvoiceLogoSize>>10)
+	}
+
+	return data, mime, nil
+}
+
+// FontStack returns the CSS font stack for the branding font.
+func (b InvoiceBranding) FontStack() string {
+	if stack, ok := InvoiceFonts[b.Font]; ok {
+		return stack
+	}
+
+	return InvoiceFonts["Inter"]
+}
+
+// InvoiceTemplateData holds the variables available to custom invoice
+// templates. Everything is preformatted text in the invoice's language, so
+// templates cannot reach past the invoice being rendered.
+type InvoiceTemplateData struct {
+	Language      string // "en" or "id"
+	Labels        InvoiceTemplateLabels
+	InvoiceNumber string
+	Status        string
+	IssueDate     string
+	DueDate       string
+	PaymentTerms  string
+	Currency      string
+	Sender        InvoiceTemplateParty
+	Client        InvoiceTemplateParty
+	Items         []InvoiceTemplateItem
+	Subtotal      string
+	TaxRate       string
+	Tax           string
+	DeliveryFee   string // empty when there is no delivery fee
+	Total         string
+	AmountInWords string // empty unless the invoice asks for it
+	Notes         string
+	Bank          InvoiceTemplateBank
+	QRISImage     htmltemplate.URL // data URI, empty when QRIS is not set up
+	Logo          htmltemplate.URL // data URI, empty without a logo
+	AccentColor   htmltemplate.CSS
+	Font          htmltemplate.CSS
+	FooterText    string
+}
+
+// InvoiceTemplateLabels holds the fixed text of an invoice translated into
+// its language.
+type InvoiceTemplateLabels struct {
+	Title           string
+	Heading         string
+	IssueDate       string
+	DueDate         string
+	PaymentTerms    string
+	From            string
+	To              string
+	Description     string
+	Quantity        string
+	UnitPrice       string
+	Total           string
+	Subtotal        string
+	Tax             string // includes the tax rate
+	DeliveryFee     string
+	TotalDue        string
+	AmountInWords   string
+	Terms           string
+	ThankYou        string
+	ForYourBusiness string
+	BankDetails     string
+	BankName        string
+	AccountName     string
+	AccountNumber   string
+	ScanToPay       string
+}
+
+type InvoiceTemplateParty struct {
+	Name    string
+	Address string
+	Email   string
+	Phone   string
+}
+
+type InvoiceTemplateItem struct {
+	Description string
+	Quantity    string
+	UnitPrice   string
+	Total       string
+}
+
+type InvoiceTemplateBank struct {
+	Name          string
+	AccountName   string
+	AccountNumber string
+}
@@ -0,0 +1,17 @@
+package entity
+
+type InvoiceBulkAction string
+
+const (
+	InvoiceBulkActionStatus    InvoiceBulkAction = "status"
+	InvoiceBulkActionDelete    InvoiceBulkAction = "delete"
+	InvoiceBulkActionDuplicate InvoiceBulkAction = "duplicate"
+	InvoiceBulkActionExportPDF InvoiceBulkAction = "export_pdf"
+)
+
+type InvoiceBulkResult struct {
+	ID           uint   `json:"id"`
+	Success      bool   `json:"success"`
+	Error        string `json:"error,omitempty"`
+	NewInvoiceID uint   `json:"new_invoice_id,omitempty"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+// InvoiceDocument is everything a PDF renderer needs to print an invoice.
+// HTML is the invoice rendered with the sender's branding, for renderers
+// that print HTML; QRISPayload is the dynamic QRIS payload to print as a QR code,
+// empty when the invoice is not payable by QRIS. Language is the language the
+// invoice is printed in. Archive asks for a PDF/A-3 document when set.
+type InvoiceDocument struct {
+	Language    string
+	Invoice     Invoice
+	Sender      User
+	Client      Client
+	Branding    InvoiceBranding
+	HTML        string
+	QRISPayload string
+	Archive     *InvoiceArchive
+}
+
+// InvoiceArchive is the machine-readable invoice a PDF/A-3 document embeds,
+// making it a Factur-X (ZUGFeRD) e-invoice.
+type InvoiceArchive struct {
+	// XML is the invoice in UN/CEFACT Cross Industry Invoice syntax.
+	XML []byte
+	// Profile is the Factur-X conformance level the XML follows.
+	Profile string
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// InvoiceExportFilter picks the invoices to export. From and To bound the
+// issue date, both inclusive; zero values do not filter.
+type InvoiceExportFilter struct {
+	From     time.Time
+	To       time.Time
+	Status   InvoiceStatus
+	ClientID *uint
+}
@@ -0,0 +1,25 @@
+package entity
+
+type InvoiceImportRecord struct {
+	Rows    []int
+	Invoice Invoice
+	Client  Client
+	Error   string
+}
+
+type InvoiceImportResult struct {
+	InvoiceNumber string          `json:"invoice_number"`
+	Rows          []int           `json:"rows"`
+	Status        ImportRowStatus `json:"status"`
+	InvoiceID     uint            `json:"invoice_id,omitempty"`
+	ClientID      uint            `json:"client_id,omitempty"`
+	Error         string          `json:"error,omitempty"`
+}
+
+type InvoiceImportReport struct {
+	Total    int                   `json:"total"`
+	Created  int                   `json:"created"`
+	Skipped  int                   `json:"skipped"`
+	Failed   int                   `json:"failed"`
+	Invoices []InvoiceImportResult `json:"invoices"`
+}
@@ -0,0 +1,119 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+type LateFeeType string
+
+const (
+	LateFeeTypeFixed      LateFeeType = "FIXED"
+	LateFeeTypePercentage LateFeeType = "PERCENTAGE"
+)
+
+type LateFeeMode string
+
+const (
+	LateFeeModeLineItem   LateFeeMode = "LINE_ITEM"
+	LateFeeModeFeeInvoice LateFeeMode = "FEE_INVOICE"
+)
+
+type LateFeeKind string
+
+const (
+	LateFeeKindInitial  LateFeeKind = "INITIAL"
+	LateFeeKindInterest LateFeeKind = "INTEREST"
+)
+
+// LateFeePolicy describes how overdue invoices are charged. A policy with a
+// ClientID overrides the user's default policy for that client.
+type LateFeePolicy struct {
+	ID                  uint        `json:"id"`
+	UserID              uint        `json:"user_id"`
+	ClientID            *uint       `json:"client_id"`
+	Enabled             bool        `json:"enabled"`
+	Type                LateFeeType `json:"type"`
+	Amount              float64     `json:"amount"`
+	GraceDays           int         `json:"grace_days"`
+	MonthlyInterestRate float64     `json:"monthly_interest_rate"`
+	Cap                 float64     `json:"cap"`
+	Mode                LateFeeMode `json:"mode"`
+}
+
+// LateFee is the audit record of a single fee charged on an invoice. Period
+// is 0 for the initial fee and n for the interest of the nth overdue month.
+type LateFee struct {
+	ID            uint        `json:"id"`
+	UserID        uint        `json:"user_id"`
+	InvoiceID     uint        `json:"invoice_id"`
+	PolicyID      uint        `json:"policy_id"`
+	Kind          LateFeeKind `json:"kind"`
+	Period        int         `json:"period"`
+	BaseAmount    float64     `json:"base_amount"`
+	Amount        float64     `json:"amount"`
+	InvoiceItemID *uint       `json:"invoice_item_id,omitempty"`
+	FeeInvoiceID  *uint       `json:"fee_invoice_id,omitempty"`
+	AppliedAt     time.Time   `json:"applied_at"`
+}
+
+// PendingFees returns the fees that are due by now on an invoice of base
+// amount that fell due on dueDate, skipping periods already in applied and
+// keeping the total of all fees within the policy cap.
+func (p LateFeePolicy) PendingFees(base float64, dueDate, now time.Time, applied []LateFee) []LateFee {
+	if !p.Enabled || base <= 0 {
+		return nil
+	}
+
+	start := dueDate.AddDate(0, 0, p.GraceDays)
+	if !now.After(start) {
+		return nil
+	}
+
+	charged := map[int]bool{}
+	total := 0.0
+	for _, fee := range applied {
+		charged[fee.Period] = true
+		total += fee.Amount
+	}
+
+	var pending []LateFee
+	add := func(kind LateFeeKind, period int, amount float64) {
+		if charged[period] {
+			return
+		}
+
+		if p.Cap > 0 {
+			amount = math.Min(amount, p.Cap-total)
+		}
+
+		amount = math.Round(amount*100) / 100
+		if amount <= 0 {
+			return
+		}
+
+		total += amount
+		pending = append(pending, LateFee{
+			UserID:     p.UserID,
+			PolicyID:   p.ID,
+			Kind:       kind,
+			Period:     period,
+			BaseAmount: base,
+			Amount:     amount,
+		})
+	}
+
+	initial := p.Amount
+	if p.Type == LateFeeTypePercentage {
+		initial = base * p.Amount / 100
+	}
+	add(LateFeeKindInitial, 0, initial)
+
+	if p.MonthlyInterestRate > 0 {
+		for month := 1; !start.AddDate(0, month, 0).After(now); month++ {
+			add(LateFeeKindInterest, month, base*p.MonthlyInterestRate/100)
+		}
+	}
+
+	return pending
+}
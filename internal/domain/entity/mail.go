@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+type MailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Mail is an email handed to a Mailer, sent as plain text or, when HTMLBody
+// is set, with Body as the plain text alternative. From is filled in by the
+// mailer when empty.
+type Mail struct {
+	From        string
+	To          []string
+	ReplyTo     string
+	Subject     string
+	Body        string
+	HTMLBody    string
+	Attachments []MailAttachment
+}
+
+// InvoiceEmail is the message a user sends an invoice with. Empty fields
+// fall back to the client email and the user's email template; Subject and
+// Body are text/template strings rendered with EmailTemplateData.
+type InvoiceEmail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// InvoiceDelivery records an invoice emailed, or attempted, to a client.
+type InvoiceDelivery struct {
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"user_id"`
+	InvoiceID uint      `json:"invoice_id"`
+	Recipient string    `json:"recipient"`
+	Subject   string    `json:"subject"`
+	SentAt    time.Time `json:"sent_at"`
+	Error     string    `json:"error,omitempty"`
+}
@@ -0,0 +1,79 @@
+package entity
+
+import "time"
+
+type PaymentMethod string
+
+const (
+	PaymentMethodGateway      PaymentMethod = "GATEWAY"
+	PaymentMethodBankTransfer PaymentMethod = "BANK_TRANSFER"
+)
+
+type PaymentLinkStatus string
+
+const (
+	PaymentLinkStatusPending PaymentLinkStatus = "PENDING"
+	PaymentLinkStatusPaid    PaymentLinkStatus = "PAID"
+	PaymentLinkStatusExpired PaymentLinkStatus = "EXPIRED"
+)
+
+// Payment is money received against an invoice.
+type Payment struct {
+	ID        uint          `json:"id"`
+	UserID    uint          `json:"user_id"`
+	InvoiceID uint          `json:"invoice_id"`
+	Amount    float64       `json:"amount"`
+	Method    PaymentMethod `json:"method"`
+	Provider  string        `json:"provider,omitempty"`
+	Reference string        `json:"reference"`
+	PaidAt    time.Time     `json:"paid_at"`
+}
+
+// PaymentLink is a checkout page created at a payment gateway for an
+// invoice. Reference is our order id at the gateway and ExternalID the
+// gateway's own id for the checkout.
+type PaymentLink struct {
+	ID         uint              `json:"id"`
+	UserID     uint              `json:"user_id"`
+	InvoiceID  uint              `json:"invoice_id"`
+	Provider   string            `json:"provider"`
+	Reference  string            `json:"reference"`
+	ExternalID string            `json:"external_id"`
+	URL        string            `json:"url"`
+	Amount     float64           `json:"amount"`
+	Status     PaymentLinkStatus `json:"status"`
+	ExpiresAt  *time.Time        `json:"expires_at,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// PaymentLinkRequest is what a gateway needs to open a checkout.
+type PaymentLinkRequest struct {
+	Reference     string
+	Amount        float64
+	Description   string
+	CustomerName  string
+	CustomerEmail string
+	SuccessURL    string
+}
+
+type PaymentEventStatus string
+
+const (
+	PaymentEventPaid    PaymentEventStatus = "PAID"
+	PaymentEventPending PaymentEventStatus = "PENDING"
+	PaymentEventFailed  PaymentEventStatus = "FAILED"
+	PaymentEventExpired PaymentEventStatus = "EXPIRED"
+)
+
+// PaymentEvent is a verified gateway webhook. EventID is unique per
+// provider and used to ignore redeliveries.
+type PaymentEvent struct {
+	Provider      string
+	EventID       string
+	Reference     string
+	TransactionID string
+	Status        PaymentEventStatus
+	Amount        float64
+	PaidAt        time.Time
+	Payload       string
+}
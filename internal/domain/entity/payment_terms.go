@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+type PaymentTerms string
+
+const (
+	PaymentTermsDueOnReceipt PaymentTerms = "DUE_ON_RECEIPT"
+	PaymentTermsNet7         PaymentTerms = "NET_7"
+	PaymentTermsNet14        PaymentTerms = "NET_14"
+	PaymentTermsNet30        PaymentTerms = "NET_30"
+	PaymentTermsEndOfMonth   PaymentTerms = "END_OF_MONTH"
+	PaymentTermsCustom       PaymentTerms = "CUSTOM"
+)
+
+var paymentTermsDays = map[PaymentTerms]int{
+	PaymentTermsDueOnReceipt: 0,
+	PaymentTermsNet7:         7,
+	PaymentTermsNet14:        14,
+	PaymentTermsNet30:        30,
+}
+
+func (t PaymentTerms) IsValid() bool {
+	_, fixed := paymentTermsDays[t]
+	return fixed || t == PaymentTermsEndOfMonth || t == PaymentTermsCustom
+}
+
+// DueDate returns the due date for an invoice issued on issueDate. customDays
+// is only used by PaymentTermsCustom.
+func (t PaymentTerms) DueDate(issueDate time.Time, customDays int) time.Time {
+	switch t {
+	case PaymentTermsEndOfMonth:
+		y, m, _ := issueDate.Date()
+		return time.Date(y, m+1, 0, 0, 0, 0, 0, issueDate.Location())
+	case PaymentTermsCustom:
+		return issueDate.AddDate(0, 0, customDays)
+	default:
+		return issueDate.AddDate(0, 0, paymentTermsDays[t])
+	}
+}
+
+// Text is the human readable form printed on invoices.
+func (t PaymentTerms) Text(customDays int) string {
+	return t.Localized(fmt.Sprintf, customDays)
+}
+
+// Localized is Text with the English wording passed through translate, which
+// formats like fmt.Sprintf.
+func (t PaymentTerms) Localized(translate func(format string, args ...any) string, customDays int) string {
+	switch t {
+	case PaymentTermsDueOnReceipt:
+		return translate("Due on receipt")
+	case PaymentTermsEndOfMonth:
+		return translate("Due end of month")
+	case PaymentTermsCustom:
+		return translate("Net %d", customDays)
+	case "":
+		return ""
+	default:
+		return translate("Net %d", paymentTermsDays[t])
+	}
+}
@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+type PDFJobStatus string
+
+const (
+	PDFJobQueued  PDFJobStatus = "QUEUED"
+	PDFJobRunning PDFJobStatus = "RUNNING"
+	PDFJobDone    PDFJobStatus = "DONE"
+	PDFJobFailed  PDFJobStatus = "FAILED"
+	// PDFJobExpired marks a finished job whose PDF was thrown away because
+	// the invoice changed since.
+	PDFJobExpired PDFJobStatus = "EXPIRED"
+)
+
+// PDFJob is a request to render an invoice PDF in the background. Once done,
+// the PDF is kept in blob storage under BlobKey.
+type PDFJob struct {
+	ID         uint         `json:"id"`
+	UserID     uint         `json:"user_id"`
+	InvoiceID  uint         `json:"invoice_id"`
+	Status     PDFJobStatus `json:"status"`
+	BlobKey    string       `json:"-"`
+	Cached     bool         `json:"cached"` // the PDF had already been rendered
+	Attempts   int          `json:"attempts"`
+	Error      string       `json:"error,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+	StartedAt  *time.Time   `json:"started_at,omitempty"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+// PDFRendererStats reports the state of the PDF renderer for monitoring.
+// Pool fields are zero for renderers that do not keep browsers around.
+type PDFRendererStats struct {
+	Renderer       string        `json:"renderer"`
+	PoolSize       int           `json:"pool_size"`
+	Running        int           `json:"running"`
+	Idle           int           `json:"idle"`
+	Waiting        int64         `json:"waiting"`
+	Renders        int64         `json:"renders"`
+	Failures       int64         `json:"failures"`
+	Timeouts       int64         `json:"timeouts"`
+	Canceled       int64         `json:"canceled"`
+	Restarts       int64         `json:"restarts"`
+	QueueWait      DurationStats `json:"queue_wait"`
+	RenderDuration DurationStats `json:"render_duration"`
+}
+
+// DurationStats summarises a series of durations, in milliseconds.
+type DurationStats struct {
+	Count  int64   `json:"count"`
+	AvgMs  float64 `json:"avg_ms"`
+	MaxMs  float64 `json:"max_ms"`
+	LastMs float64 `json:"last_ms"`
+}
@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// PortalMagicLink is a single use login link emailed to a client. Only the
+// hash of its token is stored.
+type PortalMagicLink struct {
+	ID        uint
+	UserID    uint
+	ClientID  uint
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// PortalSession is issued in exchange for a magic link and gives read
+// access to one client's documents.
+type PortalSession struct {
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Client      Client    `json:"client"`
+}
+
+// Statement summarises a client's invoices issued in a period. Drafts and
+// quotes are left out.
+type Statement struct {
+	ClientID    uint      `json:"client_id"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Invoiced    float64   `json:"invoiced"`
+	Paid        float64   `json:"paid"`
+	Outstanding float64   `json:"outstanding"`
+	Invoices    []Invoice `json:"invoices"`
+}
@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+type BankTransactionStatus string
+
+const (
+	BankTransactionUnmatched BankTransactionStatus = "UNMATCHED"
+	BankTransactionMatched   BankTransactionStatus = "MATCHED"
+)
+
+// BankTransaction is an incoming transfer read from an imported bank
+// statement. BankReference is the bank's own id for the entry, when the
+// statement format carries one.
+type BankTransaction struct {
+	ID            uint                  `json:"id"`
+	UserID        uint                  `json:"user_id"`
+	BookedAt      time.Time             `json:"booked_at"`
+	Amount        float64               `json:"amount"`
+	Currency      string                `json:"currency"`
+	Reference     string                `json:"reference"`
+	Description   string                `json:"description"`
+	Counterparty  string                `json:"counterparty"`
+	BankReference string                `json:"bank_reference"`
+	Status        BankTransactionStatus `json:"status"`
+	InvoiceID     *uint                 `json:"invoice_id"`
+	PaymentID     *uint                 `json:"payment_id"`
+	Fingerprint   string                `json:"-"`
+	ImportedAt    time.Time             `json:"imported_at"`
+}
+
+type MatchStatus string
+
+const (
+	MatchSuggested MatchStatus = "SUGGESTED"
+	MatchAccepted  MatchStatus = "ACCEPTED"
+	MatchRejected  MatchStatus = "REJECTED"
+)
+
+// ReconciliationMatch is a suggestion that a bank transaction pays an
+// invoice. Score runs up to 100; Reasons lists the signals behind it.
+type ReconciliationMatch struct {
+	ID            uint             `json:"id"`
+	UserID        uint             `json:"user_id"`
+	TransactionID uint             `json:"transaction_id"`
+	InvoiceID     uint             `json:"invoice_id"`
+	InvoiceNumber string           `json:"invoice_number"`
+	Score         int              `json:"score"`
+	Reasons       []string         `json:"reasons"`
+	Status        MatchStatus      `json:"status"`
+	CreatedAt     time.Time        `json:"created_at"`
+	DecidedAt     *time.Time       `json:"decided_at"`
+	Transaction   *BankTransaction `json:"transaction,omitempty"`
+}
+
+type BankImportReport struct {
+	Format      string `json:"format"`
+	Total       int    `json:"total"`
+	Imported    int    `json:"imported"`
+	Duplicates  int    `json:"duplicates"`
+	Debits      int    `json:"debits"` // outgoing entries, which are not imported
+	Suggestions int    `json:"suggestions"`
+}
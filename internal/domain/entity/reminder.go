@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// ReminderRule is one step of a user's reminder sequence, OffsetDays
+// relative to the invoice due date (negative for before it).
+type ReminderRule struct {
+	ID         uint `json:"id"`
+	UserID     uint `json:"user_id"`
+	OffsetDays int  `json:"offset_days"`
+}
+
+// InvoiceReminder records a reminder sent, or attempted, for an invoice.
+type InvoiceReminder struct {
+	ID         uint      `json:"id"`
+	UserID     uint      `json:"user_id"`
+	InvoiceID  uint      `json:"invoice_id"`
+	OffsetDays int       `json:"offset_days"`
+	Recipient  string    `json:"recipient"`
+	SentAt     time.Time `json:"sent_at"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Notification is a message to deliver through a Notifier.
+type Notification struct {
+	UserID   uint
+	To       string
+	Subject  string
+	Body     string
+	HTMLBody string
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// InvoiceShareLink gives anyone holding its token read access to one
+// invoice without logging in, until it expires or is revoked.
+type InvoiceShareLink struct {
+	ID        uint       `json:"id"`
+	UserID    uint       `json:"user_id"`
+	InvoiceID uint       `json:"invoice_id"`
+	Nonce     string     `json:"-"`
+	Token     string     `json:"token"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (l InvoiceShareLink) Active(now time.Time) bool {
+	if l.RevokedAt != nil {
+		return false
+	}
+
+	return l.ExpiresAt == nil || now.Before(*l.ExpiresAt)
+}
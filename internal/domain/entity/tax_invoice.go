@@ -0,0 +1,139 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TaxSerial is a Nomor Seri Faktur Pajak (NSFP), the serial number DJP
+// allocates to a PKP for each tax invoice: a 3-digit branch code, a 2-digit
+// year and an 8-digit sequence, written 010-26.00000001. Zero means no serial.
+type TaxSerial uint64
+
+// ParseTaxSerial reads a serial with or without its punctuation.
+func ParseTaxSerial(s string) (TaxSerial, error) {
+	digits := strings.NewReplacer(".", "", "-", "", " ", "").Replace(s)
+	if len(digits) != 13 {
+		return 0, errors.New("tax invoice serial must have 13 digits")
+	}
+
+	n, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		return 0, errors.New("tax invoice serial must have 13 digits")
+	}
+
+	return TaxSerial(n), nil
+}
+
+func (s TaxSerial) String() string {
+	return fmt.Sprintf("%03d-%02d.%08d", s/1e10, s/1e8%100, s%1e8)
+}
+
+// Digits returns the serial without punctuation, as e-Faktur imports it.
+func (s TaxSerial) Digits() string {
+	return fmt.Sprintf("%013d", uint64(s))
+}
+
+// Prefix returns the branch code and year, which DJP allocates ranges in.
+func (s TaxSerial) Prefix() uint64 {
+	return uint64(s) / 1e8
+}
+
+func (s TaxSerial) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *TaxSerial) UnmarshalText(text []byte) error {
+	v, err := ParseTaxSerial(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = v
+	return nil
+}
+
+// ErrNoTaxSerial is returned when all the serials DJP allocated are used.
+var ErrNoTaxSerial = errors.New("no tax invoice serials left, add a range allocated by DJP")
+
+// TaxSerialRange is a block of serials DJP allocated to the user. Serials are
+// assigned to invoices in order; Next is Last+1 once the range is used up.
+type TaxSerialRange struct {
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"user_id"`
+	First     TaxSerial `json:"first"`
+	Last      TaxSerial `json:"last"`
+	Next      TaxSerial `json:"next"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Remaining returns how many serials of the range are left to assign.
+func (r TaxSerialRange) Remaining() uint64 {
+	if r.Next > r.Last {
+		return 0
+	}
+
+	return uint64(r.Last-r.Next) + 1
+}
+
+// Overlaps reports whether the two ranges share a serial.
+func (r TaxSerialRange) Overlaps(other TaxSerialRange) bool {
+	return r.First <= other.Last && other.First <= r.Last
+}
+
+type EFakturFormat string
+
+const (
+	// EFakturCSV is the import CSV of the e-Faktur desktop application.
+	EFakturCSV EFakturFormat = "csv"
+	// EFakturXML is the bulk tax invoice import XML of Coretax.
+	EFakturXML EFakturFormat = "xml"
+)
+
+// NormalizeNPWP returns the 16 digits of an NPWP, the taxpayer
+// identification number, given with or without punctuation. A 15-digit
+// NPWP issued before 2024 gets the leading zero it has had since.
+func NormalizeNPWP(s string) (string, error) {
+	digits := strings.NewReplacer(".", "", "-", "", " ", "").Replace(s)
+	if len(digits) == 15 {
+		digits = "0" + digits
+	}
+
+	if len(digits) != 16 || !isDigits(digits) {
+		return "", errors.New("NPWP must have 15 or 16 digits")
+	}
+
+	return digits, nil
+}
+
+// NormalizeNITKU returns the 22-digit NITKU, the NPWP followed by a 6-digit
+// place of business number. An empty NITKU is that of the head office.
+func NormalizeNITKU(s, npwp string) (string, error) {
+	digits := strings.NewReplacer(".", "", "-", "", " ", "").Replace(s)
+	if digits == "" {
+		return npwp + "000000", nil
+	}
+
+	if len(digits) != 22 || !isDigits(digits) {
+		return "", errors.New("NITKU must have 22 digits")
+	}
+
+	if digits[:16] != npwp {
+		return "", errors.New("NITKU must start with the NPWP")
+	}
+
+	return digits, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
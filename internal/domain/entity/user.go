@@ -10,5 +10,11 @@ type User struct {
 	BankName          string `json:"bank_name"`
 	BankAccountName   string `json:"bank_account_name"`
 	BankAccountNumber string `json:"bank_account_number"`
-	IsDeleted         bool   `json:"-"`
+	QRISPayload       string `json:"qris_payload"` // static merchant QRIS payload, empty when not set
+	NPWP              string `json:"npwp"`         // 16-digit taxpayer number, set when the user is PKP
+	NITKU             string `json:"nitku"`        // 22-digit place of business number, empty without an NPWP
+
+	PaymentTerms     PaymentTerms `json:"payment_terms"`
+	PaymentTermsDays int          `json:"payment_terms_days"`
+	IsDeleted        bool         `json:"-"`
 }
@@ -9,6 +9,10 @@ type AuthRepository interface {
 	UpdatePassword(id uint, password string) error
 	UpdateUserProfile(userID uint, update entity.User) error
 	UpdateUserBanking(userID uint, update entity.User) error
+	UpdateUserQRIS(userID uint, payload string) error
+	UpdateUserTaxID(userID uint, npwp, nitku string) error
+
+	UpdatePaymentTerms(userID uint, terms entity.PaymentTerms, days int) error
 	DeleteUser(id uint) error
 	RestoreUser(user *entity.User) error
 }
@@ -8,6 +8,10 @@ type AuthUseCase interface {
 	Me(userID uint) (*entity.User, error)
 	UpdateUserProfile(userID uint, update entity.User) error
 	UpdateUserBanking(userID uint, update entity.User) error
+	UpdateUserQRIS(userID uint, payload string) error
+	UpdateUserTaxID(userID uint, npwp, nitku string) error
+
+	UpdatePaymentTerms(userID uint, terms entity.PaymentTerms, days int) error
 	ChangePassword(userID uint, oldPassword, newPassword string) error
 	DeactivateUser(userID uint) error
 	RefreshToken(token string) (access, refresh string, err error)
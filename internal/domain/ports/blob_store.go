@@ -0,0 +1,15 @@
+package ports
+
+import "context"
+
+// BlobStore keeps generated files, such as rendered invoice PDFs, under
+// slash separated keys.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get returns nil when there is no blob under key.
+	Get(ctx context.Context, key string) ([]byte, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	// DeletePrefix deletes every blob whose key starts with prefix, which
+	// ends with a slash.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
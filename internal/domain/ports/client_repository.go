@@ -1,13 +1,23 @@
 package ports
 
-import "github.com/hutamy/go-invoice-backend/internal/domain/entity"
+import (
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
 
 type ClientRepository interface {
 	Create(client *entity.Client) error
 	GetByID(id, userID uint) (*entity.Client, error)
 	ListByUser(userID uint, page int, pageSize int, search string) ([]entity.Client, int64, error)
+	ListEmailsByUser(userID uint) ([]string, error)
+	ListByEmail(email string) ([]entity.Client, error)
+	FindMatch(userID uint, email, name string) (*entity.Client, error)
 	Update(update entity.Client) error
 	Delete(id, userID uint) error
+	ListTrashedByUser(userID uint, page int, pageSize int) ([]entity.Client, int64, error)
+	Restore(id, userID uint) error
+	PurgeDeletedBefore(before time.Time) (int64, error)
 	SoftDeleteByUserID(userID uint) error
 	RestoreByUserID(userID uint) error
 }
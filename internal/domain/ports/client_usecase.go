@@ -1,6 +1,10 @@
 package ports
 
-import "github.com/hutamy/go-invoice-backend/internal/domain/entity"
+import (
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
 
 type ClientUseCase interface {
 	Create(client *entity.Client) error
@@ -8,4 +12,8 @@ type ClientUseCase interface {
 	ListByUser(userID uint, page int, pageSize int, search string) ([]entity.Client, int64, error)
 	Update(update entity.Client) error
 	Delete(id, userID uint) error
+	ListTrashed(userID uint, page int, pageSize int) ([]entity.Client, int64, error)
+	Restore(id, userID uint) error
+	PurgeTrashed(retention time.Duration) (int64, error)
+	Import(userID uint, rows []entity.ClientImportRow, dryRun bool) (*entity.ClientImportReport, error)
 }
@@ -0,0 +1,10 @@
+package ports
+
+import "github.com/hutamy/go-invoice-backend/internal/domain/entity"
+
+type EmailTemplateRepository interface {
+	Get(userID uint, kind entity.EmailTemplateKind) (*entity.EmailTemplate, error)
+	ListByUser(userID uint) ([]entity.EmailTemplate, error)
+	Save(template *entity.EmailTemplate) error
+	Delete(userID uint, kind entity.EmailTemplateKind) error
+}
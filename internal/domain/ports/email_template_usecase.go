@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+type EmailTemplateUseCase interface {
+	List(userID uint) ([]entity.EmailTemplate, error)
+	Get(userID uint, kind entity.EmailTemplateKind) (*entity.EmailTemplate, error)
+	Save(template *entity.EmailTemplate) error
+	Reset(userID uint, kind entity.EmailTemplateKind) error
+	Preview(template entity.EmailTemplate, invoiceID *uint) (*entity.RenderedEmail, error)
+	SendTest(ctx context.Context, template entity.EmailTemplate) (*entity.RenderedEmail, error)
+}
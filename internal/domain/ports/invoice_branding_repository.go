@@ -0,0 +1,9 @@
+package ports
+
+import "github.com/hutamy/go-invoice-backend/internal/domain/entity"
+
+type InvoiceBrandingRepository interface {
+	Get(userID uint) (*entity.InvoiceBranding, error)
+	Save(branding *entity.InvoiceBranding) error
+	Delete(userID uint) error
+}
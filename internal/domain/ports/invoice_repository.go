@@ -1,15 +1,36 @@
 package ports
 
-import "github.com/hutamy/go-invoice-backend/internal/domain/entity"
+import (
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
 
 type InvoiceRepository interface {
 	Create(invoice *entity.Invoice) error
+	CreateWithClient(invoice *entity.Invoice, client *entity.Client) error
+	ExistsByNumber(userID uint, invoiceNumber string) (bool, error)
 	GetByID(id, userID uint) (*entity.Invoice, error)
 	ListByUser(userID uint, page int, pageSize int, status string) ([]entity.Invoice, int64, error)
+	// ListForExport returns up to limit invoices matching filter, oldest first.
+	ListForExport(userID uint, filter entity.InvoiceExportFilter, limit int) ([]entity.Invoice, error)
 	Update(update entity.Invoice) error
 	Delete(id, userID uint) error
+	ListTrashedByUser(userID uint, page int, pageSize int) ([]entity.Invoice, int64, error)
+	Restore(id, userID uint) error
+	PurgeDeletedBefore(before time.Time) (int64, error)
 	SoftDeleteByUserID(userID uint) error
 	RestoreByUserID(userID uint) error
 	UpdateStatus(id uint, userID uint, status entity.InvoiceStatus) error
+	ListByStatus(status entity.InvoiceStatus) ([]entity.Invoice, error)
+	MarkOverdue(asOf time.Time) (int64, error)
+	SetRemindersDisabled(id, userID uint, disabled bool) error
+	ListByClient(userID, clientID uint, statuses []entity.InvoiceStatus) ([]entity.Invoice, error)
+	ListByStatuses(userID uint, statuses []entity.InvoiceStatus) ([]entity.Invoice, error)
+
+	AcceptQuote(id uint, at time.Time) error
+	RecordView(id uint, at time.Time) error
+	RecordDelivery(delivery *entity.InvoiceDelivery) error
+	ListDeliveries(invoiceID uint) ([]entity.InvoiceDelivery, error)
 	Summary(userID uint, status string) (float64, error)
 }
@@ -1,6 +1,12 @@
 package ports
 
-import "github.com/hutamy/go-invoice-backend/internal/domain/entity"
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
 
 type InvoiceUseCase interface {
 	Create(invoice *entity.Invoice) error
@@ -8,8 +14,38 @@ type InvoiceUseCase interface {
 	ListByUser(userID uint, page int, pageSize int, status string) ([]entity.Invoice, int64, error)
 	Update(update entity.Invoice) error
 	Delete(id uint, userID uint) error
+	ListTrashed(userID uint, page int, pageSize int) ([]entity.Invoice, int64, error)
+	Restore(id, userID uint) error
+	PurgeTrashed(retention time.Duration) (int64, error)
 	UpdateStatus(id uint, userID uint, status entity.InvoiceStatus) error
+	MarkOverdue(asOf time.Time) (int64, error)
 	Summary(userID uint) (paid, revenue float64, err error)
-	GeneratePDFPublic(invoice *entity.Invoice) ([]byte, error)
-	GeneratePDF(id, userID uint) ([]byte, error)
+	GeneratePDFPublic(ctx context.Context, invoice *entity.Invoice) ([]byte, error)
+	GeneratePDF(ctx context.Context, id, userID uint) ([]byte, error)
+	GenerateArchivalPDF(ctx context.Context, id, userID uint) ([]byte, error)
+	RenderHTML(id, userID uint) (string, error)
+	RendererStats() entity.PDFRendererStats
+	EnqueuePDF(id, userID uint) (*entity.PDFJob, error)
+	GetPDFJob(jobID, userID uint) (*entity.PDFJob, error)
+	DownloadPDFJob(ctx context.Context, jobID, userID uint) ([]byte, *entity.PDFJob, error)
+	RunPDFWorkers(ctx context.Context, workers int)
+
+	GetBranding(userID uint) (*entity.InvoiceBranding, error)
+	SaveBranding(branding *entity.InvoiceBranding) error
+	ResetBranding(userID uint) error
+	PreviewBranding(branding entity.InvoiceBranding, invoiceID *uint) (string, error)
+	QRISCode(id, userID uint) ([]byte, error)
+	ExportUBL(id, userID uint) ([]byte, error)
+
+	CreateShareLink(id, userID uint, expiresAt *time.Time) (*entity.InvoiceShareLink, error)
+	ListShareLinks(id, userID uint) ([]entity.InvoiceShareLink, error)
+	RevokeShareLink(id, userID, linkID uint) error
+	OpenSharedInvoice(token string) (*entity.Invoice, error)
+	Import(userID uint, records []entity.InvoiceImportRecord) (*entity.InvoiceImportReport, error)
+	Duplicate(id, userID uint, issueDate time.Time) (*entity.Invoice, error)
+	Bulk(userID uint, ids []uint, action entity.InvoiceBulkAction, status entity.InvoiceStatus) ([]entity.InvoiceBulkResult, error)
+	Send(ctx context.Context, id, userID uint, email entity.InvoiceEmail) (*entity.InvoiceDelivery, error)
+	ListDeliveries(id, userID uint) ([]entity.InvoiceDelivery, error)
+	ExportPDFs(ctx context.Context, w io.Writer, userID uint, ids []uint) ([]entity.InvoiceBulkResult, error)
+	ExportPDFsByFilter(ctx context.Context, w io.Writer, userID uint, filter entity.InvoiceExportFilter) error
 }
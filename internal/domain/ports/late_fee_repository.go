@@ -0,0 +1,12 @@
+package ports
+
+import "github.com/hutamy/go-invoice-backend/internal/domain/entity"
+
+type LateFeeRepository interface {
+	GetPolicy(userID uint, clientID *uint) (*entity.LateFeePolicy, error)
+	SavePolicy(policy *entity.LateFeePolicy) error
+	DeletePolicy(userID uint, clientID *uint) error
+	ListByInvoice(invoiceID uint) ([]entity.LateFee, error)
+	ApplyAsLineItem(fee *entity.LateFee, description string) error
+	ApplyAsInvoice(fee *entity.LateFee, feeInvoice *entity.Invoice) error
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+type LateFeeUseCase interface {
+	GetPolicy(userID uint, clientID *uint) (*entity.LateFeePolicy, error)
+	SavePolicy(policy *entity.LateFeePolicy) error
+	DeletePolicy(userID uint, clientID *uint) error
+	ListByInvoice(invoiceID, userID uint) ([]entity.LateFee, error)
+	ApplyDue(now time.Time) (int, error)
+}
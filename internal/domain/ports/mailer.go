@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+type Mailer interface {
+	Send(ctx context.Context, mail entity.Mail) error
+}
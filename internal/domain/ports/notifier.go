@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+type Notifier interface {
+	Notify(ctx context.Context, n entity.Notification) error
+}
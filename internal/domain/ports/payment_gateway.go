@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+// PaymentGateway is an online payment provider that invoices can be paid
+// through.
+type PaymentGateway interface {
+	Name() string
+	CreatePaymentLink(ctx context.Context, req entity.PaymentLinkRequest) (*entity.PaymentLink, error)
+	// ParseWebhook verifies a webhook request and returns the event in it.
+	ParseWebhook(header http.Header, body []byte) (*entity.PaymentEvent, error)
+}
+
+// PaymentSimulator is implemented by gateways that can simulate a payment
+// locally, producing the webhook request the provider would send.
+type PaymentSimulator interface {
+	SimulatePayment(reference string, amount float64) (http.Header, []byte, error)
+}
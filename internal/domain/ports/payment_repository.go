@@ -0,0 +1,12 @@
+package ports
+
+import "github.com/hutamy/go-invoice-backend/internal/domain/entity"
+
+type PaymentRepository interface {
+	CreateLink(link *entity.PaymentLink) error
+	GetLinkByReference(provider, reference string) (*entity.PaymentLink, error)
+	ListLinks(invoiceID uint) ([]entity.PaymentLink, error)
+	ListPayments(invoiceID uint) ([]entity.Payment, error)
+	RecordPayment(payment *entity.Payment) error
+	ApplyEvent(event *entity.PaymentEvent, link *entity.PaymentLink, payment *entity.Payment) (bool, error)
+}
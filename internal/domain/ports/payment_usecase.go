@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+type PaymentUseCase interface {
+	CreatePaymentLink(ctx context.Context, invoiceID, userID uint, provider string) (*entity.PaymentLink, error)
+	ListPaymentLinks(invoiceID, userID uint) ([]entity.PaymentLink, error)
+	ListPayments(invoiceID, userID uint) ([]entity.Payment, error)
+	HandleWebhook(provider string, header http.Header, body []byte) (bool, error)
+	SimulatePayment(provider, reference string) (bool, error)
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+type PDFJobRepository interface {
+	Create(job *entity.PDFJob) error
+	GetByID(id, userID uint) (*entity.PDFJob, error)
+	// GetActive returns the invoice's queued or running job, if any.
+	GetActive(invoiceID, userID uint) (*entity.PDFJob, error)
+	// ClaimNext marks the oldest queued job, or a job left running since
+	// before staleBefore, as running and returns it. It returns nil when
+	// there is nothing to do.
+	ClaimNext(staleBefore time.Time) (*entity.PDFJob, error)
+	Finish(job entity.PDFJob) error
+	// ExpireByInvoice marks the invoice's finished jobs expired. A zero
+	// invoiceID expires all of the user's jobs.
+	ExpireByInvoice(invoiceID, userID uint) error
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+type PDFRenderer interface {
+	Render(ctx context.Context, doc *entity.InvoiceDocument) ([]byte, error)
+	Stats() entity.PDFRendererStats
+}
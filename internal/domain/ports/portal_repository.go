@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+type PortalRepository interface {
+	CreateMagicLink(link *entity.PortalMagicLink) error
+	ConsumeMagicLink(tokenHash string, at time.Time) (*entity.PortalMagicLink, error)
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+type PortalUseCase interface {
+	RequestMagicLink(ctx context.Context, email string) error
+	Login(token string) (*entity.PortalSession, error)
+	GetClient(clientID, userID uint) (*entity.Client, error)
+	ListInvoices(clientID, userID uint, status string) ([]entity.Invoice, error)
+	ListQuotes(clientID, userID uint) ([]entity.Invoice, error)
+	GetInvoice(clientID, userID, id uint) (*entity.Invoice, error)
+	Statement(clientID, userID uint, from, to time.Time) (*entity.Statement, error)
+	AcceptQuote(clientID, userID, id uint) (*entity.Invoice, error)
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+type ReconciliationRepository interface {
+	ImportTransactions(txs []entity.BankTransaction) ([]entity.BankTransaction, error)
+	ListTransactions(userID uint, status entity.BankTransactionStatus) ([]entity.BankTransaction, error)
+	GetTransaction(id, userID uint) (*entity.BankTransaction, error)
+	SaveMatches(matches []entity.ReconciliationMatch) (int, error)
+	ListMatches(userID uint, status entity.MatchStatus) ([]entity.ReconciliationMatch, error)
+	GetMatch(id, userID uint) (*entity.ReconciliationMatch, error)
+	AcceptMatch(match *entity.ReconciliationMatch, payment *entity.Payment, at time.Time) error
+	RejectMatch(id, userID uint, at time.Time) error
+}
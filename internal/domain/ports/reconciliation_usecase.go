@@ -0,0 +1,12 @@
+package ports
+
+import "github.com/hutamy/go-invoice-backend/internal/domain/entity"
+
+type ReconciliationUseCase interface {
+	Import(userID uint, format string, txs []entity.BankTransaction) (*entity.BankImportReport, error)
+	Reconcile(userID uint) (int, error)
+	ListTransactions(userID uint, status entity.BankTransactionStatus) ([]entity.BankTransaction, error)
+	ListMatches(userID uint, status entity.MatchStatus) ([]entity.ReconciliationMatch, error)
+	AcceptMatch(id, userID uint) (*entity.Payment, error)
+	RejectMatch(id, userID uint) error
+}
@@ -0,0 +1,10 @@
+package ports
+
+import "github.com/hutamy/go-invoice-backend/internal/domain/entity"
+
+type ReminderRepository interface {
+	ListRules(userID uint) ([]entity.ReminderRule, error)
+	ReplaceRules(userID uint, offsets []int) error
+	ListByInvoice(invoiceID uint) ([]entity.InvoiceReminder, error)
+	Record(reminder *entity.InvoiceReminder) error
+}
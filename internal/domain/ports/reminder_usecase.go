@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+type ReminderUseCase interface {
+	ListRules(userID uint) ([]entity.ReminderRule, error)
+	ReplaceRules(userID uint, offsets []int) ([]entity.ReminderRule, error)
+	ListByInvoice(invoiceID, userID uint) ([]entity.InvoiceReminder, error)
+	SetInvoiceOptOut(invoiceID, userID uint, optOut bool) error
+	SendDue(ctx context.Context, now time.Time) (int, error)
+}
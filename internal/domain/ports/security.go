@@ -7,7 +7,21 @@ type PasswordHasher interface {
 	Compare(hash, password string) bool
 }
 
+// Signer signs short payloads into URL-safe tokens and verifies them.
+type Signer interface {
+	Sign(payload string) string
+	Verify(token string) (string, error)
+}
+
 type TokenService interface {
 	Generate(userID uint, ttl time.Duration) (string, error)
 	Parse(token string) (map[string]any, error)
 }
+
+// PortalTokenService issues the access tokens of the client portal. They
+// are signed with a different key than user tokens, so neither kind is
+// accepted in place of the other.
+type PortalTokenService interface {
+	Generate(clientID, userID uint, ttl time.Duration) (string, error)
+	Parse(token string) (clientID, userID uint, err error)
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+type ShareLinkRepository interface {
+	Create(link *entity.InvoiceShareLink) error
+	GetByID(id uint) (*entity.InvoiceShareLink, error)
+	ListByInvoice(invoiceID uint) ([]entity.InvoiceShareLink, error)
+	Revoke(id, invoiceID uint, at time.Time) error
+}
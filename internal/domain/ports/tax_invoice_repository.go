@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+type TaxInvoiceRepository interface {
+	CreateSerialRange(r *entity.TaxSerialRange) error
+	ListSerialRanges(userID uint) ([]entity.TaxSerialRange, error)
+	DeleteUnusedSerialRange(id, userID uint) error
+	AssignSerial(invoiceID, userID uint) (entity.TaxSerial, error)
+	ListByIssueDate(userID uint, from, to time.Time) ([]entity.Invoice, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+type TaxInvoiceUseCase interface {
+	AddSerialRange(userID uint, first, last entity.TaxSerial) (*entity.TaxSerialRange, error)
+	ListSerialRanges(userID uint) ([]entity.TaxSerialRange, error)
+	DeleteSerialRange(id, userID uint) error
+	AssignSerial(invoiceID, userID uint) (*entity.Invoice, error)
+	Export(userID uint, period time.Time, format entity.EFakturFormat) ([]byte, error)
+}
@@ -54,6 +54,20 @@ type updateBankingRequest struct {
 	BankAccountNumber string `json:"bank_account_number" binding:"required,numeric,gt=0"`
 }
 
+type updateQRISRequest struct {
+	Payload string `json:"payload"` // decoded contents of the static QRIS sticker; empty removes it
+}
+
+type updateTaxIDRequest struct {
+	NPWP  string `json:"npwp"`  // 15 or 16 digits, punctuation allowed; empty removes the tax identity
+	NITKU string `json:"nitku"` // 22 digits, empty for the head office
+}
+
+type updatePaymentTermsRequest struct {
+	PaymentTerms     string `json:"payment_terms" validate:"required,oneof=DUE_ON_RECEIPT NET_7 NET_14 NET_30 END_OF_MONTH CUSTOM"`
+	PaymentTermsDays int    `json:"payment_terms_days" validate:"required_if=PaymentTerms CUSTOM,omitempty,min=1"`
+}
+
 type updatePasswordRequest struct {
 	OldPassword string `json:"old_password" validate:"required"`
 	NewPassword string `json:"new_password" validate:"required"`
@@ -265,6 +279,99 @@ func (h *AuthHandler) UpdateBanking(c echo.Context) error {
 	return response.Response(c, http.StatusOK, "ok", nil)
 }
 
+// @Summary Update QRIS
+// @Description  Store the static QRIS merchant payload, the decoded text of the QRIS sticker. Invoices then carry
+// @Description  a dynamic QRIS code for their amount.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param request body updateQRISRequest true "Update QRIS Request"
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/qris [put]
+func (h *AuthHandler) UpdateQRIS(c echo.Context) error {
+	id := c.Get("user_id")
+	user_id, ok := id.(uint)
+	if !ok {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req updateQRISRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := h.UseCase.UpdateUserQRIS(user_id, req.Payload); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", nil)
+}
+
+// @Summary Update Tax ID
+// @Description  Store the NPWP and NITKU of a PKP (VAT-registered) user, needed to assign tax invoice serials
+// @Description  and export e-Faktur.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param request body updateTaxIDRequest true "Update Tax ID Request"
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/tax-id [put]
+func (h *AuthHandler) UpdateTaxID(c echo.Context) error {
+	id := c.Get("user_id")
+	user_id, ok := id.(uint)
+	if !ok {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req updateTaxIDRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := h.UseCase.UpdateUserTaxID(user_id, req.NPWP, req.NITKU); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", nil)
+}
+
+// @Summary Update Payment Terms
+// @Description  Update the default payment terms used to compute invoice due dates
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param request body updatePaymentTermsRequest true "Update Payment Terms Request"
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/payment-terms [put]
+func (h *AuthHandler) UpdatePaymentTerms(c echo.Context) error {
+	id := c.Get("user_id")
+	user_id, ok := id.(uint)
+	if !ok {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req updatePaymentTermsRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	if err := h.UseCase.UpdatePaymentTerms(user_id, entity.PaymentTerms(req.PaymentTerms), req.PaymentTermsDays); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", nil)
+}
+
 // @Summary Change Password
 // @Description  Change current user password
 // @Tags Auth
@@ -0,0 +1,485 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+const maxStatementEntries = 20000
+
+// statementDateLayouts are tried in order; day-first dates are the norm in
+// Indonesian bank exports.
+var statementDateLayouts = []string{
+	"2006-01-02",
+	"02/01/2006",
+	"02-01-2006",
+	"2/1/2006",
+	"02/01/06",
+	"2006/01/02",
+	"02 Jan 2006",
+	"2 Jan 2006",
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+}
+
+var statementCSVColumns = map[string][]string{
+	"date":         {"date", "tanggal", "booking date", "transaction date", "value date", "tgl"},
+	"amount":       {"amount", "jumlah", "nominal", "mutasi"},
+	"credit":       {"credit", "kredit", "cr"},
+	"debit":        {"debit", "db"},
+	"description":  {"description", "keterangan", "remarks", "details", "narrative"},
+	"reference":    {"reference", "ref", "berita", "payment reference"},
+	"counterparty": {"counterparty", "name", "payer", "nama", "sender", "pengirim"},
+	"currency":     {"currency", "ccy", "mata uang"},
+}
+
+// readStatementCSV reads a bank CSV export with a header row. Amounts come
+// either from a signed amount column (optionally suffixed CR or DB) or from
+// separate credit and debit columns.
+func readStatementCSV(data []byte) ([]entity.BankTransaction, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if first, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(first, []byte(";")) > bytes.Count(first, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("file is empty")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	cols := map[string]int{}
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		for field, aliases := range statementCSVColumns {
+			for _, a := range aliases {
+				if _, seen := cols[field]; !seen && h == a {
+					cols[field] = i
+				}
+			}
+		}
+	}
+
+	if _, ok := cols["date"]; !ok {
+		return nil, errors.New("missing date column")
+	}
+
+	_, hasAmount := cols["amount"]
+	_, hasCredit := cols["credit"]
+	if !hasAmount && !hasCredit {
+		return nil, errors.New("missing amount or credit column")
+	}
+
+	var txs []entity.BankTransaction
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		get := func(field string) string {
+			if i, ok := cols[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+
+			return ""
+		}
+
+		if strings.Join(record, "") == "" {
+			continue
+		}
+
+		bookedAt, err := parseStatementDate(get("date"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+
+		var amount float64
+		if hasAmount && get("amount") != "" {
+			amount, err = parseStatementAmount(get("amount"))
+		} else {
+			var credit, debit float64
+			if v := get("credit"); v != "" {
+				credit, err = parseStatementAmount(v)
+			}
+
+			if v := get("debit"); err == nil && v != "" {
+				debit, err = parseStatementAmount(v)
+			}
+
+			amount = credit - math.Abs(debit)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+
+		txs = append(txs, entity.BankTransaction{
+			BookedAt:     bookedAt,
+			Amount:       amount,
+			Currency:     strings.ToUpper(get("currency")),
+			Reference:    get("reference"),
+			Description:  get("description"),
+			Counterparty: get("counterparty"),
+		})
+		if len(txs) > maxStatementEntries {
+			return nil, fmt.Errorf("file exceeds %d entries", maxStatementEntries)
+		}
+	}
+
+	return txs, nil
+}
+
+func parseStatementDate(s string) (time.Time, error) {
+	for _, layout := range statementDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// parseStatementAmount parses amounts such as "1,500,000.00", "1.500.000,00",
+// "-250.5", "(250.50)" and "1,000.00 CR". When both separators appear the
+// last one is the decimal point. A separator that appears once is a decimal
+// point too unless exactly three digits follow it, as in "500.000".
+func parseStatementAmount(s string) (float64, error) {
+	orig := s
+	s = strings.ToUpper(strings.TrimSpace(s))
+	sign := 1.0
+	switch {
+	case strings.HasSuffix(s, "CR"):
+		s = strings.TrimSuffix(s, "CR")
+	case strings.HasSuffix(s, "DB"), strings.HasSuffix(s, "DR"):
+		s = s[:len(s)-2]
+		sign = -1
+	}
+
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		s = s[1 : len(s)-1]
+		sign = -sign
+	}
+
+	s = strings.TrimSpace(strings.NewReplacer("IDR", "", "RP", "", " ", "", "\u00a0", "").Replace(s))
+	if strings.HasPrefix(s, "-") {
+		s = s[1:]
+		sign = -sign
+	}
+
+	s = strings.TrimPrefix(s, "+")
+	dot, comma := strings.LastIndex(s, "."), strings.LastIndex(s, ",")
+	switch {
+	case dot >= 0 && comma >= 0 && comma > dot:
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.Replace(s, ",", ".", 1)
+	case dot >= 0 && comma >= 0:
+		s = strings.ReplaceAll(s, ",", "")
+	case comma >= 0 && len(s)-comma != 4 && strings.Count(s, ",") == 1:
+		s = strings.Replace(s, ",", ".", 1)
+	case comma >= 0:
+		s = strings.ReplaceAll(s, ",", "")
+	case dot >= 0 && (len(s)-dot == 4 || strings.Count(s, ".") > 1):
+		s = strings.ReplaceAll(s, ".", "")
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", orig)
+	}
+
+	return sign * v, nil
+}
+
+// mt940Line matches the :61: statement line: value date, optional entry
+// date, debit/credit mark, optional funds code, amount, transaction type,
+// customer reference and optional bank reference.
+var mt940Line = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?(\d[\d,]*)([NFS][A-Z0-9]{3})([^/]*?)(?://(.*))?$`)
+
+// readMT940 reads SWIFT MT940 statements. Each :61: line becomes a
+// transaction and the :86: field that follows it its description.
+func readMT940(data []byte) ([]entity.BankTransaction, error) {
+	var (
+		txs      []entity.BankTransaction
+		currency string
+		tag      string
+		value    strings.Builder
+	)
+
+	flush := func() error {
+		v := strings.TrimSpace(value.String())
+		value.Reset()
+		switch tag {
+		case "60F", "60M":
+			if len(v) >= 10 {
+				currency = v[7:10]
+			}
+		case "61":
+			first, _, _ := strings.Cut(v, "\n")
+			m := mt940Line.FindStringSubmatch(strings.TrimSpace(first))
+			if m == nil {
+				return fmt.Errorf("invalid :61: line %q", first)
+			}
+
+			bookedAt, err := time.Parse("060102", m[1])
+			if err != nil {
+				return fmt.Errorf("invalid :61: date %q", m[1])
+			}
+
+			amount, err := strconv.ParseFloat(strings.Replace(m[5], ",", ".", 1), 64)
+			if err != nil {
+				return fmt.Errorf("invalid :61: amount %q", m[5])
+			}
+
+			// C and RD (reversal of a debit) bring money in.
+			if m[3] == "D" || m[3] == "RC" {
+				amount = -amount
+			}
+
+			reference := strings.TrimSpace(m[7])
+			if reference == "NONREF" {
+				reference = ""
+			}
+
+			txs = append(txs, entity.BankTransaction{
+				BookedAt:      bookedAt,
+				Amount:        amount,
+				Currency:      currency,
+				Reference:     reference,
+				BankReference: strings.TrimSpace(m[8]),
+			})
+			if len(txs) > maxStatementEntries {
+				return fmt.Errorf("file exceeds %d entries", maxStatementEntries)
+			}
+		case "86":
+			if len(txs) > 0 && txs[len(txs)-1].Description == "" {
+				txs[len(txs)-1].Description = strings.Join(strings.Fields(v), " ")
+			}
+		}
+
+		tag = ""
+		return nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		switch {
+		case strings.HasPrefix(line, ":"):
+			if err := flush(); err != nil {
+				return nil, err
+			}
+
+			end := strings.Index(line[1:], ":")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid line %q", line)
+			}
+
+			tag = line[1 : end+1]
+			value.WriteString(line[end+2:])
+		case strings.HasPrefix(line, "-}"), strings.HasPrefix(line, "{"), line == "-":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case tag != "":
+			value.WriteString("\n" + line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	if len(txs) == 0 {
+		return nil, errors.New("no :61: statement lines found")
+	}
+
+	return txs, nil
+}
+
+// camtDocument is the subset of an ISO 20022 camt.053 bank-to-customer
+// statement needed for reconciliation. Element names are matched without
+// namespaces so every camt.053 version parses.
+type camtDocument struct {
+	Statements []struct {
+		Entries []camtEntry `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtEntry struct {
+	Amount      camtAmount    `xml:"Amt"`
+	Indicator   string        `xml:"CdtDbtInd"`
+	Reversal    bool          `xml:"RvslInd"`
+	BookingDate string        `xml:"BookgDt>Dt"`
+	BookingTime string        `xml:"BookgDt>DtTm"`
+	ValueDate   string        `xml:"ValDt>Dt"`
+	ServicerRef string        `xml:"AcctSvcrRef"`
+	Info        string        `xml:"AddtlNtryInf"`
+	Details     []camtDetails `xml:"NtryDtls>TxDtls"`
+}
+
+type camtDetails struct {
+	Amount       *camtAmount `xml:"AmtDtls>TxAmt>Amt"`
+	EndToEndID   string      `xml:"Refs>EndToEndId"`
+	ServicerRef  string      `xml:"Refs>AcctSvcrRef"`
+	Debtor       string      `xml:"RltdPties>Dbtr>Nm"`
+	DebtorParty  string      `xml:"RltdPties>Dbtr>Pty>Nm"`
+	Creditor     string      `xml:"RltdPties>Cdtr>Nm"`
+	Unstructured []string    `xml:"RmtInf>Ustrd"`
+	Structured   []string    `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+	Info         string      `xml:"AddtlTxInf"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+// readCAMT053 reads ISO 20022 camt.053 statements. Batched entries whose
+// transaction details carry their own amounts are split into one
+// transaction per detail.
+func readCAMT053(data []byte) ([]entity.BankTransaction, error) {
+	var doc camtDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid camt.053 document: %w", err)
+	}
+
+	var txs []entity.BankTransaction
+	for _, stmt := range doc.Statements {
+		for _, e := range stmt.Entries {
+			date := e.BookingDate
+			if date == "" && len(e.BookingTime) >= 10 {
+				date = e.BookingTime[:10]
+			}
+
+			if date == "" {
+				date = e.ValueDate
+			}
+
+			bookedAt, err := time.Parse(time.DateOnly, date)
+			if err != nil {
+				return nil, fmt.Errorf("invalid booking date %q", date)
+			}
+
+			// Credits bring money in, unless they reverse an earlier debit.
+			sign := 1.0
+			if (e.Indicator == "DBIT") != e.Reversal {
+				sign = -1
+			}
+
+			entryTx := func(amt camtAmount) (entity.BankTransaction, error) {
+				v, err := strconv.ParseFloat(strings.TrimSpace(amt.Value), 64)
+				if err != nil {
+					return entity.BankTransaction{}, fmt.Errorf("invalid amount %q", amt.Value)
+				}
+
+				return entity.BankTransaction{
+					BookedAt:      bookedAt,
+					Amount:        sign * v,
+					Currency:      amt.Currency,
+					BankReference: e.ServicerRef,
+					Description:   e.Info,
+				}, nil
+			}
+
+			split := len(e.Details) > 1
+			for _, d := range e.Details {
+				split = split && d.Amount != nil
+			}
+
+			if !split {
+				tx, err := entryTx(e.Amount)
+				if err != nil {
+					return nil, err
+				}
+
+				if len(e.Details) == 1 {
+					e.Details[0].apply(&tx, e.Indicator)
+				}
+
+				txs = append(txs, tx)
+				continue
+			}
+
+			for _, d := range e.Details {
+				tx, err := entryTx(*d.Amount)
+				if err != nil {
+					return nil, err
+				}
+
+				d.apply(&tx, e.Indicator)
+				txs = append(txs, tx)
+			}
+		}
+	}
+
+	if len(txs) > maxStatementEntries {
+		return nil, fmt.Errorf("file exceeds %d entries", maxStatementEntries)
+	}
+
+	if len(txs) == 0 {
+		return nil, errors.New("no statement entries found")
+	}
+
+	return txs, nil
+}
+
+// apply copies the references, counterparty and remittance information of
+// the details onto tx. The counterparty is the debtor of a credit and the
+// creditor of a debit.
+func (d camtDetails) apply(tx *entity.BankTransaction, indicator string) {
+	if d.ServicerRef != "" {
+		tx.BankReference = d.ServicerRef
+	}
+
+	switch {
+	case indicator == "DBIT":
+		tx.Counterparty = d.Creditor
+	case d.Debtor != "":
+		tx.Counterparty = d.Debtor
+	default:
+		tx.Counterparty = d.DebtorParty
+	}
+
+	switch {
+	case len(d.Structured) > 0:
+		tx.Reference = strings.Join(d.Structured, " ")
+	case d.EndToEndID != "" && d.EndToEndID != "NOTPROVIDED":
+		tx.Reference = d.EndToEndID
+	}
+
+	parts := append([]string{}, d.Unstructured...)
+	if d.Info != "" {
+		parts = append(parts, d.Info)
+	}
+
+	if len(parts) > 0 {
+		tx.Description = strings.Join(parts, " ")
+	}
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+func date(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+// checkTransactions compares parsed transactions field by field, so a
+// failure names the field that differs.
+func checkTransactions(t *testing.T, got, want []entity.BankTransaction) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d transactions, want %d: %+v", len(got), len(want), got)
+	}
+
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("transaction %d:\n got %+v\nwant %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseStatementAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"1500000", 1500000},
+		{"1,500,000.00", 1500000},
+		{"1.500.000,00", 1500000},
+		{"500.000", 500000},
+		{"500,000", 500000},
+		{"250,5", 250.5},
+		{"-250.5", -250.5},
+		{"(250.50)", -250.5},
+		{"1,000.00 CR", 1000},
+		{"1,000.00 DB", -1000},
+		{"Rp 1.500.000", 1500000},
+		{"IDR 75.000,50", 75000.5},
+	}
+
+	for _, tt := range tests {
+		got, err := parseStatementAmount(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("parseStatementAmount(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "abc", "1.2.3,4,5"} {
+		if _, err := parseStatementAmount(bad); err == nil {
+			t.Errorf("parseStatementAmount(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestReadStatementCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []entity.BankTransaction
+	}{
+		{
+			"signed amount column",
+			"\ufeffDate,Description,Reference,Amount,Currency,Name\n" +
+				"2026-03-02,Transfer from client,INV-001,\"1,500,000.00\",idr,PT Pembeli\n" +
+				"\n" +
+				"2026-03-03,Bank fee,,-6500,IDR,\n",
+			[]entity.BankTransaction{
+				{BookedAt: date("2026-03-02"), Amount: 1500000, Currency: "IDR", Reference: "INV-001", Description: "Transfer from client", Counterparty: "PT Pembeli"},
+				{BookedAt: date("2026-03-03"), Amount: -6500, Currency: "IDR", Description: "Bank fee"},
+			},
+		},
+		{
+			"semicolons with credit and debit columns",
+			"Tanggal;Keterangan;Berita;Kredit;Debit;Pengirim\n" +
+				"02/03/2026;TRSF E-BANKING CR;INV-002;1.250.000,00;;BUDI SANTOSO\n" +
+				"03/03/2026;BIAYA ADM;;;15.000,00;\n",
+			[]entity.BankTransaction{
+				{BookedAt: date("2026-03-02"), Amount: 1250000, Reference: "INV-002", Description: "TRSF E-BANKING CR", Counterparty: "BUDI SANTOSO"},
+				{BookedAt: date("2026-03-03"), Amount: -15000, Description: "BIAYA ADM"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readStatementCSV([]byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkTransactions(t, got, tt.want)
+		})
+	}
+
+	for name, data := range map[string]string{
+		"empty":          "",
+		"no date column": "Description,Amount\nx,1\n",
+		"no amount":      "Date,Description\n2026-03-02,x\n",
+		"bad date":       "Date,Amount\n31/02/2026x,1\n",
+		"bad amount":     "Date,Amount\n2026-03-02,abc\n",
+	} {
+		if _, err := readStatementCSV([]byte(data)); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+const sampleMT940 = `{1:F01BMRIIDJAXXXX0000000000}{2:O940BMRIIDJAXXXX}{4:
+:20:STMT260302
+:25:1234567890
+:28C:00001/001
+:60F:C260301IDR10000000,00
+:61:2603020302C1500000,00NTRFINV-001//BK12345
+:86:TRANSFER DARI PT PEMBELI
+ PEMBAYARAN INV-001
+:61:260303D6500,NCHGNONREF
+:86:BIAYA ADMINISTRASI
+:61:260304RD250000,NTRFREF9//BK9
+:62F:C260304IDR11743500,00
+-}`
+
+func TestReadMT940(t *testing.T) {
+	got, err := readMT940([]byte(strings.ReplaceAll(sampleMT940, "\n", "\r\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkTransactions(t, got, []entity.BankTransaction{
+		{BookedAt: date("2026-03-02"), Amount: 1500000, Currency: "IDR", Reference: "INV-001", BankReference: "BK12345", Description: "TRANSFER DARI PT PEMBELI PEMBAYARAN INV-001"},
+		{BookedAt: date("2026-03-03"), Amount: -6500, Currency: "IDR", Description: "BIAYA ADMINISTRASI"},
+		// A reversed debit brings the money back.
+		{BookedAt: date("2026-03-04"), Amount: 250000, Currency: "IDR", Reference: "REF9", BankReference: "BK9"},
+	})
+
+	for name, data := range map[string]string{
+		"no statement lines": ":20:STMT\n:60F:C260301IDR0,00\n",
+		"bad statement line": ":61:2603X2C1,00NTRFX\n",
+		"bad tag":            ":61\n",
+	} {
+		if _, err := readMT940([]byte(data)); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+const sampleCAMT053 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08">
+<BkToCstmrStmt><Stmt>
+  <Ntry>
+    <Amt Ccy="IDR">1500000.00</Amt><CdtDbtInd>CRDT</CdtDbtInd>
+    <BookgDt><Dt>2026-03-02</Dt></BookgDt><AcctSvcrRef>BK1</AcctSvcrRef>
+    <NtryDtls><TxDtls>
+      <Refs><EndToEndId>E2E-1</EndToEndId></Refs>
+      <RltdPties><Dbtr><Nm>PT Pembeli</Nm></Dbtr></RltdPties>
+      <RmtInf><Strd><CdtrRefInf><Ref>INV-001</Ref></CdtrRefInf></Strd></RmtInf>
+    </TxDtls></NtryDtls>
+  </Ntry>
+  <Ntry>
+    <Amt Ccy="IDR">300000</Amt><CdtDbtInd>CRDT</CdtDbtInd>
+    <BookgDt><DtTm>2026-03-03T10:00:00+07:00</DtTm></BookgDt><AcctSvcrRef>BATCH</AcctSvcrRef>
+    <NtryDtls>
+      <TxDtls>
+        <AmtDtls><TxAmt><Amt Ccy="IDR">100000</Amt></TxAmt></AmtDtls>
+        <Refs><EndToEndId>NOTPROVIDED</EndToEndId><AcctSvcrRef>B1</AcctSvcrRef></Refs>
+        <RltdPties><Dbtr><Pty><Nm>CV Satu</Nm></Pty></Dbtr></RltdPties>
+        <RmtInf><Ustrd>INV-002</Ustrd></RmtInf>
+      </TxDtls>
+      <TxDtls>
+        <AmtDtls><TxAmt><Amt Ccy="IDR">200000</Amt></TxAmt></AmtDtls>
+        <Refs><AcctSvcrRef>B2</AcctSvcrRef></Refs>
+        <RltdPties><Dbtr><Nm>CV Dua</Nm></Dbtr></RltdPties>
+        <RmtInf><Ustrd>INV-003</Ustrd></RmtInf>
+        <AddtlTxInf>partial</AddtlTxInf>
+      </TxDtls>
+    </NtryDtls>
+  </Ntry>
+  <Ntry>
+    <Amt Ccy="IDR">6500</Amt><CdtDbtInd>DBIT</CdtDbtInd>
+    <ValDt><Dt>2026-03-04</Dt></ValDt><AddtlNtryInf>Bank fee</AddtlNtryInf>
+    <NtryDtls><TxDtls><RltdPties><Cdtr><Nm>Bank Mandiri</Nm></Cdtr></RltdPties></TxDtls></NtryDtls>
+  </Ntry>
+  <Ntry>
+    <Amt Ccy="IDR">6500</Amt><CdtDbtInd>DBIT</CdtDbtInd><RvslInd>true</RvslInd>
+    <BookgDt><Dt>2026-03-05</Dt></BookgDt>
+  </Ntry>
+</Stmt></BkToCstmrStmt>
+</Document>`
+
+func TestReadCAMT053(t *testing.T) {
+	got, err := readCAMT053([]byte(sampleCAMT053))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkTransactions(t, got, []entity.BankTransaction{
+		{BookedAt: date("2026-03-02"), Amount: 1500000, Currency: "IDR", Reference: "INV-001", Counterparty: "PT Pembeli", BankReference: "BK1"},
+		// A batch whose details carry amounts is split per detail.
+		{BookedAt: date("2026-03-03"), Amount: 100000, Currency: "IDR", Description: "INV-002", Counterparty: "CV Satu", BankReference: "B1"},
+		{BookedAt: date("2026-03-03"), Amount: 200000, Currency: "IDR", Description: "INV-003 partial", Counterparty: "CV Dua", BankReference: "B2"},
+		{BookedAt: date("2026-03-04"), Amount: -6500, Currency: "IDR", Description: "Bank fee", Counterparty: "Bank Mandiri"},
+		// Reversing the fee brings it back.
+		{BookedAt: date("2026-03-05"), Amount: 6500, Currency: "IDR"},
+	})
+
+	for name, data := range map[string]string{
+		"not xml":    "camt",
+		"no entries": `<Document><BkToCstmrStmt><Stmt></Stmt></BkToCstmrStmt></Document>`,
+		"bad date":   `<Document><BkToCstmrStmt><Stmt><Ntry><Amt Ccy="IDR">1</Amt><CdtDbtInd>CRDT</CdtDbtInd><BookgDt><Dt>02/03/2026</Dt></BookgDt></Ntry></Stmt></BkToCstmrStmt></Document>`,
+		"bad amount": `<Document><BkToCstmrStmt><Stmt><Ntry><Amt Ccy="IDR">1,5</Amt><CdtDbtInd>CRDT</CdtDbtInd><BookgDt><Dt>2026-03-02</Dt></BookgDt></Ntry></Stmt></BkToCstmrStmt></Document>`,
+	} {
+		if _, err := readCAMT053([]byte(data)); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
@@ -23,10 +23,15 @@ func NewClientHandler(uc ports.ClientUseCase) *ClientHandler {
 }
 
 type clientRequest struct {
-	Name    string `json:"name" validate:"required"`
-	Email   string `json:"email" validate:"required,email"`
-	Phone   string `json:"phone" validate:"required"`
-	Address string `json:"address" validate:"required"`
+	Name             string `json:"name" validate:"required"`
+	Email            string `json:"email" validate:"required,email"`
+	Phone            string `json:"phone" validate:"required"`
+	Address          string `json:"address" validate:"required"`
+	PaymentTerms     string `json:"payment_terms" validate:"omitempty,oneof=DUE_ON_RECEIPT NET_7 NET_14 NET_30 END_OF_MONTH CUSTOM"`
+	PaymentTermsDays int    `json:"payment_terms_days" validate:"required_if=PaymentTerms CUSTOM,omitempty,min=1"`
+	Language         string `json:"language" validate:"omitempty,oneof=en id"` // language of the client's invoices
+	NPWP             string `json:"npwp"`                                      // 15 or 16 digits, for buyers with one
+	NITKU            string `json:"nitku"`                                     // 22 digits, empty for the head office
 }
 
 // @Summary Create Client
@@ -56,11 +61,16 @@ func (h *ClientHandler) CreateClient(c echo.Context) error {
 	}
 
 	client := &entity.Client{
-		UserID:  userID,
-		Name:    req.Name,
-		Email:   req.Email,
-		Phone:   req.Phone,
-		Address: req.Address,
+		UserID:           userID,
+		Name:             req.Name,
+		Email:            req.Email,
+		Phone:            req.Phone,
+		Address:          req.Address,
+		PaymentTerms:     entity.PaymentTerms(req.PaymentTerms),
+		PaymentTermsDays: req.PaymentTermsDays,
+		Language:         req.Language,
+		NPWP:             req.NPWP,
+		NITKU:            req.NITKU,
 	}
 	if err := h.UseCase.Create(client); err != nil {
 		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
@@ -174,12 +184,17 @@ func (h *ClientHandler) UpdateClient(c echo.Context) error {
 	}
 
 	update := entity.Client{
-		Name:    req.Name,
-		Email:   req.Email,
-		Phone:   req.Phone,
-		Address: req.Address,
-		UserID:  userID,
-		ID:      uint(clientID),
+		Name:             req.Name,
+		Email:            req.Email,
+		Phone:            req.Phone,
+		Address:          req.Address,
+		UserID:           userID,
+		ID:               uint(clientID),
+		PaymentTerms:     entity.PaymentTerms(req.PaymentTerms),
+		PaymentTermsDays: req.PaymentTermsDays,
+		Language:         req.Language,
+		NPWP:             req.NPWP,
+		NITKU:            req.NITKU,
 	}
 	if err := h.UseCase.Update(update); err != nil {
 		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
@@ -216,3 +231,68 @@ func (h *ClientHandler) DeleteClient(c echo.Context) error {
 
 	return response.Response(c, http.StatusOK, "deleted", nil)
 }
+
+// @Summary List Trashed Clients
+// @Description  List deleted clients that can still be restored
+// @Tags Client
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param page query int false "Page"
+// @Param page_size query int false "Page Size"
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/clients/trash [get]
+func (h *ClientHandler) ListTrashedClients(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	page := utils.ParseIntDefault(c.QueryParam("page"), 1)
+	size := utils.ParseIntDefault(c.QueryParam("page_size"), 10)
+	items, total, err := h.UseCase.ListTrashed(userID, page, size)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", map[string]any{
+		"data": items,
+		"pagination": map[string]any{
+			"total_items": total,
+			"page":        page,
+			"page_size":   size,
+			"total_pages": int(math.Ceil(float64(total) / float64(size))),
+		},
+	})
+}
+
+// @Summary Restore Client
+// @Description  Restore a deleted client by id
+// @Tags Client
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Client ID"
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/clients/{id}/restore [post]
+func (h *ClientHandler) RestoreClient(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	clientID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || clientID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	if err := h.UseCase.Restore(uint(clientID), userID); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "restored", nil)
+}
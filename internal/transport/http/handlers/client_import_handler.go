@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	response "github.com/hutamy/go-invoice-backend/internal/transport/http/response"
+	"github.com/labstack/echo/v4"
+)
+
+const maxClientImportRows = 5000
+
+var clientImportFields = []string{"name", "email", "phone", "address"}
+
+// @Summary Import Clients
+// @Description  Import clients from a CSV file. The first row must be a header; columns are matched to
+// @Description  name, email, phone and address case-insensitively unless overridden by the mapping field,
+// @Description  e.g. {"name":"Company","email":"E-mail"}. Rows whose email already exists are skipped.
+// @Tags Client
+// @Accept multipart/form-data
+// @Produce json
+// @Security     BearerAuth
+// @Param file formData file true "CSV file"
+// @Param mapping formData string false "JSON object mapping client fields to CSV headers"
+// @Param dry_run query bool false "Validate only, do not create clients"
+// @Success 200 {object} response.GenericResponse{data=entity.ClientImportReport}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/clients/import [post]
+func (h *ClientHandler) ImportClients(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	dryRun, _ := strconv.ParseBool(c.QueryParam("dry_run"))
+
+	mapping := map[string]string{}
+	if raw := c.FormValue("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			return response.Response(c, http.StatusBadRequest, "invalid mapping", nil)
+		}
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, "file is required", nil)
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+	defer f.Close()
+
+	rows, err := h.readClientCSV(c, f, mapping)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	report, err := h.UseCase.Import(userID, rows, dryRun)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", report)
+}
+
+// readClientCSV parses the uploaded CSV into import rows. Rows failing the
+// clientRequest validation are returned with their error set so they show up
+// in the report instead of aborting the whole import.
+func (h *ClientHandler) readClientCSV(c echo.Context, r io.Reader, mapping map[string]string) ([]entity.ClientImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv file is empty")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	columns := csvColumns(header)
+
+	index := make(map[string]int, len(clientImportFields))
+	for _, field := range clientImportFields {
+		name := field
+		if mapped, ok := mapping[field]; ok {
+			name = mapped
+		}
+
+		i, ok := columns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("missing column %q for field %s", name, field)
+		}
+		index[field] = i
+	}
+
+	value := func(record []string, field string) string {
+		i := index[field]
+		if i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []entity.ClientImportRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rows) >= maxClientImportRows {
+			return nil, fmt.Errorf("csv file exceeds %d rows", maxClientImportRows)
+		}
+
+		req := clientRequest{
+			Name:    value(record, "name"),
+			Email:   value(record, "email"),
+			Phone:   value(record, "phone"),
+			Address: value(record, "address"),
+		}
+		row := entity.ClientImportRow{
+			Row: line,
+			Client: entity.Client{
+				Name:    req.Name,
+				Email:   req.Email,
+				Phone:   req.Phone,
+				Address: req.Address,
+			},
+		}
+		if err := c.Validate(&req); err != nil {
+			row.Error = err.Error()
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// csvColumns indexes a CSV header by lower-cased column name, ignoring a
+// leading byte order mark left by spreadsheet exports.
+func csvColumns(header []string) map[string]int {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	return columns
+}
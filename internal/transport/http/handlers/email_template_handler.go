@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	response "github.com/hutamy/go-invoice-backend/internal/transport/http/response"
+	"github.com/labstack/echo/v4"
+)
+
+type EmailTemplateHandler struct {
+	UseCase ports.EmailTemplateUseCase
+}
+
+func NewEmailTemplateHandler(uc ports.EmailTemplateUseCase) *EmailTemplateHandler {
+	return &EmailTemplateHandler{
+		UseCase: uc,
+	}
+}
+
+type emailTemplateRequest struct {
+	Subject  string `json:"subject" validate:"required,max=255"`
+	Body     string `json:"body" validate:"required,max=10000"`
+	HTMLBody string `json:"html_body" validate:"max=50000"`
+}
+
+type emailTemplatePreviewRequest struct {
+	Subject   string `json:"subject" validate:"max=255"`
+	Body      string `json:"body" validate:"max=10000"`
+	HTMLBody  string `json:"html_body" validate:"max=50000"`
+	InvoiceID *uint  `json:"invoice_id"` // render with this invoice instead of sample data
+}
+
+func templateKindParam(c echo.Context) entity.EmailTemplateKind {
+	return entity.EmailTemplateKind(strings.ToUpper(c.Param("kind")))
+}
+
+// @Summary List Email Templates
+// @Description  List the email templates for every kind (INVOICE_SENT, REMINDER, RECEIPT, QUOTE), with the
+// @Description  default wording where no custom template is saved
+// @Tags Email Template
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Success 200 {object} response.GenericResponse{data=[]entity.EmailTemplate}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/email-templates [get]
+func (h *EmailTemplateHandler) ListTemplates(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	templates, err := h.UseCase.List(userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", templates)
+}
+
+// @Summary Get Email Template
+// @Description  Get the email template of a kind
+// @Tags Email Template
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param kind path string true "Template kind" Enums(INVOICE_SENT, REMINDER, RECEIPT, QUOTE)
+// @Success 200 {object} response.GenericResponse{data=entity.EmailTemplate}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/email-templates/{kind} [get]
+func (h *EmailTemplateHandler) GetTemplate(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	template, err := h.UseCase.Get(userID, templateKindParam(c))
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", template)
+}
+
+// @Summary Save Email Template
+// @Description  Save a custom email template. subject and body are Go text templates, html_body an optional Go
+// @Description  HTML template whose values are escaped automatically. Variables: {{.ClientName}},
+// @Description  {{.InvoiceNumber}}, {{.Total}}, {{.IssueDate}}, {{.DueDate}}, {{.DueStatus}}, {{.PayLink}},
+// @Description  {{.SenderName}} and {{.SenderEmail}}.
+// @Tags Email Template
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param kind path string true "Template kind" Enums(INVOICE_SENT, REMINDER, RECEIPT, QUOTE)
+// @Param request body emailTemplateRequest true "Email Template Request"
+// @Success 200 {object} response.GenericResponse{data=entity.EmailTemplate}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/email-templates/{kind} [put]
+func (h *EmailTemplateHandler) SaveTemplate(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req emailTemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	template := &entity.EmailTemplate{
+		UserID:   userID,
+		Kind:     templateKindParam(c),
+		Subject:  req.Subject,
+		Body:     req.Body,
+		HTMLBody: req.HTMLBody,
+	}
+	if err := h.UseCase.Save(template); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", template)
+}
+
+// @Summary Reset Email Template
+// @Description  Delete a custom email template so the default wording applies again
+// @Tags Email Template
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param kind path string true "Template kind" Enums(INVOICE_SENT, REMINDER, RECEIPT, QUOTE)
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/email-templates/{kind} [delete]
+func (h *EmailTemplateHandler) ResetTemplate(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	if err := h.UseCase.Reset(userID, templateKindParam(c)); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "deleted", nil)
+}
+
+// @Summary Preview Email Template
+// @Description  Render an email template with sample data, or with an invoice when invoice_id is given.
+// @Description  Fields left empty are taken from the saved template.
+// @Tags Email Template
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param kind path string true "Template kind" Enums(INVOICE_SENT, REMINDER, RECEIPT, QUOTE)
+// @Param request body emailTemplatePreviewRequest false "Email Template Preview Request"
+// @Success 200 {object} response.GenericResponse{data=entity.RenderedEmail}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/email-templates/{kind}/preview [post]
+func (h *EmailTemplateHandler) PreviewTemplate(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req emailTemplatePreviewRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	rendered, err := h.UseCase.Preview(entity.EmailTemplate{
+		UserID:   userID,
+		Kind:     templateKindParam(c),
+		Subject:  req.Subject,
+		Body:     req.Body,
+		HTMLBody: req.HTMLBody,
+	}, req.InvoiceID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", rendered)
+}
+
+// @Summary Send Test Email
+// @Description  Email a template rendered with sample data to the signed in user. Fields left empty are taken
+// @Description  from the saved template.
+// @Tags Email Template
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param kind path string true "Template kind" Enums(INVOICE_SENT, REMINDER, RECEIPT, QUOTE)
+// @Param request body emailTemplatePreviewRequest false "Email Template Request"
+// @Success 200 {object} response.GenericResponse{data=entity.RenderedEmail}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/email-templates/{kind}/test [post]
+func (h *EmailTemplateHandler) SendTestEmail(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req emailTemplatePreviewRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	rendered, err := h.UseCase.SendTest(c.Request().Context(), entity.EmailTemplate{
+		UserID:   userID,
+		Kind:     templateKindParam(c),
+		Subject:  req.Subject,
+		Body:     req.Body,
+		HTMLBody: req.HTMLBody,
+	})
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "sent", rendered)
+}
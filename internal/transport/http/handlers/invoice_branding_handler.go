@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	response "github.com/hutamy/go-invoice-backend/internal/transport/http/response"
+	"github.com/labstack/echo/v4"
+)
+
+type invoiceBrandingRequest struct {
+	Layout      string `json:"layout" validate:"omitempty,oneof=classic modern compact custom"`
+	Template    string `json:"template" validate:"max=65536"`
+	AccentColor string `json:"accent_color" validate:"omitempty,hexcolor"`
+	Font        string `json:"font" validate:"omitempty,oneof=Inter Helvetica Georgia Roboto Courier"`
+	FooterText  string `json:"footer_text" validate:"max=2000"`
+	Logo        string `json:"logo"` // data URI of a PNG or JPEG image, at most 256 KB
+}
+
+type invoiceBrandingPreviewRequest struct {
+	invoiceBrandingRequest
+	InvoiceID *uint `json:"invoice_id"` // render this invoice instead of a sample
+}
+
+func (r invoiceBrandingRequest) branding(userID uint) entity.InvoiceBranding {
+	return entity.InvoiceBranding{
+		UserID:      userID,
+		Layout:      entity.InvoiceLayout(r.Layout),
+		Template:    r.Template,
+		AccentColor: r.AccentColor,
+		Font:        r.Font,
+		FooterText:  r.FooterText,
+		Logo:        r.Logo,
+	}
+}
+
+// @Summary Get Invoice Branding
+// @Description  Get the layout, template and branding used for the user's invoices, or the defaults when
+// @Description  none are saved
+// @Tags Invoice Branding
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Success 200 {object} response.GenericResponse{data=entity.InvoiceBranding}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/invoice-branding [get]
+func (h *InvoiceHandler) GetBranding(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	branding, err := h.UseCase.GetBranding(userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", branding)
+}
+
+// @Summary Save Invoice Branding
+// @Description  Save the look of the user's invoices. layout is one of classic, modern, compact or custom; custom
+// @Description  renders template, a Go HTML template whose values are escaped automatically. Variables:
+// @Description  {{.InvoiceNumber}}, {{.Status}}, {{.IssueDate}}, {{.DueDate}}, {{.PaymentTerms}}, {{.Currency}},
+// @Description  {{.Sender.Name}}, {{.Sender.Address}}, {{.Sender.Email}}, {{.Sender.Phone}}, the same under
+// @Description  {{.Client}}, {{range .Items}} with {{.Description}}, {{.Quantity}}, {{.UnitPrice}} and {{.Total}},
+// @Description  {{.Subtotal}}, {{.TaxRate}}, {{.Tax}}, {{.DeliveryFee}}, {{.Total}}, {{.Notes}}, {{.Bank.Name}},
+// @Description  {{.Bank.AccountName}}, {{.Bank.AccountNumber}}, {{.QRISImage}}, {{.Logo}}, {{.AccentColor}},
+// @Description  {{.Font}} and {{.FooterText}}. The settings are rejected unless a sample invoice renders.
+// @Tags Invoice Branding
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param request body invoiceBrandingRequest true "Invoice Branding Request"
+// @Success 200 {object} response.GenericResponse{data=entity.InvoiceBranding}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/invoice-branding [put]
+func (h *InvoiceHandler) SaveBranding(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req invoiceBrandingRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	branding := req.branding(userID)
+	if err := h.UseCase.SaveBranding(&branding); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", branding)
+}
+
+// @Summary Reset Invoice Branding
+// @Description  Delete the saved invoice branding so the default look applies again
+// @Tags Invoice Branding
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/invoice-branding [delete]
+func (h *InvoiceHandler) ResetBranding(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	if err := h.UseCase.ResetBranding(userID); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "deleted", nil)
+}
+
+// @Summary Preview Invoice Branding
+// @Description  Render a sample invoice, or the invoice given by invoice_id, as HTML with the given branding
+// @Tags Invoice Branding
+// @Accept json
+// @Produce html
+// @Security     BearerAuth
+// @Param request body invoiceBrandingPreviewRequest true "Invoice Branding Preview Request"
+// @Success 200 {string} string "Invoice HTML"
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/invoice-branding/preview [post]
+func (h *InvoiceHandler) PreviewBranding(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req invoiceBrandingPreviewRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	page, err := h.UseCase.PreviewBranding(req.branding(userID), req.InvoiceID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return invoicePage(c, page)
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	response "github.com/hutamy/go-invoice-backend/internal/transport/http/response"
+	"github.com/labstack/echo/v4"
+)
+
+// @Summary Export Invoice PDFs
+// @Description  Render every invoice matching the filters and stream back a zip archive with one PDF per
+// @Description  invoice, named after its number, and a manifest.csv listing each invoice's totals with a
+// @Description  final row of sums. Invoices that fail to render are left out and reported in the manifest.
+// @Description  At most 1000 invoices can be exported at once.
+// @Tags Invoice
+// @Accept json
+// @Produce application/zip
+// @Security     BearerAuth
+// @Param from query string false "Issued on or after (YYYY-MM-DD)"
+// @Param to query string false "Issued on or before (YYYY-MM-DD)"
+// @Param status query string false "Invoice status"
+// @Param client_id query int false "Client ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/export [get]
+func (h *InvoiceHandler) ExportInvoicePDFs(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var filter entity.InvoiceExportFilter
+	if raw := c.QueryParam("from"); raw != "" {
+		t, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return response.Response(c, http.StatusBadRequest, "invalid from date", nil)
+		}
+
+		filter.From = t
+	}
+
+	if raw := c.QueryParam("to"); raw != "" {
+		t, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return response.Response(c, http.StatusBadRequest, "invalid to date", nil)
+		}
+
+		filter.To = t
+	}
+
+	if raw := c.QueryParam("client_id"); raw != "" {
+		clientID, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || clientID == 0 {
+			return response.Response(c, http.StatusBadRequest, "invalid client id", nil)
+		}
+
+		cid := uint(clientID)
+		filter.ClientID = &cid
+	}
+
+	filter.Status = entity.InvoiceStatus(strings.ToUpper(c.QueryParam("status")))
+
+	w := &zipResponseWriter{res: c.Response()}
+	if err := h.UseCase.ExportPDFsByFilter(c.Request().Context(), w, userID, filter); err != nil {
+		// Once the archive has started the status is sent; all that is
+		// left is to cut the download short.
+		if w.started {
+			log.Printf("Failed to export invoice PDFs: %v", err)
+			return nil
+		}
+
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return nil
+}
+
+// zipResponseWriter streams a zip archive to the response, sending the
+// headers with the first write so errors before it can still be reported
+// as JSON.
+type zipResponseWriter struct {
+	res     *echo.Response
+	started bool
+}
+
+func (w *zipResponseWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.res.Header().Set(echo.HeaderContentType, "application/zip")
+		w.res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="invoices.zip"`)
+		w.res.WriteHeader(http.StatusOK)
+	}
+
+	return w.res.Write(p)
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"math"
 	"net/http"
 	"strconv"
@@ -25,6 +27,7 @@ func NewInvoiceHandler(uc ports.InvoiceUseCase) *InvoiceHandler {
 }
 
 type invoiceItemReq struct {
+	ID          uint    `json:"id"` // of a fetched item sent back; late fee items are kept as they are
 	Description string  `json:"description" validate:"required"`
 	Quantity    int     `json:"quantity" validate:"required,min=1"`
 	UnitPrice   float64 `json:"unit_price" validate:"required,gt=0"` // Ensure unit price is greater than 0
@@ -32,18 +35,22 @@ type invoiceItemReq struct {
 }
 
 type invoiceReq struct {
-	ClientID      *uint            `json:"client_id"`
-	DueDate       string           `json:"due_date" validate:"required,datetime=2006-01-02"`
-	IssueDate     string           `json:"issue_date" validate:"required,datetime=2006-01-02"`
-	Items         []invoiceItemReq `json:"items" validate:"required,dive"`
-	Notes         string           `json:"notes"`
-	InvoiceNumber string           `json:"invoice_number" validate:"required"`
-	TaxRate       float64          `json:"tax_rate"`
-	DeliveryFee   float64          `json:"delivery_fee"`
-	ClientName    *string          `json:"client_name"`
-	ClientEmail   *string          `json:"client_email"`
-	ClientAddress *string          `json:"client_address"`
-	ClientPhone   *string          `json:"client_phone"`
+	ClientID         *uint            `json:"client_id"`
+	DueDate          string           `json:"due_date" validate:"omitempty,datetime=2006-01-02"` // derived from the payment terms when omitted
+	IssueDate        string           `json:"issue_date" validate:"required,datetime=2006-01-02"`
+	Items            []invoiceItemReq `json:"items" validate:"required,dive"`
+	Notes            string           `json:"notes"`
+	InvoiceNumber    string           `json:"invoice_number" validate:"required"`
+	TaxRate          float64          `json:"tax_rate"`
+	DeliveryFee      float64          `json:"delivery_fee"`
+	ClientName       *string          `json:"client_name"`
+	ClientEmail      *string          `json:"client_email"`
+	ClientAddress    *string          `json:"client_address"`
+	ClientPhone      *string          `json:"client_phone"`
+	PaymentTerms     string           `json:"payment_terms" validate:"omitempty,oneof=DUE_ON_RECEIPT NET_7 NET_14 NET_30 END_OF_MONTH CUSTOM"`
+	PaymentTermsDays int              `json:"payment_terms_days" validate:"required_if=PaymentTerms CUSTOM,omitempty,min=1"`
+	Language         string           `json:"language" validate:"omitempty,oneof=en id"` // empty uses the client's language
+	AmountInWords    bool             `json:"amount_in_words"`                           // print the total spelled out
 }
 
 type senderRequest struct {
@@ -51,6 +58,7 @@ type senderRequest struct {
 	BankName          string `json:"bank_name" validate:"required"`
 	BankAccountName   string `json:"bank_account_name" validate:"required"`
 	BankAccountNumber string `json:"bank_account_number" validate:"required"`
+	QRISPayload       string `json:"qris_payload"` // optional static QRIS payload to print a QRIS code for the total
 }
 
 type senderRecipientRequest struct {
@@ -61,15 +69,19 @@ type senderRecipientRequest struct {
 }
 
 type invoicePublicReq struct {
-	InvoiceNumber string                 `json:"invoice_number" validate:"required"`
-	IssueDate     string                 `json:"issue_date" validate:"required,datetime=2006-01-02"`
-	DueDate       string                 `json:"due_date" validate:"required,datetime=2006-01-02"`
-	Sender        senderRequest          `json:"sender" validate:"required"`
-	Recipient     senderRecipientRequest `json:"recipient" validate:"required"`
-	Items         []invoiceItemReq       `json:"items,omitempty"`
-	TaxRate       float64                `json:"tax_rate,omitempty"`
-	Notes         string                 `json:"notes"`
-	DeliveryFee   float64                `json:"delivery_fee,omitempty"`
+	InvoiceNumber    string                 `json:"invoice_number" validate:"required"`
+	IssueDate        string                 `json:"issue_date" validate:"required,datetime=2006-01-02"`
+	DueDate          string                 `json:"due_date" validate:"required_without=PaymentTerms,omitempty,datetime=2006-01-02"`
+	Sender           senderRequest          `json:"sender" validate:"required"`
+	Recipient        senderRecipientRequest `json:"recipient" validate:"required"`
+	Items            []invoiceItemReq       `json:"items,omitempty"`
+	TaxRate          float64                `json:"tax_rate,omitempty"`
+	Notes            string                 `json:"notes"`
+	DeliveryFee      float64                `json:"delivery_fee,omitempty"`
+	PaymentTerms     string                 `json:"payment_terms" validate:"omitempty,oneof=DUE_ON_RECEIPT NET_7 NET_14 NET_30 END_OF_MONTH CUSTOM"`
+	PaymentTermsDays int                    `json:"payment_terms_days" validate:"required_if=PaymentTerms CUSTOM,omitempty,min=1"`
+	Language         string                 `json:"language" validate:"omitempty,oneof=en id"`
+	AmountInWords    bool                   `json:"amount_in_words"`
 }
 
 func (r *invoiceReq) validate() error {
@@ -92,7 +104,23 @@ func (r *invoiceReq) validate() error {
 }
 
 type statusReq struct {
-	Status string `json:"status" validate:"required,oneof=DRAFT SENT PAID"`
+	Status string `json:"status" validate:"required,oneof=QUOTE DRAFT SENT OVERDUE PAID"`
+}
+
+type duplicateReq struct {
+	IssueDate string `json:"issue_date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type sendInvoiceReq struct {
+	To      string `json:"to" validate:"omitempty,email"` // defaults to the client email
+	Subject string `json:"subject" validate:"max=255"`
+	Body    string `json:"body" validate:"max=10000"`
+}
+
+type bulkInvoiceReq struct {
+	IDs    []uint `json:"ids" validate:"required,min=1,max=100"`
+	Action string `json:"action" validate:"required,oneof=status delete duplicate export_pdf"`
+	Status string `json:"status" validate:"required_if=Action status,omitempty,oneof=QUOTE DRAFT SENT OVERDUE PAID"`
 }
 
 // @Summary Create Invoice
@@ -125,30 +153,37 @@ func (h *InvoiceHandler) CreateInvoice(c echo.Context) error {
 		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
 	}
 
-	dueDate, err := time.Parse(time.DateOnly, req.DueDate)
+	issueDate, err := time.Parse(time.DateOnly, req.IssueDate)
 	if err != nil {
 		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
 	}
 
-	issueDate, err := time.Parse(time.DateOnly, req.IssueDate)
-	if err != nil {
-		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	var dueDate time.Time
+	if req.DueDate != "" {
+		dueDate, err = time.Parse(time.DateOnly, req.DueDate)
+		if err != nil {
+			return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+		}
 	}
 
 	inv := &entity.Invoice{
-		UserID:        userID,
-		ClientID:      req.ClientID,
-		InvoiceNumber: req.InvoiceNumber,
-		DueDate:       dueDate,
-		IssueDate:     issueDate,
-		Notes:         req.Notes,
-		Status:        string(entity.InvoiceStatusDraft),
-		TaxRate:       req.TaxRate,
-		ClientName:    req.ClientName,
-		ClientEmail:   req.ClientEmail,
-		ClientAddress: req.ClientAddress,
-		ClientPhone:   req.ClientPhone,
-		DeliveryFee:   req.DeliveryFee,
+		UserID:           userID,
+		ClientID:         req.ClientID,
+		InvoiceNumber:    req.InvoiceNumber,
+		DueDate:          dueDate,
+		IssueDate:        issueDate,
+		Notes:            req.Notes,
+		Status:           string(entity.InvoiceStatusDraft),
+		PaymentTerms:     entity.PaymentTerms(req.PaymentTerms),
+		PaymentTermsDays: req.PaymentTermsDays,
+		TaxRate:          req.TaxRate,
+		ClientName:       req.ClientName,
+		ClientEmail:      req.ClientEmail,
+		ClientAddress:    req.ClientAddress,
+		ClientPhone:      req.ClientPhone,
+		DeliveryFee:      req.DeliveryFee,
+		Language:         req.Language,
+		AmountInWords:    req.AmountInWords,
 	}
 	for _, it := range req.Items {
 		inv.Items = append(inv.Items, entity.InvoiceItem{
@@ -238,7 +273,7 @@ func (h *InvoiceHandler) ListInvoicesByUserID(c echo.Context) error {
 }
 
 // @Summary Update Invoice
-// @Description  Update invoice by id
+// @Description  Update invoice by id. The invoice keeps its status, which only changes through the status endpoint.
 // @Tags Invoice
 // @Accept json
 // @Produce json
@@ -273,33 +308,40 @@ func (h *InvoiceHandler) UpdateInvoice(c echo.Context) error {
 		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
 	}
 
-	dueDate, err := time.Parse(time.DateOnly, req.DueDate)
+	issueDate, err := time.Parse(time.DateOnly, req.IssueDate)
 	if err != nil {
 		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
 	}
 
-	issueDate, err := time.Parse(time.DateOnly, req.IssueDate)
-	if err != nil {
-		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	var dueDate time.Time
+	if req.DueDate != "" {
+		dueDate, err = time.Parse(time.DateOnly, req.DueDate)
+		if err != nil {
+			return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+		}
 	}
 
 	upd := entity.Invoice{
-		ID:            uint(invoiceID),
-		UserID:        userID,
-		ClientID:      req.ClientID,
-		InvoiceNumber: req.InvoiceNumber,
-		DueDate:       dueDate,
-		IssueDate:     issueDate,
-		Notes:         req.Notes,
-		Status:        string(entity.InvoiceStatusDraft),
-		TaxRate:       req.TaxRate,
-		ClientName:    req.ClientName,
-		ClientEmail:   req.ClientEmail,
-		ClientAddress: req.ClientAddress,
-		ClientPhone:   req.ClientPhone,
+		ID:               uint(invoiceID),
+		UserID:           userID,
+		ClientID:         req.ClientID,
+		InvoiceNumber:    req.InvoiceNumber,
+		DueDate:          dueDate,
+		IssueDate:        issueDate,
+		Notes:            req.Notes,
+		PaymentTerms:     entity.PaymentTerms(req.PaymentTerms),
+		PaymentTermsDays: req.PaymentTermsDays,
+		TaxRate:          req.TaxRate,
+		ClientName:       req.ClientName,
+		ClientEmail:      req.ClientEmail,
+		ClientAddress:    req.ClientAddress,
+		ClientPhone:      req.ClientPhone,
+		Language:         req.Language,
+		AmountInWords:    req.AmountInWords,
 	}
 	for _, it := range req.Items {
 		upd.Items = append(upd.Items, entity.InvoiceItem{
+			ID:          it.ID,
 			Description: it.Description,
 			Quantity:    it.Quantity,
 			UnitPrice:   it.UnitPrice,
@@ -342,6 +384,71 @@ func (h *InvoiceHandler) DeleteInvoice(c echo.Context) error {
 	return response.Response(c, http.StatusOK, "deleted", nil)
 }
 
+// @Summary List Trashed Invoices
+// @Description  List deleted invoices that can still be restored
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param page query int false "Page"
+// @Param page_size query int false "Page Size"
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/trash [get]
+func (h *InvoiceHandler) ListTrashedInvoices(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	page := utils.ParseIntDefault(c.QueryParam("page"), 1)
+	size := utils.ParseIntDefault(c.QueryParam("page_size"), 10)
+	items, total, err := h.UseCase.ListTrashed(userID, page, size)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", map[string]any{
+		"data": items,
+		"pagination": map[string]any{
+			"total_items": total,
+			"page":        page,
+			"page_size":   size,
+			"total_pages": int(math.Ceil(float64(total) / float64(size))),
+		},
+	})
+}
+
+// @Summary Restore Invoice
+// @Description  Restore a deleted invoice by id
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/restore [post]
+func (h *InvoiceHandler) RestoreInvoice(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	if err := h.UseCase.Restore(uint(invoiceID), userID); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "restored", nil)
+}
+
 // @Summary Update Invoice Status
 // @Description  Update invoice status by id
 // @Tags Invoice
@@ -381,6 +488,179 @@ func (h *InvoiceHandler) UpdateInvoiceStatus(c echo.Context) error {
 	return response.Response(c, http.StatusOK, "updated", nil)
 }
 
+// @Summary Duplicate Invoice
+// @Description  Copy an invoice into a new draft with a freshly allocated invoice number. The copy is issued
+// @Description  on issue_date (today when omitted) and keeps the original gap between issue and due date.
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Param request body duplicateReq false "Duplicate Request"
+// @Success 201 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/duplicate [post]
+func (h *InvoiceHandler) DuplicateInvoice(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	var req duplicateReq
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	var issueDate time.Time
+	if req.IssueDate != "" {
+		issueDate, err = time.Parse(time.DateOnly, req.IssueDate)
+		if err != nil {
+			return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+		}
+	}
+
+	inv, err := h.UseCase.Duplicate(uint(invoiceID), userID, issueDate)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusCreated, "created", inv)
+}
+
+// @Summary Bulk Invoice Actions
+// @Description  Apply an action to several invoices at once. Actions: status (requires status), delete,
+// @Description  duplicate and export_pdf. export_pdf responds with a zip archive of the PDFs plus a
+// @Description  manifest.csv of their totals and errors; the other actions respond with a result per invoice.
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Produce application/zip
+// @Security     BearerAuth
+// @Param request body bulkInvoiceReq true "Bulk Invoice Request"
+// @Success 200 {object} response.GenericResponse{data=[]entity.InvoiceBulkResult}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/bulk [post]
+func (h *InvoiceHandler) BulkInvoices(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req bulkInvoiceReq
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	action := entity.InvoiceBulkAction(req.Action)
+	if action == entity.InvoiceBulkActionExportPDF {
+		var buf bytes.Buffer
+		if _, err := h.UseCase.ExportPDFs(c.Request().Context(), &buf, userID, req.IDs); err != nil {
+			return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="invoices.zip"`)
+		return c.Blob(http.StatusOK, "application/zip", buf.Bytes())
+	}
+
+	results, err := h.UseCase.Bulk(userID, req.IDs, action, entity.InvoiceStatus(req.Status))
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", results)
+}
+
+// @Summary Send Invoice
+// @Description  Email the invoice PDF to the client and mark a draft invoice as SENT. Subject and body are
+// @Description  Go templates with {{.ClientName}}, {{.InvoiceNumber}}, {{.Total}}, {{.IssueDate}}, {{.DueDate}},
+// @Description  {{.SenderName}} and {{.SenderEmail}}; empty values use the default wording.
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Param request body sendInvoiceReq false "Send Invoice Request"
+// @Success 200 {object} response.GenericResponse{data=entity.InvoiceDelivery}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/send [post]
+func (h *InvoiceHandler) SendInvoice(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	var req sendInvoiceReq
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	delivery, err := h.UseCase.Send(c.Request().Context(), uint(invoiceID), userID, entity.InvoiceEmail{
+		To:      req.To,
+		Subject: req.Subject,
+		Body:    req.Body,
+	})
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), delivery)
+	}
+
+	return response.Response(c, http.StatusOK, "sent", delivery)
+}
+
+// @Summary List Invoice Deliveries
+// @Description  List the emails sent, or attempted, for an invoice
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} response.GenericResponse{data=[]entity.InvoiceDelivery}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/deliveries [get]
+func (h *InvoiceHandler) ListInvoiceDeliveries(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	deliveries, err := h.UseCase.ListDeliveries(uint(invoiceID), userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", deliveries)
+}
+
 // @Summary Invoice Summary
 // @Description  Invoice summary
 // @Tags Invoice
@@ -409,12 +689,14 @@ func (h *InvoiceHandler) Summary(c echo.Context) error {
 }
 
 // @Summary Download Invoice PDF
-// @Description  Download invoice pdf
+// @Description  Download invoice pdf. With format=pdfa-3 the PDF is a PDF/A-3 archive embedding the invoice
+// @Description  as Factur-X (EN 16931) XML, for e-invoicing.
 // @Tags Invoice
 // @Accept json
 // @Produce json
 // @Security     BearerAuth
 // @Param id path int true "Invoice ID"
+// @Param format query string false "pdf (default) or pdfa-3"
 // @Success 200 {object} response.GenericResponse
 // @Failure 400 {object} response.GenericResponse
 // @Router /v1/protected/invoices/{id}/pdf [post]
@@ -430,7 +712,16 @@ func (h *InvoiceHandler) DownloadInvoicePDF(c echo.Context) error {
 		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
 	}
 
-	pdf, err := h.UseCase.GeneratePDF(uint(invoiceID), userID)
+	generate := h.UseCase.GeneratePDF
+	switch c.QueryParam("format") {
+	case "", "pdf":
+	case "pdfa-3":
+		generate = h.UseCase.GenerateArchivalPDF
+	default:
+		return response.Response(c, http.StatusBadRequest, "invalid format", nil)
+	}
+
+	pdf, err := generate(c.Request().Context(), uint(invoiceID), userID)
 	if err != nil {
 		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
 	}
@@ -438,6 +729,79 @@ func (h *InvoiceHandler) DownloadInvoicePDF(c echo.Context) error {
 	return c.Blob(http.StatusOK, "application/pdf", pdf)
 }
 
+// @Summary PDF Renderer Health
+// @Description  Report the PDF renderer's browser pool, queue wait and render duration metrics
+// @Tags Health
+// @Produce json
+// @Success 200 {object} response.GenericResponse{data=entity.PDFRendererStats}
+// @Router /health/pdf [get]
+func (h *InvoiceHandler) PDFRendererHealth(c echo.Context) error {
+	return response.Response(c, http.StatusOK, "ok", h.UseCase.RendererStats())
+}
+
+// @Summary Invoice QRIS Code
+// @Description  Download a QRIS code, as PNG, that pays the outstanding amount of the invoice.
+// @Description  Requires a QRIS payload set with PUT /v1/protected/me/qris.
+// @Tags Invoice
+// @Accept json
+// @Produce png
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {file} binary
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/qris [get]
+func (h *InvoiceHandler) DownloadInvoiceQRIS(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	png, err := h.UseCase.QRISCode(uint(invoiceID), userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return c.Blob(http.StatusOK, "image/png", png)
+}
+
+// @Summary Download Invoice UBL
+// @Description  Download the invoice as a UBL 2.1 Peppol BIS Billing 3.0 e-invoice. Invoices breaking the
+// @Description  EN 16931 or Peppol business rules are rejected, naming the rules broken.
+// @Tags Invoice
+// @Accept json
+// @Produce xml
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {file} binary
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/ubl [get]
+func (h *InvoiceHandler) DownloadInvoiceUBL(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	ubl, err := h.UseCase.ExportUBL(uint(invoiceID), userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="invoice-%d.xml"`, invoiceID))
+	return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, ubl)
+}
+
 // @Summary Generate Public Invoice
 // @Description  Generate public invoice
 // @Tags Invoice
@@ -457,14 +821,17 @@ func (h *InvoiceHandler) GeneratePublicInvoice(c echo.Context) error {
 		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
 	}
 
-	dueDate, err := time.Parse(time.DateOnly, req.DueDate)
+	issueDate, err := time.Parse(time.DateOnly, req.IssueDate)
 	if err != nil {
 		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
 	}
 
-	issueDate, err := time.Parse(time.DateOnly, req.IssueDate)
-	if err != nil {
-		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	var dueDate time.Time
+	if req.DueDate != "" {
+		dueDate, err = time.Parse(time.DateOnly, req.DueDate)
+		if err != nil {
+			return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+		}
 	}
 
 	inv := entity.Invoice{
@@ -476,6 +843,7 @@ func (h *InvoiceHandler) GeneratePublicInvoice(c echo.Context) error {
 			BankName:          req.Sender.BankName,
 			BankAccountNumber: req.Sender.BankAccountNumber,
 			BankAccountName:   req.Sender.BankAccountName,
+			QRISPayload:       req.Sender.QRISPayload,
 		},
 		Client: entity.Client{
 			Name:    req.Recipient.Name,
@@ -483,12 +851,16 @@ func (h *InvoiceHandler) GeneratePublicInvoice(c echo.Context) error {
 			Address: req.Recipient.Address,
 			Phone:   req.Recipient.Phone,
 		},
-		InvoiceNumber: req.InvoiceNumber,
-		IssueDate:     issueDate,
-		DueDate:       dueDate,
-		Notes:         req.Notes,
-		TaxRate:       req.TaxRate,
-		DeliveryFee:   req.DeliveryFee,
+		InvoiceNumber:    req.InvoiceNumber,
+		IssueDate:        issueDate,
+		DueDate:          dueDate,
+		Notes:            req.Notes,
+		TaxRate:          req.TaxRate,
+		DeliveryFee:      req.DeliveryFee,
+		PaymentTerms:     entity.PaymentTerms(req.PaymentTerms),
+		PaymentTermsDays: req.PaymentTermsDays,
+		Language:         req.Language,
+		AmountInWords:    req.AmountInWords,
 	}
 
 	for _, it := range req.Items {
@@ -499,7 +871,7 @@ func (h *InvoiceHandler) GeneratePublicInvoice(c echo.Context) error {
 		})
 	}
 
-	pdf, err := h.UseCase.GeneratePDFPublic(&inv)
+	pdf, err := h.UseCase.GeneratePDFPublic(c.Request().Context(), &inv)
 	if err != nil {
 		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
 	}
@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	response "github.com/hutamy/go-invoice-backend/internal/transport/http/response"
+	"github.com/labstack/echo/v4"
+)
+
+const maxInvoiceImportRows = 20000
+
+// invoiceImportLine is one line item of an exported invoice. Invoice level
+// fields are repeated on every line and taken from the first line of a group.
+type invoiceImportLine struct {
+	InvoiceNumber   string  `json:"invoice_number"`
+	IssueDate       string  `json:"issue_date"`
+	DueDate         string  `json:"due_date"`
+	Status          string  `json:"status"`
+	Notes           string  `json:"notes"`
+	TaxRate         float64 `json:"tax_rate"`
+	DeliveryFee     float64 `json:"delivery_fee"`
+	ClientName      string  `json:"client_name"`
+	ClientEmail     string  `json:"client_email"`
+	ClientPhone     string  `json:"client_phone"`
+	ClientAddress   string  `json:"client_address"`
+	ItemDescription string  `json:"item_description"`
+	ItemQuantity    int     `json:"item_quantity"`
+	ItemUnitPrice   float64 `json:"item_unit_price"`
+
+	row int
+	err error
+}
+
+// @Summary Import Invoices
+// @Description  Import invoices exported from another tool. The file is CSV or JSON (an array of objects) with
+// @Description  one line item per row; rows sharing an invoice_number form one invoice. Columns: invoice_number,
+// @Description  issue_date, due_date, status, notes, tax_rate, delivery_fee, client_name, client_email,
+// @Description  client_phone, client_address, item_description, item_quantity, item_unit_price.
+// @Description  Clients are matched by email (or name) and created when missing. Invoices whose number already
+// @Description  exists are skipped. Each invoice is stored in its own transaction.
+// @Tags Invoice
+// @Accept multipart/form-data
+// @Produce json
+// @Security     BearerAuth
+// @Param file formData file true "CSV or JSON export"
+// @Param format query string false "csv or json, detected from the file extension when omitted"
+// @Success 200 {object} response.GenericResponse{data=entity.InvoiceImportReport}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/import [post]
+func (h *InvoiceHandler) ImportInvoices(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, "file is required", nil)
+	}
+
+	format := strings.ToLower(c.QueryParam("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fh.Filename)), ".")
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+	defer f.Close()
+
+	var lines []invoiceImportLine
+	switch format {
+	case "csv":
+		lines, err = readInvoiceImportCSV(f)
+	case "json":
+		lines, err = readInvoiceImportJSON(f)
+	default:
+		return response.Response(c, http.StatusBadRequest, "unsupported format", nil)
+	}
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	report, err := h.UseCase.Import(userID, groupInvoiceImportLines(lines))
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", report)
+}
+
+func readInvoiceImportJSON(r io.Reader) ([]invoiceImportLine, error) {
+	var lines []invoiceImportLine
+	if err := json.NewDecoder(r).Decode(&lines); err != nil {
+		return nil, err
+	}
+
+	if len(lines) > maxInvoiceImportRows {
+		return nil, fmt.Errorf("file exceeds %d rows", maxInvoiceImportRows)
+	}
+
+	for i := range lines {
+		lines[i].row = i + 1
+	}
+
+	return lines, nil
+}
+
+func readInvoiceImportCSV(r io.Reader) ([]invoiceImportLine, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv file is empty")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	columns := csvColumns(header)
+
+	for _, required := range []string{"invoice_number", "issue_date", "due_date", "item_description", "item_quantity", "item_unit_price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing column %q", required)
+		}
+	}
+
+	var lines []invoiceImportLine
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(lines) >= maxInvoiceImportRows {
+			return nil, fmt.Errorf("file exceeds %d rows", maxInvoiceImportRows)
+		}
+
+		value := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		line := invoiceImportLine{
+			row:             row,
+			InvoiceNumber:   value("invoice_number"),
+			IssueDate:       value("issue_date"),
+			DueDate:         value("due_date"),
+			Status:          value("status"),
+			Notes:           value("notes"),
+			ClientName:      value("client_name"),
+			ClientEmail:     value("client_email"),
+			ClientPhone:     value("client_phone"),
+			ClientAddress:   value("client_address"),
+			ItemDescription: value("item_description"),
+		}
+
+		var errs []error
+		parseFloat := func(name string) float64 {
+			v := value(name)
+			if v == "" {
+				return 0
+			}
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s %q", name, v))
+			}
+			return n
+		}
+
+		line.TaxRate = parseFloat("tax_rate")
+		line.DeliveryFee = parseFloat("delivery_fee")
+		line.ItemUnitPrice = parseFloat("item_unit_price")
+		if qty, err := strconv.Atoi(value("item_quantity")); err != nil {
+			errs = append(errs, fmt.Errorf("invalid item_quantity %q", value("item_quantity")))
+		} else {
+			line.ItemQuantity = qty
+		}
+
+		line.err = errors.Join(errs...)
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+// groupInvoiceImportLines collects lines into invoices by invoice number,
+// keeping the order in which invoice numbers first appear.
+func groupInvoiceImportLines(lines []invoiceImportLine) []entity.InvoiceImportRecord {
+	var records []entity.InvoiceImportRecord
+	index := map[string]int{}
+	for _, line := range lines {
+		i, ok := index[line.InvoiceNumber]
+		if !ok {
+			i = len(records)
+			index[line.InvoiceNumber] = i
+			records = append(records, newInvoiceImportRecord(line))
+		}
+
+		rec := &records[i]
+		rec.Rows = append(rec.Rows, line.row)
+		if rec.Error != "" {
+			continue
+		}
+
+		if err := line.validate(); err != nil {
+			rec.Error = fmt.Sprintf("row %d: %s", line.row, err)
+			continue
+		}
+
+		rec.Invoice.Items = append(rec.Invoice.Items, entity.InvoiceItem{
+			Description: line.ItemDescription,
+			Quantity:    line.ItemQuantity,
+			UnitPrice:   line.ItemUnitPrice,
+		})
+	}
+
+	return records
+}
+
+func newInvoiceImportRecord(line invoiceImportLine) entity.InvoiceImportRecord {
+	rec := entity.InvoiceImportRecord{
+		Invoice: entity.Invoice{
+			InvoiceNumber: line.InvoiceNumber,
+			Status:        line.Status,
+			Notes:         line.Notes,
+			TaxRate:       line.TaxRate,
+			DeliveryFee:   line.DeliveryFee,
+		},
+		Client: entity.Client{
+			Name:    line.ClientName,
+			Email:   line.ClientEmail,
+			Phone:   line.ClientPhone,
+			Address: line.ClientAddress,
+		},
+	}
+
+	var err error
+	switch {
+	case line.InvoiceNumber == "":
+		err = errors.New("invoice_number is required")
+	case line.ClientName == "" && line.ClientEmail == "":
+		err = errors.New("client_name or client_email is required")
+	}
+
+	if err == nil {
+		rec.Invoice.IssueDate, err = time.Parse(time.DateOnly, line.IssueDate)
+	}
+
+	if err == nil {
+		rec.Invoice.DueDate, err = time.Parse(time.DateOnly, line.DueDate)
+	}
+
+	if err != nil {
+		rec.Error = fmt.Sprintf("row %d: %s", line.row, err)
+	}
+
+	if rec.Client.Name == "" {
+		rec.Client.Name = rec.Client.Email
+	}
+
+	return rec
+}
+
+func (l invoiceImportLine) validate() error {
+	if l.err != nil {
+		return l.err
+	}
+
+	if l.ItemDescription == "" {
+		return errors.New("item_description is required")
+	}
+
+	if l.ItemQuantity < 1 {
+		return errors.New("item_quantity must be at least 1")
+	}
+
+	if l.ItemUnitPrice <= 0 {
+		return errors.New("item_unit_price must be greater than 0")
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	response "github.com/hutamy/go-invoice-backend/internal/transport/http/response"
+	"github.com/labstack/echo/v4"
+)
+
+// @Summary Queue Invoice PDF
+// @Description  Queue the invoice PDF to be rendered in the background. Poll the returned job with
+// @Description  GET /v1/protected/pdf-jobs/{id} and download it once it is DONE. A job already queued
+// @Description  for the invoice is returned instead of a new one.
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 202 {object} response.GenericResponse{data=entity.PDFJob}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/pdf-jobs [post]
+func (h *InvoiceHandler) EnqueuePDFJob(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	job, err := h.UseCase.EnqueuePDF(uint(invoiceID), userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusAccepted, "accepted", job)
+}
+
+// @Summary Get PDF Job
+// @Description  Get the status of a background PDF job: QUEUED, RUNNING, DONE, FAILED, or EXPIRED
+// @Description  when the invoice changed after the PDF was rendered.
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "PDF Job ID"
+// @Success 200 {object} response.GenericResponse{data=entity.PDFJob}
+// @Failure 400 {object} response.GenericResponse
+// @Failure 404 {object} response.GenericResponse
+// @Router /v1/protected/pdf-jobs/{id} [get]
+func (h *InvoiceHandler) GetPDFJob(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || jobID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	job, err := h.UseCase.GetPDFJob(uint(jobID), userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	if job == nil {
+		return response.Response(c, http.StatusNotFound, "not found", nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", job)
+}
+
+// @Summary Download PDF Job
+// @Description  Download the PDF rendered by a finished job. Fails when the invoice changed since.
+// @Tags Invoice
+// @Produce application/pdf
+// @Security     BearerAuth
+// @Param id path int true "PDF Job ID"
+// @Success 200 {file} binary
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/pdf-jobs/{id}/download [get]
+func (h *InvoiceHandler) DownloadPDFJob(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || jobID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	pdf, _, err := h.UseCase.DownloadPDFJob(c.Request().Context(), uint(jobID), userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return c.Blob(http.StatusOK, "application/pdf", pdf)
+}
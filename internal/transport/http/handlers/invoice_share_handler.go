@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	response "github.com/hutamy/go-invoice-backend/internal/transport/http/response"
+	"github.com/labstack/echo/v4"
+)
+
+type shareLinkReq struct {
+	ExpiresInDays int `json:"expires_in_days" validate:"gte=0,lte=365"` // 0 for a link that never expires
+}
+
+// @Summary Create Invoice Share Link
+// @Description  Create a link to view the invoice without logging in at /v1/public/invoices/view/{token}
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Param request body shareLinkReq false "Share Link Request"
+// @Success 201 {object} response.GenericResponse{data=entity.InvoiceShareLink}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/share-links [post]
+func (h *InvoiceHandler) CreateShareLink(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	var req shareLinkReq
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays > 0 {
+		t := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	link, err := h.UseCase.CreateShareLink(uint(invoiceID), userID, expiresAt)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusCreated, "created", link)
+}
+
+// @Summary List Invoice Share Links
+// @Description  List the share links of an invoice, including expired and revoked ones
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} response.GenericResponse{data=[]entity.InvoiceShareLink}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/share-links [get]
+func (h *InvoiceHandler) ListShareLinks(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	links, err := h.UseCase.ListShareLinks(uint(invoiceID), userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", links)
+}
+
+// @Summary Revoke Invoice Share Link
+// @Description  Revoke a share link so its token stops working
+// @Tags Invoice
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Param linkId path int true "Share Link ID"
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/share-links/{linkId} [delete]
+func (h *InvoiceHandler) RevokeShareLink(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	linkID, err := strconv.ParseUint(c.Param("linkId"), 10, 64)
+	if err != nil || linkID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	if err := h.UseCase.RevokeShareLink(uint(invoiceID), userID, uint(linkID)); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "revoked", nil)
+}
+
+// @Summary View Shared Invoice
+// @Description  View an invoice through a share link without logging in, as JSON, an HTML page or a PDF.
+// @Description  Every request counts as a view.
+// @Tags Invoice
+// @Produce json
+// @Produce html
+// @Produce application/pdf
+// @Param token path string true "Share link token"
+// @Param format query string false "Response format" Enums(json, html, pdf)
+// @Success 200 {object} response.GenericResponse{data=entity.Invoice}
+// @Failure 400 {object} response.GenericResponse
+// @Failure 404 {object} response.GenericResponse
+// @Router /v1/public/invoices/view/{token} [get]
+func (h *InvoiceHandler) ViewSharedInvoice(c echo.Context) error {
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "json"
+	}
+
+	if format != "json" && format != "html" && format != "pdf" {
+		return response.Response(c, http.StatusBadRequest, "format must be json, html or pdf", nil)
+	}
+
+	inv, err := h.UseCase.OpenSharedInvoice(c.Param("token"))
+	if err != nil {
+		return response.Response(c, http.StatusNotFound, err.Error(), nil)
+	}
+
+	switch format {
+	case "html":
+		page, err := h.UseCase.RenderHTML(inv.ID, inv.UserID)
+		if err != nil {
+			return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+		}
+
+		return invoicePage(c, page)
+	case "pdf":
+		pdf, err := h.UseCase.GeneratePDF(c.Request().Context(), inv.ID, inv.UserID)
+		if err != nil {
+			return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+		}
+
+		return c.Blob(http.StatusOK, "application/pdf", pdf)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", inv)
+}
+
+// invoicePageCSP confines rendered invoices, which carry user content and
+// custom templates, to inline styles and embedded images: no scripts, no
+// requests and no frames.
+const invoicePageCSP = "default-src 'none'; img-src data:; style-src 'unsafe-inline'"
+
+// invoicePage replies with an invoice rendered as HTML under invoicePageCSP.
+func invoicePage(c echo.Context, page string) error {
+	c.Response().Header().Set(echo.HeaderContentSecurityPolicy, invoicePageCSP)
+	return c.HTML(http.StatusOK, page)
+}
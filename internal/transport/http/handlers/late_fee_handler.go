@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	response "github.com/hutamy/go-invoice-backend/internal/transport/http/response"
+	"github.com/labstack/echo/v4"
+)
+
+type LateFeeHandler struct {
+	UseCase ports.LateFeeUseCase
+}
+
+func NewLateFeeHandler(uc ports.LateFeeUseCase) *LateFeeHandler {
+	return &LateFeeHandler{
+		UseCase: uc,
+	}
+}
+
+type lateFeePolicyRequest struct {
+	Enabled             bool    `json:"enabled"`
+	Type                string  `json:"type" validate:"required,oneof=FIXED PERCENTAGE"`
+	Amount              float64 `json:"amount" validate:"gte=0"`
+	GraceDays           int     `json:"grace_days" validate:"gte=0"`
+	MonthlyInterestRate float64 `json:"monthly_interest_rate" validate:"gte=0"` // percent of the invoice per overdue month
+	Cap                 float64 `json:"cap" validate:"gte=0"`                   // maximum total of all fees, 0 for no cap
+	Mode                string  `json:"mode" validate:"required,oneof=LINE_ITEM FEE_INVOICE"`
+}
+
+// clientIDParam returns the client id of /clients/:id/... routes and nil for
+// the user's default policy routes.
+func clientIDParam(c echo.Context) (*uint, bool) {
+	raw := c.Param("id")
+	if raw == "" {
+		return nil, true
+	}
+
+	clientID, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil || clientID == 0 {
+		return nil, false
+	}
+
+	id := uint(clientID)
+	return &id, true
+}
+
+// @Summary Get Late Fee Policy
+// @Description  Get the default late fee policy
+// @Tags Late Fee
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Success 200 {object} response.GenericResponse{data=entity.LateFeePolicy}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/late-fee-policy [get]
+func (h *LateFeeHandler) GetPolicy(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	clientID, ok := clientIDParam(c)
+	if !ok {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	policy, err := h.UseCase.GetPolicy(userID, clientID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	if policy == nil {
+		return response.Response(c, http.StatusNotFound, "not found", nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", policy)
+}
+
+// @Summary Save Late Fee Policy
+// @Description  Create or replace the default late fee policy. Fees are charged on OVERDUE invoices once the
+// @Description  grace period has passed: a fixed or percentage fee first, then monthly interest, never more
+// @Description  than cap in total. LINE_ITEM adds fees to the invoice, FEE_INVOICE issues a linked invoice.
+// @Tags Late Fee
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param request body lateFeePolicyRequest true "Late Fee Policy Request"
+// @Success 200 {object} response.GenericResponse{data=entity.LateFeePolicy}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/late-fee-policy [put]
+func (h *LateFeeHandler) SavePolicy(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	clientID, ok := clientIDParam(c)
+	if !ok {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	var req lateFeePolicyRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	policy := &entity.LateFeePolicy{
+		UserID:              userID,
+		ClientID:            clientID,
+		Enabled:             req.Enabled,
+		Type:                entity.LateFeeType(req.Type),
+		Amount:              req.Amount,
+		GraceDays:           req.GraceDays,
+		MonthlyInterestRate: req.MonthlyInterestRate,
+		Cap:                 req.Cap,
+		Mode:                entity.LateFeeMode(req.Mode),
+	}
+	if err := h.UseCase.SavePolicy(policy); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", policy)
+}
+
+// @Summary Delete Late Fee Policy
+// @Description  Delete the default late fee policy
+// @Tags Late Fee
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/late-fee-policy [delete]
+func (h *LateFeeHandler) DeletePolicy(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	clientID, ok := clientIDParam(c)
+	if !ok {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	if err := h.UseCase.DeletePolicy(userID, clientID); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "deleted", nil)
+}
+
+// @Summary Get Client Late Fee Policy
+// @Description  Get the late fee policy overriding the default for a client
+// @Tags Late Fee
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Client ID"
+// @Success 200 {object} response.GenericResponse{data=entity.LateFeePolicy}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/clients/{id}/late-fee-policy [get]
+func (h *LateFeeHandler) GetClientPolicy(c echo.Context) error {
+	return h.GetPolicy(c)
+}
+
+// @Summary Save Client Late Fee Policy
+// @Description  Create or replace the late fee policy overriding the default for a client
+// @Tags Late Fee
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Client ID"
+// @Param request body lateFeePolicyRequest true "Late Fee Policy Request"
+// @Success 200 {object} response.GenericResponse{data=entity.LateFeePolicy}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/clients/{id}/late-fee-policy [put]
+func (h *LateFeeHandler) SaveClientPolicy(c echo.Context) error {
+	return h.SavePolicy(c)
+}
+
+// @Summary Delete Client Late Fee Policy
+// @Description  Delete a client's late fee policy so the default applies again
+// @Tags Late Fee
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Client ID"
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/clients/{id}/late-fee-policy [delete]
+func (h *LateFeeHandler) DeleteClientPolicy(c echo.Context) error {
+	return h.DeletePolicy(c)
+}
+
+// @Summary List Invoice Late Fees
+// @Description  List the late fees charged on an invoice
+// @Tags Late Fee
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} response.GenericResponse{data=[]entity.LateFee}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/late-fees [get]
+func (h *LateFeeHandler) ListInvoiceFees(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	fees, err := h.UseCase.ListByInvoice(uint(invoiceID), userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", fees)
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	response "github.com/hutamy/go-invoice-backend/internal/transport/http/response"
+	"github.com/labstack/echo/v4"
+)
+
+const maxWebhookBody = 1 << 20
+
+type PaymentHandler struct {
+	UseCase ports.PaymentUseCase
+}
+
+func NewPaymentHandler(uc ports.PaymentUseCase) *PaymentHandler {
+	return &PaymentHandler{
+		UseCase: uc,
+	}
+}
+
+type paymentLinkReq struct {
+	Provider string `json:"provider" validate:"omitempty,oneof=xendit midtrans fake"` // defaults to PAYMENT_PROVIDER
+}
+
+// @Summary Create Payment Link
+// @Description  Create a payment gateway checkout for the outstanding amount of a SENT or OVERDUE invoice.
+// @Description  The invoice is marked PAID automatically once the gateway reports the payment.
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Param request body paymentLinkReq false "Payment Link Request"
+// @Success 201 {object} response.GenericResponse{data=entity.PaymentLink}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/payment-links [post]
+func (h *PaymentHandler) CreatePaymentLink(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	var req paymentLinkReq
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	link, err := h.UseCase.CreatePaymentLink(c.Request().Context(), uint(invoiceID), userID, req.Provider)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusCreated, "created", link)
+}
+
+// @Summary List Payment Links
+// @Description  List the payment gateway checkouts created for an invoice
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} response.GenericResponse{data=[]entity.PaymentLink}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/payment-links [get]
+func (h *PaymentHandler) ListPaymentLinks(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	links, err := h.UseCase.ListPaymentLinks(uint(invoiceID), userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", links)
+}
+
+// @Summary List Invoice Payments
+// @Description  List the payments recorded against an invoice
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} response.GenericResponse{data=[]entity.Payment}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/payments [get]
+func (h *PaymentHandler) ListPayments(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	payments, err := h.UseCase.ListPayments(uint(invoiceID), userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", payments)
+}
+
+// @Summary Payment Gateway Webhook
+// @Description  Receive a payment notification from a gateway. The signature is verified against the
+// @Description  provider's credentials and events already processed are acknowledged without being applied again.
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param provider path string true "Payment provider" Enums(xendit, midtrans, fake)
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/public/payments/webhooks/{provider} [post]
+func (h *PaymentHandler) Webhook(c echo.Context) error {
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookBody))
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	applied, err := h.UseCase.HandleWebhook(c.Param("provider"), c.Request().Header, body)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	if !applied {
+		return response.Response(c, http.StatusOK, "already processed", nil)
+	}
+
+	return response.Response(c, http.StatusOK, "processed", nil)
+}
+
+// @Summary Simulate Payment
+// @Description  Pay a fake gateway checkout in full, for development. Only available with PAYMENT_PROVIDER=fake.
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param reference path string true "Payment link reference"
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/public/payments/fake/{reference}/pay [post]
+func (h *PaymentHandler) SimulatePayment(c echo.Context) error {
+	applied, err := h.UseCase.SimulatePayment("fake", c.Param("reference"))
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	if !applied {
+		return response.Response(c, http.StatusOK, "already processed", nil)
+	}
+
+	return response.Response(c, http.StatusOK, "paid", nil)
+}
@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	response "github.com/hutamy/go-invoice-backend/internal/transport/http/response"
+	"github.com/labstack/echo/v4"
+	echomw "github.com/labstack/echo/v4/middleware"
+)
+
+type PortalHandler struct {
+	UseCase  ports.PortalUseCase
+	Invoices ports.InvoiceUseCase
+
+	// EmailLimit limits sign-in links per email address, so the endpoint
+	// cannot be used to flood a client's inbox.
+	EmailLimit echomw.RateLimiterStore
+}
+
+func NewPortalHandler(uc ports.PortalUseCase, invoices ports.InvoiceUseCase, emailLimit echomw.RateLimiterStore) *PortalHandler {
+	return &PortalHandler{
+		UseCase:    uc,
+		Invoices:   invoices,
+		EmailLimit: emailLimit,
+	}
+}
+
+type magicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type portalLoginRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// portalClient returns the client and user ids PortalMiddleware set.
+func portalClient(c echo.Context) (clientID, userID uint, ok bool) {
+	clientID, okClient := c.Get("portal_client_id").(uint)
+	userID, okUser := c.Get("portal_user_id").(uint)
+	return clientID, userID, okClient && okUser && clientID != 0 && userID != 0
+}
+
+// @Summary Request Portal Link
+// @Description  Email a single use sign-in link for the client portal to the given address. The response is
+// @Description  the same whether or not the address belongs to a client.
+// @Tags Portal
+// @Accept json
+// @Produce json
+// @Param request body magicLinkRequest true "Magic Link Request"
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Failure 429 {object} response.GenericResponse
+// @Router /v1/public/portal/magic-link [post]
+func (h *PortalHandler) RequestMagicLink(c echo.Context) error {
+	var req magicLinkRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	if allowed, _ := h.EmailLimit.Allow(strings.ToLower(req.Email)); !allowed {
+		return response.Response(c, http.StatusTooManyRequests, "too many requests, try again later", nil)
+	}
+
+	if err := h.UseCase.RequestMagicLink(c.Request().Context(), req.Email); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "if the email belongs to a client, a sign-in link has been sent", nil)
+}
+
+// @Summary Portal Login
+// @Description  Exchange the token of a portal sign-in link for a portal access token
+// @Tags Portal
+// @Accept json
+// @Produce json
+// @Param request body portalLoginRequest true "Portal Login Request"
+// @Success 200 {object} response.GenericResponse{data=entity.PortalSession}
+// @Failure 401 {object} response.GenericResponse
+// @Router /v1/public/portal/login [post]
+func (h *PortalHandler) Login(c echo.Context) error {
+	var req portalLoginRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	session, err := h.UseCase.Login(req.Token)
+	if err != nil {
+		return response.Response(c, http.StatusUnauthorized, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", session)
+}
+
+// @Summary Portal Client
+// @Description  Get the signed in client
+// @Tags Portal
+// @Accept json
+// @Produce json
+// @Security     PortalAuth
+// @Success 200 {object} response.GenericResponse{data=entity.Client}
+// @Failure 401 {object} response.GenericResponse
+// @Router /v1/portal/me [get]
+func (h *PortalHandler) Me(c echo.Context) error {
+	clientID, userID, ok := portalClient(c)
+	if !ok {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	client, err := h.UseCase.GetClient(clientID, userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", client)
+}
+
+// @Summary Portal Invoices
+// @Description  List the client's sent, overdue and paid invoices
+// @Tags Portal
+// @Accept json
+// @Produce json
+// @Security     PortalAuth
+// @Param status query string false "Filter by status" Enums(SENT, OVERDUE, PAID)
+// @Success 200 {object} response.GenericResponse{data=[]entity.Invoice}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/portal/invoices [get]
+func (h *PortalHandler) ListInvoices(c echo.Context) error {
+	clientID, userID, ok := portalClient(c)
+	if !ok {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoices, err := h.UseCase.ListInvoices(clientID, userID, c.QueryParam("status"))
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", invoices)
+}
+
+// @Summary Portal Invoice
+// @Description  Get one of the client's invoices or quotes
+// @Tags Portal
+// @Accept json
+// @Produce json
+// @Security     PortalAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} response.GenericResponse{data=entity.Invoice}
+// @Failure 404 {object} response.GenericResponse
+// @Router /v1/portal/invoices/{id} [get]
+func (h *PortalHandler) GetInvoice(c echo.Context) error {
+	clientID, userID, ok := portalClient(c)
+	if !ok {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	inv, err := h.UseCase.GetInvoice(clientID, userID, uint(invoiceID))
+	if err != nil {
+		return response.Response(c, http.StatusNotFound, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", inv)
+}
+
+// @Summary Portal Invoice PDF
+// @Description  Download one of the client's invoices or quotes as a PDF
+// @Tags Portal
+// @Produce application/pdf
+// @Security     PortalAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {file} file
+// @Failure 404 {object} response.GenericResponse
+// @Router /v1/portal/invoices/{id}/pdf [get]
+func (h *PortalHandler) DownloadInvoicePDF(c echo.Context) error {
+	clientID, userID, ok := portalClient(c)
+	if !ok {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	inv, err := h.UseCase.GetInvoice(clientID, userID, uint(invoiceID))
+	if err != nil {
+		return response.Response(c, http.StatusNotFound, err.Error(), nil)
+	}
+
+	pdf, err := h.Invoices.GeneratePDF(c.Request().Context(), inv.ID, inv.UserID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return c.Blob(http.StatusOK, "application/pdf", pdf)
+}
+
+// @Summary Portal Quotes
+// @Description  List the client's quotes awaiting acceptance
+// @Tags Portal
+// @Accept json
+// @Produce json
+// @Security     PortalAuth
+// @Success 200 {object} response.GenericResponse{data=[]entity.Invoice}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/portal/quotes [get]
+func (h *PortalHandler) ListQuotes(c echo.Context) error {
+	clientID, userID, ok := portalClient(c)
+	if !ok {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	quotes, err := h.UseCase.ListQuotes(clientID, userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", quotes)
+}
+
+// @Summary Accept Quote
+// @Description  Accept a quote, which turns it into a draft invoice
+// @Tags Portal
+// @Accept json
+// @Produce json
+// @Security     PortalAuth
+// @Param id path int true "Quote ID"
+// @Success 200 {object} response.GenericResponse{data=entity.Invoice}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/portal/quotes/{id}/accept [post]
+func (h *PortalHandler) AcceptQuote(c echo.Context) error {
+	clientID, userID, ok := portalClient(c)
+	if !ok {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	inv, err := h.UseCase.AcceptQuote(clientID, userID, uint(invoiceID))
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "accepted", inv)
+}
+
+// @Summary Portal Statement
+// @Description  Get a statement of the client's invoices issued in a period, by default the last 12 months
+// @Tags Portal
+// @Accept json
+// @Produce json
+// @Security     PortalAuth
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} response.GenericResponse{data=entity.Statement}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/portal/statement [get]
+func (h *PortalHandler) Statement(c echo.Context) error {
+	clientID, userID, ok := portalClient(c)
+	if !ok {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if raw := c.QueryParam("to"); raw != "" {
+		t, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return response.Response(c, http.StatusBadRequest, "invalid to date", nil)
+		}
+
+		to = t
+	}
+
+	from := to.AddDate(-1, 0, 1)
+	if raw := c.QueryParam("from"); raw != "" {
+		t, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return response.Response(c, http.StatusBadRequest, "invalid from date", nil)
+		}
+
+		from = t
+	}
+
+	statement, err := h.UseCase.Statement(clientID, userID, from, to)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", statement)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	"github.com/hutamy/go-invoice-backend/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+type structValidator struct{ v *validator.Validate }
+
+func (s structValidator) Validate(i interface{}) error { return s.v.Struct(i) }
+
+// portalUseCase counts the sign-in links requested.
+type portalUseCase struct {
+	ports.PortalUseCase
+	requests map[string]int
+}
+
+func (u *portalUseCase) RequestMagicLink(ctx context.Context, email string) error {
+	u.requests[email]++
+	return nil
+}
+
+func TestRequestMagicLinkLimitsPerEmail(t *testing.T) {
+	uc := &portalUseCase{requests: map[string]int{}}
+	h := NewPortalHandler(uc, nil, middleware.NewRateLimitStore(2))
+	e := echo.New()
+	e.Validator = structValidator{validator.New()}
+
+	post := func(email string) int {
+		req := httptest.NewRequest(http.MethodPost, "/v1/public/portal/magic-link", strings.NewReader(`{"email":"`+email+`"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		if err := h.RequestMagicLink(e.NewContext(req, rec)); err != nil {
+			t.Fatal(err)
+		}
+
+		return rec.Code
+	}
+
+	// Letter case does not make an address a different one.
+	for i, email := range []string{"ap@pembeli.co.id", "AP@Pembeli.co.id", "ap@pembeli.co.id"} {
+		want := http.StatusOK
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+
+		if got := post(email); got != want {
+			t.Errorf("request %d for %q: got %d, want %d", i+1, email, got, want)
+		}
+	}
+
+	if got := post("finance@pembeli.co.id"); got != http.StatusOK {
+		t.Errorf("another email got %d", got)
+	}
+
+	if n := uc.requests["ap@pembeli.co.id"] + uc.requests["AP@Pembeli.co.id"]; n != 2 {
+		t.Errorf("%d links requested for the limited email, want 2", n)
+	}
+}
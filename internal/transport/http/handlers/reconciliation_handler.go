@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	response "github.com/hutamy/go-invoice-backend/internal/transport/http/response"
+	"github.com/labstack/echo/v4"
+)
+
+const maxStatementSize = 10 << 20
+
+type ReconciliationHandler struct {
+	UseCase ports.ReconciliationUseCase
+}
+
+func NewReconciliationHandler(uc ports.ReconciliationUseCase) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		UseCase: uc,
+	}
+}
+
+// @Summary Import Bank Statement
+// @Description  Import a bank statement and suggest which open invoices its incoming transfers pay. Accepts CSV
+// @Description  (columns date, amount or credit/debit, description, reference, counterparty, currency; day-first
+// @Description  dates), SWIFT MT940 and ISO 20022 camt.053. Outgoing entries and entries imported before are skipped.
+// @Tags Reconciliation
+// @Accept multipart/form-data
+// @Produce json
+// @Security     BearerAuth
+// @Param file formData file true "Bank statement"
+// @Param format query string false "csv, mt940 or camt053, detected from the file when omitted"
+// @Success 200 {object} response.GenericResponse{data=entity.BankImportReport}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/bank/statements [post]
+func (h *ReconciliationHandler) ImportStatement(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, "file is required", nil)
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, maxStatementSize+1))
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	if len(data) > maxStatementSize {
+		return response.Response(c, http.StatusBadRequest, fmt.Sprintf("file exceeds %d MB", maxStatementSize>>20), nil)
+	}
+
+	format := strings.ToLower(c.QueryParam("format"))
+	if format == "" {
+		format = detectStatementFormat(fh.Filename, data)
+	}
+
+	var txs []entity.BankTransaction
+	switch format {
+	case "csv":
+		txs, err = readStatementCSV(data)
+	case "mt940":
+		txs, err = readMT940(data)
+	case "camt053":
+		txs, err = readCAMT053(data)
+	default:
+		return response.Response(c, http.StatusBadRequest, "unsupported format", nil)
+	}
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	report, err := h.UseCase.Import(userID, format, txs)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", report)
+}
+
+// detectStatementFormat guesses the format from the content, falling back
+// to the file extension.
+func detectStatementFormat(filename string, data []byte) string {
+	head := data[:min(len(data), 4096)]
+	switch {
+	case bytes.Contains(head, []byte("camt.053")) || bytes.Contains(head, []byte("BkToCstmrStmt")):
+		return "camt053"
+	case bytes.Contains(head, []byte(":20:")) && bytes.Contains(data, []byte(":61:")):
+		return "mt940"
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".xml":
+		return "camt053"
+	case ".sta", ".mt940", ".940":
+		return "mt940"
+	}
+
+	return "csv"
+}
+
+// @Summary List Bank Transactions
+// @Description  List imported incoming bank transactions
+// @Tags Reconciliation
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param status query string false "UNMATCHED or MATCHED"
+// @Success 200 {object} response.GenericResponse{data=[]entity.BankTransaction}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/bank/transactions [get]
+func (h *ReconciliationHandler) ListTransactions(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	status := entity.BankTransactionStatus(strings.ToUpper(c.QueryParam("status")))
+	if status != "" && status != entity.BankTransactionUnmatched && status != entity.BankTransactionMatched {
+		return response.Response(c, http.StatusBadRequest, "invalid status", nil)
+	}
+
+	txs, err := h.UseCase.ListTransactions(userID, status)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", txs)
+}
+
+// @Summary Reconcile Bank Transactions
+// @Description  Suggest matches for all unmatched transactions against the open invoices, e.g. after new invoices
+// @Description  were sent. Pairs suggested before are not suggested again.
+// @Tags Reconciliation
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/bank/reconcile [post]
+func (h *ReconciliationHandler) Reconcile(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	n, err := h.UseCase.Reconcile(userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", map[string]any{
+		"suggestions": n,
+	})
+}
+
+// @Summary List Reconciliation Matches
+// @Description  List suggested matches between bank transactions and invoices, best first for each transaction
+// @Tags Reconciliation
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param status query string false "SUGGESTED (default), ACCEPTED or REJECTED"
+// @Success 200 {object} response.GenericResponse{data=[]entity.ReconciliationMatch}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/bank/matches [get]
+func (h *ReconciliationHandler) ListMatches(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	status := entity.MatchStatus(strings.ToUpper(c.QueryParam("status")))
+	switch status {
+	case "":
+		status = entity.MatchSuggested
+	case entity.MatchSuggested, entity.MatchAccepted, entity.MatchRejected:
+	default:
+		return response.Response(c, http.StatusBadRequest, "invalid status", nil)
+	}
+
+	matches, err := h.UseCase.ListMatches(userID, status)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", matches)
+}
+
+// @Summary Accept Reconciliation Match
+// @Description  Record the matched transaction as a bank transfer payment of the invoice. The invoice is marked PAID
+// @Description  once its payments cover the total, and the transaction's other suggestions are rejected.
+// @Tags Reconciliation
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Match ID"
+// @Success 200 {object} response.GenericResponse{data=entity.Payment}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/bank/matches/{id}/accept [post]
+func (h *ReconciliationHandler) AcceptMatch(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || matchID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	payment, err := h.UseCase.AcceptMatch(uint(matchID), userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "accepted", payment)
+}
+
+// @Summary Reject Reconciliation Match
+// @Description  Reject a suggested match; the pair is not suggested again
+// @Tags Reconciliation
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Match ID"
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/bank/matches/{id}/reject [post]
+func (h *ReconciliationHandler) RejectMatch(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || matchID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	if err := h.UseCase.RejectMatch(uint(matchID), userID); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "rejected", nil)
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	response "github.com/hutamy/go-invoice-backend/internal/transport/http/response"
+	"github.com/labstack/echo/v4"
+)
+
+type ReminderHandler struct {
+	UseCase ports.ReminderUseCase
+}
+
+func NewReminderHandler(uc ports.ReminderUseCase) *ReminderHandler {
+	return &ReminderHandler{
+		UseCase: uc,
+	}
+}
+
+type reminderRulesRequest struct {
+	Offsets []int `json:"offsets" validate:"max=20"` // days relative to the due date, negative for before it
+}
+
+type invoiceRemindersRequest struct {
+	Enabled *bool `json:"enabled" validate:"required"`
+}
+
+// @Summary List Reminder Schedule
+// @Description  List the reminder steps sent for unpaid invoices, in days relative to the due date
+// @Tags Reminder
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Success 200 {object} response.GenericResponse{data=[]entity.ReminderRule}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/reminders [get]
+func (h *ReminderHandler) ListRules(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	rules, err := h.UseCase.ListRules(userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", rules)
+}
+
+// @Summary Replace Reminder Schedule
+// @Description  Replace the reminder schedule, e.g. [-3, 0, 7, 14] reminds 3 days before the due date, on it,
+// @Description  and 7 and 14 days after. Reminders go to SENT and OVERDUE invoices; an empty list turns them off.
+// @Tags Reminder
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param request body reminderRulesRequest true "Reminder Schedule Request"
+// @Success 200 {object} response.GenericResponse{data=[]entity.ReminderRule}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/me/reminders [put]
+func (h *ReminderHandler) ReplaceRules(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req reminderRulesRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	rules, err := h.UseCase.ReplaceRules(userID, req.Offsets)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", rules)
+}
+
+// @Summary List Invoice Reminders
+// @Description  List the reminders sent, or attempted, for an invoice
+// @Tags Reminder
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} response.GenericResponse{data=[]entity.InvoiceReminder}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/reminders [get]
+func (h *ReminderHandler) ListInvoiceReminders(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	reminders, err := h.UseCase.ListByInvoice(uint(invoiceID), userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", reminders)
+}
+
+// @Summary Toggle Invoice Reminders
+// @Description  Opt an invoice out of, or back into, automatic reminders
+// @Tags Reminder
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Param request body invoiceRemindersRequest true "Invoice Reminders Request"
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/reminders [patch]
+func (h *ReminderHandler) SetInvoiceReminders(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	var req invoiceRemindersRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	if err := h.UseCase.SetInvoiceOptOut(uint(invoiceID), userID, !*req.Enabled); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "updated", nil)
+}
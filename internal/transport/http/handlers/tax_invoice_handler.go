@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	response "github.com/hutamy/go-invoice-backend/internal/transport/http/response"
+	"github.com/labstack/echo/v4"
+)
+
+type TaxInvoiceHandler struct {
+	UseCase ports.TaxInvoiceUseCase
+}
+
+func NewTaxInvoiceHandler(uc ports.TaxInvoiceUseCase) *TaxInvoiceHandler {
+	return &TaxInvoiceHandler{
+		UseCase: uc,
+	}
+}
+
+type taxSerialRangeRequest struct {
+	First string `json:"first" validate:"required"` // e.g. 010-26.00000001, punctuation optional
+	Last  string `json:"last" validate:"required"`
+}
+
+// @Summary List Tax Serial Ranges
+// @Description  List the tax invoice serial (NSFP) ranges DJP allocated, with the next serial of each
+// @Tags Tax Invoice
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Success 200 {object} response.GenericResponse{data=[]entity.TaxSerialRange}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/tax-invoices/serial-ranges [get]
+func (h *TaxInvoiceHandler) ListSerialRanges(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	ranges, err := h.UseCase.ListSerialRanges(userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", ranges)
+}
+
+// @Summary Add Tax Serial Range
+// @Description  Add a range of tax invoice serials (NSFP) allocated by DJP. Serials are assigned to invoices in
+// @Description  order, starting from the lowest range.
+// @Tags Tax Invoice
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param request body taxSerialRangeRequest true "Tax Serial Range Request"
+// @Success 201 {object} response.GenericResponse{data=entity.TaxSerialRange}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/tax-invoices/serial-ranges [post]
+func (h *TaxInvoiceHandler) AddSerialRange(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	var req taxSerialRangeRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid request", nil)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	first, err := entity.ParseTaxSerial(req.First)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	last, err := entity.ParseTaxSerial(req.Last)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	rng, err := h.UseCase.AddSerialRange(userID, first, last)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusCreated, "created", rng)
+}
+
+// @Summary Delete Tax Serial Range
+// @Description  Delete a tax invoice serial range none of whose serials have been assigned
+// @Tags Tax Invoice
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Range ID"
+// @Success 200 {object} response.GenericResponse
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/tax-invoices/serial-ranges/{id} [delete]
+func (h *TaxInvoiceHandler) DeleteSerialRange(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	rangeID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || rangeID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	if err := h.UseCase.DeleteSerialRange(uint(rangeID), userID); err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "deleted", nil)
+}
+
+// @Summary Assign Tax Invoice Serial
+// @Description  Give an issued invoice with PPN its tax invoice serial (NSFP), the next one left in the serial
+// @Description  ranges. Requires an NPWP set with PUT /v1/protected/me/tax-id. An invoice keeps its serial, so
+// @Description  assigning again returns the same one.
+// @Tags Tax Invoice
+// @Accept json
+// @Produce json
+// @Security     BearerAuth
+// @Param id path int true "Invoice ID"
+// @Success 200 {object} response.GenericResponse{data=entity.Invoice}
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/invoices/{id}/tax-serial [post]
+func (h *TaxInvoiceHandler) AssignSerial(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	invoiceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || invoiceID == 0 {
+		return response.Response(c, http.StatusBadRequest, "invalid id", nil)
+	}
+
+	inv, err := h.UseCase.AssignSerial(uint(invoiceID), userID)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return response.Response(c, http.StatusOK, "ok", inv)
+}
+
+// @Summary Export e-Faktur
+// @Description  Export the tax invoices issued in a month, for reporting to DJP. csv is the import CSV of the
+// @Description  e-Faktur desktop application, xml the bulk tax invoice import of Coretax. Both carry the DPP,
+// @Description  PPN and PPnBM of every item; delivery fees are not subject to PPN and are left out.
+// @Tags Tax Invoice
+// @Accept json
+// @Produce octet-stream
+// @Security     BearerAuth
+// @Param period query string true "Tax period (YYYY-MM)"
+// @Param format query string false "csv (default) or xml"
+// @Success 200 {file} binary
+// @Failure 400 {object} response.GenericResponse
+// @Router /v1/protected/tax-invoices/export [get]
+func (h *TaxInvoiceHandler) Export(c echo.Context) error {
+	id := c.Get("user_id")
+	userID, ok := id.(uint)
+	if !ok || userID == 0 {
+		return response.Response(c, http.StatusUnauthorized, "unauthorized", nil)
+	}
+
+	period, err := time.Parse("2006-01", c.QueryParam("period"))
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, "invalid period", nil)
+	}
+
+	format := entity.EFakturCSV
+	contentType := "text/csv"
+	switch c.QueryParam("format") {
+	case "", "csv":
+	case "xml":
+		format = entity.EFakturXML
+		contentType = echo.MIMEApplicationXMLCharsetUTF8
+	default:
+		return response.Response(c, http.StatusBadRequest, "invalid format", nil)
+	}
+
+	data, err := h.UseCase.Export(userID, period, format)
+	if err != nil {
+		return response.Response(c, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	filename := fmt.Sprintf("efaktur-%s.%s", period.Format("2006-01"), format)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	return c.Blob(http.StatusOK, contentType, data)
+}
@@ -8,14 +8,30 @@ import (
 )
 
 type RouterDeps struct {
-	Auth    *handlers.AuthHandler
-	Client  *handlers.ClientHandler
-	Invoice *handlers.InvoiceHandler
+	Auth           *handlers.AuthHandler
+	Client         *handlers.ClientHandler
+	Invoice        *handlers.InvoiceHandler
+	LateFee        *handlers.LateFeeHandler
+	Reminder       *handlers.ReminderHandler
+	EmailTemplate  *handlers.EmailTemplateHandler
+	Portal         *handlers.PortalHandler
+	Payment        *handlers.PaymentHandler
+	Reconciliation *handlers.ReconciliationHandler
+	TaxInvoice     *handlers.TaxInvoiceHandler
+
+	// MagicLinksPerIP is how many portal sign-in links a client IP may
+	// request an hour.
+	MagicLinksPerIP int
+
+	// FakePayments registers the fake gateway's checkout, which marks
+	// invoices paid on request. It must stay off in production.
+	FakePayments bool
 }
 
 func RegisterRoutes(e *echo.Echo, deps RouterDeps) {
 	e.GET("/", deps.Auth.Health)
 	e.GET("/health", deps.Auth.Health)
+	e.GET("/health/pdf", deps.Invoice.PDFRendererHealth)
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
 	v1 := e.Group("/v1")
@@ -28,28 +44,113 @@ func RegisterRoutes(e *echo.Echo, deps RouterDeps) {
 	protected.Use(middleware.JWTMiddleware)
 	protected.GET("/me", deps.Auth.Me)
 	protected.PUT("/me/banking", deps.Auth.UpdateBanking)
+	protected.PUT("/me/qris", deps.Auth.UpdateQRIS)
+	protected.PUT("/me/tax-id", deps.Auth.UpdateTaxID)
 	protected.PUT("/me/profile", deps.Auth.UpdateProfile)
+	protected.PUT("/me/payment-terms", deps.Auth.UpdatePaymentTerms)
 	protected.POST("/me/change-password", deps.Auth.ChangePassword)
 	protected.POST("/me/deactivate", deps.Auth.DeactivateUser)
 	protected.POST("/auth/refresh-token", deps.Auth.RefreshToken)
+	protected.GET("/me/late-fee-policy", deps.LateFee.GetPolicy)
+	protected.PUT("/me/late-fee-policy", deps.LateFee.SavePolicy)
+	protected.DELETE("/me/late-fee-policy", deps.LateFee.DeletePolicy)
+	protected.GET("/me/reminders", deps.Reminder.ListRules)
+	protected.PUT("/me/reminders", deps.Reminder.ReplaceRules)
+	protected.GET("/me/email-templates", deps.EmailTemplate.ListTemplates)
+	protected.GET("/me/email-templates/:kind", deps.EmailTemplate.GetTemplate)
+	protected.PUT("/me/email-templates/:kind", deps.EmailTemplate.SaveTemplate)
+	protected.DELETE("/me/email-templates/:kind", deps.EmailTemplate.ResetTemplate)
+	protected.POST("/me/email-templates/:kind/preview", deps.EmailTemplate.PreviewTemplate)
+	protected.POST("/me/email-templates/:kind/test", deps.EmailTemplate.SendTestEmail)
+	protected.GET("/me/invoice-branding", deps.Invoice.GetBranding)
+	protected.PUT("/me/invoice-branding", deps.Invoice.SaveBranding)
+	protected.DELETE("/me/invoice-branding", deps.Invoice.ResetBranding)
+	protected.POST("/me/invoice-branding/preview", deps.Invoice.PreviewBranding)
 
 	clientRoutes := protected.Group("/clients")
 	clientRoutes.POST("", deps.Client.CreateClient)
 	clientRoutes.GET("", deps.Client.GetAllClients)
+	clientRoutes.POST("/import", deps.Client.ImportClients)
+	clientRoutes.GET("/trash", deps.Client.ListTrashedClients)
 	clientRoutes.GET("/:id", deps.Client.GetClientByID)
 	clientRoutes.PUT("/:id", deps.Client.UpdateClient)
 	clientRoutes.DELETE("/:id", deps.Client.DeleteClient)
+	clientRoutes.POST("/:id/restore", deps.Client.RestoreClient)
+	clientRoutes.GET("/:id/late-fee-policy", deps.LateFee.GetClientPolicy)
+	clientRoutes.PUT("/:id/late-fee-policy", deps.LateFee.SaveClientPolicy)
+	clientRoutes.DELETE("/:id/late-fee-policy", deps.LateFee.DeleteClientPolicy)
 
 	invoiceRoutes := protected.Group("/invoices")
 	invoiceRoutes.GET("/summary", deps.Invoice.Summary)
 	invoiceRoutes.POST("", deps.Invoice.CreateInvoice)
+	invoiceRoutes.POST("/import", deps.Invoice.ImportInvoices)
+	invoiceRoutes.POST("/bulk", deps.Invoice.BulkInvoices)
+	invoiceRoutes.GET("/trash", deps.Invoice.ListTrashedInvoices)
+	invoiceRoutes.GET("/export", deps.Invoice.ExportInvoicePDFs)
 	invoiceRoutes.GET("/:id", deps.Invoice.GetInvoiceByID)
 	invoiceRoutes.PUT("/:id", deps.Invoice.UpdateInvoice)
 	invoiceRoutes.DELETE("/:id", deps.Invoice.DeleteInvoice)
+	invoiceRoutes.POST("/:id/restore", deps.Invoice.RestoreInvoice)
 	invoiceRoutes.GET("", deps.Invoice.ListInvoicesByUserID)
 	invoiceRoutes.PATCH("/:id/status", deps.Invoice.UpdateInvoiceStatus)
 	invoiceRoutes.POST("/:id/pdf", deps.Invoice.DownloadInvoicePDF)
+	invoiceRoutes.POST("/:id/pdf-jobs", deps.Invoice.EnqueuePDFJob)
+	invoiceRoutes.GET("/:id/qris", deps.Invoice.DownloadInvoiceQRIS)
+	invoiceRoutes.GET("/:id/ubl", deps.Invoice.DownloadInvoiceUBL)
+	invoiceRoutes.POST("/:id/duplicate", deps.Invoice.DuplicateInvoice)
+	invoiceRoutes.POST("/:id/send", deps.Invoice.SendInvoice)
+	invoiceRoutes.GET("/:id/deliveries", deps.Invoice.ListInvoiceDeliveries)
+	invoiceRoutes.POST("/:id/share-links", deps.Invoice.CreateShareLink)
+	invoiceRoutes.GET("/:id/share-links", deps.Invoice.ListShareLinks)
+	invoiceRoutes.DELETE("/:id/share-links/:linkId", deps.Invoice.RevokeShareLink)
+	invoiceRoutes.GET("/:id/late-fees", deps.LateFee.ListInvoiceFees)
+	invoiceRoutes.GET("/:id/reminders", deps.Reminder.ListInvoiceReminders)
+	invoiceRoutes.PATCH("/:id/reminders", deps.Reminder.SetInvoiceReminders)
+	invoiceRoutes.POST("/:id/payment-links", deps.Payment.CreatePaymentLink)
+	invoiceRoutes.GET("/:id/payment-links", deps.Payment.ListPaymentLinks)
+	invoiceRoutes.GET("/:id/payments", deps.Payment.ListPayments)
+	invoiceRoutes.POST("/:id/tax-serial", deps.TaxInvoice.AssignSerial)
+
+	pdfJobRoutes := protected.Group("/pdf-jobs")
+	pdfJobRoutes.GET("/:id", deps.Invoice.GetPDFJob)
+	pdfJobRoutes.GET("/:id/download", deps.Invoice.DownloadPDFJob)
+
+	taxInvoiceRoutes := protected.Group("/tax-invoices")
+	taxInvoiceRoutes.GET("/serial-ranges", deps.TaxInvoice.ListSerialRanges)
+	taxInvoiceRoutes.POST("/serial-ranges", deps.TaxInvoice.AddSerialRange)
+	taxInvoiceRoutes.DELETE("/serial-ranges/:id", deps.TaxInvoice.DeleteSerialRange)
+	taxInvoiceRoutes.GET("/export", deps.TaxInvoice.Export)
+
+	bankRoutes := protected.Group("/bank")
+	bankRoutes.POST("/statements", deps.Reconciliation.ImportStatement)
+	bankRoutes.GET("/transactions", deps.Reconciliation.ListTransactions)
+	bankRoutes.POST("/reconcile", deps.Reconciliation.Reconcile)
+	bankRoutes.GET("/matches", deps.Reconciliation.ListMatches)
+	bankRoutes.POST("/matches/:id/accept", deps.Reconciliation.AcceptMatch)
+	bankRoutes.POST("/matches/:id/reject", deps.Reconciliation.RejectMatch)
 
 	publicInvoices := public.Group("/invoices")
 	publicInvoices.POST("/generate-pdf", deps.Invoice.GeneratePublicInvoice)
+	publicInvoices.GET("/view/:token", deps.Invoice.ViewSharedInvoice)
+
+	publicPayments := public.Group("/payments")
+	publicPayments.POST("/webhooks/:provider", deps.Payment.Webhook)
+	if deps.FakePayments {
+		publicPayments.GET("/fake/:reference/pay", deps.Payment.SimulatePayment)
+		publicPayments.POST("/fake/:reference/pay", deps.Payment.SimulatePayment)
+	}
+
+	portalPublic := public.Group("/portal")
+	portalPublic.POST("/magic-link", deps.Portal.RequestMagicLink, middleware.RateLimitByIP(deps.MagicLinksPerIP))
+	portalPublic.POST("/login", deps.Portal.Login)
+
+	portal := v1.Group("/portal")
+	portal.Use(middleware.PortalMiddleware)
+	portal.GET("/me", deps.Portal.Me)
+	portal.GET("/invoices", deps.Portal.ListInvoices)
+	portal.GET("/invoices/:id", deps.Portal.GetInvoice)
+	portal.GET("/invoices/:id/pdf", deps.Portal.DownloadInvoicePDF)
+	portal.GET("/quotes", deps.Portal.ListQuotes)
+	portal.POST("/quotes/:id/accept", deps.Portal.AcceptQuote)
+	portal.GET("/statement", deps.Portal.Statement)
 }
@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+// PurgeTrashJob permanently deletes invoices and clients that have been in
+// the trash for longer than retention. Invoices go first so clients they
+// referenced become purgeable.
+func PurgeTrashJob(invoices ports.InvoiceUseCase, clients ports.ClientUseCase, retention, interval time.Duration) Job {
+	return Job{
+		Name:     "purge-trash",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			purgedInvoices, err := invoices.PurgeTrashed(retention)
+			if err != nil {
+				return err
+			}
+
+			purgedClients, err := clients.PurgeTrashed(retention)
+			if err != nil {
+				return err
+			}
+
+			if purgedInvoices > 0 || purgedClients > 0 {
+				log.Printf("Purged %d invoices and %d clients from trash", purgedInvoices, purgedClients)
+			}
+
+			return nil
+		},
+	}
+}
+
+// MarkOverdueJob moves sent invoices past their due date to OVERDUE.
+func MarkOverdueJob(invoices ports.InvoiceUseCase, interval time.Duration) Job {
+	return Job{
+		Name:     "mark-overdue",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			n, err := invoices.MarkOverdue(time.Now())
+			if err != nil {
+				return err
+			}
+
+			if n > 0 {
+				log.Printf("Marked %d invoices overdue", n)
+			}
+
+			return nil
+		},
+	}
+}
+
+// LateFeeJob charges the late fees accrued by overdue invoices.
+func LateFeeJob(lateFees ports.LateFeeUseCase, interval time.Duration) Job {
+	return Job{
+		Name:     "late-fees",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			n, err := lateFees.ApplyDue(time.Now())
+			if n > 0 {
+				log.Printf("Applied %d late fees", n)
+			}
+
+			return err
+		},
+	}
+}
+
+// ReminderJob sends the payment reminders that have fallen due.
+func ReminderJob(reminders ports.ReminderUseCase, interval time.Duration) Job {
+	return Job{
+		Name:     "reminders",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			n, err := reminders.SendDue(ctx, time.Now())
+			if n > 0 {
+				log.Printf("Sent %d payment reminders", n)
+			}
+
+			return err
+		},
+	}
+}
@@ -2,10 +2,12 @@ package auth
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
 	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+	"github.com/hutamy/go-invoice-backend/pkg/qris"
 )
 
 type UseCase struct {
@@ -148,6 +150,51 @@ func (u *UseCase) UpdateUserBanking(userID uint, update entity.User) error {
 	return u.AuthRepo.UpdateUserBanking(userID, update)
 }
 
+// UpdateUserQRIS stores the static QRIS payload printed on the user's QRIS
+// sticker, from which invoice QR codes are derived. An empty payload removes it.
+func (u *UseCase) UpdateUserQRIS(userID uint, payload string) error {
+	payload = strings.TrimSpace(payload)
+	if payload != "" {
+		if _, err := qris.Validate(payload); err != nil {
+			return err
+		}
+	}
+
+	return u.AuthRepo.UpdateUserQRIS(userID, payload)
+}
+
+// UpdateUserTaxID stores the NPWP and NITKU the user issues tax invoices
+// under. An empty NPWP removes both.
+func (u *UseCase) UpdateUserTaxID(userID uint, npwp, nitku string) error {
+	if strings.TrimSpace(npwp) == "" {
+		return u.AuthRepo.UpdateUserTaxID(userID, "", "")
+	}
+
+	npwp, err := entity.NormalizeNPWP(npwp)
+	if err != nil {
+		return err
+	}
+
+	nitku, err = entity.NormalizeNITKU(nitku, npwp)
+	if err != nil {
+		return err
+	}
+
+	return u.AuthRepo.UpdateUserTaxID(userID, npwp, nitku)
+}
+
+func (u *UseCase) UpdatePaymentTerms(userID uint, terms entity.PaymentTerms, days int) error {
+	if !terms.IsValid() {
+		return errors.New("invalid payment terms")
+	}
+
+	if terms == entity.PaymentTermsCustom && days <= 0 {
+		return errors.New("payment terms days must be greater than 0")
+	}
+
+	return u.AuthRepo.UpdatePaymentTerms(userID, terms, days)
+}
+
 func (u *UseCase) ChangePassword(userID uint, oldPassword, newPassword string) error {
 	user, err := u.AuthRepo.GetUserByID(userID)
 	if err != nil {
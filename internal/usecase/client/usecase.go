@@ -2,6 +2,8 @@ package client
 
 import (
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
 	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
@@ -18,6 +20,10 @@ func NewUseCase(repo ports.ClientRepository) ports.ClientUseCase {
 }
 
 func (u *UseCase) Create(c *entity.Client) error {
+	if err := normalizeTaxID(c); err != nil {
+		return err
+	}
+
 	return u.Repo.Create(c)
 }
 
@@ -42,9 +48,121 @@ func (u *UseCase) ListByUser(userID uint, page, pageSize int, search string) ([]
 }
 
 func (u *UseCase) Update(update entity.Client) error {
+	if err := normalizeTaxID(&update); err != nil {
+		return err
+	}
+
 	return u.Repo.Update(update)
 }
 
 func (u *UseCase) Delete(id uint, userID uint) error {
 	return u.Repo.Delete(id, userID)
 }
+
+func (u *UseCase) ListTrashed(userID uint, page, pageSize int) ([]entity.Client, int64, error) {
+	if userID == 0 {
+		return nil, 0, errors.New("unauthorized")
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	return u.Repo.ListTrashedByUser(userID, page, pageSize)
+}
+
+func (u *UseCase) Restore(id, userID uint) error {
+	return u.Repo.Restore(id, userID)
+}
+
+func (u *UseCase) PurgeTrashed(retention time.Duration) (int64, error) {
+	return u.Repo.PurgeDeletedBefore(time.Now().Add(-retention))
+}
+
+func (u *UseCase) Import(userID uint, rows []entity.ClientImportRow, dryRun bool) (*entity.ClientImportReport, error) {
+	if userID == 0 {
+		return nil, errors.New("unauthorized")
+	}
+
+	existing, err := u.Repo.ListEmailsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(existing)+len(rows))
+	for _, email := range existing {
+		seen[strings.ToLower(strings.TrimSpace(email))] = true
+	}
+
+	report := &entity.ClientImportReport{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   make([]entity.ClientImportResult, 0, len(rows)),
+	}
+	for _, row := range rows {
+		res := entity.ClientImportResult{
+			Row:   row.Row,
+			Name:  row.Client.Name,
+			Email: row.Client.Email,
+		}
+
+		key := strings.ToLower(strings.TrimSpace(row.Client.Email))
+		switch {
+		case row.Error != "":
+			res.Status = entity.ImportRowStatusFailed
+			res.Error = row.Error
+			report.Failed++
+		case seen[key]:
+			res.Status = entity.ImportRowStatusSkipped
+			res.Error = "client with this email already exists"
+			report.Skipped++
+		case dryRun:
+			seen[key] = true
+			res.Status = entity.ImportRowStatusValid
+			report.Valid++
+		default:
+			client := row.Client
+			client.UserID = userID
+			if err := u.Repo.Create(&client); err != nil {
+				res.Status = entity.ImportRowStatusFailed
+				res.Error = err.Error()
+				report.Failed++
+				break
+			}
+
+			seen[key] = true
+			res.Status = entity.ImportRowStatusCreated
+			report.Created++
+		}
+
+		report.Rows = append(report.Rows, res)
+	}
+
+	return report, nil
+}
+
+// normalizeTaxID stores the NPWP and NITKU of c without punctuation, as tax
+// invoices carry them.
+func normalizeTaxID(c *entity.Client) error {
+	if strings.TrimSpace(c.NPWP) == "" {
+		c.NPWP, c.NITKU = "", ""
+		return nil
+	}
+
+	npwp, err := entity.NormalizeNPWP(c.NPWP)
+	if err != nil {
+		return err
+	}
+
+	nitku, err := entity.NormalizeNITKU(c.NITKU, npwp)
+	if err != nil {
+		return err
+	}
+
+	c.NPWP, c.NITKU = npwp, nitku
+	return nil
+}
@@ -0,0 +1,204 @@
+package emailtemplate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+type UseCase struct {
+	TemplateRepo ports.EmailTemplateRepository
+	InvoiceRepo  ports.InvoiceRepository
+	AuthRepo     ports.AuthRepository
+	PaymentRepo  ports.PaymentRepository
+	Mailer       ports.Mailer
+}
+
+func NewUseCase(
+	templateRepo ports.EmailTemplateRepository,
+	invoiceRepo ports.InvoiceRepository,
+	authRepo ports.AuthRepository,
+	paymentRepo ports.PaymentRepository,
+	mailer ports.Mailer,
+) ports.EmailTemplateUseCase {
+	return &UseCase{
+		TemplateRepo: templateRepo,
+		InvoiceRepo:  invoiceRepo,
+		AuthRepo:     authRepo,
+		PaymentRepo:  paymentRepo,
+		Mailer:       mailer,
+	}
+}
+
+// List returns every kind of template, the user's own where they have one
+// and the built-in default otherwise.
+func (u *UseCase) List(userID uint) ([]entity.EmailTemplate, error) {
+	custom, err := u.TemplateRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byKind := map[entity.EmailTemplateKind]entity.EmailTemplate{}
+	for _, t := range custom {
+		byKind[t.Kind] = t
+	}
+
+	out := make([]entity.EmailTemplate, 0, len(entity.EmailTemplateKinds))
+	for _, kind := range entity.EmailTemplateKinds {
+		t, ok := byKind[kind]
+		if !ok {
+			t = entity.DefaultEmailTemplate(kind)
+		}
+
+		out = append(out, t)
+	}
+
+	return out, nil
+}
+
+func (u *UseCase) Get(userID uint, kind entity.EmailTemplateKind) (*entity.EmailTemplate, error) {
+	if !kind.IsValid() {
+		return nil, fmt.Errorf("unknown template kind %q", kind)
+	}
+
+	t, err := u.TemplateRepo.Get(userID, kind)
+	if err != nil || t != nil {
+		return t, err
+	}
+
+	def := entity.DefaultEmailTemplate(kind)
+	return &def, nil
+}
+
+// Save stores the template after checking it renders.
+func (u *UseCase) Save(template *entity.EmailTemplate) error {
+	if !template.Kind.IsValid() {
+		return fmt.Errorf("unknown template kind %q", template.Kind)
+	}
+
+	user, err := u.user(template.UserID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := template.Render(entity.SampleEmailTemplateData(user)); err != nil {
+		return err
+	}
+
+	return u.TemplateRepo.Save(template)
+}
+
+func (u *UseCase) Reset(userID uint, kind entity.EmailTemplateKind) error {
+	if !kind.IsValid() {
+		return fmt.Errorf("unknown template kind %q", kind)
+	}
+
+	return u.TemplateRepo.Delete(userID, kind)
+}
+
+// Preview renders template, with empty fields taken from the user's saved
+// template, against the given invoice or sample data when invoiceID is nil.
+func (u *UseCase) Preview(template entity.EmailTemplate, invoiceID *uint) (*entity.RenderedEmail, error) {
+	t, user, err := u.resolve(template)
+	if err != nil {
+		return nil, err
+	}
+
+	data := entity.SampleEmailTemplateData(user)
+	if invoiceID != nil {
+		invoice, err := u.InvoiceRepo.GetByID(*invoiceID, template.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		if invoice == nil {
+			return nil, errors.New("invoice not found")
+		}
+
+		links, err := u.PaymentRepo.ListLinks(invoice.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		data = entity.NewEmailTemplateData(invoice, user, links, time.Now())
+	}
+
+	rendered, err := t.Render(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rendered, nil
+}
+
+// SendTest emails the template rendered with sample data to the user.
+func (u *UseCase) SendTest(ctx context.Context, template entity.EmailTemplate) (*entity.RenderedEmail, error) {
+	if u.Mailer == nil {
+		return nil, errors.New("email delivery is not configured")
+	}
+
+	t, user, err := u.resolve(template)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := t.Render(entity.SampleEmailTemplateData(user))
+	if err != nil {
+		return nil, err
+	}
+
+	rendered.Subject = "[Test] " + rendered.Subject
+	if err := u.Mailer.Send(ctx, entity.Mail{
+		To:       []string{user.Email},
+		Subject:  rendered.Subject,
+		Body:     rendered.Body,
+		HTMLBody: rendered.HTMLBody,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to send test email: %w", err)
+	}
+
+	return &rendered, nil
+}
+
+func (u *UseCase) resolve(template entity.EmailTemplate) (*entity.EmailTemplate, *entity.User, error) {
+	saved, err := u.Get(template.UserID, template.Kind)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if template.Subject != "" {
+		saved.Subject = template.Subject
+	}
+
+	if template.Body != "" {
+		saved.Body = template.Body
+	}
+
+	if template.HTMLBody != "" {
+		saved.HTMLBody = template.HTMLBody
+	}
+
+	user, err := u.user(template.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return saved, user, nil
+}
+
+func (u *UseCase) user(userID uint) (*entity.User, error) {
+	user, err := u.AuthRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	return user, nil
+}
@@ -0,0 +1,311 @@
+package invoice
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/pkg/i18n"
+)
+
+// Limits for custom invoice templates and logos.
+const (
+	maxRenderedTemplateSize = 2 << 20
+	maxLogoDimension        = 2000
+)
+
+var errTemplateOutputTooLarge = errors.New("invoice template output is too large")
+
+// GetBranding returns the user's invoice branding, or the default look when
+// they have not set one.
+func (u *UseCase) GetBranding(userID uint) (*entity.InvoiceBranding, error) {
+	b, err := u.BrandingRepo.Get(userID)
+	if err != nil || b != nil {
+		return b, err
+	}
+
+	def := entity.DefaultInvoiceBranding()
+	def.UserID = userID
+	return &def, nil
+}
+
+// SaveBranding stores the branding after checking that a sample invoice
+// renders with it.
+func (u *UseCase) SaveBranding(branding *entity.InvoiceBranding) error {
+	if _, err := u.PreviewBranding(*branding, nil); err != nil {
+		return err
+	}
+
+	applyBrandingDefaults(branding)
+	if err := u.BrandingRepo.Save(branding); err != nil {
+		return err
+	}
+
+	u.invalidatePDFs(0, branding.UserID)
+	return nil
+}
+
+func (u *UseCase) ResetBranding(userID uint) error {
+	if err := u.BrandingRepo.Delete(userID); err != nil {
+		return err
+	}
+
+	u.invalidatePDFs(0, userID)
+	return nil
+}
+
+// PreviewBranding renders the given invoice, or a sample invoice when
+// invoiceID is nil, as HTML with branding.
+func (u *UseCase) PreviewBranding(branding entity.InvoiceBranding, invoiceID *uint) (string, error) {
+	applyBrandingDefaults(&branding)
+	if err := validateBranding(branding); err != nil {
+		return "", err
+	}
+
+	user, err := u.AuthRepo.GetUserByID(branding.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	if user == nil {
+		return "", errors.New("user not found")
+	}
+
+	invoice, client := sampleInvoice(*user)
+	if invoiceID != nil {
+		doc, err := u.document(*invoiceID, branding.UserID)
+		if err != nil {
+			return "", err
+		}
+
+		invoice, client = doc.Invoice, doc.Client
+	}
+
+	return u.generateTemplate(invoice, *user, client, branding)
+}
+
+// branding returns the branding to render the user's invoices with.
+func (u *UseCase) branding(userID uint) (entity.InvoiceBranding, error) {
+	b, err := u.GetBranding(userID)
+	if err != nil {
+		return entity.InvoiceBranding{}, err
+	}
+
+	return *b, nil
+}
+
+func applyBrandingDefaults(b *entity.InvoiceBranding) {
+	def := entity.DefaultInvoiceBranding()
+	if b.Layout == "" {
+		b.Layout = def.Layout
+	}
+
+	if b.AccentColor == "" {
+		b.AccentColor = def.AccentColor
+	}
+
+	if b.Font == "" {
+		b.Font = def.Font
+	}
+}
+
+// validateBranding checks the settings and that the logo is an image of a
+// sensible size.
+func validateBranding(b entity.InvoiceBranding) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+
+	if b.Logo == "" {
+		return nil
+	}
+
+	data, _, err := b.LogoImage()
+	if err != nil {
+		return err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return errors.New("logo is not a valid PNG or JPEG image")
+	}
+
+	if cfg.Width > maxLogoDimension || cfg.Height > maxLogoDimension {
+		return fmt.Errorf("logo must be at most %dx%d pixels", maxLogoDimension, maxLogoDimension)
+	}
+
+	return nil
+}
+
+// templateData flattens the invoice into the variables custom templates
+// may use, formatted for the invoice's language.
+func templateData(invoice entity.Invoice, user entity.User, client entity.Client, branding entity.InvoiceBranding) entity.InvoiceTemplateData {
+	loc := i18n.For(invoiceLanguage(invoice, client))
+	data := entity.InvoiceTemplateData{
+		Language:      loc.Lang,
+		Labels:        templateLabels(loc, invoice.TaxRate),
+		InvoiceNumber: invoice.InvoiceNumber,
+		Status:        invoice.Status,
+		IssueDate:     loc.Date(invoice.IssueDate),
+		DueDate:       loc.Date(invoice.DueDate),
+		PaymentTerms:  invoice.PaymentTerms.Localized(loc.T, invoice.PaymentTermsDays),
+		Currency:      loc.Currency,
+		Sender: entity.InvoiceTemplateParty{
+			Name:    user.Name,
+			Address: user.Address,
+			Email:   user.Email,
+			Phone:   user.Phone,
+		},
+		Client: entity.InvoiceTemplateParty{
+			Name:    client.Name,
+			Address: client.Address,
+			Email:   client.Email,
+			Phone:   client.Phone,
+		},
+		Subtotal: loc.Number(invoice.Subtotal),
+		TaxRate:  loc.Number(invoice.TaxRate),
+		Tax:      loc.Number(invoice.Tax),
+		Total:    loc.Number(invoice.Total),
+		Notes:    invoice.Notes,
+		Bank: entity.InvoiceTemplateBank{
+			Name:          user.BankName,
+			AccountName:   user.BankAccountName,
+			AccountNumber: user.BankAccountNumber,
+		},
+		QRISImage:   htmltemplate.URL(qrisImage(invoice, user)),
+		Logo:        htmltemplate.URL(branding.Logo),
+		AccentColor: htmltemplate.CSS(branding.AccentColor),
+		Font:        htmltemplate.CSS(branding.FontStack()),
+		FooterText:  branding.FooterText,
+	}
+
+	if invoice.DeliveryFee > 0 {
+		data.DeliveryFee = loc.Number(invoice.DeliveryFee)
+	}
+
+	if invoice.AmountInWords {
+		data.AmountInWords = loc.Words(invoice.Total)
+	}
+
+	for _, it := range invoice.Items {
+		data.Items = append(data.Items, entity.InvoiceTemplateItem{
+			Description: it.Description,
+			Quantity:    fmt.Sprintf("%d", it.Quantity),
+			UnitPrice:   loc.Number(it.UnitPrice),
+			Total:       loc.Number(it.Total),
+		})
+	}
+
+	return data
+}
+
+// invoiceLanguage returns the language to print the invoice in: its own,
+// else the client's, else English.
+func invoiceLanguage(invoice entity.Invoice, client entity.Client) string {
+	for _, lang := range []string{invoice.Language, client.Language} {
+		if i18n.IsSupported(lang) {
+			return lang
+		}
+	}
+
+	return i18n.English
+}
+
+func templateLabels(loc *i18n.Locale, taxRate float64) entity.InvoiceTemplateLabels {
+	return entity.InvoiceTemplateLabels{
+		Title:           loc.T("Invoice"),
+		Heading:         loc.T("INVOICE"),
+		IssueDate:       loc.T("Issue Date:"),
+		DueDate:         loc.T("Due Date:"),
+		PaymentTerms:    loc.T("Payment Terms:"),
+		From:            loc.T("From"),
+		To:              loc.T("To"),
+		Description:     loc.T("Description"),
+		Quantity:        loc.T("Quantity"),
+		UnitPrice:       loc.T("Unit Price"),
+		Total:           loc.T("Total"),
+		Subtotal:        loc.T("Subtotal:"),
+		Tax:             loc.T("Tax (%s%%):", loc.Number(taxRate)),
+		DeliveryFee:     loc.T("Delivery Fee:"),
+		TotalDue:        loc.T("Total:"),
+		AmountInWords:   loc.T("Amount in words:"),
+		Terms:           loc.T("Terms:"),
+		ThankYou:        loc.T("Thank you"),
+		ForYourBusiness: loc.T("for your business!"),
+		BankDetails:     loc.T("Bank Account Details"),
+		BankName:        loc.T("Bank Name:"),
+		AccountName:     loc.T("Account Name:"),
+		AccountNumber:   loc.T("Account Number:"),
+		ScanToPay:       loc.T("Scan to pay with QRIS"),
+	}
+}
+
+// renderCustomTemplate executes a user's invoice template. Only the
+// template data is reachable from it and its output is capped.
+func renderCustomTemplate(src string, data entity.InvoiceTemplateData) (string, error) {
+	tmpl, err := htmltemplate.New("invoice").Option("missingkey=error").Parse(src)
+	if err != nil {
+		return "", fmt.Errorf("invalid invoice template: %w", err)
+	}
+
+	w := &cappedBuffer{max: maxRenderedTemplateSize}
+	if err := tmpl.Execute(w, data); err != nil {
+		if errors.Is(err, errTemplateOutputTooLarge) {
+			return "", errTemplateOutputTooLarge
+		}
+
+		return "", fmt.Errorf("invalid invoice template: %w", err)
+	}
+
+	return w.String(), nil
+}
+
+// cappedBuffer is a bytes.Buffer that refuses to grow past max bytes.
+type cappedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.max {
+		return 0, errTemplateOutputTooLarge
+	}
+
+	return b.Buffer.Write(p)
+}
+
+// sampleInvoice returns a made-up invoice from user for previews.
+func sampleInvoice(user entity.User) (entity.Invoice, entity.Client) {
+	now := time.Now()
+	client := entity.Client{
+		Name:    "Jane Doe",
+		Email:   "jane@example.com",
+		Address: "Jl. Sudirman No. 1, Jakarta",
+		Phone:   "+62 812 0000 0000",
+	}
+
+	invoice := entity.Invoice{
+		UserID:        user.ID,
+		InvoiceNumber: "INV-0001",
+		Status:        string(entity.InvoiceStatusSent),
+		IssueDate:     now,
+		DueDate:       now.AddDate(0, 0, 30),
+		Items: []entity.InvoiceItem{
+			{Description: "Website design", Quantity: 1, UnitPrice: 1000000, Total: 1000000},
+			{Description: "Hosting (12 months)", Quantity: 12, UnitPrice: 25000, Total: 300000},
+		},
+		Subtotal: 1300000,
+		TaxRate:  11,
+		Tax:      143000,
+		Total:    1443000,
+		Notes:    "Payment within 30 days.",
+	}
+
+	return invoice, client
+}
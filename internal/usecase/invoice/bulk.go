@@ -0,0 +1,168 @@
+package invoice
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+const maxBulkInvoices = 100
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func (u *UseCase) Bulk(userID uint, ids []uint, action entity.InvoiceBulkAction, status entity.InvoiceStatus) ([]entity.InvoiceBulkResult, error) {
+	if err := validateBulkIDs(ids); err != nil {
+		return nil, err
+	}
+
+	var apply func(id uint) (uint, error)
+	switch action {
+	case entity.InvoiceBulkActionStatus:
+		if status == "" {
+			return nil, errors.New("status is required")
+		}
+
+		apply = func(id uint) (uint, error) {
+			return 0, u.UpdateStatus(id, userID, status)
+		}
+	case entity.InvoiceBulkActionDelete:
+		apply = func(id uint) (uint, error) {
+			return 0, u.Delete(id, userID)
+		}
+	case entity.InvoiceBulkActionDuplicate:
+		apply = func(id uint) (uint, error) {
+			dup, err := u.Duplicate(id, userID, time.Time{})
+			if err != nil {
+				return 0, err
+			}
+			return dup.ID, nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported action %q", action)
+	}
+
+	results := make([]entity.InvoiceBulkResult, 0, len(ids))
+	for _, id := range ids {
+		res := entity.InvoiceBulkResult{ID: id}
+		if err := u.checkOwnership(id, userID); err != nil {
+			res.Error = err.Error()
+			results = append(results, res)
+			continue
+		}
+
+		newID, err := apply(id)
+		if err != nil {
+			res.Error = err.Error()
+		} else {
+			res.Success = true
+			res.NewInvoiceID = newID
+		}
+
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+// ExportPDFs renders every invoice in ids and writes them to w as a zip
+// archive with a manifest.csv, the same archive ExportPDFsByFilter writes.
+// Invoices that are not found or cannot be rendered are reported in the
+// manifest as well as in the returned results.
+func (u *UseCase) ExportPDFs(ctx context.Context, w io.Writer, userID uint, ids []uint) ([]entity.InvoiceBulkResult, error) {
+	if err := validateBulkIDs(ids); err != nil {
+		return nil, err
+	}
+
+	var invoices []entity.Invoice
+	var missing, unique []uint
+	seen := map[uint]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		unique = append(unique, id)
+		invoice, err := u.InvoiceRepo.GetByID(id, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		if invoice == nil {
+			missing = append(missing, id)
+			continue
+		}
+
+		invoices = append(invoices, *invoice)
+	}
+
+	failed, err := u.writePDFArchive(ctx, w, userID, invoices, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint]bool, len(invoices))
+	for _, inv := range invoices {
+		found[inv.ID] = true
+	}
+
+	results := make([]entity.InvoiceBulkResult, 0, len(unique))
+	for _, id := range unique {
+		res := entity.InvoiceBulkResult{ID: id, Success: true}
+		switch {
+		case !found[id]:
+			res.Success, res.Error = false, "invoice not found"
+		case failed[id] != nil:
+			res.Success, res.Error = false, failed[id].Error()
+		}
+
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+func (u *UseCase) checkOwnership(id, userID uint) error {
+	invoice, err := u.InvoiceRepo.GetByID(id, userID)
+	if err != nil {
+		return err
+	}
+
+	if invoice == nil {
+		return errors.New("invoice not found")
+	}
+
+	return nil
+}
+
+func validateBulkIDs(ids []uint) error {
+	if len(ids) == 0 {
+		return errors.New("ids is required")
+	}
+
+	if len(ids) > maxBulkInvoices {
+		return fmt.Errorf("at most %d invoices per request", maxBulkInvoices)
+	}
+
+	return nil
+}
+
+func pdfFileName(invoice *entity.Invoice) string {
+	return fmt.Sprintf("%s_%d.pdf", unsafeFileChars.ReplaceAllString(invoice.InvoiceNumber, "_"), invoice.ID)
+}
+
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	return err
+}
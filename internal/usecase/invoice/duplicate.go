@@ -0,0 +1,109 @@
+package invoice
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+const maxInvoiceNumberAttempts = 1000
+
+var trailingDigits = regexp.MustCompile(`^(.*?)(\d+)$`)
+
+// Duplicate copies an invoice into a new draft with a fresh invoice number.
+// The copy is issued on issueDate (today when zero) and gets its due date from
+// the invoice payment terms, or keeps the original gap between issue and due
+// date when it has none.
+func (u *UseCase) Duplicate(id, userID uint, issueDate time.Time) (*entity.Invoice, error) {
+	src, err := u.InvoiceRepo.GetByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if src == nil {
+		return nil, errors.New("invoice not found")
+	}
+
+	number, err := u.nextInvoiceNumber(userID, src.InvoiceNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if issueDate.IsZero() {
+		issueDate = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+
+	dueDate := issueDate.Add(src.DueDate.Sub(src.IssueDate))
+	if src.PaymentTerms != "" {
+		dueDate = src.PaymentTerms.DueDate(issueDate, src.PaymentTermsDays)
+	}
+
+	dup := &entity.Invoice{
+		UserID:           userID,
+		ClientID:         src.ClientID,
+		ClientName:       src.ClientName,
+		ClientEmail:      src.ClientEmail,
+		ClientAddress:    src.ClientAddress,
+		ClientPhone:      src.ClientPhone,
+		InvoiceNumber:    number,
+		IssueDate:        issueDate,
+		DueDate:          dueDate,
+		PaymentTerms:     src.PaymentTerms,
+		PaymentTermsDays: src.PaymentTermsDays,
+		Status:           string(entity.InvoiceStatusDraft),
+		Notes:            src.Notes,
+		TaxRate:          src.TaxRate,
+		DeliveryFee:      src.DeliveryFee,
+		Language:         src.Language,
+		AmountInWords:    src.AmountInWords,
+	}
+	for _, it := range src.Items {
+		dup.Items = append(dup.Items, entity.InvoiceItem{
+			Description: it.Description,
+			Quantity:    it.Quantity,
+			UnitPrice:   it.UnitPrice,
+		})
+	}
+
+	if err := u.InvoiceRepo.Create(dup); err != nil {
+		return nil, err
+	}
+
+	return dup, nil
+}
+
+// nextInvoiceNumber derives an unused invoice number from base by
+// incrementing its trailing number (keeping zero padding), or by appending
+// a counter when base does not end in digits.
+func (u *UseCase) nextInvoiceNumber(userID uint, base string) (string, error) {
+	prefix, digits := base+"-", ""
+	if m := trailingDigits.FindStringSubmatch(base); m != nil {
+		prefix, digits = m[1], m[2]
+	}
+
+	start := 1
+	if digits != "" {
+		n, err := strconv.Atoi(digits)
+		if err == nil {
+			start = n + 1
+		}
+	}
+
+	for n := start; n < start+maxInvoiceNumberAttempts; n++ {
+		candidate := fmt.Sprintf("%s%0*d", prefix, len(digits), n)
+		exists, err := u.InvoiceRepo.ExistsByNumber(userID, candidate)
+		if err != nil {
+			return "", err
+		}
+
+		if !exists {
+			return candidate, nil
+		}
+	}
+
+	return "", errors.New("unable to allocate invoice number")
+}
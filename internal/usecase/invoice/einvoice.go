@@ -0,0 +1,84 @@
+package invoice
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+const (
+	// Invoices are issued in rupiah by senders and clients in Indonesia;
+	// addresses are free text, so the country cannot be read from them.
+	invoiceCurrency = "IDR"
+	invoiceCountry  = "ID"
+
+	// UN/ECE codes shared by the e-invoice syntaxes.
+	commercialInvoice = "380"
+	creditTransfer    = "30"
+	unitPiece         = "C62"
+)
+
+// VAT categories of EN 16931.
+const (
+	vatStandard  = "S"
+	vatZeroRated = "Z"
+	vatExempt    = "E"
+)
+
+const deliveryFeeExemption = "Delivery fee not subject to VAT"
+
+// vatSubtotal is the VAT on the invoice amounts of one category.
+type vatSubtotal struct {
+	category        string
+	rate            float64
+	basis           float64
+	tax             float64
+	exemptionReason string
+}
+
+// vatBreakdown splits the invoice into VAT categories for e-invoices. Items
+// are taxed at the invoice's tax rate, or zero rated without one. The
+// delivery fee is never taxed: it is exempt next to taxed items and zero
+// rated along with untaxed ones.
+func vatBreakdown(inv entity.Invoice) (itemCategory, feeCategory string, subtotals []vatSubtotal) {
+	itemCategory, feeCategory = vatStandard, vatExempt
+	if inv.TaxRate == 0 {
+		itemCategory, feeCategory = vatZeroRated, vatZeroRated
+	}
+
+	subtotals = []vatSubtotal{{category: itemCategory, rate: inv.TaxRate, basis: inv.Subtotal, tax: inv.Tax}}
+	switch {
+	case inv.DeliveryFee == 0:
+	case feeCategory == itemCategory:
+		subtotals[0].basis += inv.DeliveryFee
+	default:
+		subtotals = append(subtotals, vatSubtotal{category: feeCategory, basis: inv.DeliveryFee, exemptionReason: deliveryFeeExemption})
+	}
+
+	return itemCategory, feeCategory, subtotals
+}
+
+// vatID returns the VAT identifier of an NPWP, prefixed with the country as
+// EN 16931 requires, or "" without one.
+func vatID(npwp string) string {
+	if npwp == "" {
+		return ""
+	}
+
+	return invoiceCountry + npwp
+}
+
+// oneLine joins a multi-line address into the single line e-invoices hold.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func amount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// percent formats a tax rate, which invoices store as a percentage.
+func percent(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', -1, 64)
+}
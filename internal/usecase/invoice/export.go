@@ -0,0 +1,236 @@
+package invoice
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+const (
+	maxExportInvoices = 1000
+	// exportConcurrency bounds the PDFs rendered at once for one export, on
+	// top of whatever limit the renderer has.
+	exportConcurrency = 4
+)
+
+type exportedPDF struct {
+	pdf  []byte
+	err  error
+	slot bool // holds one of the concurrency slots
+}
+
+// ExportPDFsByFilter renders every invoice matching filter and streams them
+// to w as a zip archive with a manifest.csv of their totals. Nothing is
+// written to w when the invoices cannot be listed. Invoices that fail to
+// render are left out of the archive and reported in the manifest.
+func (u *UseCase) ExportPDFsByFilter(ctx context.Context, w io.Writer, userID uint, filter entity.InvoiceExportFilter) error {
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return errors.New("to must not be before from")
+	}
+
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return fmt.Errorf("invalid status %q", filter.Status)
+	}
+
+	invoices, err := u.InvoiceRepo.ListForExport(userID, filter, maxExportInvoices+1)
+	if err != nil {
+		return err
+	}
+
+	if len(invoices) == 0 {
+		return errors.New("no invoices match the filters")
+	}
+
+	if len(invoices) > maxExportInvoices {
+		return fmt.Errorf("more than %d invoices match, narrow the filters", maxExportInvoices)
+	}
+
+	_, err = u.writePDFArchive(ctx, w, userID, invoices, nil)
+	return err
+}
+
+// writePDFArchive renders the invoices and writes them to w, in order, as a
+// zip archive with a manifest.csv of their totals. The IDs in missing are
+// listed in the manifest as not found. It returns the errors of the
+// invoices that failed to render, by invoice ID.
+func (u *UseCase) writePDFArchive(ctx context.Context, w io.Writer, userID uint, invoices []entity.Invoice, missing []uint) (map[uint]error, error) {
+	// Stops the renders still running when the archive cannot be finished.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	next := u.renderPDFs(ctx, invoices, userID)
+	zw := zip.NewWriter(w)
+	manifest := newExportManifest()
+	failed := map[uint]error{}
+	for i, inv := range invoices {
+		pdf, err := next(i)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
+		name := ""
+		if err == nil {
+			name = pdfFileName(&inv)
+			if err := writeZipFile(zw, name, pdf); err != nil {
+				return nil, err
+			}
+		} else {
+			failed[inv.ID] = err
+		}
+
+		manifest.add(inv, name, err)
+	}
+
+	for _, id := range missing {
+		manifest.addMissing(id)
+	}
+
+	data, err := manifest.bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeZipFile(zw, "manifest.csv", data); err != nil {
+		return nil, err
+	}
+
+	return failed, zw.Close()
+}
+
+// renderPDFs renders the invoices at most exportConcurrency at a time and
+// returns a function that waits for the i-th PDF, so the caller can write
+// them in order while later ones render. A slot is only freed once the
+// caller has taken the PDF, which bounds the PDFs held in memory.
+func (u *UseCase) renderPDFs(ctx context.Context, invoices []entity.Invoice, userID uint) func(i int) ([]byte, error) {
+	results := make([]chan exportedPDF, len(invoices))
+	for i := range results {
+		results[i] = make(chan exportedPDF, 1)
+	}
+
+	slots := make(chan struct{}, exportConcurrency)
+	go func() {
+		for i, inv := range invoices {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				for _, ch := range results[i:] {
+					ch <- exportedPDF{err: ctx.Err()}
+				}
+				return
+			}
+
+			go func() {
+				pdf, err := u.GeneratePDF(ctx, inv.ID, userID)
+				results[i] <- exportedPDF{pdf: pdf, err: err, slot: true}
+			}()
+		}
+	}()
+
+	return func(i int) ([]byte, error) {
+		res := <-results[i]
+		if res.slot {
+			<-slots
+		}
+
+		return res.pdf, res.err
+	}
+}
+
+// exportManifest is the CSV listing each exported invoice and a final row
+// with the sums.
+type exportManifest struct {
+	rows                                    [][]string
+	invoices                                int
+	subtotal, tax, deliveryFee, total, paid float64
+}
+
+func newExportManifest() *exportManifest {
+	return &exportManifest{
+		rows: [][]string{{
+			"file", "invoice_number", "client", "issue_date", "due_date", "status",
+			"subtotal", "tax", "delivery_fee", "total", "amount_paid", "error",
+		}},
+	}
+}
+
+func (m *exportManifest) add(inv entity.Invoice, file string, err error) {
+	client := ""
+	if inv.ClientName != nil {
+		client = *inv.ClientName
+	}
+
+	errText := ""
+	if err != nil {
+		errText = err.Error()
+	}
+
+	m.rows = append(m.rows, []string{
+		file,
+		csvText(inv.InvoiceNumber),
+		csvText(client),
+		inv.IssueDate.Format(time.DateOnly),
+		inv.DueDate.Format(time.DateOnly),
+		inv.Status,
+		csvAmount(inv.Subtotal),
+		csvAmount(inv.Tax),
+		csvAmount(inv.DeliveryFee),
+		csvAmount(inv.Total),
+		csvAmount(inv.AmountPaid),
+		csvText(errText),
+	})
+
+	m.invoices++
+	m.subtotal += inv.Subtotal
+	m.tax += inv.Tax
+	m.deliveryFee += inv.DeliveryFee
+	m.total += inv.Total
+	m.paid += inv.AmountPaid
+}
+
+// addMissing lists an invoice that was asked for but not found, which adds
+// nothing to the totals.
+func (m *exportManifest) addMissing(id uint) {
+	m.rows = append(m.rows, []string{
+		"", "", "", "", "", "", "", "", "", "", "", fmt.Sprintf("invoice %d not found", id),
+	})
+}
+
+func (m *exportManifest) bytes() ([]byte, error) {
+	rows := append(m.rows, []string{
+		"TOTAL", fmt.Sprintf("%d invoices", m.invoices), "", "", "", "",
+		csvAmount(m.subtotal),
+		csvAmount(m.tax),
+		csvAmount(m.deliveryFee),
+		csvAmount(m.total),
+		csvAmount(m.paid),
+		"",
+	})
+
+	var b strings.Builder
+	cw := csv.NewWriter(&b)
+	if err := cw.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return []byte(b.String()), nil
+}
+
+func csvAmount(v float64) string {
+	return fmt.Sprintf("%.2f", v)
+}
+
+// csvText keeps spreadsheets from reading user text as a formula.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+
+	return s
+}
@@ -0,0 +1,316 @@
+package invoice
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+// exportRepo holds the invoices of user 7.
+type exportRepo struct {
+	ports.InvoiceRepository
+	invoices []entity.Invoice
+}
+
+func (r *exportRepo) GetByID(id, userID uint) (*entity.Invoice, error) {
+	for _, inv := range r.invoices {
+		if inv.ID == id && inv.UserID == userID {
+			return &inv, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *exportRepo) ListForExport(userID uint, filter entity.InvoiceExportFilter, limit int) ([]entity.Invoice, error) {
+	return r.invoices, nil
+}
+
+type exportAuthRepo struct{ ports.AuthRepository }
+
+func (exportAuthRepo) GetUserByID(id uint) (*entity.User, error) {
+	return &entity.User{ID: id, Name: "Studio Hutamy"}, nil
+}
+
+type exportBrandingRepo struct {
+	ports.InvoiceBrandingRepository
+}
+
+func (exportBrandingRepo) Get(userID uint) (*entity.InvoiceBranding, error) {
+	return nil, nil
+}
+
+// exportBlobs never has a PDF stored.
+type exportBlobs struct{ ports.BlobStore }
+
+func (exportBlobs) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, nil
+}
+
+func (exportBlobs) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	return nil
+}
+
+// exportRenderer renders an invoice as its number, fails for INV-FAIL and
+// tracks the renders running at once. With a gate set, every render waits
+// for it to be closed.
+type exportRenderer struct {
+	gate  chan struct{}
+	delay func(number string) time.Duration
+
+	mu               sync.Mutex
+	started, running int
+	maxRunning       int
+}
+
+func (r *exportRenderer) Render(ctx context.Context, doc *entity.InvoiceDocument) ([]byte, error) {
+	r.mu.Lock()
+	r.started++
+	r.running++
+	r.maxRunning = max(r.maxRunning, r.running)
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		r.running--
+		r.mu.Unlock()
+	}()
+
+	if r.gate != nil {
+		<-r.gate
+	}
+
+	if r.delay != nil {
+		time.Sleep(r.delay(doc.Invoice.InvoiceNumber))
+	}
+
+	if doc.Invoice.InvoiceNumber == "INV-FAIL" {
+		return nil, errors.New("renderer crashed")
+	}
+
+	return []byte("%PDF " + doc.Invoice.InvoiceNumber), nil
+}
+
+func (r *exportRenderer) Stats() entity.PDFRendererStats {
+	return entity.PDFRendererStats{Renderer: "test"}
+}
+
+func (r *exportRenderer) counts() (started, maxRunning int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.started, r.maxRunning
+}
+
+func exportInvoice(id uint, number string, subtotal, tax, paid float64) entity.Invoice {
+	name, email, address, phone := "PT Pembeli", "ap@pembeli.co.id", "Jakarta", "021"
+	return entity.Invoice{
+		ID:            id,
+		UserID:        7,
+		InvoiceNumber: number,
+		IssueDate:     time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		DueDate:       time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		Status:        string(entity.InvoiceStatusSent),
+		Subtotal:      subtotal,
+		Tax:           tax,
+		Total:         subtotal + tax,
+		AmountPaid:    paid,
+		ClientName:    &name,
+		ClientEmail:   &email,
+		ClientAddress: &address,
+		ClientPhone:   &phone,
+	}
+}
+
+func newExportUseCase(renderer *exportRenderer, invoices ...entity.Invoice) *UseCase {
+	return &UseCase{
+		InvoiceRepo:  &exportRepo{invoices: invoices},
+		AuthRepo:     exportAuthRepo{},
+		BrandingRepo: exportBrandingRepo{},
+		Renderer:     renderer,
+		Blobs:        exportBlobs{},
+	}
+}
+
+// readArchive returns the names and contents of the files in a zip archive,
+// in the order they were written.
+func readArchive(t *testing.T, data []byte) ([]string, map[string]string) {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("zip.NewReader: %v", err)
+	}
+
+	var names []string
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", f.Name, err)
+		}
+
+		names = append(names, f.Name)
+		files[f.Name] = string(b)
+	}
+
+	return names, files
+}
+
+func TestExportPDFsKeepsOrder(t *testing.T) {
+	var invoices []entity.Invoice
+	var ids []uint
+	for i := uint(1); i <= 8; i++ {
+		invoices = append(invoices, exportInvoice(i, fmt.Sprintf("INV-%03d", i), 100, 11, 0))
+		ids = append(ids, i)
+	}
+
+	// Earlier invoices take longer, so they finish rendering last.
+	renderer := &exportRenderer{delay: func(number string) time.Duration {
+		var n int
+		fmt.Sscanf(number, "INV-%d", &n)
+		return time.Duration(9-n) * 5 * time.Millisecond
+	}}
+
+	for _, export := range []struct {
+		name string
+		run  func(u *UseCase, w io.Writer) error
+	}{
+		{"by filter", func(u *UseCase, w io.Writer) error {
+			return u.ExportPDFsByFilter(context.Background(), w, 7, entity.InvoiceExportFilter{})
+		}},
+		{"by ids", func(u *UseCase, w io.Writer) error {
+			_, err := u.ExportPDFs(context.Background(), w, 7, ids)
+			return err
+		}},
+	} {
+		t.Run(export.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := export.run(newExportUseCase(renderer, invoices...), &buf); err != nil {
+				t.Fatalf("export: %v", err)
+			}
+
+			names, files := readArchive(t, buf.Bytes())
+			if len(names) != len(invoices)+1 || names[len(names)-1] != "manifest.csv" {
+				t.Fatalf("archive holds %v", names)
+			}
+
+			for i, inv := range invoices {
+				if want := pdfFileName(&inv); names[i] != want {
+					t.Errorf("file %d is %s, want %s", i, names[i], want)
+				}
+
+				if want := "%PDF " + inv.InvoiceNumber; files[names[i]] != want {
+					t.Errorf("%s holds %q, want %q", names[i], files[names[i]], want)
+				}
+			}
+		})
+	}
+}
+
+func TestExportPDFsBoundsConcurrency(t *testing.T) {
+	var invoices []entity.Invoice
+	var ids []uint
+	for i := uint(1); i <= 3*exportConcurrency; i++ {
+		invoices = append(invoices, exportInvoice(i, fmt.Sprintf("INV-%03d", i), 100, 11, 0))
+		ids = append(ids, i)
+	}
+
+	renderer := &exportRenderer{gate: make(chan struct{})}
+	u := newExportUseCase(renderer, invoices...)
+	done := make(chan error, 1)
+	go func() {
+		_, err := u.ExportPDFs(context.Background(), io.Discard, 7, ids)
+		done <- err
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for started, _ := renderer.counts(); started < exportConcurrency; started, _ = renderer.counts() {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d renders started, want %d", started, exportConcurrency)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	// No render beyond the bound starts while the others are held.
+	time.Sleep(50 * time.Millisecond)
+	if started, _ := renderer.counts(); started != exportConcurrency {
+		t.Errorf("%d renders started while %d were running, want %d", started, exportConcurrency, exportConcurrency)
+	}
+
+	close(renderer.gate)
+	if err := <-done; err != nil {
+		t.Fatalf("ExportPDFs: %v", err)
+	}
+
+	if started, maxRunning := renderer.counts(); started != len(invoices) || maxRunning > exportConcurrency {
+		t.Errorf("%d renders, %d at once; want %d, at most %d", started, maxRunning, len(invoices), exportConcurrency)
+	}
+}
+
+func TestExportPDFsManifest(t *testing.T) {
+	u := newExportUseCase(&exportRenderer{},
+		exportInvoice(1, "INV-001", 1000, 110, 1110),
+		exportInvoice(2, "INV-FAIL", 500, 55, 0),
+		exportInvoice(3, "=HYPERLINK()", 250.5, 27.56, 100),
+	)
+
+	var buf bytes.Buffer
+	results, err := u.ExportPDFs(context.Background(), &buf, 7, []uint{3, 99, 1, 2, 1})
+	if err != nil {
+		t.Fatalf("ExportPDFs: %v", err)
+	}
+
+	wantResults := []entity.InvoiceBulkResult{
+		{ID: 3, Success: true},
+		{ID: 99, Error: "invoice not found"},
+		{ID: 1, Success: true},
+		{ID: 2, Error: "renderer crashed"},
+	}
+	if fmt.Sprint(results) != fmt.Sprint(wantResults) {
+		t.Errorf("results = %+v, want %+v", results, wantResults)
+	}
+
+	names, files := readArchive(t, buf.Bytes())
+	if want := []string{"_HYPERLINK__3.pdf", "INV-001_1.pdf", "manifest.csv"}; fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Errorf("archive holds %v, want %v", names, want)
+	}
+
+	rows, err := csv.NewReader(bytes.NewBufferString(files["manifest.csv"])).ReadAll()
+	if err != nil {
+		t.Fatalf("manifest.csv: %v", err)
+	}
+
+	want := [][]string{
+		{"file", "invoice_number", "client", "issue_date", "due_date", "status", "subtotal", "tax", "delivery_fee", "total", "amount_paid", "error"},
+		{"_HYPERLINK__3.pdf", "'=HYPERLINK()", "PT Pembeli", "2026-03-02", "2026-04-01", "SENT", "250.50", "27.56", "0.00", "278.06", "100.00", ""},
+		{"INV-001_1.pdf", "INV-001", "PT Pembeli", "2026-03-02", "2026-04-01", "SENT", "1000.00", "110.00", "0.00", "1110.00", "1110.00", ""},
+		{"", "INV-FAIL", "PT Pembeli", "2026-03-02", "2026-04-01", "SENT", "500.00", "55.00", "0.00", "555.00", "0.00", "renderer crashed"},
+		{"", "", "", "", "", "", "", "", "", "", "", "invoice 99 not found"},
+		{"TOTAL", "3 invoices", "", "", "", "", "1750.50", "192.56", "0.00", "1943.06", "1210.00", ""},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("manifest has %d rows, want %d:\n%s", len(rows), len(want), files["manifest.csv"])
+	}
+
+	for i := range want {
+		if fmt.Sprint(rows[i]) != fmt.Sprint(want[i]) {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package invoice
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/pkg/i18n"
+)
+
+const (
+	// facturXProfile is the Factur-X conformance level of the XML, which
+	// carries the full EN 16931 semantic model.
+	facturXProfile   = "EN 16931"
+	facturXGuideline = "urn:cen.eu:en16931:2017"
+	dateFormatYMD    = "102"
+)
+
+// facturX serializes the invoice of doc as Factur-X Cross Industry Invoice
+// XML, with the delivery fee as a document level charge.
+func facturX(doc *entity.InvoiceDocument) ([]byte, error) {
+	inv := doc.Invoice
+	category, feeCategory, subtotals := vatBreakdown(inv)
+	rate := percent(inv.TaxRate)
+	lines := make([]ciiLine, len(inv.Items))
+	for i, it := range inv.Items {
+		lines[i] = ciiLine{
+			LineID:   strconv.Itoa(i + 1),
+			Name:     it.Description,
+			NetPrice: amount(it.UnitPrice),
+			Quantity: ciiQuantity{UnitCode: unitPiece, Value: strconv.Itoa(it.Quantity)},
+			Settlement: ciiLineSettlement{
+				Tax:   ciiTax{TypeCode: "VAT", CategoryCode: category, Rate: rate},
+				Total: amount(it.Total),
+			},
+		}
+	}
+
+	taxes := make([]ciiTax, len(subtotals))
+	for i, s := range subtotals {
+		taxes[i] = ciiTax{
+			CalculatedAmount: amount(s.tax),
+			TypeCode:         "VAT",
+			ExemptionReason:  s.exemptionReason,
+			BasisAmount:      amount(s.basis),
+			CategoryCode:     s.category,
+			Rate:             percent(s.rate),
+		}
+	}
+
+	var charges []ciiCharge
+	if inv.DeliveryFee != 0 {
+		charges = append(charges, ciiCharge{
+			Indicator:    true,
+			ActualAmount: amount(inv.DeliveryFee),
+			Reason:       "Delivery fee",
+			Tax:          ciiTax{TypeCode: "VAT", CategoryCode: feeCategory, Rate: "0"},
+		})
+	}
+
+	var means *ciiPaymentMeans
+	if doc.Sender.BankAccountNumber != "" {
+		means = &ciiPaymentMeans{
+			TypeCode: creditTransfer,
+			Account: ciiAccount{
+				AccountName: doc.Sender.BankAccountName,
+				ID:          doc.Sender.BankAccountNumber,
+			},
+		}
+	}
+
+	loc := i18n.For(doc.Language)
+	out := ciiInvoice{
+		RSM:     "urn:un:unece:uncefact:data:standard:CrossIndustryInvoice:100",
+		RAM:     "urn:un:unece:uncefact:data:standard:ReusableAggregateBusinessInformationEntity:100",
+		UDT:     "urn:un:unece:uncefact:data:standard:UnqualifiedDataType:100",
+		Context: facturXGuideline,
+		Document: ciiDocument{
+			ID:        inv.InvoiceNumber,
+			TypeCode:  commercialInvoice,
+			IssueDate: ciiDate(inv.IssueDate),
+			Note:      inv.Notes,
+		},
+		Transaction: ciiTransaction{
+			Lines: lines,
+			Agreement: ciiAgreement{
+				Seller: ciiTradeParty(doc.Sender.Name, doc.Sender.Email, doc.Sender.Phone, doc.Sender.Address, doc.Sender.NPWP),
+				Buyer:  ciiTradeParty(doc.Client.Name, doc.Client.Email, doc.Client.Phone, doc.Client.Address, doc.Client.NPWP),
+			},
+			Settlement: ciiSettlement{
+				Currency:     invoiceCurrency,
+				PaymentMeans: means,
+				Taxes:        taxes,
+				Charges:      charges,
+				Terms: ciiTerms{
+					Description: inv.PaymentTerms.Localized(loc.T, inv.PaymentTermsDays),
+					DueDate:     ciiDate(inv.DueDate),
+				},
+				Summation: ciiSummation{
+					LineTotal:     amount(inv.Subtotal),
+					ChargeTotal:   amount(inv.DeliveryFee),
+					TaxBasisTotal: amount(inv.Subtotal + inv.DeliveryFee),
+					TaxTotal:      ciiAmount{Currency: invoiceCurrency, Value: amount(inv.Tax)},
+					GrandTotal:    amount(inv.Total),
+					Prepaid:       amount(inv.AmountPaid),
+					DuePayable:    amount(inv.Total - inv.AmountPaid),
+				},
+			},
+		},
+	}
+
+	data, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("factur-x: %w", err)
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+func ciiTradeParty(name, email, phone, address, npwp string) ciiParty {
+	p := ciiParty{
+		Name: name,
+		Address: ciiAddress{
+			LineOne: oneLine(address),
+			Country: invoiceCountry,
+		},
+	}
+	if phone != "" {
+		p.Contact = &ciiContact{Phone: phone}
+	}
+
+	if email != "" {
+		p.Email = &ciiURI{Scheme: "EM", Value: email}
+	}
+
+	if id := vatID(npwp); id != "" {
+		p.TaxID = &ciiURI{Scheme: "VA", Value: id}
+	}
+
+	return p
+}
+
+func ciiDate(t time.Time) ciiDateTime {
+	return ciiDateTime{Value: ciiDateString{Format: dateFormatYMD, Value: t.Format("20060102")}}
+}
+
+// The Cross Industry Invoice elements, in the order the schema requires.
+
+type ciiInvoice struct {
+	XMLName     xml.Name       `xml:"rsm:CrossIndustryInvoice"`
+	RSM         string         `xml:"xmlns:rsm,attr"`
+	RAM         string         `xml:"xmlns:ram,attr"`
+	UDT         string         `xml:"xmlns:udt,attr"`
+	Context     string         `xml:"rsm:ExchangedDocumentContext>ram:GuidelineSpecifiedDocumentContextParameter>ram:ID"`
+	Document    ciiDocument    `xml:"rsm:ExchangedDocument"`
+	Transaction ciiTransaction `xml:"rsm:SupplyChainTradeTransaction"`
+}
+
+type ciiDocument struct {
+	ID        string      `xml:"ram:ID"`
+	TypeCode  string      `xml:"ram:TypeCode"`
+	IssueDate ciiDateTime `xml:"ram:IssueDateTime"`
+	Note      string      `xml:"ram:IncludedNote>ram:Content,omitempty"`
+}
+
+type ciiDateTime struct {
+	Value ciiDateString `xml:"udt:DateTimeString"`
+}
+
+type ciiDateString struct {
+	Format string `xml:"format,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type ciiTransaction struct {
+	Lines      []ciiLine     `xml:"ram:IncludedSupplyChainTradeLineItem"`
+	Agreement  ciiAgreement  `xml:"ram:ApplicableHeaderTradeAgreement"`
+	Delivery   struct{}      `xml:"ram:ApplicableHeaderTradeDelivery"`
+	Settlement ciiSettlement `xml:"ram:ApplicableHeaderTradeSettlement"`
+}
+
+type ciiLine struct {
+	LineID     string            `xml:"ram:AssociatedDocumentLineDocument>ram:LineID"`
+	Name       string            `xml:"ram:SpecifiedTradeProduct>ram:Name"`
+	NetPrice   string            `xml:"ram:SpecifiedLineTradeAgreement>ram:NetPriceProductTradePrice>ram:ChargeAmount"`
+	Quantity   ciiQuantity       `xml:"ram:SpecifiedLineTradeDelivery>ram:BilledQuantity"`
+	Settlement ciiLineSettlement `xml:"ram:SpecifiedLineTradeSettlement"`
+}
+
+type ciiQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ciiLineSettlement struct {
+	Tax   ciiTax `xml:"ram:ApplicableTradeTax"`
+	Total string `xml:"ram:SpecifiedTradeSettlementLineMonetarySummation>ram:LineTotalAmount"`
+}
+
+type ciiTax struct {
+	CalculatedAmount string `xml:"ram:CalculatedAmount,omitempty"`
+	TypeCode         string `xml:"ram:TypeCode"`
+	ExemptionReason  string `xml:"ram:ExemptionReason,omitempty"`
+	BasisAmount      string `xml:"ram:BasisAmount,omitempty"`
+	CategoryCode     string `xml:"ram:CategoryCode"`
+	Rate             string `xml:"ram:RateApplicablePercent"`
+}
+
+type ciiAgreement struct {
+	Seller ciiParty `xml:"ram:SellerTradeParty"`
+	Buyer  ciiParty `xml:"ram:BuyerTradeParty"`
+}
+
+type ciiParty struct {
+	Name    string      `xml:"ram:Name"`
+	Contact *ciiContact `xml:"ram:DefinedTradeContact,omitempty"`
+	Address ciiAddress  `xml:"ram:PostalTradeAddress"`
+	Email   *ciiURI     `xml:"ram:URIUniversalCommunication>ram:URIID,omitempty"`
+	TaxID   *ciiURI     `xml:"ram:SpecifiedTaxRegistration>ram:ID,omitempty"`
+}
+
+type ciiContact struct {
+	Phone string `xml:"ram:TelephoneUniversalCommunication>ram:CompleteNumber"`
+}
+
+type ciiAddress struct {
+	LineOne string `xml:"ram:LineOne,omitempty"`
+	Country string `xml:"ram:CountryID"`
+}
+
+type ciiURI struct {
+	Scheme string `xml:"schemeID,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type ciiSettlement struct {
+	Currency     string           `xml:"ram:InvoiceCurrencyCode"`
+	PaymentMeans *ciiPaymentMeans `xml:"ram:SpecifiedTradeSettlementPaymentMeans,omitempty"`
+	Taxes        []ciiTax         `xml:"ram:ApplicableTradeTax"`
+	Charges      []ciiCharge      `xml:"ram:SpecifiedTradeAllowanceCharge"`
+	Terms        ciiTerms         `xml:"ram:SpecifiedTradePaymentTerms"`
+	Summation    ciiSummation     `xml:"ram:SpecifiedTradeSettlementHeaderMonetarySummation"`
+}
+
+type ciiPaymentMeans struct {
+	TypeCode string     `xml:"ram:TypeCode"`
+	Account  ciiAccount `xml:"ram:PayeePartyCreditorFinancialAccount"`
+}
+
+type ciiAccount struct {
+	AccountName string `xml:"ram:AccountName,omitempty"`
+	ID          string `xml:"ram:ProprietaryID"`
+}
+
+type ciiCharge struct {
+	Indicator    bool   `xml:"ram:ChargeIndicator>udt:Indicator"`
+	ActualAmount string `xml:"ram:ActualAmount"`
+	Reason       string `xml:"ram:Reason"`
+	Tax          ciiTax `xml:"ram:CategoryTradeTax"`
+}
+
+type ciiTerms struct {
+	Description string      `xml:"ram:Description,omitempty"`
+	DueDate     ciiDateTime `xml:"ram:DueDateDateTime"`
+}
+
+type ciiSummation struct {
+	LineTotal     string    `xml:"ram:LineTotalAmount"`
+	ChargeTotal   string    `xml:"ram:ChargeTotalAmount"`
+	TaxBasisTotal string    `xml:"ram:TaxBasisTotalAmount"`
+	TaxTotal      ciiAmount `xml:"ram:TaxTotalAmount"`
+	GrandTotal    string    `xml:"ram:GrandTotalAmount"`
+	Prepaid       string    `xml:"ram:TotalPrepaidAmount"`
+	DuePayable    string    `xml:"ram:DuePayableAmount"`
+}
+
+type ciiAmount struct {
+	Currency string `xml:"currencyID,attr"`
+	Value    string `xml:",chardata"`
+}
@@ -0,0 +1,154 @@
+package invoice
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	pdfadapter "github.com/hutamy/go-invoice-backend/internal/adapter/pdf"
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+var (
+	pdfObject = regexp.MustCompile(`(?s)(\d+) 0 obj\n(.*?)\nendobj\n`)
+	pdfStream = regexp.MustCompile(`(?s)^(<<.*?>>)\nstream\n(.*)\nendstream$`)
+)
+
+// pdfObjects splits the archive the native renderer writes into its objects
+// by number.
+func pdfObjects(t *testing.T, data []byte) map[int]string {
+	t.Helper()
+	objs := make(map[int]string)
+	for _, m := range pdfObject.FindAllSubmatch(data, -1) {
+		n, _ := strconv.Atoi(string(m[1]))
+		objs[n] = string(m[2])
+	}
+
+	if len(objs) == 0 {
+		t.Fatal("no objects in the PDF")
+	}
+
+	return objs
+}
+
+// ref returns the object the named entry of dict refers to.
+func ref(t *testing.T, objs map[int]string, dict, entry string) string {
+	t.Helper()
+	m := regexp.MustCompile(regexp.QuoteMeta(entry) + `\s*\[?(\d+) 0 R`).FindStringSubmatch(dict)
+	if m == nil {
+		t.Fatalf("%s missing from %.200s", entry, dict)
+	}
+
+	n, _ := strconv.Atoi(m[1])
+	obj, ok := objs[n]
+	if !ok {
+		t.Fatalf("%s refers to missing object %d", entry, n)
+	}
+
+	return obj
+}
+
+func TestFacturXArchive(t *testing.T) {
+	doc := sampleDocument(11, 15000)
+	xmlData, err := facturX(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc.Archive = &entity.InvoiceArchive{XML: xmlData, Profile: facturXProfile}
+	data, err := pdfadapter.NewNativeRenderer().Render(context.Background(), doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		t.Fatalf("output starts with %q", data[:min(len(data), 8)])
+	}
+
+	objs := pdfObjects(t, data)
+	var catalog string
+	for _, o := range objs {
+		if strings.HasPrefix(o, "<< /Type /Catalog") {
+			catalog = o
+		}
+	}
+
+	if catalog == "" {
+		t.Fatal("no catalog")
+	}
+
+	t.Run("output intent", func(t *testing.T) {
+		if !strings.Contains(catalog, "/OutputIntents [<< /Type /OutputIntent /S /GTS_PDFA1") {
+			t.Errorf("catalog lacks a PDF/A output intent: %s", catalog)
+		}
+
+		if profile := ref(t, objs, catalog, "/DestOutputProfile"); !strings.Contains(profile, "/N 3") {
+			t.Errorf("output profile is not RGB: %.100s", profile)
+		}
+	})
+
+	t.Run("xmp metadata", func(t *testing.T) {
+		xmp := ref(t, objs, catalog, "/Metadata")
+		for _, want := range []string{
+			"<pdfaid:part>3</pdfaid:part>",
+			"<pdfaid:conformance>B</pdfaid:conformance>",
+			`xmlns:fx="urn:factur-x:pdfa:CrossIndustryDocument:invoice:1p0#"`,
+			"<fx:DocumentType>INVOICE</fx:DocumentType>",
+			"<fx:DocumentFileName>factur-x.xml</fx:DocumentFileName>",
+			"<fx:Version>1.0</fx:Version>",
+			"<fx:ConformanceLevel>" + facturXProfile + "</fx:ConformanceLevel>",
+			"<pdfaSchema:prefix>fx</pdfaSchema:prefix>",
+		} {
+			if !strings.Contains(xmp, want) {
+				t.Errorf("metadata lacks %s", want)
+			}
+		}
+	})
+
+	t.Run("embedded xml", func(t *testing.T) {
+		spec := ref(t, objs, catalog, "/AF")
+		for _, want := range []string{"/Type /Filespec", "/F (factur-x.xml)", "/UF (factur-x.xml)", "/AFRelationship /Alternative"} {
+			if !strings.Contains(spec, want) {
+				t.Errorf("file specification lacks %s: %s", want, spec)
+			}
+		}
+
+		if !strings.Contains(catalog, "/EmbeddedFiles << /Names [(factur-x.xml) ") {
+			t.Errorf("embedded files tree lacks factur-x.xml: %s", catalog)
+		}
+
+		m := pdfStream.FindStringSubmatch(ref(t, objs, spec, "/EF << /F"))
+		if m == nil || !strings.Contains(m[1], "/Type /EmbeddedFile /Subtype /text#2Fxml") {
+			t.Fatalf("not an embedded XML file: %.200v", m)
+		}
+
+		zr, err := zlib.NewReader(strings.NewReader(m[2]))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		embedded, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(embedded, xmlData) {
+			t.Error("embedded XML differs from the Factur-X XML")
+		}
+
+		for _, want := range []string{
+			"<ram:RateApplicablePercent>11</ram:RateApplicablePercent>",
+			"<ram:RateApplicablePercent>0</ram:RateApplicablePercent>",
+			`<ram:ID schemeID="VA">ID0012345678901234</ram:ID>`,
+		} {
+			if !strings.Contains(string(embedded), want) {
+				t.Errorf("XML lacks %s", want)
+			}
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package invoice
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+// importStatuses maps status names used by other invoicing tools onto ours.
+var importStatuses = map[string]entity.InvoiceStatus{
+	"quote":       entity.InvoiceStatusQuote,
+	"estimate":    entity.InvoiceStatusQuote,
+	"draft":       entity.InvoiceStatusDraft,
+	"sent":        entity.InvoiceStatusSent,
+	"open":        entity.InvoiceStatusSent,
+	"unpaid":      entity.InvoiceStatusSent,
+	"pending":     entity.InvoiceStatusSent,
+	"outstanding": entity.InvoiceStatusSent,
+	"overdue":     entity.InvoiceStatusOverdue,
+	"paid":        entity.InvoiceStatusPaid,
+	"closed":      entity.InvoiceStatusPaid,
+}
+
+func (u *UseCase) Import(userID uint, records []entity.InvoiceImportRecord) (*entity.InvoiceImportReport, error) {
+	if userID == 0 {
+		return nil, errors.New("unauthorized")
+	}
+
+	report := &entity.InvoiceImportReport{
+		Total:    len(records),
+		Invoices: make([]entity.InvoiceImportResult, 0, len(records)),
+	}
+	for _, rec := range records {
+		res := u.importInvoice(userID, rec)
+		switch res.Status {
+		case entity.ImportRowStatusCreated:
+			report.Created++
+		case entity.ImportRowStatusSkipped:
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+
+		report.Invoices = append(report.Invoices, res)
+	}
+
+	return report, nil
+}
+
+func (u *UseCase) importInvoice(userID uint, rec entity.InvoiceImportRecord) entity.InvoiceImportResult {
+	res := entity.InvoiceImportResult{
+		InvoiceNumber: rec.Invoice.InvoiceNumber,
+		Rows:          rec.Rows,
+		Status:        entity.ImportRowStatusFailed,
+	}
+
+	if rec.Error != "" {
+		res.Error = rec.Error
+		return res
+	}
+
+	status, ok := importStatuses[strings.ToLower(strings.TrimSpace(rec.Invoice.Status))]
+	if rec.Invoice.Status == "" {
+		status, ok = entity.InvoiceStatusDraft, true
+	}
+
+	if !ok {
+		res.Error = fmt.Sprintf("unknown status %q", rec.Invoice.Status)
+		return res
+	}
+
+	exists, err := u.InvoiceRepo.ExistsByNumber(userID, rec.Invoice.InvoiceNumber)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	if exists {
+		res.Status = entity.ImportRowStatusSkipped
+		res.Error = "invoice number already exists"
+		return res
+	}
+
+	client, err := u.ClientRepo.FindMatch(userID, rec.Client.Email, rec.Client.Name)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	if client == nil {
+		client = &rec.Client
+		client.ID = 0
+		client.UserID = userID
+	}
+
+	inv := rec.Invoice
+	inv.ID = 0
+	inv.UserID = userID
+	inv.Status = string(status)
+	if err := u.InvoiceRepo.CreateWithClient(&inv, client); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	res.Status = entity.ImportRowStatusCreated
+	res.InvoiceID = inv.ID
+	res.ClientID = client.ID
+	return res
+}
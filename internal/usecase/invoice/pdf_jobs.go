@@ -0,0 +1,285 @@
+package invoice
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+const (
+	// pdfCacheVersion is part of every stored PDF's key; bump it when the
+	// renderers change how invoices look to stop serving old PDFs.
+	pdfCacheVersion = "1"
+
+	maxPDFJobAttempts  = 3
+	pdfJobPollInterval = 5 * time.Second
+	pdfJobStaleAfter   = 10 * time.Minute
+
+	// Stored PDFs live under a prefix per user and invoice.
+	pdfKeyUserPrefix    = "invoices/%d/"
+	pdfKeyInvoicePrefix = "invoices/%d/%d/"
+)
+
+// EnqueuePDF queues the invoice's PDF to be rendered in the background, or
+// returns the job already queued for it.
+func (u *UseCase) EnqueuePDF(id, userID uint) (*entity.PDFJob, error) {
+	if err := u.checkOwnership(id, userID); err != nil {
+		return nil, err
+	}
+
+	job, err := u.PDFJobRepo.GetActive(id, userID)
+	if err != nil || job != nil {
+		return job, err
+	}
+
+	job = &entity.PDFJob{
+		UserID:    userID,
+		InvoiceID: id,
+		Status:    entity.PDFJobQueued,
+	}
+	if err := u.PDFJobRepo.Create(job); err != nil {
+		return nil, err
+	}
+
+	// Wake an idle worker; busy ones pick the job up when they are done.
+	select {
+	case u.pdfJobWake <- struct{}{}:
+	default:
+	}
+
+	return job, nil
+}
+
+func (u *UseCase) GetPDFJob(jobID, userID uint) (*entity.PDFJob, error) {
+	return u.PDFJobRepo.GetByID(jobID, userID)
+}
+
+// DownloadPDFJob returns the PDF a finished job rendered, as long as the
+// invoice has not changed since.
+func (u *UseCase) DownloadPDFJob(ctx context.Context, jobID, userID uint) ([]byte, *entity.PDFJob, error) {
+	job, err := u.PDFJobRepo.GetByID(jobID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if job == nil {
+		return nil, nil, errors.New("pdf job not found")
+	}
+
+	switch job.Status {
+	case entity.PDFJobDone:
+	case entity.PDFJobExpired:
+		return nil, job, errors.New("pdf is out of date, request a new one")
+	case entity.PDFJobFailed:
+		return nil, job, fmt.Errorf("pdf job failed: %s", job.Error)
+	default:
+		return nil, job, errors.New("pdf is not ready yet")
+	}
+
+	doc, err := u.document(job.InvoiceID, userID)
+	if err != nil {
+		return nil, job, err
+	}
+
+	if key, err := u.pdfBlobKey(doc, userID, job.InvoiceID); err != nil || key != job.BlobKey {
+		return nil, job, errors.New("pdf is out of date, request a new one")
+	}
+
+	pdf, err := u.Blobs.Get(ctx, job.BlobKey)
+	if err != nil {
+		return nil, job, err
+	}
+
+	if pdf == nil {
+		return nil, job, errors.New("pdf is out of date, request a new one")
+	}
+
+	return pdf, job, nil
+}
+
+// RunPDFWorkers starts workers that render queued PDF jobs until ctx is
+// cancelled. Jobs are kept in the database, so they survive restarts and
+// may be shared with workers in other processes.
+func (u *UseCase) RunPDFWorkers(ctx context.Context, workers int) {
+	for range workers {
+		go u.pdfWorker(ctx)
+	}
+}
+
+func (u *UseCase) pdfWorker(ctx context.Context) {
+	ticker := time.NewTicker(pdfJobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil && u.runNextPDFJob(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-u.pdfJobWake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// runNextPDFJob renders the next queued job and reports whether there was
+// one.
+func (u *UseCase) runNextPDFJob(ctx context.Context) bool {
+	job, err := u.PDFJobRepo.ClaimNext(time.Now().Add(-pdfJobStaleAfter))
+	if err != nil {
+		log.Printf("Failed to claim PDF job: %v", err)
+		return false
+	}
+
+	if job == nil {
+		return false
+	}
+
+	key, cached, err := u.storePDF(ctx, job.InvoiceID, job.UserID)
+	now := time.Now()
+	switch {
+	case err == nil:
+		job.Status = entity.PDFJobDone
+		job.BlobKey = key
+		job.Cached = cached
+		job.Error = ""
+		job.FinishedAt = &now
+	case job.Attempts < maxPDFJobAttempts || ctx.Err() != nil:
+		job.Status = entity.PDFJobQueued
+		job.Error = err.Error()
+	default:
+		job.Status = entity.PDFJobFailed
+		job.Error = err.Error()
+		job.FinishedAt = &now
+	}
+
+	if err := u.PDFJobRepo.Finish(*job); err != nil {
+		log.Printf("Failed to save PDF job %d: %v", job.ID, err)
+	}
+
+	return true
+}
+
+// storePDF makes sure the invoice's current PDF is in blob storage and
+// returns its key, and whether it was there already.
+func (u *UseCase) storePDF(ctx context.Context, id, userID uint) (string, bool, error) {
+	doc, err := u.document(id, userID)
+	if err != nil {
+		return "", false, err
+	}
+
+	key, err := u.pdfBlobKey(doc, userID, id)
+	if err != nil {
+		return "", false, err
+	}
+
+	exists, err := u.Blobs.Exists(ctx, key)
+	if err != nil || exists {
+		return key, exists, err
+	}
+
+	pdf, err := u.Renderer.Render(ctx, doc)
+	if err != nil {
+		return "", false, err
+	}
+
+	return key, false, u.Blobs.Put(ctx, key, pdf, "application/pdf")
+}
+
+// GeneratePDF returns the invoice's PDF, rendering it only when the current
+// version is not in blob storage yet.
+func (u *UseCase) GeneratePDF(ctx context.Context, id, userID uint) ([]byte, error) {
+	return u.cachedPDF(ctx, id, userID, false)
+}
+
+// GenerateArchivalPDF returns the invoice as a PDF/A-3 document with its
+// Factur-X XML embedded, for archiving and e-invoicing.
+func (u *UseCase) GenerateArchivalPDF(ctx context.Context, id, userID uint) ([]byte, error) {
+	return u.cachedPDF(ctx, id, userID, true)
+}
+
+func (u *UseCase) cachedPDF(ctx context.Context, id, userID uint, archival bool) ([]byte, error) {
+	doc, err := u.document(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if archival {
+		data, err := facturX(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		doc.Archive = &entity.InvoiceArchive{XML: data, Profile: facturXProfile}
+	}
+
+	key, err := u.pdfBlobKey(doc, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if pdf, err := u.Blobs.Get(ctx, key); err != nil {
+		log.Printf("Failed to read cached PDF %s: %v", key, err)
+	} else if pdf != nil {
+		return pdf, nil
+	}
+
+	pdf, err := u.Renderer.Render(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.Blobs.Put(ctx, key, pdf, "application/pdf"); err != nil {
+		log.Printf("Failed to cache PDF %s: %v", key, err)
+	}
+
+	return pdf, nil
+}
+
+// pdfBlobKey names the stored PDF of doc after a hash of everything that
+// ends up on it, so any change to the invoice, its parties or the branding
+// gives a new key and stale PDFs are never served.
+func (u *UseCase) pdfBlobKey(doc *entity.InvoiceDocument, userID, id uint) (string, error) {
+	data, err := json.Marshal(templateData(doc.Invoice, doc.Sender, doc.Client, doc.Branding))
+	if err != nil {
+		return "", err
+	}
+
+	parts := []string{pdfCacheVersion, u.Renderer.Stats().Renderer, doc.HTML, doc.QRISPayload, string(data)}
+	if doc.Archive != nil {
+		parts = append(parts, doc.Archive.Profile, string(doc.Archive.XML))
+	}
+
+	h := sha256.New()
+	for _, part := range parts {
+		fmt.Fprintf(h, "%d:%s", len(part), part)
+	}
+
+	return fmt.Sprintf(pdfKeyInvoicePrefix+"%s.pdf", userID, id, hex.EncodeToString(h.Sum(nil))), nil
+}
+
+// invalidatePDFs throws away the stored PDFs of the invoice, or of all the
+// user's invoices when id is zero, and expires the jobs that made them.
+// Keys already change with the content, so failures are only logged.
+func (u *UseCase) invalidatePDFs(id, userID uint) {
+	if err := u.PDFJobRepo.ExpireByInvoice(id, userID); err != nil {
+		log.Printf("Failed to expire PDF jobs of invoice %d: %v", id, err)
+	}
+
+	prefix := fmt.Sprintf(pdfKeyUserPrefix, userID)
+	if id != 0 {
+		prefix = fmt.Sprintf(pdfKeyInvoicePrefix, userID, id)
+	}
+
+	if err := u.Blobs.DeletePrefix(context.Background(), prefix); err != nil {
+		log.Printf("Failed to delete stored PDFs under %s: %v", prefix, err)
+	}
+}
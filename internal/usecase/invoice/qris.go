@@ -0,0 +1,78 @@
+package invoice
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/pkg/qrcode"
+	"github.com/hutamy/go-invoice-backend/pkg/qris"
+)
+
+const qrisModulePixels = 8
+
+// QRISCode returns a PNG of the dynamic QRIS code that pays the outstanding
+// amount of the invoice into the user's QRIS merchant account.
+func (u *UseCase) QRISCode(id, userID uint) ([]byte, error) {
+	invoice, err := u.InvoiceRepo.GetByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice == nil {
+		return nil, errors.New("invoice not found")
+	}
+
+	user, err := u.AuthRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	payload, err := qrisPayload(*invoice, *user)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.PNG([]byte(payload), qrisModulePixels)
+}
+
+// qrisPayload derives the dynamic QRIS payload for the amount still owed
+// on invoice from the user's static merchant payload.
+func qrisPayload(invoice entity.Invoice, user entity.User) (string, error) {
+	if user.QRISPayload == "" {
+		return "", errors.New("QRIS is not set up")
+	}
+
+	switch entity.InvoiceStatus(invoice.Status) {
+	case entity.InvoiceStatusPaid, entity.InvoiceStatusQuote:
+		return "", errors.New("invoice is not payable")
+	}
+
+	outstanding := invoice.Total - invoice.AmountPaid
+	if outstanding < 1 {
+		return "", errors.New("invoice has nothing outstanding")
+	}
+
+	return qris.Dynamic(user.QRISPayload, outstanding)
+}
+
+// qrisImage returns the invoice's QRIS code as a data URI for embedding in
+// the invoice document, or an empty string when there is nothing to pay by
+// QRIS.
+func qrisImage(invoice entity.Invoice, user entity.User) string {
+	payload, err := qrisPayload(invoice, user)
+	if err != nil {
+		return ""
+	}
+
+	png, err := qrcode.PNG([]byte(payload), qrisModulePixels)
+	if err != nil {
+		return ""
+	}
+
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+}
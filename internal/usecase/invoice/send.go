@@ -0,0 +1,145 @@
+package invoice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+// Send emails the invoice PDF to the client, records the delivery and moves
+// a draft invoice to SENT. Paid invoices are sent with the receipt template,
+// quotes with the quote template and all others with the invoice template. A failed delivery is recorded as
+// well and leaves the status untouched.
+func (u *UseCase) Send(ctx context.Context, id, userID uint, email entity.InvoiceEmail) (*entity.InvoiceDelivery, error) {
+	if u.Mailer == nil {
+		return nil, errors.New("email delivery is not configured")
+	}
+
+	invoice, err := u.InvoiceRepo.GetByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice == nil {
+		return nil, errors.New("invoice not found")
+	}
+
+	user, err := u.AuthRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	to := strings.TrimSpace(email.To)
+	if to == "" && invoice.ClientEmail != nil {
+		to = strings.TrimSpace(*invoice.ClientEmail)
+	}
+
+	if to == "" {
+		return nil, errors.New("invoice has no client email")
+	}
+
+	kind := entity.EmailTemplateInvoiceSent
+	switch entity.InvoiceStatus(invoice.Status) {
+	case entity.InvoiceStatusPaid:
+		kind = entity.EmailTemplateReceipt
+	case entity.InvoiceStatusQuote:
+		kind = entity.EmailTemplateQuote
+	}
+
+	tmpl, err := u.emailTemplate(userID, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	if email.Subject != "" {
+		tmpl.Subject = email.Subject
+	}
+
+	// A custom plain text message replaces the whole template, HTML included.
+	if email.Body != "" {
+		tmpl.Body = email.Body
+		tmpl.HTMLBody = ""
+	}
+
+	links, err := u.PaymentRepo.ListLinks(invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := tmpl.Render(entity.NewEmailTemplateData(invoice, user, links, time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	pdf, err := u.GeneratePDF(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &entity.InvoiceDelivery{
+		UserID:    userID,
+		InvoiceID: id,
+		Recipient: to,
+		Subject:   rendered.Subject,
+		SentAt:    time.Now(),
+	}
+
+	sendErr := u.Mailer.Send(ctx, entity.Mail{
+		To:       []string{to},
+		ReplyTo:  user.Email,
+		Subject:  rendered.Subject,
+		Body:     rendered.Body,
+		HTMLBody: rendered.HTMLBody,
+		Attachments: []entity.MailAttachment{{
+			Filename:    pdfFileName(invoice),
+			ContentType: "application/pdf",
+			Data:        pdf,
+		}},
+	})
+	if sendErr != nil {
+		delivery.Error = sendErr.Error()
+	}
+
+	if err := u.InvoiceRepo.RecordDelivery(delivery); err != nil {
+		return nil, err
+	}
+
+	if sendErr != nil {
+		return delivery, fmt.Errorf("failed to send invoice: %w", sendErr)
+	}
+
+	if entity.InvoiceStatus(invoice.Status) == entity.InvoiceStatusDraft {
+		if err := u.InvoiceRepo.UpdateStatus(id, userID, entity.InvoiceStatusSent); err != nil {
+			return delivery, err
+		}
+	}
+
+	return delivery, nil
+}
+
+func (u *UseCase) ListDeliveries(id, userID uint) ([]entity.InvoiceDelivery, error) {
+	if err := u.checkOwnership(id, userID); err != nil {
+		return nil, err
+	}
+
+	return u.InvoiceRepo.ListDeliveries(id)
+}
+
+// emailTemplate returns the user's template of kind, or the default one.
+func (u *UseCase) emailTemplate(userID uint, kind entity.EmailTemplateKind) (*entity.EmailTemplate, error) {
+	tmpl, err := u.TemplateRepo.Get(userID, kind)
+	if err != nil || tmpl != nil {
+		return tmpl, err
+	}
+
+	def := entity.DefaultEmailTemplate(kind)
+	return &def, nil
+}
@@ -0,0 +1,123 @@
+package invoice
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+var errShareLinkInvalid = errors.New("link is invalid or has expired")
+
+// CreateShareLink creates a link to view the invoice without logging in.
+// A nil expiresAt creates a link that is valid until revoked.
+func (u *UseCase) CreateShareLink(id, userID uint, expiresAt *time.Time) (*entity.InvoiceShareLink, error) {
+	if err := u.checkOwnership(id, userID); err != nil {
+		return nil, err
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	link := &entity.InvoiceShareLink{
+		UserID:    userID,
+		InvoiceID: id,
+		Nonce:     hex.EncodeToString(nonce),
+		ExpiresAt: expiresAt,
+	}
+	if err := u.ShareLinkRepo.Create(link); err != nil {
+		return nil, err
+	}
+
+	link.Token = u.shareToken(link)
+	return link, nil
+}
+
+func (u *UseCase) ListShareLinks(id, userID uint) ([]entity.InvoiceShareLink, error) {
+	if err := u.checkOwnership(id, userID); err != nil {
+		return nil, err
+	}
+
+	links, err := u.ShareLinkRepo.ListByInvoice(id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range links {
+		links[i].Token = u.shareToken(&links[i])
+	}
+
+	return links, nil
+}
+
+func (u *UseCase) RevokeShareLink(id, userID, linkID uint) error {
+	if err := u.checkOwnership(id, userID); err != nil {
+		return err
+	}
+
+	return u.ShareLinkRepo.Revoke(linkID, id, time.Now())
+}
+
+// OpenSharedInvoice returns the invoice a share link token points to and
+// records the view.
+func (u *UseCase) OpenSharedInvoice(token string) (*entity.Invoice, error) {
+	payload, err := u.Signer.Verify(token)
+	if err != nil {
+		return nil, errShareLinkInvalid
+	}
+
+	rawID, nonce, ok := strings.Cut(payload, ".")
+	if !ok {
+		return nil, errShareLinkInvalid
+	}
+
+	linkID, err := strconv.ParseUint(rawID, 10, 64)
+	if err != nil {
+		return nil, errShareLinkInvalid
+	}
+
+	link, err := u.ShareLinkRepo.GetByID(uint(linkID))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if link == nil || link.Nonce != nonce || !link.Active(now) {
+		return nil, errShareLinkInvalid
+	}
+
+	invoice, err := u.InvoiceRepo.GetByID(link.InvoiceID, link.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice == nil {
+		return nil, errShareLinkInvalid
+	}
+
+	if err := u.InvoiceRepo.RecordView(invoice.ID, now); err != nil {
+		return nil, err
+	}
+
+	if invoice.FirstViewedAt == nil {
+		invoice.FirstViewedAt = &now
+	}
+
+	invoice.ViewCount++
+	return invoice, nil
+}
+
+func (u *UseCase) shareToken(link *entity.InvoiceShareLink) string {
+	return u.Signer.Sign(fmt.Sprintf("%d.%s", link.ID, link.Nonce))
+}
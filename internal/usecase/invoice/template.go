@@ -0,0 +1,511 @@
+package invoice
+
+import (
+	htmltemplate "html/template"
+	"strings"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+// builtinTemplateData is what the built-in layouts render: the same data as
+// custom templates plus the layout's stylesheet.
+type builtinTemplateData struct {
+	entity.InvoiceTemplateData
+	LayoutStyle htmltemplate.CSS
+}
+
+// generateTemplate renders the invoice as an HTML document with the user's
+// branding. Values are escaped by html/template, so user content can never
+// add markup to the page, and line breaks in it are kept.
+func (u *UseCase) generateTemplate(invoice entity.Invoice, user entity.User, client entity.Client, branding entity.InvoiceBranding) (string, error) {
+	data := templateData(invoice, user, client, branding)
+	if branding.Layout == entity.InvoiceLayoutCustom {
+		return renderCustomTemplate(branding.Template, data)
+	}
+
+	var buf strings.Builder
+	err := invoiceTemplate.Execute(&buf, builtinTemplateData{
+		InvoiceTemplateData: data,
+		LayoutStyle:         htmltemplate.CSS(layoutStyles[branding.Layout]),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+var invoiceTemplate = htmltemplate.Must(htmltemplate.New("invoice").Parse(`
+	<!DOCTYPE html>
+	<html lang="{{.Language}}">
+	<head>
+		<meta charset="utf-8" />
+		<title>{{.Labels.Title}} {{.InvoiceNumber}}</title>
+		<meta name="viewport" content="width=device-width, initial-scale=1.0" />
+		<style>
+		:root {
+			--primary-color: #111827;
+			--accent-color: {{.AccentColor}};
+			--font-family: {{.Font}};
+			--text-color: #1f2937;
+			--light-gray: #f9fafb;
+			--border-color: #e5e7eb;
+		}
+
+		* {
+			margin: 0;
+			padding: 0;
+			box-sizing: border-box;
+		}
+
+		body {
+			font-family: var(--font-family);
+			color: var(--text-color);
+			line-height: 1.5;
+			background-color: white;
+			padding: 32px 20px;
+		}
+
+		.invoice-container {
+			max-width: 800px;
+			margin: 0 auto;
+			background: white;
+			padding: 32px;
+		}
+
+		.invoice-header {
+			display: flex;
+			justify-content: space-between;
+			align-items: flex-start;
+			margin-bottom: 48px;
+		}
+
+		.invoice-title {
+			font-weight: 700;
+			font-size: 36px;
+			color: var(--accent-color);
+			margin-bottom: 8px;
+		}
+
+		.invoice-id {
+			font-size: 14px;
+			color: #6b7280;
+		}
+
+		.invoice-dates {
+			text-align: right;
+			font-size: 14px;
+			color: #4b5563;
+			line-height: 1.6;
+		}
+
+		.invoice-dates > div {
+			margin-bottom: 4px;
+		}
+
+		.invoice-parties {
+			display: grid;
+			grid-template-columns: 1fr 1fr;
+			margin-bottom: 48px;
+			gap: 48px;
+		}
+
+		.invoice-parties h3 {
+			font-size: 14px;
+			font-weight: 600;
+			text-transform: uppercase;
+			letter-spacing: 0.025em;
+			color: #4b5563;
+			margin-bottom: 16px;
+		}
+
+		.party-info {
+			font-size: 14px;
+			line-height: 1.6;
+			color: #1f2937;
+		}
+
+		.invoice-table {
+			width: 100%;
+			border-collapse: collapse;
+			margin-bottom: 32px;
+		}
+
+		.invoice-table th {
+			padding: 12px 8px;
+			text-align: left;
+			background-color: #f9fafb;
+			font-weight: 600;
+			font-size: 14px;
+			border-bottom: 2px solid #e5e7eb;
+		}
+
+		.invoice-table td {
+			padding: 16px 8px;
+			font-size: 14px;
+			color: #1f2937;
+			border-bottom: 1px solid #f3f4f6;
+		}
+
+		.invoice-table tr:last-child td {
+			border-bottom: none;
+		}
+
+		.invoice-table th:last-child,
+		.invoice-table td:last-child {
+			text-align: right;
+		}
+
+		.invoice-totals {
+			display: flex;
+			flex-direction: column;
+			align-items: flex-end;
+			margin-bottom: 32px;
+		}
+
+		.invoice-subtotal,
+		.invoice-tax {
+			display: flex;
+			justify-content: space-between;
+			width: 320px;
+			padding: 8px 0;
+			font-size: 14px;
+		}
+
+		.invoice-subtotal span:first-child,
+		.invoice-tax span:first-child {
+			color: #4b5563;
+		}
+
+		.invoice-subtotal span:last-child,
+		.invoice-tax span:last-child {
+			color: #1f2937;
+		}
+
+		.invoice-total {
+			display: flex;
+			justify-content: space-between;
+			width: 320px;
+			padding: 12px 0;
+			border-top: 1px solid #d1d5db;
+		}
+
+		.invoice-total-label {
+			font-size: 16px;
+			font-weight: 600;
+			color: var(--accent-color);
+		}
+
+		.invoice-total-amount {
+			font-size: 16px;
+			font-weight: 700;
+			color: var(--accent-color);
+		}
+
+		.invoice-amount-words {
+			width: 320px;
+			padding-top: 4px;
+			font-size: 13px;
+			font-style: italic;
+			color: #4b5563;
+		}
+
+		.invoice-amount-words span {
+			font-style: normal;
+			font-weight: 500;
+		}
+
+		.invoice-notes {
+			margin-bottom: 32px;
+			font-size: 14px;
+			color: #374151;
+			line-height: 1.6;
+		}
+
+		.invoice-notes > div {
+			margin-bottom: 16px;
+		}
+
+		.bank-details {
+			padding: 24px;
+			background-color: #f3f4f6;
+			border-radius: 4px;
+			font-size: 14px;
+		}
+
+		.bank-details h4 {
+			font-size: 14px;
+			font-weight: 600;
+			text-transform: uppercase;
+			letter-spacing: 0.025em;
+			color: #4b5563;
+			margin-bottom: 16px;
+		}
+
+		.bank-details-grid {
+			color: #374151;
+		}
+
+		.bank-details-grid > div {
+			margin-bottom: 8px;
+		}
+
+		.bank-details-label {
+			font-weight: 500;
+			display: inline-block;
+			min-width: 150px;
+		}
+
+		.bank-details-body {
+			display: flex;
+			justify-content: space-between;
+			align-items: center;
+			gap: 24px;
+		}
+
+		.qris {
+			text-align: center;
+			font-size: 12px;
+			color: #4b5563;
+		}
+
+		.qris img {
+			display: block;
+			width: 160px;
+			height: 160px;
+			margin-bottom: 4px;
+		}
+
+		.party-info > div,
+		.item-description,
+		.invoice-notes-text {
+			white-space: pre-line;
+		}
+
+		.invoice-logo {
+			display: block;
+			max-width: 200px;
+			max-height: 64px;
+			margin-bottom: 16px;
+		}
+
+		.invoice-footer {
+			margin-top: 32px;
+			padding-top: 16px;
+			border-top: 1px solid var(--border-color);
+			font-size: 12px;
+			color: #6b7280;
+			text-align: center;
+			white-space: pre-line;
+		}
+
+		@media (max-width: 768px) {
+			.invoice-header,
+			.invoice-parties {
+			flex-direction: column;
+			}
+
+			.invoice-dates,
+			.invoice-parties div:last-child {
+			margin-top: 20px;
+			text-align: left;
+			}
+		}
+
+		{{.LayoutStyle}}
+		</style>
+	</head>
+	<body>
+		<div class="invoice-container">
+		<div class="invoice-header">
+			<div>
+			{{if .Logo}}<img class="invoice-logo" src="{{.Logo}}" alt="" />{{end}}
+			<div class="invoice-title">{{.Labels.Heading}}</div>
+			<div class="invoice-id">{{.InvoiceNumber}}</div>
+			</div>
+			<div class="invoice-dates">
+			<div>{{.Labels.IssueDate}} {{.IssueDate}}</div>
+			<div>{{.Labels.DueDate}} {{.DueDate}}</div>
+			{{if .PaymentTerms}}<div>{{.Labels.PaymentTerms}} {{.PaymentTerms}}</div>{{end}}
+			</div>
+		</div>
+
+		<div class="invoice-parties">
+			<div>
+				<h3>{{.Labels.From}}</h3>
+				<div class="party-info">
+					<div>{{.Sender.Name}}</div>
+					<div>{{.Sender.Address}}</div>
+					<div>{{.Sender.Email}}</div>
+					<div>{{.Sender.Phone}}</div>
+				</div>
+				</div>
+				<div>
+				<h3>{{.Labels.To}}</h3>
+				<div class="party-info">
+					<div>{{.Client.Name}}</div>
+					<div>{{.Client.Address}}</div>
+					<div>{{.Client.Email}}</div>
+					<div>{{.Client.Phone}}</div>
+				</div>
+			</div>
+		</div>
+
+		<table class="invoice-table">
+			<thead>
+			<tr>
+				<th>{{.Labels.Description}}</th>
+				<th>{{.Labels.Quantity}}</th>
+				<th>{{.Labels.UnitPrice}}</th>
+				<th>{{.Labels.Total}}</th>
+			</tr>
+			</thead>
+			<tbody>
+			{{range .Items}}
+			<tr>
+				<td class="item-description">{{.Description}}</td>
+				<td>{{.Quantity}}</td>
+				<td>{{$.Currency}} {{.UnitPrice}}</td>
+				<td>{{$.Currency}} {{.Total}}</td>
+			</tr>
+			{{end}}
+			</tbody>
+		</table>
+
+		<div class="invoice-totals">
+			<div class="invoice-subtotal">
+				<span>{{.Labels.Subtotal}}</span>
+				<span>{{.Currency}} {{.Subtotal}}</span>
+			</div>
+			<div class="invoice-tax">
+				<span>{{.Labels.Tax}}</span>
+				<span>{{.Currency}} {{.Tax}}</span>
+			</div>
+			{{if .DeliveryFee}}
+			<div class="invoice-tax">
+				<span>{{.Labels.DeliveryFee}}</span>
+				<span>{{.Currency}} {{.DeliveryFee}}</span>
+			</div>
+			{{end}}
+			<div class="invoice-total">
+				<span class="invoice-total-label">{{.Labels.TotalDue}}</span>
+				<span class="invoice-total-amount"
+					>{{.Currency}} {{.Total}}</span
+				>
+			</div>
+			{{if .AmountInWords}}<div class="invoice-amount-words"><span>{{.Labels.AmountInWords}}</span> {{.AmountInWords}}</div>{{end}}
+		</div>
+
+		<div class="invoice-notes">
+			{{if .Notes}}<div><span style="font-weight: 500;">{{.Labels.Terms}}</span> <span class="invoice-notes-text">{{.Notes}}</span></div>{{end}}
+			<div><strong>{{.Labels.ThankYou}}</strong> {{.Labels.ForYourBusiness}}</div>
+		</div>
+
+		<div class="bank-details">
+			<h4>{{.Labels.BankDetails}}</h4>
+			<div class="bank-details-body">
+			<div class="bank-details-grid">
+				<div>
+					<span class="bank-details-label">{{.Labels.BankName}}</span>
+					<span>{{.Bank.Name}}</span>
+				</div>
+				<div>
+					<span class="bank-details-label">{{.Labels.AccountName}}</span>
+					<span>{{.Bank.AccountName}}</span>
+				</div>
+				<div>
+					<span class="bank-details-label">{{.Labels.AccountNumber}}</span>
+					<span>{{.Bank.AccountNumber}}</span>
+				</div>
+			</div>
+			{{if .QRISImage}}
+			<div class="qris">
+				<img src="{{.QRISImage}}" alt="QRIS" />
+				<span>{{.Labels.ScanToPay}}</span>
+			</div>
+			{{end}}
+			</div>
+		</div>
+		{{if .FooterText}}<div class="invoice-footer">{{.FooterText}}</div>{{end}}
+		</div>
+	</body>
+	</html>
+	`))
+
+// layoutStyles holds the stylesheet each built-in layout adds on top of the
+// classic design.
+var layoutStyles = map[entity.InvoiceLayout]string{
+	entity.InvoiceLayoutClassic: "",
+	entity.InvoiceLayoutModern: `
+		.invoice-header {
+			padding: 32px;
+			border-radius: 12px;
+			background-color: var(--accent-color);
+		}
+
+		.invoice-title,
+		.invoice-id,
+		.invoice-dates {
+			color: white;
+		}
+
+		.invoice-table th {
+			background-color: transparent;
+			border-bottom: 2px solid var(--accent-color);
+			font-size: 12px;
+			text-transform: uppercase;
+			letter-spacing: 0.05em;
+		}
+
+		.invoice-total {
+			border-top: 2px solid var(--accent-color);
+		}
+
+		.bank-details {
+			background-color: white;
+			border: 1px solid var(--border-color);
+			border-left: 4px solid var(--accent-color);
+			border-radius: 8px;
+		}`,
+	entity.InvoiceLayoutCompact: `
+		body {
+			padding: 16px 12px;
+		}
+
+		.invoice-container {
+			padding: 16px;
+		}
+
+		.invoice-header,
+		.invoice-parties {
+			margin-bottom: 24px;
+		}
+
+		.invoice-title {
+			font-size: 24px;
+		}
+
+		.invoice-parties {
+			gap: 24px;
+		}
+
+		.invoice-parties h3,
+		.bank-details h4 {
+			margin-bottom: 8px;
+		}
+
+		.invoice-table th,
+		.invoice-table td {
+			padding: 8px 6px;
+		}
+
+		.invoice-table,
+		.invoice-totals,
+		.invoice-notes {
+			margin-bottom: 16px;
+		}
+
+		.bank-details {
+			padding: 16px;
+		}`,
+}
@@ -0,0 +1,91 @@
+package invoice
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+const (
+	scriptPayload    = "<script>alert(1)</script>"
+	attributePayload = `"><img src=x onerror=alert(1)>`
+)
+
+// hostileDocument returns the sample invoice with markup in every field a
+// user or client controls, each spread over two lines.
+func hostileDocument() *entity.InvoiceDocument {
+	doc := sampleDocument(11, 0)
+	doc.Invoice.Notes = scriptPayload + "\n" + attributePayload
+	doc.Invoice.Items[0].Description = attributePayload + "\n" + scriptPayload
+	doc.Client.Name = scriptPayload + attributePayload
+	doc.Client.Address = scriptPayload + "\n" + attributePayload
+	return doc
+}
+
+func TestGenerateTemplateEscapesUserContent(t *testing.T) {
+	const custom = `<!DOCTYPE html><html><body>
+<div class="to">{{.Client.Name}}</div>
+<div class="address">{{.Client.Address}}</div>
+{{range .Items}}<div class="item">{{.Description}}</div>{{end}}
+<div class="notes">{{.Notes}}</div>
+</body></html>`
+
+	escapedScript := "&lt;script&gt;alert(1)&lt;/script&gt;"
+	escapedAttribute := "&#34;&gt;&lt;img src=x onerror=alert(1)&gt;"
+	for _, layout := range entity.InvoiceLayouts {
+		t.Run(string(layout), func(t *testing.T) {
+			doc := hostileDocument()
+			branding := entity.InvoiceBranding{Layout: layout}
+			if layout == entity.InvoiceLayoutCustom {
+				branding.Template = custom
+			}
+
+			out, err := (&UseCase{}).generateTemplate(doc.Invoice, doc.Sender, doc.Client, branding)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, bad := range []string{"<script", "<img src=x"} {
+				if strings.Contains(out, bad) {
+					t.Errorf("output contains unescaped %q", bad)
+				}
+			}
+
+			for _, want := range []string{
+				escapedScript + escapedAttribute,        // client name
+				escapedScript + "\n" + escapedAttribute, // client address, notes
+				escapedAttribute + "\n" + escapedScript, // item description
+			} {
+				if !strings.Contains(out, want) {
+					t.Errorf("output lacks %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateTemplateKeepsLineBreaks(t *testing.T) {
+	doc := hostileDocument()
+	out, err := (&UseCase{}).generateTemplate(doc.Invoice, doc.Sender, doc.Client, entity.InvoiceBranding{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`<div>Jl. Sudirman 1` + "\n" + `Jakarta</div>`,
+		`<td class="item-description">&#34;&gt;`,
+		`<span class="invoice-notes-text">&lt;script&gt;`,
+		"white-space: pre-line;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q", want)
+		}
+	}
+
+	// The breaks are kept as newlines shown by pre-line, never turned into
+	// markup.
+	if strings.Contains(out, "<br") {
+		t.Error("output has <br> elements")
+	}
+}
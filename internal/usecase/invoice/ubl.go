@@ -0,0 +1,284 @@
+package invoice
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/pkg/i18n"
+)
+
+const (
+	peppolCustomization = "urn:cen.eu:en16931:2017#compliant#urn:fdc:peppol.eu:2017:poacc:billing:3.0"
+	peppolProfile       = "urn:fdc:peppol.eu:2017:poacc:billing:01:1.0"
+	// electronicMail is the Peppol electronic address scheme of emails.
+	electronicMail = "EM"
+)
+
+// ExportUBL returns the invoice as a UBL 2.1 Peppol BIS Billing 3.0
+// e-invoice, after checking it against the business rules Peppol access
+// points validate.
+func (u *UseCase) ExportUBL(id, userID uint) ([]byte, error) {
+	doc, err := u.document(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := ublDocument(doc)
+	if err := validateUBL(out); err != nil {
+		return nil, err
+	}
+
+	data, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("ubl: %w", err)
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+// ublDocument maps the invoice of doc to UBL, the seller being the sender
+// and the buyer the client. The delivery fee is a document level charge.
+func ublDocument(doc *entity.InvoiceDocument) *ublInvoice {
+	inv := doc.Invoice
+	money := func(v float64) ublAmount {
+		return ublAmount{Currency: invoiceCurrency, Value: amount(v)}
+	}
+
+	category, feeCategory, subtotals := vatBreakdown(inv)
+	lines := make([]ublLine, len(inv.Items))
+	for i, it := range inv.Items {
+		lines[i] = ublLine{
+			ID:        strconv.Itoa(i + 1),
+			Quantity:  ublQuantity{UnitCode: unitPiece, Value: strconv.Itoa(it.Quantity)},
+			NetAmount: money(it.Total),
+			Item: ublItem{
+				Name:        it.Description,
+				TaxCategory: ublTaxCategory{ID: category, Percent: percent(inv.TaxRate), TaxScheme: "VAT"},
+			},
+			Price: money(it.UnitPrice),
+		}
+	}
+
+	var charges []ublCharge
+	if inv.DeliveryFee != 0 {
+		charges = append(charges, ublCharge{
+			Indicator:   true,
+			Reason:      "Delivery fee",
+			Amount:      money(inv.DeliveryFee),
+			TaxCategory: ublTaxCategory{ID: feeCategory, Percent: "0", TaxScheme: "VAT"},
+		})
+	}
+
+	tax := ublTaxTotal{TaxAmount: money(inv.Tax)}
+	for _, s := range subtotals {
+		tax.Subtotals = append(tax.Subtotals, ublTaxSubtotal{
+			TaxableAmount: money(s.basis),
+			TaxAmount:     money(s.tax),
+			TaxCategory: ublTaxCategory{
+				ID:              s.category,
+				Percent:         percent(s.rate),
+				ExemptionReason: s.exemptionReason,
+				TaxScheme:       "VAT",
+			},
+		})
+	}
+
+	var means *ublPaymentMeans
+	if doc.Sender.BankAccountNumber != "" {
+		means = &ublPaymentMeans{
+			Code: creditTransfer,
+			// The invoice number lets the seller match the transfer.
+			PaymentID: inv.InvoiceNumber,
+			Account: ublAccount{
+				ID:   doc.Sender.BankAccountNumber,
+				Name: doc.Sender.BankAccountName,
+			},
+		}
+	}
+
+	var terms *ublNote
+	if text := inv.PaymentTerms.Localized(i18n.For(doc.Language).T, inv.PaymentTermsDays); text != "" {
+		terms = &ublNote{Note: text}
+	}
+
+	return &ublInvoice{
+		CAC:             "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2",
+		CBC:             "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2",
+		CustomizationID: peppolCustomization,
+		ProfileID:       peppolProfile,
+		ID:              inv.InvoiceNumber,
+		IssueDate:       inv.IssueDate.Format("2006-01-02"),
+		DueDate:         inv.DueDate.Format("2006-01-02"),
+		TypeCode:        commercialInvoice,
+		Note:            inv.Notes,
+		Currency:        invoiceCurrency,
+		// Clients do not give order references, so the buyer finds the
+		// invoice by its number.
+		BuyerReference: inv.InvoiceNumber,
+		Supplier:       ublPartyOf(doc.Sender.Name, doc.Sender.Email, doc.Sender.Phone, doc.Sender.Address, doc.Sender.NPWP),
+		Customer:       ublPartyOf(doc.Client.Name, doc.Client.Email, doc.Client.Phone, doc.Client.Address, doc.Client.NPWP),
+		PaymentMeans:   means,
+		PaymentTerms:   terms,
+		Charges:        charges,
+		TaxTotal:       tax,
+		Totals: ublTotals{
+			LineExtension: money(inv.Subtotal),
+			TaxExclusive:  money(inv.Subtotal + inv.DeliveryFee),
+			TaxInclusive:  money(inv.Total),
+			ChargeTotal:   money(inv.DeliveryFee),
+			Prepaid:       money(inv.AmountPaid),
+			Payable:       money(inv.Total - inv.AmountPaid),
+		},
+		Lines: lines,
+	}
+}
+
+func ublPartyOf(name, email, phone, address, npwp string) ublParty {
+	p := ublParty{
+		Name:    name,
+		Address: ublAddress{Street: oneLine(address), Country: invoiceCountry},
+		Legal:   name,
+	}
+	if email != "" {
+		p.Endpoint = &ublEndpoint{Scheme: electronicMail, Value: email}
+	}
+
+	if id := vatID(npwp); id != "" {
+		p.TaxScheme = &ublPartyTaxScheme{CompanyID: id, TaxScheme: "VAT"}
+	}
+
+	if email != "" || phone != "" {
+		p.Contact = &ublContact{Phone: phone, Email: email}
+	}
+
+	return p
+}
+
+// The UBL 2.1 invoice elements Peppol uses, in the order the schema
+// requires. Peppol rejects empty elements, so optional ones are omitted.
+
+type ublInvoice struct {
+	XMLName         xml.Name         `xml:"urn:oasis:names:specification:ubl:schema:xsd:Invoice-2 Invoice"`
+	CAC             string           `xml:"xmlns:cac,attr"`
+	CBC             string           `xml:"xmlns:cbc,attr"`
+	CustomizationID string           `xml:"cbc:CustomizationID"`
+	ProfileID       string           `xml:"cbc:ProfileID"`
+	ID              string           `xml:"cbc:ID"`
+	IssueDate       string           `xml:"cbc:IssueDate"`
+	DueDate         string           `xml:"cbc:DueDate,omitempty"`
+	TypeCode        string           `xml:"cbc:InvoiceTypeCode"`
+	Note            string           `xml:"cbc:Note,omitempty"`
+	Currency        string           `xml:"cbc:DocumentCurrencyCode"`
+	BuyerReference  string           `xml:"cbc:BuyerReference,omitempty"`
+	Supplier        ublParty         `xml:"cac:AccountingSupplierParty>cac:Party"`
+	Customer        ublParty         `xml:"cac:AccountingCustomerParty>cac:Party"`
+	PaymentMeans    *ublPaymentMeans `xml:"cac:PaymentMeans,omitempty"`
+	PaymentTerms    *ublNote         `xml:"cac:PaymentTerms,omitempty"`
+	Charges         []ublCharge      `xml:"cac:AllowanceCharge"`
+	TaxTotal        ublTaxTotal      `xml:"cac:TaxTotal"`
+	Totals          ublTotals        `xml:"cac:LegalMonetaryTotal"`
+	Lines           []ublLine        `xml:"cac:InvoiceLine"`
+}
+
+type ublAmount struct {
+	Currency string `xml:"currencyID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ublParty struct {
+	Endpoint  *ublEndpoint       `xml:"cbc:EndpointID,omitempty"`
+	Name      string             `xml:"cac:PartyName>cbc:Name,omitempty"`
+	Address   ublAddress         `xml:"cac:PostalAddress"`
+	TaxScheme *ublPartyTaxScheme `xml:"cac:PartyTaxScheme,omitempty"`
+	Legal     string             `xml:"cac:PartyLegalEntity>cbc:RegistrationName,omitempty"`
+	Contact   *ublContact        `xml:"cac:Contact,omitempty"`
+}
+
+type ublPartyTaxScheme struct {
+	CompanyID string `xml:"cbc:CompanyID"`
+	TaxScheme string `xml:"cac:TaxScheme>cbc:ID"`
+}
+
+type ublEndpoint struct {
+	Scheme string `xml:"schemeID,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type ublAddress struct {
+	Street  string `xml:"cbc:StreetName,omitempty"`
+	Country string `xml:"cac:Country>cbc:IdentificationCode,omitempty"`
+}
+
+type ublContact struct {
+	Phone string `xml:"cbc:Telephone,omitempty"`
+	Email string `xml:"cbc:ElectronicMail,omitempty"`
+}
+
+type ublPaymentMeans struct {
+	Code      string     `xml:"cbc:PaymentMeansCode"`
+	PaymentID string     `xml:"cbc:PaymentID,omitempty"`
+	Account   ublAccount `xml:"cac:PayeeFinancialAccount"`
+}
+
+type ublAccount struct {
+	ID   string `xml:"cbc:ID"`
+	Name string `xml:"cbc:Name,omitempty"`
+}
+
+type ublNote struct {
+	Note string `xml:"cbc:Note"`
+}
+
+type ublCharge struct {
+	Indicator   bool           `xml:"cbc:ChargeIndicator"`
+	Reason      string         `xml:"cbc:AllowanceChargeReason,omitempty"`
+	Amount      ublAmount      `xml:"cbc:Amount"`
+	TaxCategory ublTaxCategory `xml:"cac:TaxCategory"`
+}
+
+type ublTaxCategory struct {
+	ID              string `xml:"cbc:ID"`
+	Percent         string `xml:"cbc:Percent,omitempty"`
+	ExemptionReason string `xml:"cbc:TaxExemptionReason,omitempty"`
+	TaxScheme       string `xml:"cac:TaxScheme>cbc:ID"`
+}
+
+type ublTaxTotal struct {
+	TaxAmount ublAmount        `xml:"cbc:TaxAmount"`
+	Subtotals []ublTaxSubtotal `xml:"cac:TaxSubtotal"`
+}
+
+type ublTaxSubtotal struct {
+	TaxableAmount ublAmount      `xml:"cbc:TaxableAmount"`
+	TaxAmount     ublAmount      `xml:"cbc:TaxAmount"`
+	TaxCategory   ublTaxCategory `xml:"cac:TaxCategory"`
+}
+
+type ublTotals struct {
+	LineExtension ublAmount `xml:"cbc:LineExtensionAmount"`
+	TaxExclusive  ublAmount `xml:"cbc:TaxExclusiveAmount"`
+	TaxInclusive  ublAmount `xml:"cbc:TaxInclusiveAmount"`
+	ChargeTotal   ublAmount `xml:"cbc:ChargeTotalAmount"`
+	Prepaid       ublAmount `xml:"cbc:PrepaidAmount"`
+	Payable       ublAmount `xml:"cbc:PayableAmount"`
+}
+
+type ublLine struct {
+	ID        string      `xml:"cbc:ID"`
+	Quantity  ublQuantity `xml:"cbc:InvoicedQuantity"`
+	NetAmount ublAmount   `xml:"cbc:LineExtensionAmount"`
+	Item      ublItem     `xml:"cac:Item"`
+	Price     ublAmount   `xml:"cac:Price>cbc:PriceAmount"`
+}
+
+type ublQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ublItem struct {
+	Name        string         `xml:"cbc:Name"`
+	TaxCategory ublTaxCategory `xml:"cac:ClassifiedTaxCategory"`
+}
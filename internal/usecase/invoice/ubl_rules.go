@@ -0,0 +1,296 @@
+package invoice
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ublRule is one of the EN 16931 and Peppol BIS Billing 3.0 business rules
+// that Peppol access points check with schematron before accepting an
+// e-invoice. Like the schematron, the rules look at the finished document,
+// so they also catch mistakes in the mapping.
+type ublRule struct {
+	id      string
+	message string
+	holds   func(d *ublInvoice) bool
+}
+
+var ublRules = []ublRule{
+	{"BR-01", "An Invoice shall have a Specification identifier", func(d *ublInvoice) bool {
+		return d.CustomizationID != ""
+	}},
+	{"PEPPOL-EN16931-R004", "Specification identifier MUST have the value '" + peppolCustomization + "'", func(d *ublInvoice) bool {
+		return d.CustomizationID == peppolCustomization
+	}},
+	{"PEPPOL-EN16931-R001", "Business process MUST be provided", func(d *ublInvoice) bool {
+		return d.ProfileID != ""
+	}},
+	{"BR-02", "An Invoice shall have an Invoice number", func(d *ublInvoice) bool {
+		return d.ID != ""
+	}},
+	{"BR-03", "An Invoice shall have an Invoice issue date", func(d *ublInvoice) bool {
+		return d.IssueDate != ""
+	}},
+	{"BR-04", "An Invoice shall have an Invoice type code", func(d *ublInvoice) bool {
+		return d.TypeCode != ""
+	}},
+	{"BR-05", "An Invoice shall have an Invoice currency code", func(d *ublInvoice) bool {
+		return d.Currency != ""
+	}},
+	{"PEPPOL-EN16931-R003", "A buyer reference or purchase order reference MUST be provided", func(d *ublInvoice) bool {
+		return d.BuyerReference != ""
+	}},
+	{"BR-06", "An Invoice shall contain the Seller name", func(d *ublInvoice) bool {
+		return d.Supplier.Legal != ""
+	}},
+	{"BR-07", "An Invoice shall contain the Buyer name", func(d *ublInvoice) bool {
+		return d.Customer.Legal != ""
+	}},
+	{"BR-09", "The Seller postal address shall contain a Seller country code", func(d *ublInvoice) bool {
+		return d.Supplier.Address.Country != ""
+	}},
+	{"BR-11", "The Buyer postal address shall contain a Buyer country code", func(d *ublInvoice) bool {
+		return d.Customer.Address.Country != ""
+	}},
+	{"PEPPOL-EN16931-R020", "Seller electronic address MUST be provided", func(d *ublInvoice) bool {
+		return d.Supplier.Endpoint != nil && d.Supplier.Endpoint.Value != ""
+	}},
+	{"PEPPOL-EN16931-R010", "Buyer electronic address MUST be provided", func(d *ublInvoice) bool {
+		return d.Customer.Endpoint != nil && d.Customer.Endpoint.Value != ""
+	}},
+	{"BR-16", "An Invoice shall have at least one Invoice line", func(d *ublInvoice) bool {
+		return len(d.Lines) > 0
+	}},
+	{"BR-25", "Each Invoice line shall contain the Item name", func(d *ublInvoice) bool {
+		for _, l := range d.Lines {
+			if l.Item.Name == "" {
+				return false
+			}
+		}
+
+		return true
+	}},
+	{"BR-27", "The Item net price shall NOT be negative", func(d *ublInvoice) bool {
+		for _, l := range d.Lines {
+			if cents(l.Price) < 0 {
+				return false
+			}
+		}
+
+		return true
+	}},
+	{"PEPPOL-EN16931-R120", "Invoice line net amount MUST equal (Invoiced quantity * Item net price)", func(d *ublInvoice) bool {
+		for _, l := range d.Lines {
+			qty, _ := strconv.ParseFloat(l.Quantity.Value, 64)
+			if cents(l.NetAmount) != int64(math.Round(qty*float64(cents(l.Price)))) {
+				return false
+			}
+		}
+
+		return true
+	}},
+	{"PEPPOL-EN16931-R051", "All currencyID attributes MUST have the same value as the invoice currency code", func(d *ublInvoice) bool {
+		for _, a := range ublAmounts(d) {
+			if a.Currency != d.Currency {
+				return false
+			}
+		}
+
+		return true
+	}},
+	{"BR-CO-10", "Sum of Invoice line net amount = Σ Invoice line net amount", func(d *ublInvoice) bool {
+		var sum int64
+		for _, l := range d.Lines {
+			sum += cents(l.NetAmount)
+		}
+
+		return cents(d.Totals.LineExtension) == sum
+	}},
+	{"BR-CO-12", "Sum of charges on document level = Σ Document level charge amount", func(d *ublInvoice) bool {
+		var sum int64
+		for _, c := range d.Charges {
+			sum += cents(c.Amount)
+		}
+
+		return cents(d.Totals.ChargeTotal) == sum
+	}},
+	{"BR-CO-13", "Invoice total amount without VAT = Σ Invoice line net amount - Sum of allowances on document level + Sum of charges on document level", func(d *ublInvoice) bool {
+		return cents(d.Totals.TaxExclusive) == cents(d.Totals.LineExtension)+cents(d.Totals.ChargeTotal)
+	}},
+	{"BR-CO-14", "Invoice total VAT amount = Σ VAT category tax amount", func(d *ublInvoice) bool {
+		var sum int64
+		for _, s := range d.TaxTotal.Subtotals {
+			sum += cents(s.TaxAmount)
+		}
+
+		return cents(d.TaxTotal.TaxAmount) == sum
+	}},
+	{"BR-CO-15", "Invoice total amount with VAT = Invoice total amount without VAT + Invoice total VAT amount", func(d *ublInvoice) bool {
+		return cents(d.Totals.TaxInclusive) == cents(d.Totals.TaxExclusive)+cents(d.TaxTotal.TaxAmount)
+	}},
+	{"BR-CO-16", "Amount due for payment = Invoice total amount with VAT - Paid amount", func(d *ublInvoice) bool {
+		return cents(d.Totals.Payable) == cents(d.Totals.TaxInclusive)-cents(d.Totals.Prepaid)
+	}},
+	{"BR-CO-18", "An Invoice shall at least have one VAT breakdown group", func(d *ublInvoice) bool {
+		return len(d.TaxTotal.Subtotals) > 0
+	}},
+	{"BR-CO-25", "In case the Amount due for payment is positive, either the Payment due date or the Payment terms shall be present", func(d *ublInvoice) bool {
+		return cents(d.Totals.Payable) <= 0 || d.DueDate != "" || d.PaymentTerms != nil
+	}},
+	{"BR-61", "If the Payment means type code means credit transfer, the Payment account identifier shall be present", func(d *ublInvoice) bool {
+		return d.PaymentMeans == nil || d.PaymentMeans.Code != creditTransfer || d.PaymentMeans.Account.ID != ""
+	}},
+	{"BR-S-2", "An Invoice with a Standard rated VAT breakdown shall contain the Seller VAT Identifier", func(d *ublInvoice) bool {
+		for _, s := range d.TaxTotal.Subtotals {
+			if s.TaxCategory.ID == vatStandard && d.Supplier.TaxScheme == nil {
+				return false
+			}
+		}
+
+		return true
+	}},
+	{"BR-CO-09", "The Seller VAT identifier and the Buyer VAT identifier shall have a prefix in accordance with ISO code ISO 3166-1 alpha-2", func(d *ublInvoice) bool {
+		for _, p := range []ublParty{d.Supplier, d.Customer} {
+			if p.TaxScheme != nil && !isCountryPrefixed(p.TaxScheme.CompanyID) {
+				return false
+			}
+		}
+
+		return true
+	}},
+	{"BR-S-5", "The VAT rate of the Standard rated category shall be greater than zero", func(d *ublInvoice) bool {
+		return categoryRates(d, vatStandard, func(rate float64) bool { return rate > 0 })
+	}},
+	{"BR-S-8", "The VAT taxable amount of the Standard rated category shall equal the sum of its Invoice line net amounts and document level charges", func(d *ublInvoice) bool {
+		return categoryBasis(d, vatStandard)
+	}},
+	{"BR-S-9", "The VAT tax amount of the Standard rated category shall equal its taxable amount multiplied by its rate", func(d *ublInvoice) bool {
+		for _, s := range d.TaxTotal.Subtotals {
+			rate, _ := strconv.ParseFloat(s.TaxCategory.Percent, 64)
+			if s.TaxCategory.ID == vatStandard && cents(s.TaxAmount) != int64(math.Round(float64(cents(s.TaxableAmount))*rate/100)) {
+				return false
+			}
+		}
+
+		return true
+	}},
+	{"BR-Z-5", "The VAT rate of the Zero rated category shall be 0", func(d *ublInvoice) bool {
+		return categoryRates(d, vatZeroRated, func(rate float64) bool { return rate == 0 })
+	}},
+	{"BR-Z-8", "The VAT taxable amount of the Zero rated category shall equal the sum of its Invoice line net amounts and document level charges", func(d *ublInvoice) bool {
+		return categoryBasis(d, vatZeroRated)
+	}},
+	{"BR-E-5", "The VAT rate of the Exempt from VAT category shall be 0", func(d *ublInvoice) bool {
+		return categoryRates(d, vatExempt, func(rate float64) bool { return rate == 0 })
+	}},
+	{"BR-E-8", "The VAT taxable amount of the Exempt from VAT category shall equal the sum of its Invoice line net amounts and document level charges", func(d *ublInvoice) bool {
+		return categoryBasis(d, vatExempt)
+	}},
+	{"BR-E-10", "A VAT breakdown with VAT Category code Exempt from VAT shall have a VAT exemption reason", func(d *ublInvoice) bool {
+		for _, s := range d.TaxTotal.Subtotals {
+			if s.TaxCategory.ID == vatExempt && s.TaxCategory.ExemptionReason == "" {
+				return false
+			}
+		}
+
+		return true
+	}},
+}
+
+// validateUBL checks d against ublRules and reports every rule it breaks.
+func validateUBL(d *ublInvoice) error {
+	var broken []string
+	for _, r := range ublRules {
+		if !r.holds(d) {
+			broken = append(broken, fmt.Sprintf("[%s] %s", r.id, r.message))
+		}
+	}
+
+	if len(broken) > 0 {
+		return fmt.Errorf("invoice is not a valid Peppol e-invoice: %s", strings.Join(broken, "; "))
+	}
+
+	return nil
+}
+
+// cents reads a serialized amount in hundredths, as the rules compare
+// amounts rounded to two decimals.
+func cents(a ublAmount) int64 {
+	v, _ := strconv.ParseFloat(a.Value, 64)
+	return int64(math.Round(v * 100))
+}
+
+func ublAmounts(d *ublInvoice) []ublAmount {
+	t := d.Totals
+	amounts := []ublAmount{d.TaxTotal.TaxAmount, t.LineExtension, t.TaxExclusive, t.TaxInclusive, t.ChargeTotal, t.Prepaid, t.Payable}
+	for _, s := range d.TaxTotal.Subtotals {
+		amounts = append(amounts, s.TaxableAmount, s.TaxAmount)
+	}
+
+	for _, c := range d.Charges {
+		amounts = append(amounts, c.Amount)
+	}
+
+	for _, l := range d.Lines {
+		amounts = append(amounts, l.NetAmount, l.Price)
+	}
+
+	return amounts
+}
+
+// categoryRates reports whether every line, charge and VAT breakdown of the
+// category has a rate accepted by ok.
+func categoryRates(d *ublInvoice, category string, ok func(rate float64) bool) bool {
+	categories := make([]ublTaxCategory, 0, len(d.Lines)+len(d.Charges)+len(d.TaxTotal.Subtotals))
+	for _, l := range d.Lines {
+		categories = append(categories, l.Item.TaxCategory)
+	}
+
+	for _, c := range d.Charges {
+		categories = append(categories, c.TaxCategory)
+	}
+
+	for _, s := range d.TaxTotal.Subtotals {
+		categories = append(categories, s.TaxCategory)
+	}
+
+	for _, c := range categories {
+		rate, err := strconv.ParseFloat(c.Percent, 64)
+		if c.ID == category && (err != nil || !ok(rate)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// categoryBasis reports whether the VAT breakdown of the category has the
+// lines and charges in it as its taxable amount.
+func categoryBasis(d *ublInvoice, category string) bool {
+	var basis int64
+	for _, l := range d.Lines {
+		if l.Item.TaxCategory.ID == category {
+			basis += cents(l.NetAmount)
+		}
+	}
+
+	for _, c := range d.Charges {
+		if c.TaxCategory.ID == category {
+			basis += cents(c.Amount)
+		}
+	}
+
+	for _, s := range d.TaxTotal.Subtotals {
+		if s.TaxCategory.ID == category && cents(s.TaxableAmount) != basis {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isCountryPrefixed(id string) bool {
+	return len(id) > 2 && id[0] >= 'A' && id[0] <= 'Z' && id[1] >= 'A' && id[1] <= 'Z'
+}
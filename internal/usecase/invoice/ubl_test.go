@@ -0,0 +1,168 @@
+package invoice
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+// sampleDocument returns an invoice as stored through the API, with its tax
+// rate as a percentage.
+func sampleDocument(taxRate, deliveryFee float64) *entity.InvoiceDocument {
+	issued := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	inv := entity.Invoice{
+		InvoiceNumber: "INV-0042",
+		Status:        string(entity.InvoiceStatusSent),
+		IssueDate:     issued,
+		DueDate:       issued.AddDate(0, 0, 30),
+		PaymentTerms:  entity.PaymentTermsNet30,
+		Items: []entity.InvoiceItem{
+			{Description: "Website design", Quantity: 1, UnitPrice: 1000000, Total: 1000000},
+			{Description: "Hosting (12 months)", Quantity: 12, UnitPrice: 25000, Total: 300000},
+		},
+		Subtotal:    1300000,
+		TaxRate:     taxRate,
+		Tax:         1300000 * taxRate / 100,
+		DeliveryFee: deliveryFee,
+		AmountPaid:  100000,
+	}
+	inv.Total = inv.Subtotal + inv.Tax + inv.DeliveryFee
+
+	return &entity.InvoiceDocument{
+		Invoice: inv,
+		Sender: entity.User{
+			Name:              "Studio Hutamy",
+			Email:             "billing@hutamy.id",
+			Address:           "Jl. Sudirman 1\nJakarta",
+			BankName:          "BCA",
+			BankAccountName:   "Studio Hutamy",
+			BankAccountNumber: "1234567890",
+			NPWP:              "0012345678901234",
+		},
+		Client: entity.Client{
+			Name:    "PT Pembeli",
+			Email:   "ap@pembeli.co.id",
+			Address: "Jl. Asia Afrika 8\nBandung",
+		},
+	}
+}
+
+func TestUBLSampleDocumentsAreValid(t *testing.T) {
+	tests := []struct {
+		name        string
+		taxRate     float64
+		deliveryFee float64
+		percents    []string
+		taxes       []string
+	}{
+		{"taxed", 11, 0, []string{"11"}, []string{"143000.00"}},
+		{"taxed with delivery fee", 11, 15000, []string{"11", "0"}, []string{"143000.00", "0.00"}},
+		{"untaxed with delivery fee", 0, 15000, []string{"0"}, []string{"0.00"}},
+		{"fractional rate", 1.1, 0, []string{"1.1"}, []string{"14300.00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := ublDocument(sampleDocument(tt.taxRate, tt.deliveryFee))
+			if err := validateUBL(d); err != nil {
+				t.Fatalf("validateUBL: %v", err)
+			}
+
+			if len(d.TaxTotal.Subtotals) != len(tt.percents) {
+				t.Fatalf("got %d VAT breakdowns, want %d", len(d.TaxTotal.Subtotals), len(tt.percents))
+			}
+
+			for i, s := range d.TaxTotal.Subtotals {
+				if s.TaxCategory.Percent != tt.percents[i] {
+					t.Errorf("breakdown %d: percent %s, want %s", i, s.TaxCategory.Percent, tt.percents[i])
+				}
+
+				if s.TaxAmount.Value != tt.taxes[i] {
+					t.Errorf("breakdown %d: tax %s, want %s", i, s.TaxAmount.Value, tt.taxes[i])
+				}
+			}
+
+			for _, l := range d.Lines {
+				if l.Item.TaxCategory.Percent != tt.percents[0] {
+					t.Errorf("line %s: percent %s, want %s", l.ID, l.Item.TaxCategory.Percent, tt.percents[0])
+				}
+			}
+		})
+	}
+}
+
+func TestUBLSerialization(t *testing.T) {
+	d := ublDocument(sampleDocument(11, 15000))
+	data, err := xml.MarshalIndent(d, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := string(data)
+	for _, want := range []string{
+		`<Invoice xmlns="urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"`,
+		`<cbc:CustomizationID>` + peppolCustomization + `</cbc:CustomizationID>`,
+		`<cbc:EndpointID schemeID="EM">billing@hutamy.id</cbc:EndpointID>`,
+		`<cbc:CompanyID>ID0012345678901234</cbc:CompanyID>`,
+		`<cbc:StreetName>Jl. Sudirman 1 Jakarta</cbc:StreetName>`,
+		`<cbc:PaymentMeansCode>30</cbc:PaymentMeansCode>`,
+		`<cbc:ID>1234567890</cbc:ID>`,
+		`<cbc:Percent>11</cbc:Percent>`,
+		`<cbc:TaxExemptionReason>` + deliveryFeeExemption + `</cbc:TaxExemptionReason>`,
+		`<cbc:PayableAmount currencyID="IDR">1358000.00</cbc:PayableAmount>`,
+		`<cbc:InvoicedQuantity unitCode="C62">12</cbc:InvoicedQuantity>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %s", want)
+		}
+	}
+
+	// Peppol rejects empty elements.
+	if strings.Contains(out, "></cbc:") {
+		t.Error("output has an empty element")
+	}
+}
+
+func TestUBLRulesRejectBrokenDocuments(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(doc *entity.InvoiceDocument)
+		rules  []string
+	}{
+		{"tax does not match the rate", func(doc *entity.InvoiceDocument) {
+			doc.Invoice.Tax++
+		}, []string{"BR-S-9", "BR-CO-15"}},
+		{"line total does not match its price", func(doc *entity.InvoiceDocument) {
+			doc.Invoice.Items[0].Total = 1
+		}, []string{"PEPPOL-EN16931-R120", "BR-CO-10", "BR-S-8"}},
+		{"buyer without an email", func(doc *entity.InvoiceDocument) {
+			doc.Client.Email = ""
+		}, []string{"PEPPOL-EN16931-R010"}},
+		{"taxed by a seller without an NPWP", func(doc *entity.InvoiceDocument) {
+			doc.Sender.NPWP = ""
+		}, []string{"BR-S-2"}},
+		{"no items", func(doc *entity.InvoiceDocument) {
+			doc.Invoice.Items = nil
+		}, []string{"BR-16"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := sampleDocument(11, 0)
+			tt.mutate(doc)
+			err := validateUBL(ublDocument(doc))
+			if err == nil {
+				t.Fatal("validateUBL accepted a broken document")
+			}
+
+			for _, rule := range tt.rules {
+				if !strings.Contains(err.Error(), "["+rule+"]") {
+					t.Errorf("error %q does not name %s", err, rule)
+				}
+			}
+		})
+	}
+}
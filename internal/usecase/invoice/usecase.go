@@ -2,36 +2,68 @@ package invoice
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strconv"
+	"time"
 
-	"github.com/chromedp/cdproto/page"
-	"github.com/chromedp/chromedp"
 	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
 	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
-	"golang.org/x/text/language"
-	"golang.org/x/text/message"
+	"github.com/hutamy/go-invoice-backend/pkg/qris"
 )
 
 type UseCase struct {
-	InvoiceRepo ports.InvoiceRepository
-	ClientRepo  ports.ClientRepository
-	AuthRepo    ports.AuthRepository
+	InvoiceRepo   ports.InvoiceRepository
+	ClientRepo    ports.ClientRepository
+	AuthRepo      ports.AuthRepository
+	TemplateRepo  ports.EmailTemplateRepository
+	ShareLinkRepo ports.ShareLinkRepository
+	BrandingRepo  ports.InvoiceBrandingRepository
+	PaymentRepo   ports.PaymentRepository
+	Mailer        ports.Mailer
+	Signer        ports.Signer
+	Renderer      ports.PDFRenderer
+	PDFJobRepo    ports.PDFJobRepository
+	Blobs         ports.BlobStore
+
+	pdfJobWake chan struct{}
 }
 
 func NewUseCase(
 	invRepo ports.InvoiceRepository,
 	clientRepo ports.ClientRepository,
 	authRepo ports.AuthRepository,
+	templateRepo ports.EmailTemplateRepository,
+	shareLinkRepo ports.ShareLinkRepository,
+	brandingRepo ports.InvoiceBrandingRepository,
+	paymentRepo ports.PaymentRepository,
+	mailer ports.Mailer,
+	signer ports.Signer,
+	renderer ports.PDFRenderer,
+	pdfJobRepo ports.PDFJobRepository,
+	blobs ports.BlobStore,
 ) ports.InvoiceUseCase {
 	return &UseCase{
-		InvoiceRepo: invRepo,
-		ClientRepo:  clientRepo,
-		AuthRepo:    authRepo,
+		InvoiceRepo:   invRepo,
+		ClientRepo:    clientRepo,
+		AuthRepo:      authRepo,
+		TemplateRepo:  templateRepo,
+		ShareLinkRepo: shareLinkRepo,
+		BrandingRepo:  brandingRepo,
+		PaymentRepo:   paymentRepo,
+		Mailer:        mailer,
+		Signer:        signer,
+		Renderer:      renderer,
+		PDFJobRepo:    pdfJobRepo,
+		Blobs:         blobs,
+		pdfJobWake:    make(chan struct{}, 1),
 	}
 }
 
 func (u *UseCase) Create(inv *entity.Invoice) error {
+	if err := u.applyPaymentTerms(inv); err != nil {
+		return err
+	}
+
 	return u.InvoiceRepo.Create(inv)
 }
 
@@ -51,16 +83,136 @@ func (u *UseCase) ListByUser(userID uint, page, pageSize int, status string) ([]
 	return u.InvoiceRepo.ListByUser(userID, page, pageSize, status)
 }
 
+// Update replaces the invoice's details and items. Its status only changes
+// through UpdateStatus, so the stored one is kept.
 func (u *UseCase) Update(update entity.Invoice) error {
-	return u.InvoiceRepo.Update(update)
+	invoice, err := u.InvoiceRepo.GetByID(update.ID, update.UserID)
+	if err != nil {
+		return err
+	}
+
+	if invoice == nil {
+		return errors.New("invoice not found")
+	}
+
+	update.Status = invoice.Status
+	if err := u.applyPaymentTerms(&update); err != nil {
+		return err
+	}
+
+	if err := u.InvoiceRepo.Update(update); err != nil {
+		return err
+	}
+
+	u.invalidatePDFs(update.ID, update.UserID)
+	return nil
+}
+
+// applyPaymentTerms fills in the invoice payment terms from the client
+// override or the user's default when none were given, and derives the due
+// date from them when it was left empty.
+func (u *UseCase) applyPaymentTerms(inv *entity.Invoice) error {
+	if inv.PaymentTerms == "" && inv.ClientID != nil {
+		client, err := u.ClientRepo.GetByID(*inv.ClientID, inv.UserID)
+		if err != nil {
+			return err
+		}
+
+		if client != nil && client.PaymentTerms != "" {
+			inv.PaymentTerms = client.PaymentTerms
+			inv.PaymentTermsDays = client.PaymentTermsDays
+		}
+	}
+
+	if inv.PaymentTerms == "" {
+		user, err := u.AuthRepo.GetUserByID(inv.UserID)
+		if err != nil {
+			return err
+		}
+
+		if user != nil {
+			inv.PaymentTerms = user.PaymentTerms
+			inv.PaymentTermsDays = user.PaymentTermsDays
+		}
+	}
+
+	if inv.PaymentTerms != "" && !inv.PaymentTerms.IsValid() {
+		return errors.New("invalid payment terms")
+	}
+
+	if inv.DueDate.IsZero() {
+		if inv.PaymentTerms == "" {
+			return errors.New("due date is required")
+		}
+
+		inv.DueDate = inv.PaymentTerms.DueDate(inv.IssueDate, inv.PaymentTermsDays)
+	}
+
+	return nil
 }
 
 func (u *UseCase) Delete(id uint, userID uint) error {
-	return u.InvoiceRepo.Delete(id, userID)
+	if err := u.InvoiceRepo.Delete(id, userID); err != nil {
+		return err
+	}
+
+	u.invalidatePDFs(id, userID)
+	return nil
+}
+
+func (u *UseCase) ListTrashed(userID uint, page, pageSize int) ([]entity.Invoice, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	return u.InvoiceRepo.ListTrashedByUser(userID, page, pageSize)
+}
+
+func (u *UseCase) Restore(id, userID uint) error {
+	return u.InvoiceRepo.Restore(id, userID)
+}
+
+func (u *UseCase) PurgeTrashed(retention time.Duration) (int64, error) {
+	return u.InvoiceRepo.PurgeDeletedBefore(time.Now().Add(-retention))
 }
 
 func (u *UseCase) UpdateStatus(id uint, userID uint, status entity.InvoiceStatus) error {
-	return u.InvoiceRepo.UpdateStatus(id, userID, status)
+	invoice, err := u.InvoiceRepo.GetByID(id, userID)
+	if err != nil {
+		return err
+	}
+
+	if invoice == nil {
+		return errors.New("invoice not found")
+	}
+
+	// Setting the status an invoice already has changes nothing, so repeated
+	// and bulk requests succeed.
+	current := entity.InvoiceStatus(invoice.Status)
+	if current == status {
+		return nil
+	}
+
+	if !current.CanTransitionTo(status) {
+		return fmt.Errorf("cannot change status from %s to %s", current, status)
+	}
+
+	if err := u.InvoiceRepo.UpdateStatus(id, userID, status); err != nil {
+		return err
+	}
+
+	u.invalidatePDFs(id, userID)
+	return nil
+}
+
+// MarkOverdue flags sent invoices that were due before the start of asOf's day.
+func (u *UseCase) MarkOverdue(asOf time.Time) (int64, error) {
+	y, m, d := asOf.Date()
+	return u.InvoiceRepo.MarkOverdue(time.Date(y, m, d, 0, 0, 0, 0, asOf.Location()))
 }
 
 func (u *UseCase) Summary(userID uint) (paid, revenue float64, err error) {
@@ -77,19 +229,42 @@ func (u *UseCase) Summary(userID uint) (paid, revenue float64, err error) {
 	return paid, total, nil
 }
 
-func (u *UseCase) GeneratePDF(id, userID uint) ([]byte, error) {
+// RendererStats reports the PDF renderer's pool and timing metrics.
+func (u *UseCase) RendererStats() entity.PDFRendererStats {
+	return u.Renderer.Stats()
+}
+
+// RenderHTML returns the invoice as the HTML document its PDF is printed from.
+func (u *UseCase) RenderHTML(id, userID uint) (string, error) {
+	doc, err := u.document(id, userID)
+	if err != nil {
+		return "", err
+	}
+
+	return doc.HTML, nil
+}
+
+// document gathers the invoice, its sender and client for rendering.
+func (u *UseCase) document(id, userID uint) (*entity.InvoiceDocument, error) {
 	invoice, err := u.InvoiceRepo.GetByID(id, userID)
 	if err != nil {
 		return nil, err
 	}
 
+	if invoice == nil {
+		return nil, errors.New("invoice not found")
+	}
+
 	var client *entity.Client
 	if invoice.ClientID != nil {
 		client, err = u.ClientRepo.GetByID(*invoice.ClientID, userID)
 		if err != nil {
 			return nil, err
 		}
-	} else {
+	}
+
+	// Trashed clients are not returned, but the invoice still carries their details.
+	if client == nil {
 		client = &entity.Client{
 			ID:      0,
 			Name:    *invoice.ClientName,
@@ -104,410 +279,60 @@ func (u *UseCase) GeneratePDF(id, userID uint) ([]byte, error) {
 		return nil, err
 	}
 
-	htmlContent := u.generateTemplate(*invoice, *user, *client)
-	return u.generatePdf(htmlContent)
-}
-
-func (u *UseCase) GeneratePDFPublic(invoice *entity.Invoice) ([]byte, error) {
-	for i, it := range invoice.Items {
-		total := float64(it.Quantity) * it.UnitPrice
-		invoice.Items[i].Total = total
-		invoice.Subtotal += total
+	branding, err := u.branding(userID)
+	if err != nil {
+		return nil, err
 	}
 
-	invoice.Tax = invoice.Subtotal * invoice.TaxRate
-	invoice.Total = invoice.Subtotal + invoice.Tax + invoice.DeliveryFee
-
-	htmlContent := u.generateTemplate(*invoice, invoice.User, invoice.Client)
-	return u.generatePdf(htmlContent)
+	return u.newDocument(*invoice, *user, *client, branding)
 }
 
-func (u *UseCase) generatePdf(htmlContent string) ([]byte, error) {
-	// Setup headless browser
-	ctx, cancel := chromedp.NewContext(context.Background())
-	defer cancel()
-
-	var pdfBuf []byte
-	err := chromedp.Run(ctx,
-		chromedp.Navigate("about:blank"),
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			return chromedp.Evaluate(`document.documentElement.innerHTML = `+strconv.Quote(htmlContent), nil).Do(ctx)
-		}),
-		chromedp.ActionFunc(func(ctx context.Context) error {
-			var err error
-			pdfBuf, _, err = page.PrintToPDF().WithPrintBackground(true).Do(ctx)
-			return err
-		}),
-	)
+func (u *UseCase) newDocument(invoice entity.Invoice, user entity.User, client entity.Client, branding entity.InvoiceBranding) (*entity.InvoiceDocument, error) {
+	html, err := u.generateTemplate(invoice, user, client, branding)
 	if err != nil {
 		return nil, err
 	}
 
-	return pdfBuf, nil
+	payload, _ := qrisPayload(invoice, user)
+	return &entity.InvoiceDocument{
+		Language:    invoiceLanguage(invoice, client),
+		Invoice:     invoice,
+		Sender:      user,
+		Client:      client,
+		Branding:    branding,
+		HTML:        html,
+		QRISPayload: payload,
+	}, nil
 }
 
-func (u *UseCase) generateTemplate(invoice entity.Invoice, user entity.User, client entity.Client) string {
-	p := message.NewPrinter(language.English)
-	items := ""
-	for _, it := range invoice.Items {
-		items += fmt.Sprintf(`
-			<tr>
-				<td>%s</td>
-				<td>%d</td>
-				<td>IDR %s</td>
-				<td>IDR %s</td>
-			</tr>`, it.Description, it.Quantity, p.Sprintf("%.2f", it.UnitPrice), p.Sprintf("%.2f", it.Total))
-	}
-
-	deliveryRate := ""
-	if invoice.DeliveryFee > 0 {
-		deliveryRate = fmt.Sprintf(`
-			<div class="invoice-tax">
-				<span>Delivery Fee:</span>
-				<span>IDR %s</span>
-			</div>
-		`, p.Sprintf("%.2f", invoice.DeliveryFee))
-	}
-
-	terms := ""
-	if invoice.Notes != "" {
-		terms = fmt.Sprintf("<div><span style=\"font-weight: 500;\">Terms:</span> %s</div>", invoice.Notes)
-	}
-
-	template := fmt.Sprintf(`
-	<!DOCTYPE html>
-	<html lang="en">
-	<head>
-		<meta charset="utf-8" />
-		<title>Invoice %s</title>
-		<meta name="viewport" content="width=device-width, initial-scale=1.0" />
-		<style>
-		:root {
-			--primary-color: #111827;
-			--text-color: #1f2937;
-			--light-gray: #f9fafb;
-			--border-color: #e5e7eb;
-		}
-
-		* {
-			margin: 0;
-			padding: 0;
-			box-sizing: border-box;
-		}
-
-		body {
-			font-family: "Inter", "Segoe UI", sans-serif;
-			color: var(--text-color);
-			line-height: 1.5;
-			background-color: white;
-			padding: 32px 20px;
-		}
-
-		.invoice-container {
-			max-width: 800px;
-			margin: 0 auto;
-			background: white;
-			padding: 32px;
-		}
-
-		.invoice-header {
-			display: flex;
-			justify-content: space-between;
-			align-items: flex-start;
-			margin-bottom: 48px;
-		}
-
-		.invoice-title {
-			font-weight: 700;
-			font-size: 36px;
-			color: #111827;
-			margin-bottom: 8px;
-		}
-
-		.invoice-id {
-			font-size: 14px;
-			color: #6b7280;
-		}
-
-		.invoice-dates {
-			text-align: right;
-			font-size: 14px;
-			color: #4b5563;
-			line-height: 1.6;
-		}
-
-		.invoice-dates > div {
-			margin-bottom: 4px;
-		}
-
-		.invoice-parties {
-			display: grid;
-			grid-template-columns: 1fr 1fr;
-			margin-bottom: 48px;
-			gap: 48px;
-		}
-
-		.invoice-parties h3 {
-			font-size: 14px;
-			font-weight: 600;
-			text-transform: uppercase;
-			letter-spacing: 0.025em;
-			color: #4b5563;
-			margin-bottom: 16px;
-		}
-
-		.party-info {
-			font-size: 14px;
-			line-height: 1.6;
-			color: #1f2937;
-		}
-
-		.invoice-table {
-			width: 100%%;
-			border-collapse: collapse;
-			margin-bottom: 32px;
-		}
-
-		.invoice-table th {
-			padding: 12px 8px;
-			text-align: left;
-			background-color: #f9fafb;
-			font-weight: 600;
-			font-size: 14px;
-			border-bottom: 2px solid #e5e7eb;
-		}
-
-		.invoice-table td {
-			padding: 16px 8px;
-			font-size: 14px;
-			color: #1f2937;
-			border-bottom: 1px solid #f3f4f6;
-		}
-
-		.invoice-table tr:last-child td {
-			border-bottom: none;
-		}
-
-		.invoice-table th:last-child,
-		.invoice-table td:last-child {
-			text-align: right;
-		}
-
-		.invoice-totals {
-			display: flex;
-			flex-direction: column;
-			align-items: flex-end;
-			margin-bottom: 32px;
-		}
-
-		.invoice-subtotal,
-		.invoice-tax {
-			display: flex;
-			justify-content: space-between;
-			width: 320px;
-			padding: 8px 0;
-			font-size: 14px;
-		}
-
-		.invoice-subtotal span:first-child,
-		.invoice-tax span:first-child {
-			color: #4b5563;
-		}
-
-		.invoice-subtotal span:last-child,
-		.invoice-tax span:last-child {
-			color: #1f2937;
-		}
-
-		.invoice-total {
-			display: flex;
-			justify-content: space-between;
-			width: 320px;
-			padding: 12px 0;
-			border-top: 1px solid #d1d5db;
-		}
-
-		.invoice-total-label {
-			font-size: 16px;
-			font-weight: 600;
-			color: #111827;
-		}
-
-		.invoice-total-amount {
-			font-size: 16px;
-			font-weight: 700;
-			color: #111827;
-		}
-
-		.invoice-notes {
-			margin-bottom: 32px;
-			font-size: 14px;
-			color: #374151;
-			line-height: 1.6;
-		}
-
-		.invoice-notes > div {
-			margin-bottom: 16px;
-		}
+func (u *UseCase) GeneratePDFPublic(ctx context.Context, invoice *entity.Invoice) ([]byte, error) {
+	for i, it := range invoice.Items {
+		total := float64(it.Quantity) * it.UnitPrice
+		invoice.Items[i].Total = total
+		invoice.Subtotal += total
+	}
 
-		.bank-details {
-			padding: 24px;
-			background-color: #f3f4f6;
-			border-radius: 4px;
-			font-size: 14px;
-		}
+	invoice.Tax = invoice.Subtotal * invoice.TaxRate
+	invoice.Total = invoice.Subtotal + invoice.Tax + invoice.DeliveryFee
 
-		.bank-details h4 {
-			font-size: 14px;
-			font-weight: 600;
-			text-transform: uppercase;
-			letter-spacing: 0.025em;
-			color: #4b5563;
-			margin-bottom: 16px;
+	if invoice.DueDate.IsZero() {
+		if invoice.PaymentTerms == "" {
+			return nil, errors.New("due date is required")
 		}
 
-		.bank-details-grid {
-			color: #374151;
-		}
+		invoice.DueDate = invoice.PaymentTerms.DueDate(invoice.IssueDate, invoice.PaymentTermsDays)
+	}
 
-		.bank-details-grid > div {
-			margin-bottom: 8px;
+	if invoice.User.QRISPayload != "" {
+		if _, err := qris.Validate(invoice.User.QRISPayload); err != nil {
+			return nil, err
 		}
+	}
 
-		.bank-details-label {
-			font-weight: 500;
-			display: inline-block;
-			min-width: 150px;
-		}
+	doc, err := u.newDocument(*invoice, invoice.User, invoice.Client, entity.DefaultInvoiceBranding())
+	if err != nil {
+		return nil, err
+	}
 
-		@media (max-width: 768px) {
-			.invoice-header,
-			.invoice-parties {
-			flex-direction: column;
-			}
-
-			.invoice-dates,
-			.invoice-parties div:last-child {
-			margin-top: 20px;
-			text-align: left;
-			}
-		}
-		</style>
-	</head>
-	<body>
-		<div class="invoice-container">
-		<div class="invoice-header">
-			<div>
-			<div class="invoice-title">INVOICE</div>
-			<div class="invoice-id">%s</div>
-			</div>
-			<div class="invoice-dates">
-			<div>Issue Date: %s</div>
-			<div>Due Date: %s</div>
-			</div>
-		</div>
-
-		<div class="invoice-parties">
-			<div>
-				<h3>From</h3>
-				<div class="party-info">
-					%s<br />
-					%s <br />
-					%s<br />
-					%s
-				</div>
-				</div>
-				<div>
-				<h3>To</h3>
-				<div class="party-info">
-					%s <br />
-					%s<br />
-					%s<br />
-					%s
-				</div>
-			</div>
-		</div>
-
-		<table class="invoice-table">
-			<thead>
-			<tr>
-				<th>Description</th>
-				<th>Quantity</th>
-				<th>Unit Price</th>
-				<th>Total</th>
-			</tr>
-			</thead>
-			<tbody>
-			%s
-			</tbody>
-		</table>
-
-		<div class="invoice-totals">
-			<div class="invoice-subtotal">
-				<span>Subtotal:</span>
-				<span>IDR %s</span>
-			</div>
-			<div class="invoice-tax">
-				<span>Tax (%s%%):</span>
-				<span>IDR %s</span>	
-			</div>
-			%s
-			<div class="invoice-total">
-				<span class="invoice-total-label">Total:</span>
-				<span class="invoice-total-amount"
-					>IDR %s</span
-				>
-			</div>
-		</div>
-
-		<div class="invoice-notes">
-			%s
-			<div><strong>Thank you</strong> for your business!</div>
-		</div>
-
-		<div class="bank-details">
-			<h4>Bank Account Details</h4>
-			<div class="bank-details-grid">
-				<div>
-					<span class="bank-details-label">Bank Name:</span>
-					<span>%s</span>
-				</div>
-				<div>
-					<span class="bank-details-label">Account Name:</span>
-					<span>%s</span>
-				</div>
-				<div>
-					<span class="bank-details-label">Account Number:</span>
-					<span>%s</span>
-				</div>
-			</div>
-		</div>
-		</div>
-	</body>
-	</html>
-	`, invoice.InvoiceNumber,
-		invoice.InvoiceNumber,
-		invoice.DueDate.Format("02 Jan 2006"),
-		invoice.IssueDate.Format("02 Jan 2006"),
-		user.Name,
-		user.Address,
-		user.Email,
-		user.Phone,
-		client.Name,
-		client.Address,
-		client.Email,
-		client.Phone,
-		items,
-		p.Sprintf("%.2f", invoice.Subtotal),
-		p.Sprintf("%.2f", invoice.TaxRate),
-		p.Sprintf("%.2f", invoice.Tax),
-		deliveryRate,
-		p.Sprintf("%.2f", invoice.Total),
-		terms,
-		user.BankName,
-		user.BankAccountName,
-		user.BankAccountNumber,
-	)
-
-	return template
+	return u.Renderer.Render(ctx, doc)
 }
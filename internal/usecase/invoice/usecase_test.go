@@ -0,0 +1,130 @@
+package invoice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+// statusRepo holds a single invoice and records status updates. Other
+// repository methods are not used by the tests and panic.
+type statusRepo struct {
+	ports.InvoiceRepository
+	invoice entity.Invoice
+	updates []entity.InvoiceStatus
+}
+
+func (r *statusRepo) GetByID(id, userID uint) (*entity.Invoice, error) {
+	if id != r.invoice.ID || userID != r.invoice.UserID {
+		return nil, nil
+	}
+
+	inv := r.invoice
+	return &inv, nil
+}
+
+func (r *statusRepo) Update(update entity.Invoice) error {
+	r.invoice = update
+	return nil
+}
+
+func (r *statusRepo) UpdateStatus(id, userID uint, status entity.InvoiceStatus) error {
+	r.updates = append(r.updates, status)
+	r.invoice.Status = string(status)
+	return nil
+}
+
+// pdfCache counts invalidations of cached PDFs.
+type pdfCache struct {
+	ports.PDFJobRepository
+	ports.BlobStore
+	invalidations int
+}
+
+func (c *pdfCache) ExpireByInvoice(id, userID uint) error {
+	c.invalidations++
+	return nil
+}
+
+func (c *pdfCache) DeletePrefix(ctx context.Context, prefix string) error {
+	return nil
+}
+
+func TestUpdateStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    entity.InvoiceStatus
+		to      entity.InvoiceStatus
+		wantErr bool
+		updated bool
+	}{
+		{"draft to sent", entity.InvoiceStatusDraft, entity.InvoiceStatusSent, false, true},
+		{"sent to paid", entity.InvoiceStatusSent, entity.InvoiceStatusPaid, false, true},
+		{"sent again", entity.InvoiceStatusSent, entity.InvoiceStatusSent, false, false},
+		{"paid again", entity.InvoiceStatusPaid, entity.InvoiceStatusPaid, false, false},
+		{"paid back to draft", entity.InvoiceStatusPaid, entity.InvoiceStatusDraft, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &statusRepo{invoice: entity.Invoice{ID: 1, UserID: 7, Status: string(tt.from)}}
+			cache := &pdfCache{}
+			u := &UseCase{InvoiceRepo: repo, PDFJobRepo: cache, Blobs: cache}
+
+			err := u.UpdateStatus(1, 7, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateStatus: %v, want error %v", err, tt.wantErr)
+			}
+
+			if updated := len(repo.updates) > 0; updated != tt.updated {
+				t.Errorf("status written: %v, want %v", updated, tt.updated)
+			}
+
+			// Cached PDFs show the status, so only a change discards them.
+			if invalidated := cache.invalidations > 0; invalidated != tt.updated {
+				t.Errorf("PDFs invalidated: %v, want %v", invalidated, tt.updated)
+			}
+		})
+	}
+
+	t.Run("another user's invoice", func(t *testing.T) {
+		u := &UseCase{InvoiceRepo: &statusRepo{invoice: entity.Invoice{ID: 1, UserID: 7, Status: string(entity.InvoiceStatusSent)}}}
+		if err := u.UpdateStatus(1, 8, entity.InvoiceStatusSent); err == nil {
+			t.Error("updated an invoice of another user")
+		}
+	})
+}
+
+func TestUpdateKeepsStatus(t *testing.T) {
+	for _, status := range []entity.InvoiceStatus{entity.InvoiceStatusDraft, entity.InvoiceStatusSent, entity.InvoiceStatusOverdue, entity.InvoiceStatusPaid} {
+		t.Run(string(status), func(t *testing.T) {
+			repo := &statusRepo{invoice: entity.Invoice{ID: 1, UserID: 7, Status: string(status)}}
+			cache := &pdfCache{}
+			u := &UseCase{InvoiceRepo: repo, PDFJobRepo: cache, Blobs: cache}
+
+			err := u.Update(entity.Invoice{
+				ID:           1,
+				UserID:       7,
+				Notes:        "Edited",
+				PaymentTerms: entity.PaymentTermsDueOnReceipt,
+				IssueDate:    time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+				DueDate:      time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if repo.invoice.Notes != "Edited" || repo.invoice.Status != string(status) {
+				t.Errorf("stored notes %q and status %s, want Edited and %s", repo.invoice.Notes, repo.invoice.Status, status)
+			}
+		})
+	}
+
+	u := &UseCase{InvoiceRepo: &statusRepo{invoice: entity.Invoice{ID: 1, UserID: 7}}}
+	if err := u.Update(entity.Invoice{ID: 1, UserID: 8}); err == nil {
+		t.Error("updated an invoice of another user")
+	}
+}
@@ -0,0 +1,231 @@
+package latefee
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+type UseCase struct {
+	LateFeeRepo ports.LateFeeRepository
+	InvoiceRepo ports.InvoiceRepository
+	ClientRepo  ports.ClientRepository
+}
+
+func NewUseCase(
+	lateFeeRepo ports.LateFeeRepository,
+	invoiceRepo ports.InvoiceRepository,
+	clientRepo ports.ClientRepository,
+) ports.LateFeeUseCase {
+	return &UseCase{
+		LateFeeRepo: lateFeeRepo,
+		InvoiceRepo: invoiceRepo,
+		ClientRepo:  clientRepo,
+	}
+}
+
+func (u *UseCase) GetPolicy(userID uint, clientID *uint) (*entity.LateFeePolicy, error) {
+	if err := u.checkClient(userID, clientID); err != nil {
+		return nil, err
+	}
+
+	return u.LateFeeRepo.GetPolicy(userID, clientID)
+}
+
+func (u *UseCase) SavePolicy(policy *entity.LateFeePolicy) error {
+	if err := u.checkClient(policy.UserID, policy.ClientID); err != nil {
+		return err
+	}
+
+	if policy.Type == entity.LateFeeTypePercentage && policy.Amount > 100 {
+		return errors.New("percentage must not exceed 100")
+	}
+
+	return u.LateFeeRepo.SavePolicy(policy)
+}
+
+func (u *UseCase) DeletePolicy(userID uint, clientID *uint) error {
+	if err := u.checkClient(userID, clientID); err != nil {
+		return err
+	}
+
+	return u.LateFeeRepo.DeletePolicy(userID, clientID)
+}
+
+func (u *UseCase) ListByInvoice(invoiceID, userID uint) ([]entity.LateFee, error) {
+	invoice, err := u.InvoiceRepo.GetByID(invoiceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice == nil {
+		return nil, errors.New("invoice not found")
+	}
+
+	return u.LateFeeRepo.ListByInvoice(invoiceID)
+}
+
+// ApplyDue charges every overdue invoice the fees its policy has accrued by
+// now and returns how many fees were applied. Invoices that fail are
+// reported in the returned error without stopping the others.
+func (u *UseCase) ApplyDue(now time.Time) (int, error) {
+	invoices, err := u.InvoiceRepo.ListByStatus(entity.InvoiceStatusOverdue)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	var errs []error
+	for i := range invoices {
+		n, err := u.applyInvoice(&invoices[i], now)
+		applied += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invoice %d: %w", invoices[i].ID, err))
+		}
+	}
+
+	return applied, errors.Join(errs...)
+}
+
+func (u *UseCase) applyInvoice(inv *entity.Invoice, now time.Time) (int, error) {
+	// Fee invoices are not charged fees themselves.
+	if inv.FeeForInvoiceID != nil {
+		return 0, nil
+	}
+
+	policy, err := u.effectivePolicy(inv.UserID, inv.ClientID)
+	if err != nil || policy == nil {
+		return 0, err
+	}
+
+	charged, err := u.LateFeeRepo.ListByInvoice(inv.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Fees are always computed on the amount the invoice had before the
+	// first fee, so line item fees do not compound.
+	base := inv.Total
+	if len(charged) > 0 {
+		base = charged[0].BaseAmount
+	}
+
+	applied := 0
+	for _, fee := range policy.PendingFees(base, inv.DueDate, now, charged) {
+		fee.InvoiceID = inv.ID
+		fee.AppliedAt = now
+		if policy.Mode == entity.LateFeeModeFeeInvoice {
+			err = u.applyAsInvoice(inv, &fee, now)
+		} else {
+			err = u.LateFeeRepo.ApplyAsLineItem(&fee, feeDescription(fee, inv.InvoiceNumber))
+		}
+
+		if err != nil {
+			return applied, err
+		}
+
+		applied++
+	}
+
+	return applied, nil
+}
+
+// applyAsInvoice bills the fee on an invoice of its own, sent with the
+// payment terms of the late invoice. Terms that would make it due the day
+// it is issued give Net 14 instead, or the overdue sweep and reminders
+// would chase it at once.
+func (u *UseCase) applyAsInvoice(inv *entity.Invoice, fee *entity.LateFee, now time.Time) error {
+	number, err := u.feeInvoiceNumber(inv, fee.Period)
+	if err != nil {
+		return err
+	}
+
+	issueDate := now.UTC().Truncate(24 * time.Hour)
+	terms, termsDays := inv.PaymentTerms, inv.PaymentTermsDays
+	dueDate := terms.DueDate(issueDate, termsDays)
+	if !terms.IsValid() || !dueDate.After(issueDate) {
+		terms, termsDays = entity.PaymentTermsNet14, 0
+		dueDate = terms.DueDate(issueDate, termsDays)
+	}
+
+	feeInvoice := &entity.Invoice{
+		UserID:           inv.UserID,
+		ClientID:         inv.ClientID,
+		ClientName:       inv.ClientName,
+		ClientEmail:      inv.ClientEmail,
+		ClientAddress:    inv.ClientAddress,
+		ClientPhone:      inv.ClientPhone,
+		InvoiceNumber:    number,
+		IssueDate:        issueDate,
+		DueDate:          dueDate,
+		PaymentTerms:     terms,
+		PaymentTermsDays: termsDays,
+		Status:           string(entity.InvoiceStatusSent),
+		FeeForInvoiceID:  &inv.ID,
+		Items: []entity.InvoiceItem{{
+			Description: feeDescription(*fee, inv.InvoiceNumber),
+			Quantity:    1,
+			UnitPrice:   fee.Amount,
+		}},
+	}
+
+	return u.LateFeeRepo.ApplyAsInvoice(fee, feeInvoice)
+}
+
+func (u *UseCase) feeInvoiceNumber(inv *entity.Invoice, period int) (string, error) {
+	base := fmt.Sprintf("%s-LF%d", inv.InvoiceNumber, period)
+	candidate := base
+	for n := 2; ; n++ {
+		exists, err := u.InvoiceRepo.ExistsByNumber(inv.UserID, candidate)
+		if err != nil {
+			return "", err
+		}
+
+		if !exists {
+			return candidate, nil
+		}
+
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// effectivePolicy returns the client's policy when there is one, falling
+// back to the user's default policy.
+func (u *UseCase) effectivePolicy(userID uint, clientID *uint) (*entity.LateFeePolicy, error) {
+	if clientID != nil {
+		policy, err := u.LateFeeRepo.GetPolicy(userID, clientID)
+		if err != nil || policy != nil {
+			return policy, err
+		}
+	}
+
+	return u.LateFeeRepo.GetPolicy(userID, nil)
+}
+
+func (u *UseCase) checkClient(userID uint, clientID *uint) error {
+	if clientID == nil {
+		return nil
+	}
+
+	client, err := u.ClientRepo.GetByID(*clientID, userID)
+	if err != nil {
+		return err
+	}
+
+	if client == nil {
+		return errors.New("client not found")
+	}
+
+	return nil
+}
+
+func feeDescription(fee entity.LateFee, invoiceNumber string) string {
+	if fee.Kind == entity.LateFeeKindInterest {
+		return fmt.Sprintf("Late payment interest for invoice %s (month %d)", invoiceNumber, fee.Period)
+	}
+
+	return fmt.Sprintf("Late fee for invoice %s", invoiceNumber)
+}
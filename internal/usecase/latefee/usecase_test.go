@@ -0,0 +1,110 @@
+package latefee
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+// invoiceRepo lists a single overdue invoice.
+type invoiceRepo struct {
+	ports.InvoiceRepository
+	invoice entity.Invoice
+}
+
+func (r *invoiceRepo) ListByStatus(status entity.InvoiceStatus) ([]entity.Invoice, error) {
+	if status != entity.InvoiceStatusOverdue {
+		return nil, nil
+	}
+
+	return []entity.Invoice{r.invoice}, nil
+}
+
+func (r *invoiceRepo) ExistsByNumber(userID uint, number string) (bool, error) {
+	return false, nil
+}
+
+// lateFeeRepo has a fixed fee policy billed on fee invoices and keeps the
+// fee invoices created.
+type lateFeeRepo struct {
+	ports.LateFeeRepository
+	feeInvoices []entity.Invoice
+}
+
+func (r *lateFeeRepo) GetPolicy(userID uint, clientID *uint) (*entity.LateFeePolicy, error) {
+	if clientID != nil {
+		return nil, nil
+	}
+
+	return &entity.LateFeePolicy{
+		UserID:  userID,
+		Enabled: true,
+		Type:    entity.LateFeeTypeFixed,
+		Amount:  50000,
+		Mode:    entity.LateFeeModeFeeInvoice,
+	}, nil
+}
+
+func (r *lateFeeRepo) ListByInvoice(invoiceID uint) ([]entity.LateFee, error) {
+	return nil, nil
+}
+
+func (r *lateFeeRepo) ApplyAsInvoice(fee *entity.LateFee, feeInvoice *entity.Invoice) error {
+	r.feeInvoices = append(r.feeInvoices, *feeInvoice)
+	return nil
+}
+
+func TestFeeInvoiceDueDate(t *testing.T) {
+	now := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	issued := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		terms     entity.PaymentTerms
+		termsDays int
+		wantTerms entity.PaymentTerms
+		wantDue   time.Time
+	}{
+		{"net 30", entity.PaymentTermsNet30, 0, entity.PaymentTermsNet30, issued.AddDate(0, 0, 30)},
+		{"custom", entity.PaymentTermsCustom, 10, entity.PaymentTermsCustom, issued.AddDate(0, 0, 10)},
+		{"end of month", entity.PaymentTermsEndOfMonth, 0, entity.PaymentTermsEndOfMonth, time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{"due on receipt", entity.PaymentTermsDueOnReceipt, 0, entity.PaymentTermsNet14, issued.AddDate(0, 0, 14)},
+		{"custom zero days", entity.PaymentTermsCustom, 0, entity.PaymentTermsNet14, issued.AddDate(0, 0, 14)},
+		{"no terms", "", 0, entity.PaymentTermsNet14, issued.AddDate(0, 0, 14)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fees := &lateFeeRepo{}
+			u := &UseCase{
+				LateFeeRepo: fees,
+				InvoiceRepo: &invoiceRepo{invoice: entity.Invoice{
+					ID:               1,
+					UserID:           7,
+					InvoiceNumber:    "INV-0042",
+					DueDate:          time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+					Total:            1000000,
+					PaymentTerms:     tt.terms,
+					PaymentTermsDays: tt.termsDays,
+					Status:           string(entity.InvoiceStatusOverdue),
+				}},
+			}
+
+			if n, err := u.ApplyDue(now); err != nil || n != 1 {
+				t.Fatalf("ApplyDue = %d, %v; want 1 fee", n, err)
+			}
+
+			inv := fees.feeInvoices[0]
+			if !inv.IssueDate.Equal(issued) || !inv.DueDate.Equal(tt.wantDue) || inv.PaymentTerms != tt.wantTerms {
+				t.Errorf("fee invoice issued %s due %s on %s, want due %s on %s",
+					inv.IssueDate.Format(time.DateOnly), inv.DueDate.Format(time.DateOnly), inv.PaymentTerms,
+					tt.wantDue.Format(time.DateOnly), tt.wantTerms)
+			}
+
+			if inv.FeeForInvoiceID == nil || *inv.FeeForInvoiceID != 1 || inv.Status != string(entity.InvoiceStatusSent) {
+				t.Errorf("fee invoice %+v", inv)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+type UseCase struct {
+	PaymentRepo     ports.PaymentRepository
+	InvoiceRepo     ports.InvoiceRepository
+	Gateways        map[string]ports.PaymentGateway
+	DefaultProvider string
+	SuccessURL      string
+}
+
+func NewUseCase(
+	paymentRepo ports.PaymentRepository,
+	invoiceRepo ports.InvoiceRepository,
+	gateways []ports.PaymentGateway,
+	defaultProvider, successURL string,
+) ports.PaymentUseCase {
+	byName := make(map[string]ports.PaymentGateway, len(gateways))
+	for _, g := range gateways {
+		byName[g.Name()] = g
+	}
+
+	return &UseCase{
+		PaymentRepo:     paymentRepo,
+		InvoiceRepo:     invoiceRepo,
+		Gateways:        byName,
+		DefaultProvider: defaultProvider,
+		SuccessURL:      successURL,
+	}
+}
+
+// CreatePaymentLink opens a checkout for the outstanding amount of a sent
+// or overdue invoice at provider, or the default provider when empty.
+func (u *UseCase) CreatePaymentLink(ctx context.Context, invoiceID, userID uint, provider string) (*entity.PaymentLink, error) {
+	if provider == "" {
+		provider = u.DefaultProvider
+	}
+
+	gateway, err := u.gateway(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := u.invoice(invoiceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := entity.InvoiceStatus(invoice.Status)
+	if status != entity.InvoiceStatusSent && status != entity.InvoiceStatusOverdue {
+		return nil, fmt.Errorf("cannot take payment for a %s invoice", status)
+	}
+
+	outstanding := math.Round((invoice.Total-invoice.AmountPaid)*100) / 100
+	if outstanding <= 0 {
+		return nil, errors.New("invoice has nothing outstanding")
+	}
+
+	req := entity.PaymentLinkRequest{
+		Reference:   fmt.Sprintf("inv-%d-%d", invoice.ID, time.Now().UnixNano()),
+		Amount:      outstanding,
+		Description: "Invoice " + invoice.InvoiceNumber,
+		SuccessURL:  u.SuccessURL,
+	}
+	if invoice.ClientName != nil {
+		req.CustomerName = *invoice.ClientName
+	}
+
+	if invoice.ClientEmail != nil {
+		req.CustomerEmail = *invoice.ClientEmail
+	}
+
+	link, err := gateway.CreatePaymentLink(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment link: %w", err)
+	}
+
+	link.UserID = userID
+	link.InvoiceID = invoice.ID
+	if err := u.PaymentRepo.CreateLink(link); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+func (u *UseCase) ListPaymentLinks(invoiceID, userID uint) ([]entity.PaymentLink, error) {
+	if _, err := u.invoice(invoiceID, userID); err != nil {
+		return nil, err
+	}
+
+	return u.PaymentRepo.ListLinks(invoiceID)
+}
+
+func (u *UseCase) ListPayments(invoiceID, userID uint) ([]entity.Payment, error) {
+	if _, err := u.invoice(invoiceID, userID); err != nil {
+		return nil, err
+	}
+
+	return u.PaymentRepo.ListPayments(invoiceID)
+}
+
+// HandleWebhook verifies and applies a gateway webhook. It returns false
+// for events that were already processed, which are acknowledged without
+// being applied again. Events for unknown references are recorded and
+// otherwise ignored.
+func (u *UseCase) HandleWebhook(provider string, header http.Header, body []byte) (bool, error) {
+	gateway, err := u.gateway(provider)
+	if err != nil {
+		return false, err
+	}
+
+	event, err := gateway.ParseWebhook(header, body)
+	if err != nil {
+		return false, err
+	}
+
+	if event.EventID == "" {
+		return false, errors.New("event has no id")
+	}
+
+	link, err := u.PaymentRepo.GetLinkByReference(provider, event.Reference)
+	if err != nil {
+		return false, err
+	}
+
+	if link == nil {
+		return u.PaymentRepo.ApplyEvent(event, nil, nil)
+	}
+
+	var payment *entity.Payment
+	update := *link
+	update.Status = ""
+	switch event.Status {
+	case entity.PaymentEventPaid:
+		update.Status = entity.PaymentLinkStatusPaid
+		payment = &entity.Payment{
+			UserID:    link.UserID,
+			InvoiceID: link.InvoiceID,
+			Amount:    event.Amount,
+			Method:    entity.PaymentMethodGateway,
+			Provider:  provider,
+			Reference: event.TransactionID,
+			PaidAt:    event.PaidAt,
+		}
+		if payment.Amount <= 0 {
+			payment.Amount = link.Amount
+		}
+
+		if payment.PaidAt.IsZero() {
+			payment.PaidAt = time.Now()
+		}
+	case entity.PaymentEventExpired:
+		update.Status = entity.PaymentLinkStatusExpired
+	}
+
+	return u.PaymentRepo.ApplyEvent(event, &update, payment)
+}
+
+// SimulatePayment pays the link with reference in full through a gateway
+// that supports simulation, by feeding its simulated webhook through
+// HandleWebhook.
+func (u *UseCase) SimulatePayment(provider, reference string) (bool, error) {
+	gateway, err := u.gateway(provider)
+	if err != nil {
+		return false, err
+	}
+
+	simulator, ok := gateway.(ports.PaymentSimulator)
+	if !ok {
+		return false, fmt.Errorf("payment provider %q cannot simulate payments", provider)
+	}
+
+	link, err := u.PaymentRepo.GetLinkByReference(provider, reference)
+	if err != nil {
+		return false, err
+	}
+
+	if link == nil {
+		return false, errors.New("payment link not found")
+	}
+
+	header, body, err := simulator.SimulatePayment(reference, link.Amount)
+	if err != nil {
+		return false, err
+	}
+
+	return u.HandleWebhook(provider, header, body)
+}
+
+func (u *UseCase) gateway(provider string) (ports.PaymentGateway, error) {
+	if provider == "" {
+		return nil, errors.New("online payments are not configured")
+	}
+
+	gateway, ok := u.Gateways[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown payment provider %q", provider)
+	}
+
+	return gateway, nil
+}
+
+func (u *UseCase) invoice(id, userID uint) (*entity.Invoice, error) {
+	invoice, err := u.InvoiceRepo.GetByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice == nil {
+		return nil, errors.New("invoice not found")
+	}
+
+	return invoice, nil
+}
@@ -0,0 +1,284 @@
+package portal
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+// portalStatuses are the invoice statuses a client can see.
+var portalStatuses = []entity.InvoiceStatus{
+	entity.InvoiceStatusSent,
+	entity.InvoiceStatusOverdue,
+	entity.InvoiceStatusPaid,
+}
+
+var errInvalidMagicLink = errors.New("link is invalid or has expired")
+
+type UseCase struct {
+	PortalRepo  ports.PortalRepository
+	ClientRepo  ports.ClientRepository
+	InvoiceRepo ports.InvoiceRepository
+	AuthRepo    ports.AuthRepository
+	Mailer      ports.Mailer
+	Tokens      ports.PortalTokenService
+	LoginURL    string
+	LinkTTL     time.Duration
+	SessionTTL  time.Duration
+}
+
+func NewUseCase(
+	portalRepo ports.PortalRepository,
+	clientRepo ports.ClientRepository,
+	invoiceRepo ports.InvoiceRepository,
+	authRepo ports.AuthRepository,
+	mailer ports.Mailer,
+	tokens ports.PortalTokenService,
+	loginURL string,
+	linkTTL, sessionTTL time.Duration,
+) ports.PortalUseCase {
+	return &UseCase{
+		PortalRepo:  portalRepo,
+		ClientRepo:  clientRepo,
+		InvoiceRepo: invoiceRepo,
+		AuthRepo:    authRepo,
+		Mailer:      mailer,
+		Tokens:      tokens,
+		LoginURL:    loginURL,
+		LinkTTL:     linkTTL,
+		SessionTTL:  sessionTTL,
+	}
+}
+
+// RequestMagicLink emails a login link to every client record with the
+// given email, one per business the client works with. Unknown emails are
+// ignored so callers cannot tell which emails are registered.
+func (u *UseCase) RequestMagicLink(ctx context.Context, email string) error {
+	if u.Mailer == nil {
+		return errors.New("email delivery is not configured")
+	}
+
+	clients, err := u.ClientRepo.ListByEmail(strings.TrimSpace(email))
+	if err != nil {
+		return err
+	}
+
+	for _, client := range clients {
+		if err := u.sendMagicLink(ctx, client); err != nil {
+			log.Printf("Failed to send portal link to client %d: %v", client.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (u *UseCase) sendMagicLink(ctx context.Context, client entity.Client) error {
+	user, err := u.AuthRepo.GetUserByID(client.UserID)
+	if err != nil || user == nil || user.IsDeleted {
+		return err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	link := &entity.PortalMagicLink{
+		UserID:    client.UserID,
+		ClientID:  client.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(u.LinkTTL),
+	}
+	if err := u.PortalRepo.CreateMagicLink(link); err != nil {
+		return err
+	}
+
+	loginURL, err := url.Parse(u.LoginURL)
+	if err != nil {
+		return err
+	}
+
+	query := loginURL.Query()
+	query.Set("token", token)
+	loginURL.RawQuery = query.Encode()
+
+	return u.Mailer.Send(ctx, entity.Mail{
+		To:      []string{client.Email},
+		ReplyTo: user.Email,
+		Subject: fmt.Sprintf("Your sign-in link for %s", user.Name),
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse this link to view your invoices from %s:\n\n%s\n\nThe link works once and expires in %s. If you did not ask for it, you can ignore this email.",
+			client.Name, user.Name, loginURL.String(), u.LinkTTL,
+		),
+	})
+}
+
+// Login exchanges a magic link token for a portal session.
+func (u *UseCase) Login(token string) (*entity.PortalSession, error) {
+	link, err := u.PortalRepo.ConsumeMagicLink(hashToken(token), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if link == nil {
+		return nil, errInvalidMagicLink
+	}
+
+	client, err := u.ClientRepo.GetByID(link.ClientID, link.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		return nil, errInvalidMagicLink
+	}
+
+	access, err := u.Tokens.Generate(client.ID, client.UserID, u.SessionTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.PortalSession{
+		AccessToken: access,
+		ExpiresAt:   time.Now().Add(u.SessionTTL),
+		Client:      *client,
+	}, nil
+}
+
+func (u *UseCase) GetClient(clientID, userID uint) (*entity.Client, error) {
+	client, err := u.ClientRepo.GetByID(clientID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if client == nil {
+		return nil, errors.New("client not found")
+	}
+
+	return client, nil
+}
+
+func (u *UseCase) ListInvoices(clientID, userID uint, status string) ([]entity.Invoice, error) {
+	statuses := portalStatuses
+	if status != "" {
+		s := entity.InvoiceStatus(strings.ToUpper(status))
+		if !isPortalStatus(s) {
+			return nil, fmt.Errorf("unknown status %q", status)
+		}
+
+		statuses = []entity.InvoiceStatus{s}
+	}
+
+	return u.InvoiceRepo.ListByClient(userID, clientID, statuses)
+}
+
+func (u *UseCase) ListQuotes(clientID, userID uint) ([]entity.Invoice, error) {
+	return u.InvoiceRepo.ListByClient(userID, clientID, []entity.InvoiceStatus{entity.InvoiceStatusQuote})
+}
+
+// GetInvoice returns an invoice or quote of the client. Drafts are not
+// visible to clients.
+func (u *UseCase) GetInvoice(clientID, userID, id uint) (*entity.Invoice, error) {
+	invoice, err := u.InvoiceRepo.GetByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice == nil || invoice.ClientID == nil || *invoice.ClientID != clientID {
+		return nil, errors.New("invoice not found")
+	}
+
+	status := entity.InvoiceStatus(invoice.Status)
+	if status != entity.InvoiceStatusQuote && !isPortalStatus(status) {
+		return nil, errors.New("invoice not found")
+	}
+
+	return invoice, nil
+}
+
+// Statement lists the client's invoices issued between from and to,
+// inclusive, with what was invoiced, paid and is still outstanding.
+func (u *UseCase) Statement(clientID, userID uint, from, to time.Time) (*entity.Statement, error) {
+	if to.Before(from) {
+		return nil, errors.New("to must not be before from")
+	}
+
+	invoices, err := u.InvoiceRepo.ListByClient(userID, clientID, portalStatuses)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &entity.Statement{
+		ClientID: clientID,
+		From:     from,
+		To:       to,
+		Invoices: make([]entity.Invoice, 0, len(invoices)),
+	}
+	end := to.AddDate(0, 0, 1)
+	for _, inv := range invoices {
+		if inv.IssueDate.Before(from) || !inv.IssueDate.Before(end) {
+			continue
+		}
+
+		statement.Invoiced += inv.Total
+		if entity.InvoiceStatus(inv.Status) == entity.InvoiceStatusPaid {
+			statement.Paid += inv.Total
+		} else {
+			statement.Paid += inv.AmountPaid
+			statement.Outstanding += inv.Total - inv.AmountPaid
+		}
+
+		statement.Invoices = append(statement.Invoices, inv)
+	}
+
+	return statement, nil
+}
+
+// AcceptQuote records the client's acceptance of a quote, which turns it
+// into a draft invoice for the user to finalise and send.
+func (u *UseCase) AcceptQuote(clientID, userID, id uint) (*entity.Invoice, error) {
+	invoice, err := u.GetInvoice(clientID, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if entity.InvoiceStatus(invoice.Status) != entity.InvoiceStatusQuote {
+		return nil, errors.New("invoice is not a quote awaiting acceptance")
+	}
+
+	now := time.Now()
+	if err := u.InvoiceRepo.AcceptQuote(id, now); err != nil {
+		return nil, err
+	}
+
+	invoice.Status = string(entity.InvoiceStatusDraft)
+	invoice.QuoteAcceptedAt = &now
+	return invoice, nil
+}
+
+func isPortalStatus(status entity.InvoiceStatus) bool {
+	for _, s := range portalStatuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,319 @@
+package reconciliation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+const (
+	// minScore is the score a pair needs to be suggested. An invoice number
+	// in the reference is enough on its own; amount and client name only
+	// together.
+	minScore = 50
+	// maxSuggestions caps the suggestions made for one transaction.
+	maxSuggestions = 3
+
+	scoreInvoiceNumber = 50
+	scoreOutstanding   = 35
+	scoreTotal         = 30
+	scoreClientName    = 20
+	scoreClientWord    = 10
+)
+
+var openStatuses = []entity.InvoiceStatus{
+	entity.InvoiceStatusSent,
+	entity.InvoiceStatusOverdue,
+}
+
+type UseCase struct {
+	ReconciliationRepo ports.ReconciliationRepository
+	InvoiceRepo        ports.InvoiceRepository
+}
+
+func NewUseCase(reconciliationRepo ports.ReconciliationRepository, invoiceRepo ports.InvoiceRepository) ports.ReconciliationUseCase {
+	return &UseCase{
+		ReconciliationRepo: reconciliationRepo,
+		InvoiceRepo:        invoiceRepo,
+	}
+}
+
+// Import stores the incoming transfers of a parsed statement, skipping
+// debits and entries imported before, and suggests matches for them.
+func (u *UseCase) Import(userID uint, format string, txs []entity.BankTransaction) (*entity.BankImportReport, error) {
+	report := &entity.BankImportReport{Format: format, Total: len(txs)}
+	credits := make([]entity.BankTransaction, 0, len(txs))
+	for _, tx := range txs {
+		if tx.Amount <= 0 {
+			report.Debits++
+			continue
+		}
+
+		tx.UserID = userID
+		tx.Status = entity.BankTransactionUnmatched
+		tx.Fingerprint = fingerprint(tx)
+		credits = append(credits, tx)
+	}
+
+	imported, err := u.ReconciliationRepo.ImportTransactions(credits)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Imported = len(imported)
+	report.Duplicates = len(credits) - len(imported)
+	report.Suggestions, err = u.suggest(userID, imported)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Reconcile suggests matches for every unmatched transaction against the
+// currently open invoices and returns how many new suggestions it made.
+func (u *UseCase) Reconcile(userID uint) (int, error) {
+	txs, err := u.ReconciliationRepo.ListTransactions(userID, entity.BankTransactionUnmatched)
+	if err != nil {
+		return 0, err
+	}
+
+	return u.suggest(userID, txs)
+}
+
+func (u *UseCase) ListTransactions(userID uint, status entity.BankTransactionStatus) ([]entity.BankTransaction, error) {
+	return u.ReconciliationRepo.ListTransactions(userID, status)
+}
+
+func (u *UseCase) ListMatches(userID uint, status entity.MatchStatus) ([]entity.ReconciliationMatch, error) {
+	return u.ReconciliationRepo.ListMatches(userID, status)
+}
+
+// AcceptMatch records the transaction as a bank transfer payment of the
+// matched invoice.
+func (u *UseCase) AcceptMatch(id, userID uint) (*entity.Payment, error) {
+	match, err := u.ReconciliationRepo.GetMatch(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if match == nil {
+		return nil, errors.New("match not found")
+	}
+
+	if match.Status != entity.MatchSuggested {
+		return nil, fmt.Errorf("match is already %s", strings.ToLower(string(match.Status)))
+	}
+
+	tx, err := u.ReconciliationRepo.GetTransaction(match.TransactionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx == nil {
+		return nil, errors.New("transaction not found")
+	}
+
+	reference := tx.BankReference
+	if reference == "" {
+		reference = tx.Reference
+	}
+
+	payment := &entity.Payment{
+		UserID:    userID,
+		InvoiceID: match.InvoiceID,
+		Amount:    tx.Amount,
+		Method:    entity.PaymentMethodBankTransfer,
+		Provider:  "bank",
+		Reference: reference,
+		PaidAt:    tx.BookedAt,
+	}
+	if err := u.ReconciliationRepo.AcceptMatch(match, payment, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (u *UseCase) RejectMatch(id, userID uint) error {
+	return u.ReconciliationRepo.RejectMatch(id, userID, time.Now())
+}
+
+func (u *UseCase) suggest(userID uint, txs []entity.BankTransaction) (int, error) {
+	if len(txs) == 0 {
+		return 0, nil
+	}
+
+	invoices, err := u.InvoiceRepo.ListByStatuses(userID, openStatuses)
+	if err != nil {
+		return 0, err
+	}
+
+	var matches []entity.ReconciliationMatch
+	for _, tx := range txs {
+		var candidates []entity.ReconciliationMatch
+		for _, inv := range invoices {
+			score, reasons := scoreMatch(tx, inv)
+			if score < minScore {
+				continue
+			}
+
+			candidates = append(candidates, entity.ReconciliationMatch{
+				UserID:        userID,
+				TransactionID: tx.ID,
+				InvoiceID:     inv.ID,
+				InvoiceNumber: inv.InvoiceNumber,
+				Score:         score,
+				Reasons:       reasons,
+				Status:        entity.MatchSuggested,
+			})
+		}
+
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+		if len(candidates) > maxSuggestions {
+			candidates = candidates[:maxSuggestions]
+		}
+
+		matches = append(matches, candidates...)
+	}
+
+	if len(matches) == 0 {
+		return 0, nil
+	}
+
+	return u.ReconciliationRepo.SaveMatches(matches)
+}
+
+// scoreMatch rates how likely tx pays inv from the invoice number appearing in
+// the transfer reference, the amount and the client name, capped at 100.
+func scoreMatch(tx entity.BankTransaction, inv entity.Invoice) (int, []string) {
+	score := 0
+	var reasons []string
+
+	if containsInvoiceNumber(tx.Reference+" "+tx.Description, normalize(inv.InvoiceNumber)) {
+		score += scoreInvoiceNumber
+		reasons = append(reasons, "reference contains invoice number")
+	}
+
+	outstanding := inv.Total - inv.AmountPaid
+	switch {
+	case math.Abs(tx.Amount-outstanding) < 0.005:
+		score += scoreOutstanding
+		reasons = append(reasons, "amount equals outstanding balance")
+	case math.Abs(tx.Amount-inv.Total) < 0.005:
+		score += scoreTotal
+		reasons = append(reasons, "amount equals invoice total")
+	}
+
+	if inv.ClientName != nil {
+		payer := normalize(tx.Counterparty + " " + tx.Description)
+		name := normalize(*inv.ClientName)
+		switch {
+		case len(name) >= 3 && strings.Contains(payer, name):
+			score += scoreClientName
+			reasons = append(reasons, "client name matches")
+		case sharesWord(tx.Counterparty+" "+tx.Description, *inv.ClientName):
+			score += scoreClientWord
+			reasons = append(reasons, "client name partly matches")
+		}
+	}
+
+	return min(score, 100), reasons
+}
+
+// containsInvoiceNumber reports whether the normalized number occurs in
+// text without running on into further digits, so INV-1 does not match
+// INV-10. Whitespace in text ends a number, so INV-001 followed by an amount
+// still matches, while a number split as "INV 001" is still found.
+func containsInvoiceNumber(text, number string) bool {
+	if len(number) < 3 {
+		return false
+	}
+
+	var b strings.Builder
+	breaks := map[int]bool{}
+	for _, word := range strings.Fields(text) {
+		breaks[b.Len()] = true
+		b.WriteString(normalize(word))
+	}
+
+	norm := b.String()
+	breaks[len(norm)] = true
+	digitAt := func(i int) bool { return i >= 0 && i < len(norm) && isDigit(norm[i]) }
+	for i := 0; ; {
+		j := strings.Index(norm[i:], number)
+		if j < 0 {
+			return false
+		}
+
+		start, end := i+j, i+j+len(number)
+		startsClean := breaks[start] || !isDigit(number[0]) || !digitAt(start-1)
+		endsClean := breaks[end] || !isDigit(number[len(number)-1]) || !digitAt(end)
+		if startsClean && endsClean {
+			return true
+		}
+
+		i = start + 1
+	}
+}
+
+// sharesWord reports whether a and b share a word of four or more letters,
+// ignoring legal forms common in Indonesian company names.
+func sharesWord(a, b string) bool {
+	words := map[string]bool{}
+	for _, w := range strings.Fields(strings.ToLower(a)) {
+		words[normalize(w)] = true
+	}
+
+	for _, w := range strings.Fields(strings.ToLower(b)) {
+		w = normalize(w)
+		if len(w) >= 4 && !legalForms[w] && words[w] {
+			return true
+		}
+	}
+
+	return false
+}
+
+var legalForms = map[string]bool{"persero": true, "tbk": true, "ltd": true, "corp": true, "indonesia": true}
+
+// normalize lowercases s and drops everything but letters and digits, so
+// "INV/2024-001" and "inv2024001" compare equal.
+func normalize(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// fingerprint identifies a statement entry across imports of overlapping
+// statements.
+func fingerprint(tx entity.BankTransaction) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		tx.BookedAt.Format(time.DateOnly),
+		fmt.Sprintf("%.2f", tx.Amount),
+		tx.Currency,
+		tx.BankReference,
+		tx.Reference,
+		tx.Description,
+		tx.Counterparty,
+	}, "\x1f")))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,81 @@
+package reconciliation
+
+import (
+	"testing"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+func TestContainsInvoiceNumber(t *testing.T) {
+	tests := []struct {
+		text   string
+		number string
+		want   bool
+	}{
+		{"Pembayaran INV-001 1500000", "INV-001", true},
+		{"Pembayaran INV-001", "INV-001", true},
+		{"TRF inv/001 PT Pembeli", "INV-001", true},
+		{"Pembayaran INV 001", "INV-001", true},
+		{"INV-2024-001,INV-2024-002", "INV/2024/002", true},
+		{"Pembayaran INV-0011500000", "INV-001", false},
+		{"Pembayaran INV-0010", "INV-001", false},
+		{"Pembayaran INV-10", "INV-1", false},
+		{"Pembayaran INV-10 dan INV-1", "INV-1", true},
+		{"Invoice 2024001", "2024001", true},
+		{"Ref 12024001", "2024001", false},
+		{"Ref 1 2024001", "2024001", true},
+		{"INV-001", "I1", false},
+	}
+
+	for _, tt := range tests {
+		if got := containsInvoiceNumber(tt.text, normalize(tt.number)); got != tt.want {
+			t.Errorf("containsInvoiceNumber(%q, %q) = %v, want %v", tt.text, tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestScoreMatch(t *testing.T) {
+	client := "PT Sumber Makmur Tbk"
+	inv := entity.Invoice{InvoiceNumber: "INV-001", Total: 1500000, AmountPaid: 500000, ClientName: &client}
+
+	tests := []struct {
+		name  string
+		tx    entity.BankTransaction
+		score int
+	}{
+		{
+			"number, outstanding amount and client",
+			entity.BankTransaction{Amount: 1000000, Description: "Pembayaran INV-001 1000000", Counterparty: "PT SUMBER MAKMUR TBK"},
+			100,
+		},
+		{
+			"number and total",
+			entity.BankTransaction{Amount: 1500000, Reference: "INV001"},
+			scoreInvoiceNumber + scoreTotal,
+		},
+		{
+			"outstanding amount and a word of the client name",
+			entity.BankTransaction{Amount: 1000000, Counterparty: "SUMBER JAYA"},
+			scoreOutstanding + scoreClientWord,
+		},
+		{
+			"legal forms alone are no match",
+			entity.BankTransaction{Amount: 1, Counterparty: "PT Lain Tbk Indonesia"},
+			0,
+		},
+		{
+			"another invoice's number",
+			entity.BankTransaction{Amount: 1, Reference: "INV-0012"},
+			0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reasons := scoreMatch(tt.tx, inv)
+			if score != tt.score {
+				t.Errorf("score %d (%v), want %d", score, reasons, tt.score)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+const (
+	// maxOffsetDays bounds reminder offsets to a year either side of the due date.
+	maxOffsetDays = 365
+
+	// maxAttempts is how often a step is tried before it is given up, and
+	// retryBackoff the wait after its first failure, doubled after each one
+	// that follows.
+	maxAttempts  = 5
+	retryBackoff = time.Hour
+)
+
+type UseCase struct {
+	ReminderRepo ports.ReminderRepository
+	InvoiceRepo  ports.InvoiceRepository
+	AuthRepo     ports.AuthRepository
+	TemplateRepo ports.EmailTemplateRepository
+	PaymentRepo  ports.PaymentRepository
+	Notifier     ports.Notifier
+}
+
+func NewUseCase(
+	reminderRepo ports.ReminderRepository,
+	invoiceRepo ports.InvoiceRepository,
+	authRepo ports.AuthRepository,
+	templateRepo ports.EmailTemplateRepository,
+	paymentRepo ports.PaymentRepository,
+	notifier ports.Notifier,
+) ports.ReminderUseCase {
+	return &UseCase{
+		ReminderRepo: reminderRepo,
+		InvoiceRepo:  invoiceRepo,
+		AuthRepo:     authRepo,
+		TemplateRepo: templateRepo,
+		PaymentRepo:  paymentRepo,
+		Notifier:     notifier,
+	}
+}
+
+func (u *UseCase) ListRules(userID uint) ([]entity.ReminderRule, error) {
+	return u.ReminderRepo.ListRules(userID)
+}
+
+func (u *UseCase) ReplaceRules(userID uint, offsets []int) ([]entity.ReminderRule, error) {
+	seen := map[int]bool{}
+	unique := make([]int, 0, len(offsets))
+	for _, offset := range offsets {
+		if offset < -maxOffsetDays || offset > maxOffsetDays {
+			return nil, fmt.Errorf("offset %d must be between -%d and %d days", offset, maxOffsetDays, maxOffsetDays)
+		}
+
+		if !seen[offset] {
+			seen[offset] = true
+			unique = append(unique, offset)
+		}
+	}
+
+	if err := u.ReminderRepo.ReplaceRules(userID, unique); err != nil {
+		return nil, err
+	}
+
+	return u.ReminderRepo.ListRules(userID)
+}
+
+func (u *UseCase) ListByInvoice(invoiceID, userID uint) ([]entity.InvoiceReminder, error) {
+	invoice, err := u.InvoiceRepo.GetByID(invoiceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice == nil {
+		return nil, errors.New("invoice not found")
+	}
+
+	return u.ReminderRepo.ListByInvoice(invoiceID)
+}
+
+func (u *UseCase) SetInvoiceOptOut(invoiceID, userID uint, optOut bool) error {
+	return u.InvoiceRepo.SetRemindersDisabled(invoiceID, userID, optOut)
+}
+
+// SendDue sends the reminders that have fallen due by now on SENT and
+// OVERDUE invoices and returns how many were delivered. Only the latest due
+// step of an invoice's sequence is sent, so a schedule configured late does
+// not flood the client with every past step. Failed deliveries are recorded
+// and retried with a growing wait, up to maxAttempts times per step.
+func (u *UseCase) SendDue(ctx context.Context, now time.Time) (int, error) {
+	var invoices []entity.Invoice
+	for _, status := range []entity.InvoiceStatus{entity.InvoiceStatusSent, entity.InvoiceStatusOverdue} {
+		list, err := u.InvoiceRepo.ListByStatus(status)
+		if err != nil {
+			return 0, err
+		}
+
+		invoices = append(invoices, list...)
+	}
+
+	rules := map[uint][]int{}
+	sent := 0
+	var errs []error
+	for i := range invoices {
+		inv := &invoices[i]
+		if inv.RemindersDisabled || inv.ClientEmail == nil || strings.TrimSpace(*inv.ClientEmail) == "" {
+			continue
+		}
+
+		offsets, ok := rules[inv.UserID]
+		if !ok {
+			list, err := u.ReminderRepo.ListRules(inv.UserID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("user %d: %w", inv.UserID, err))
+				continue
+			}
+
+			for _, rule := range list {
+				offsets = append(offsets, rule.OffsetDays)
+			}
+
+			sort.Ints(offsets)
+			rules[inv.UserID] = offsets
+		}
+
+		ok, err := u.remindInvoice(ctx, inv, offsets, now)
+		if ok {
+			sent++
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invoice %d: %w", inv.ID, err))
+		}
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+func (u *UseCase) remindInvoice(ctx context.Context, inv *entity.Invoice, offsets []int, now time.Time) (bool, error) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	due := inv.DueDate.UTC().Truncate(24 * time.Hour)
+
+	step, found := 0, false
+	for _, offset := range offsets {
+		if due.AddDate(0, 0, offset).After(today) {
+			break
+		}
+
+		step, found = offset, true
+	}
+
+	if !found {
+		return false, nil
+	}
+
+	history, err := u.ReminderRepo.ListByInvoice(inv.ID)
+	if err != nil {
+		return false, err
+	}
+
+	failures := 0
+	var lastFailure time.Time
+	for _, r := range history {
+		if r.OffsetDays != step {
+			continue
+		}
+
+		if r.Error == "" {
+			return false, nil
+		}
+
+		failures++
+		if r.SentAt.After(lastFailure) {
+			lastFailure = r.SentAt
+		}
+	}
+
+	if failures >= maxAttempts {
+		return false, nil
+	}
+
+	if failures > 0 && now.Before(lastFailure.Add(retryBackoff<<(failures-1))) {
+		return false, nil
+	}
+
+	user, err := u.AuthRepo.GetUserByID(inv.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	if user == nil {
+		return false, errors.New("user not found")
+	}
+
+	tmpl, err := u.TemplateRepo.Get(inv.UserID, entity.EmailTemplateReminder)
+	if err != nil {
+		return false, err
+	}
+
+	if tmpl == nil {
+		def := entity.DefaultEmailTemplate(entity.EmailTemplateReminder)
+		tmpl = &def
+	}
+
+	links, err := u.PaymentRepo.ListLinks(inv.ID)
+	if err != nil {
+		return false, err
+	}
+
+	rendered, err := tmpl.Render(entity.NewEmailTemplateData(inv, user, links, now))
+	if err != nil {
+		return false, err
+	}
+
+	notification := entity.Notification{
+		UserID:   inv.UserID,
+		To:       *inv.ClientEmail,
+		Subject:  rendered.Subject,
+		Body:     rendered.Body,
+		HTMLBody: rendered.HTMLBody,
+	}
+
+	record := &entity.InvoiceReminder{
+		UserID:     inv.UserID,
+		InvoiceID:  inv.ID,
+		OffsetDays: step,
+		Recipient:  notification.To,
+		SentAt:     now,
+	}
+
+	sendErr := u.Notifier.Notify(ctx, notification)
+	if sendErr != nil {
+		record.Error = sendErr.Error()
+	}
+
+	if err := u.ReminderRepo.Record(record); err != nil {
+		return sendErr == nil, errors.Join(sendErr, err)
+	}
+
+	return sendErr == nil, sendErr
+}
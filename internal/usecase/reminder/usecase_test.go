@@ -0,0 +1,212 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+// invoiceRepo lists a single sent invoice.
+type invoiceRepo struct {
+	ports.InvoiceRepository
+	invoice entity.Invoice
+}
+
+func (r *invoiceRepo) ListByStatus(status entity.InvoiceStatus) ([]entity.Invoice, error) {
+	if status != entity.InvoiceStatusSent {
+		return nil, nil
+	}
+
+	return []entity.Invoice{r.invoice}, nil
+}
+
+// reminderRepo has one rule, a reminder on the due date, and keeps the
+// reminders recorded.
+type reminderRepo struct {
+	ports.ReminderRepository
+	history []entity.InvoiceReminder
+}
+
+func (r *reminderRepo) ListRules(userID uint) ([]entity.ReminderRule, error) {
+	return []entity.ReminderRule{{UserID: userID, OffsetDays: 0}}, nil
+}
+
+func (r *reminderRepo) ListByInvoice(invoiceID uint) ([]entity.InvoiceReminder, error) {
+	return r.history, nil
+}
+
+func (r *reminderRepo) Record(reminder *entity.InvoiceReminder) error {
+	r.history = append(r.history, *reminder)
+	return nil
+}
+
+type authRepo struct{ ports.AuthRepository }
+
+func (authRepo) GetUserByID(id uint) (*entity.User, error) {
+	return &entity.User{ID: id, Name: "Studio Hutamy"}, nil
+}
+
+// templateRepo has no templates, so the default one is used.
+type templateRepo struct{ ports.EmailTemplateRepository }
+
+func (templateRepo) Get(userID uint, kind entity.EmailTemplateKind) (*entity.EmailTemplate, error) {
+	return nil, nil
+}
+
+// paymentRepo holds the payment links of the invoice.
+type paymentRepo struct {
+	ports.PaymentRepository
+	links []entity.PaymentLink
+}
+
+func (r *paymentRepo) ListLinks(invoiceID uint) ([]entity.PaymentLink, error) {
+	return r.links, nil
+}
+
+// notifier counts deliveries, keeps the last one and fails them while down.
+type notifier struct {
+	down  bool
+	calls int
+	last  entity.Notification
+}
+
+func (n *notifier) Notify(ctx context.Context, msg entity.Notification) error {
+	n.calls++
+	n.last = msg
+	if n.down {
+		return errors.New("connection refused")
+	}
+
+	return nil
+}
+
+func newTestUseCase(due time.Time, links ...entity.PaymentLink) (*UseCase, *reminderRepo, *notifier) {
+	email := "ap@pembeli.co.id"
+	invoices := &invoiceRepo{invoice: entity.Invoice{ID: 1, UserID: 7, InvoiceNumber: "INV-0042", DueDate: due, ClientEmail: &email}}
+	reminders := &reminderRepo{}
+	n := &notifier{}
+	return &UseCase{
+		ReminderRepo: reminders,
+		InvoiceRepo:  invoices,
+		AuthRepo:     authRepo{},
+		TemplateRepo: templateRepo{},
+		PaymentRepo:  &paymentRepo{links: links},
+		Notifier:     n,
+	}, reminders, n
+}
+
+func TestSendDueGivesUpAfterMaxAttempts(t *testing.T) {
+	due := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	u, reminders, n := newTestUseCase(due)
+	n.down = true
+
+	// Run every 30 minutes for two days. The step is retried after waits of
+	// 1, 2, 4 and 8 hours and then given up.
+	var attempts []time.Duration
+	for now := due; now.Before(due.Add(48 * time.Hour)); now = now.Add(30 * time.Minute) {
+		before := n.calls
+		_, err := u.SendDue(context.Background(), now)
+		if n.calls > before {
+			attempts = append(attempts, now.Sub(due))
+			if err == nil {
+				t.Errorf("failed delivery at %s reported no error", now)
+			}
+		} else if err != nil {
+			t.Errorf("run at %s without a delivery: %v", now, err)
+		}
+	}
+
+	want := []time.Duration{0, time.Hour, 3 * time.Hour, 7 * time.Hour, 15 * time.Hour}
+	if len(attempts) != len(want) {
+		t.Fatalf("attempted at %v, want %v", attempts, want)
+	}
+
+	for i := range want {
+		if attempts[i] != want[i] {
+			t.Errorf("attempt %d at %s, want %s", i+1, attempts[i], want[i])
+		}
+	}
+
+	if len(reminders.history) != maxAttempts {
+		t.Errorf("%d reminders recorded, want %d", len(reminders.history), maxAttempts)
+	}
+}
+
+func TestSendDueRetriesUntilDelivered(t *testing.T) {
+	due := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	u, reminders, n := newTestUseCase(due)
+	n.down = true
+	for _, now := range []time.Time{due, due.Add(time.Hour)} {
+		if _, err := u.SendDue(context.Background(), now); err == nil {
+			t.Fatal("failed delivery reported no error")
+		}
+	}
+
+	n.down = false
+	for i, want := range []int{1, 0} {
+		sent, err := u.SendDue(context.Background(), due.Add(3*time.Hour))
+		if err != nil || sent != want {
+			t.Errorf("run %d sent %d, %v; want %d", i+1, sent, err, want)
+		}
+	}
+
+	if len(reminders.history) != 3 || reminders.history[2].Error != "" {
+		t.Errorf("got history %+v, want a delivery after two failures", reminders.history)
+	}
+}
+
+func TestSendDueIncludesPayLink(t *testing.T) {
+	due := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	expired := due.Add(-time.Hour)
+	later := due.Add(24 * time.Hour)
+	tests := []struct {
+		name  string
+		links []entity.PaymentLink
+		want  string
+	}{
+		{name: "no links"},
+		{
+			name: "newest pending link",
+			links: []entity.PaymentLink{
+				{URL: "https://pay.example.com/old", Status: entity.PaymentLinkStatusPending, CreatedAt: due.AddDate(0, 0, -20)},
+				{URL: "https://pay.example.com/new", Status: entity.PaymentLinkStatusPending, ExpiresAt: &later, CreatedAt: due.AddDate(0, 0, -2)},
+			},
+			want: "https://pay.example.com/new",
+		},
+		{
+			name: "expired and paid links skipped",
+			links: []entity.PaymentLink{
+				{URL: "https://pay.example.com/expired", Status: entity.PaymentLinkStatusPending, ExpiresAt: &expired, CreatedAt: due.AddDate(0, 0, -1)},
+				{URL: "https://pay.example.com/paid", Status: entity.PaymentLinkStatusPaid, CreatedAt: due.AddDate(0, 0, -1)},
+				{URL: "https://pay.example.com/closed", Status: entity.PaymentLinkStatusExpired, CreatedAt: due.AddDate(0, 0, -1)},
+				{URL: "https://pay.example.com/open", Status: entity.PaymentLinkStatusPending, CreatedAt: due.AddDate(0, 0, -5)},
+			},
+			want: "https://pay.example.com/open",
+		},
+		{
+			name: "only expired links",
+			links: []entity.PaymentLink{
+				{URL: "https://pay.example.com/expired", Status: entity.PaymentLinkStatusPending, ExpiresAt: &expired},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _, n := newTestUseCase(due, tt.links...)
+			if _, err := u.SendDue(context.Background(), due); err != nil {
+				t.Fatalf("SendDue: %v", err)
+			}
+
+			hasBlock := strings.Contains(n.last.Body, "You can pay online")
+			if hasBlock != (tt.want != "") || !strings.Contains(n.last.Body, tt.want) {
+				t.Errorf("body %q, want pay link %q", n.last.Body, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package taxinvoice
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+// noNPWP stands in for the NPWP of buyers without one.
+const noNPWP = "0000000000000000"
+
+// taxLine is an invoice item as a tax invoice reports it. Tax invoices are in
+// whole rupiah, with PPN rounded down.
+type taxLine struct {
+	name     string
+	price    float64
+	quantity int
+	dpp      int64 // Dasar Pengenaan Pajak, the taxable base
+	ppn      int64
+	ppnbm    int64 // PPnBM, the luxury goods tax, which invoices do not charge
+}
+
+// taxInvoice is an invoice with the buyer and amounts its tax invoice
+// reports. The delivery fee is not subject to PPN, so it is left out.
+type taxInvoice struct {
+	invoice entity.Invoice
+	buyer   entity.Client
+	lines   []taxLine
+	rate    float64 // the PPN rate, a percentage as invoices store it
+	dpp     int64
+	ppn     int64
+	ppnbm   int64
+}
+
+func newTaxInvoice(inv entity.Invoice) taxInvoice {
+	t := taxInvoice{invoice: inv, buyer: buyerOf(inv), rate: math.Round(inv.TaxRate*100) / 100}
+	rate := t.rate / 100
+	for _, it := range inv.Items {
+		dpp := int64(math.Round(it.Total))
+		line := taxLine{
+			name:     it.Description,
+			price:    it.UnitPrice,
+			quantity: it.Quantity,
+			dpp:      dpp,
+			ppn:      floorRupiah(float64(dpp) * rate),
+		}
+		t.lines = append(t.lines, line)
+		t.dpp += line.dpp
+		t.ppnbm += line.ppnbm
+	}
+
+	// The invoice's PPN is rounded down once on its total DPP rather than
+	// summed from the lines.
+	t.ppn = floorRupiah(float64(t.dpp) * rate)
+	return t
+}
+
+// buyerOf returns the invoice's client, or the details the invoice was
+// issued with when it has no client.
+func buyerOf(inv entity.Invoice) entity.Client {
+	if inv.ClientID != nil && inv.Client.ID != 0 {
+		return inv.Client
+	}
+
+	var buyer entity.Client
+	if inv.ClientName != nil {
+		buyer.Name = *inv.ClientName
+	}
+
+	if inv.ClientEmail != nil {
+		buyer.Email = *inv.ClientEmail
+	}
+
+	if inv.ClientAddress != nil {
+		buyer.Address = *inv.ClientAddress
+	}
+
+	return buyer
+}
+
+// floorRupiah rounds down to whole rupiah, allowing for float error.
+func floorRupiah(v float64) int64 {
+	return int64(math.Floor(v + 1e-6))
+}
+
+// oneLine joins a multi-line address into the single line tax invoices hold.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func rupiah(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+// eFakturCSV writes the invoices in the import CSV of the e-Faktur desktop
+// application: the three header rows, then an FK row per tax invoice
+// followed by an OF row per item.
+func eFakturCSV(invoices []taxInvoice) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	rows := [][]string{
+		{"FK", "KD_JENIS_TRANSAKSI", "FG_PENGGANTI", "NOMOR_FAKTUR", "MASA_PAJAK", "TAHUN_PAJAK", "TANGGAL_FAKTUR", "NPWP", "NAMA", "ALAMAT_LENGKAP", "JUMLAH_DPP", "JUMLAH_PPN", "JUMLAH_PPNBM", "ID_KETERANGAN_TAMBAHAN", "FG_UANG_MUKA", "UANG_MUKA_DPP", "UANG_MUKA_PPN", "UANG_MUKA_PPNBM", "REFERENSI", "KODE_DOKUMEN_PENDUKUNG"},
+		{"LT", "NPWP", "NAMA", "JALAN", "BLOK", "NOMOR", "RT", "RW", "KECAMATAN", "KELURAHAN", "KABUPATEN", "PROPINSI", "KODE_POS", "NOMOR_TELEPON"},
+		{"OF", "KODE_OBJEK", "NAMA", "HARGA_SATUAN", "JUMLAH_BARANG", "HARGA_TOTAL", "DISKON", "DPP", "PPN", "TARIF_PPNBM", "PPNBM"},
+	}
+
+	for _, t := range invoices {
+		inv := t.invoice
+		npwp := t.buyer.NPWP
+		if npwp == "" {
+			npwp = noNPWP
+		}
+
+		rows = append(rows, []string{
+			"FK",
+			"01", // delivery to a buyer who is not a VAT collector
+			"0",  // not a replacement
+			inv.TaxSerial.Digits(),
+			strconv.Itoa(int(inv.IssueDate.Month())),
+			strconv.Itoa(inv.IssueDate.Year()),
+			inv.IssueDate.Format("02/01/2006"),
+			npwp,
+			t.buyer.Name,
+			oneLine(t.buyer.Address),
+			rupiah(t.dpp),
+			rupiah(t.ppn),
+			rupiah(t.ppnbm),
+			"",
+			"0", // not a down payment
+			"0",
+			"0",
+			"0",
+			inv.InvoiceNumber,
+			"",
+		})
+		for _, l := range t.lines {
+			rows = append(rows, []string{
+				"OF",
+				"",
+				l.name,
+				strconv.FormatFloat(l.price, 'f', -1, 64),
+				strconv.Itoa(l.quantity),
+				rupiah(l.dpp),
+				"0",
+				rupiah(l.dpp),
+				rupiah(l.ppn),
+				"0",
+				rupiah(l.ppnbm),
+			})
+		}
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("e-faktur csv: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Coretax taxes most deliveries at 12% of a base of 11/12 of the DPP, an
+// effective 11%, under transaction code 04. Both rates are percentages.
+const (
+	coretaxRate          = 12
+	coretaxEffectiveRate = 11
+)
+
+// coretaxXML writes the invoices as a Coretax bulk tax invoice import.
+func coretaxXML(seller entity.User, invoices []taxInvoice) ([]byte, error) {
+	out := coretaxBulk{
+		XSI:    "http://www.w3.org/2001/XMLSchema-instance",
+		Schema: "TaxInvoice.xsd",
+		TIN:    seller.NPWP,
+	}
+	for _, t := range invoices {
+		inv := t.invoice
+		trxCode := "01"
+		if t.rate == coretaxEffectiveRate {
+			trxCode = "04"
+		}
+
+		ti := coretaxInvoice{
+			TaxInvoiceDate: inv.IssueDate.Format("2006-01-02"),
+			TaxInvoiceOpt:  "Normal",
+			TrxCode:        trxCode,
+			RefDesc:        inv.InvoiceNumber,
+			SellerIDTKU:    seller.NITKU,
+			BuyerTin:       t.buyer.NPWP,
+			BuyerDocument:  "TIN",
+			BuyerCountry:   "IDN",
+			BuyerName:      t.buyer.Name,
+			BuyerAddress:   oneLine(t.buyer.Address),
+			BuyerEmail:     t.buyer.Email,
+			BuyerIDTKU:     t.buyer.NITKU,
+		}
+		if ti.BuyerTin == "" {
+			ti.BuyerTin = noNPWP
+			ti.BuyerDocument = "Other ID"
+			ti.BuyerDocumentNumber = "-"
+			ti.BuyerIDTKU = "000000"
+		}
+
+		for _, l := range t.lines {
+			good := coretaxGood{
+				Opt:           "A", // goods
+				Code:          "000000",
+				Name:          l.name,
+				Unit:          "UM.0018", // piece
+				Price:         strconv.FormatFloat(l.price, 'f', -1, 64),
+				Qty:           strconv.Itoa(l.quantity),
+				TotalDiscount: "0",
+				TaxBase:       rupiah(l.dpp),
+				OtherTaxBase:  rupiah(l.dpp),
+				VATRate:       strconv.FormatFloat(t.rate, 'f', -1, 64),
+				VAT:           rupiah(l.ppn),
+				STLGRate:      "0",
+				STLG:          rupiah(l.ppnbm),
+			}
+			if trxCode == "04" {
+				base := int64(math.Round(float64(l.dpp) * 11 / 12))
+				good.OtherTaxBase = rupiah(base)
+				good.VATRate = strconv.Itoa(coretaxRate)
+				good.VAT = rupiah(floorRupiah(float64(base) * coretaxRate / 100))
+			}
+
+			ti.Goods = append(ti.Goods, good)
+		}
+
+		out.Invoices = append(out.Invoices, ti)
+	}
+
+	data, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("coretax xml: %w", err)
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+// The Coretax bulk import elements, in the order its schema requires.
+
+type coretaxBulk struct {
+	XMLName  xml.Name         `xml:"TaxInvoiceBulk"`
+	XSI      string           `xml:"xmlns:xsi,attr"`
+	Schema   string           `xml:"xsi:noNamespaceSchemaLocation,attr"`
+	TIN      string           `xml:"TIN"`
+	Invoices []coretaxInvoice `xml:"ListOfTaxInvoice>TaxInvoice"`
+}
+
+type coretaxInvoice struct {
+	TaxInvoiceDate      string        `xml:"TaxInvoiceDate"`
+	TaxInvoiceOpt       string        `xml:"TaxInvoiceOpt"`
+	TrxCode             string        `xml:"TrxCode"`
+	AddInfo             string        `xml:"AddInfo"`
+	CustomDoc           string        `xml:"CustomDoc"`
+	RefDesc             string        `xml:"RefDesc"`
+	FacilityStamp       string        `xml:"FacilityStamp"`
+	SellerIDTKU         string        `xml:"SellerIDTKU"`
+	BuyerTin            string        `xml:"BuyerTin"`
+	BuyerDocument       string        `xml:"BuyerDocument"`
+	BuyerCountry        string        `xml:"BuyerCountry"`
+	BuyerDocumentNumber string        `xml:"BuyerDocumentNumber"`
+	BuyerName           string        `xml:"BuyerName"`
+	BuyerAddress        string        `xml:"BuyerAdress"` // sic
+	BuyerEmail          string        `xml:"BuyerEmail"`
+	BuyerIDTKU          string        `xml:"BuyerIDTKU"`
+	Goods               []coretaxGood `xml:"ListOfGoodService>GoodService"`
+}
+
+type coretaxGood struct {
+	Opt           string `xml:"Opt"`
+	Code          string `xml:"Code"`
+	Name          string `xml:"Name"`
+	Unit          string `xml:"Unit"`
+	Price         string `xml:"Price"`
+	Qty           string `xml:"Qty"`
+	TotalDiscount string `xml:"TotalDiscount"`
+	TaxBase       string `xml:"TaxBase"`
+	OtherTaxBase  string `xml:"OtherTaxBase"`
+	VATRate       string `xml:"VATRate"`
+	VAT           string `xml:"VAT"`
+	STLGRate      string `xml:"STLGRate"`
+	STLG          string `xml:"STLG"`
+}
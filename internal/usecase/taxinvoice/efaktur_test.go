@@ -0,0 +1,163 @@
+package taxinvoice
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+)
+
+// sampleInvoice returns an invoice as stored through the API, with its tax
+// rate as a percentage and an item priced in fractions of a rupiah.
+func sampleInvoice(taxRate float64) entity.Invoice {
+	name := "PT Pembeli"
+	address := "Jl. Asia Afrika 8\nBandung"
+	return entity.Invoice{
+		InvoiceNumber: "INV-0042",
+		IssueDate:     time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		ClientName:    &name,
+		ClientAddress: &address,
+		TaxSerial:     entity.TaxSerial(100_2600000001),
+		TaxRate:       taxRate,
+		DeliveryFee:   15000,
+		Items: []entity.InvoiceItem{
+			{Description: "Website design", Quantity: 1, UnitPrice: 99999.99, Total: 99999.99},
+			{Description: "Domain", Quantity: 1, UnitPrice: 1001, Total: 1001},
+		},
+	}
+}
+
+func TestNewTaxInvoiceRounding(t *testing.T) {
+	tests := []struct {
+		name     string
+		taxRate  float64
+		dpp      int64
+		ppn      int64
+		linesPPN []int64
+	}{
+		{"11%", 11, 101001, 11110, []int64{11000, 110}},
+		{"12%", 12, 101001, 12120, []int64{12000, 120}},
+		{"untaxed", 0, 101001, 0, []int64{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTaxInvoice(sampleInvoice(tt.taxRate))
+			if ti.dpp != tt.dpp {
+				t.Errorf("dpp %d, want %d", ti.dpp, tt.dpp)
+			}
+
+			// The invoice's PPN is rounded down on the total DPP, not summed
+			// from the lines.
+			if ti.ppn != tt.ppn {
+				t.Errorf("ppn %d, want %d", ti.ppn, tt.ppn)
+			}
+
+			var linesPPN []int64
+			for _, l := range ti.lines {
+				linesPPN = append(linesPPN, l.ppn)
+			}
+
+			if !slices.Equal(linesPPN, tt.linesPPN) {
+				t.Errorf("line ppn %v, want %v", linesPPN, tt.linesPPN)
+			}
+		})
+	}
+}
+
+func TestEFakturCSV(t *testing.T) {
+	data, err := eFakturCSV([]taxInvoice{newTaxInvoice(sampleInvoice(11))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1 // the FK, LT and OF rows differ in length
+	rows, err := r.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 6 {
+		t.Fatalf("got %d rows, want 3 header rows, an FK row and 2 OF rows", len(rows))
+	}
+
+	fk := rows[3]
+	want := []string{"FK", "01", "0", "1002600000001", "3", "2026", "02/03/2026", noNPWP, "PT Pembeli", "Jl. Asia Afrika 8 Bandung", "101001", "11110", "0"}
+	if !slices.Equal(fk[:len(want)], want) {
+		t.Errorf("FK row %q, want %q", fk[:len(want)], want)
+	}
+
+	if fk[18] != "INV-0042" {
+		t.Errorf("reference %q, want INV-0042", fk[18])
+	}
+
+	of := rows[4]
+	want = []string{"OF", "", "Website design", "99999.99", "1", "100000", "0", "100000", "11000", "0", "0"}
+	if !slices.Equal(of, want) {
+		t.Errorf("OF row %q, want %q", of, want)
+	}
+}
+
+func TestCoretaxXML(t *testing.T) {
+	seller := entity.User{NPWP: "0012345678901234", NITKU: "0012345678901234000000"}
+	tests := []struct {
+		name    string
+		taxRate float64
+		trxCode string
+		goods   []coretaxGood
+	}{
+		{"11% is 12% of 11/12 of the DPP", 11, "04", []coretaxGood{
+			{TaxBase: "100000", OtherTaxBase: "91667", VATRate: "12", VAT: "11000"},
+			{TaxBase: "1001", OtherTaxBase: "918", VATRate: "12", VAT: "110"},
+		}},
+		{"12% is taxed on the DPP", 12, "01", []coretaxGood{
+			{TaxBase: "100000", OtherTaxBase: "100000", VATRate: "12", VAT: "12000"},
+			{TaxBase: "1001", OtherTaxBase: "1001", VATRate: "12", VAT: "120"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := coretaxXML(seller, []taxInvoice{newTaxInvoice(sampleInvoice(tt.taxRate))})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var bulk coretaxBulk
+			if err := xml.Unmarshal(data, &bulk); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+
+			if bulk.TIN != seller.NPWP || len(bulk.Invoices) != 1 {
+				t.Fatalf("got TIN %q and %d invoices", bulk.TIN, len(bulk.Invoices))
+			}
+
+			inv := bulk.Invoices[0]
+			if inv.TrxCode != tt.trxCode {
+				t.Errorf("TrxCode %s, want %s", inv.TrxCode, tt.trxCode)
+			}
+
+			if inv.BuyerTin != noNPWP || inv.BuyerDocument != "Other ID" {
+				t.Errorf("buyer %s %s, want the stand-in for buyers without an NPWP", inv.BuyerTin, inv.BuyerDocument)
+			}
+
+			if len(inv.Goods) != len(tt.goods) {
+				t.Fatalf("got %d goods, want %d", len(inv.Goods), len(tt.goods))
+			}
+
+			for i, want := range tt.goods {
+				got := inv.Goods[i]
+				if got.TaxBase != want.TaxBase || got.OtherTaxBase != want.OtherTaxBase || got.VATRate != want.VATRate || got.VAT != want.VAT {
+					t.Errorf("good %d: base %s, other base %s, rate %s, VAT %s; want %s, %s, %s, %s", i,
+						got.TaxBase, got.OtherTaxBase, got.VATRate, got.VAT,
+						want.TaxBase, want.OtherTaxBase, want.VATRate, want.VAT)
+				}
+			}
+		})
+	}
+}
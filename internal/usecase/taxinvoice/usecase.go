@@ -0,0 +1,156 @@
+package taxinvoice
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+type UseCase struct {
+	Repo        ports.TaxInvoiceRepository
+	InvoiceRepo ports.InvoiceRepository
+	AuthRepo    ports.AuthRepository
+}
+
+func NewUseCase(
+	repo ports.TaxInvoiceRepository,
+	invoiceRepo ports.InvoiceRepository,
+	authRepo ports.AuthRepository,
+) ports.TaxInvoiceUseCase {
+	return &UseCase{
+		Repo:        repo,
+		InvoiceRepo: invoiceRepo,
+		AuthRepo:    authRepo,
+	}
+}
+
+// AddSerialRange records serials first to last, allocated by DJP, for
+// assigning to the user's tax invoices.
+func (u *UseCase) AddSerialRange(userID uint, first, last entity.TaxSerial) (*entity.TaxSerialRange, error) {
+	if first == 0 || first > last {
+		return nil, errors.New("first serial must not be after the last")
+	}
+
+	// DJP allocates each range within a branch code and year.
+	if first.Prefix() != last.Prefix() {
+		return nil, errors.New("serials of a range must share their branch code and year")
+	}
+
+	rng := &entity.TaxSerialRange{UserID: userID, First: first, Last: last, Next: first}
+	existing, err := u.Repo.ListSerialRanges(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range existing {
+		if rng.Overlaps(e) {
+			return nil, fmt.Errorf("serials overlap the range %s to %s", e.First, e.Last)
+		}
+	}
+
+	if err := u.Repo.CreateSerialRange(rng); err != nil {
+		return nil, err
+	}
+
+	return rng, nil
+}
+
+func (u *UseCase) ListSerialRanges(userID uint) ([]entity.TaxSerialRange, error) {
+	return u.Repo.ListSerialRanges(userID)
+}
+
+func (u *UseCase) DeleteSerialRange(id, userID uint) error {
+	return u.Repo.DeleteUnusedSerialRange(id, userID)
+}
+
+// AssignSerial gives the invoice its tax invoice serial, the next one the user
+// has left. An invoice keeps the serial it was given, so assigning again
+// returns the same one.
+func (u *UseCase) AssignSerial(invoiceID, userID uint) (*entity.Invoice, error) {
+	invoice, err := u.InvoiceRepo.GetByID(invoiceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice == nil {
+		return nil, errors.New("invoice not found")
+	}
+
+	if invoice.TaxSerial != 0 {
+		return invoice, nil
+	}
+
+	switch entity.InvoiceStatus(invoice.Status) {
+	case entity.InvoiceStatusQuote, entity.InvoiceStatusDraft:
+		return nil, errors.New("only issued invoices get a tax invoice serial")
+	}
+
+	if invoice.Tax <= 0 {
+		return nil, errors.New("invoice has no PPN")
+	}
+
+	user, err := u.AuthRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.NPWP == "" {
+		return nil, errors.New("NPWP is not set up")
+	}
+
+	serial, err := u.Repo.AssignSerial(invoiceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.TaxSerial = serial
+	return invoice, nil
+}
+
+// Export returns the tax invoices of the month of period in an e-Faktur
+// import format, for reporting to DJP.
+func (u *UseCase) Export(userID uint, period time.Time, format entity.EFakturFormat) ([]byte, error) {
+	user, err := u.AuthRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.NPWP == "" {
+		return nil, errors.New("NPWP is not set up")
+	}
+
+	from := time.Date(period.Year(), period.Month(), 1, 0, 0, 0, 0, period.Location())
+	invoices, err := u.Repo.ListByIssueDate(userID, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(invoices) == 0 {
+		return nil, errors.New("no tax invoices in the period")
+	}
+
+	taxInvoices := make([]taxInvoice, len(invoices))
+	for i, inv := range invoices {
+		taxInvoices[i] = newTaxInvoice(inv)
+	}
+
+	switch format {
+	case entity.EFakturCSV:
+		return eFakturCSV(taxInvoices)
+	case entity.EFakturXML:
+		return coretaxXML(*user, taxInvoices)
+	default:
+		return nil, errors.New("invalid format")
+	}
+}
@@ -0,0 +1,41 @@
+package taxinvoice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hutamy/go-invoice-backend/internal/domain/entity"
+	"github.com/hutamy/go-invoice-backend/internal/domain/ports"
+)
+
+// missingUsers finds no users, as for a deleted or deactivated account.
+type missingUsers struct{ ports.AuthRepository }
+
+func (missingUsers) GetUserByID(id uint) (*entity.User, error) {
+	return nil, nil
+}
+
+type invoiceRepo struct {
+	ports.InvoiceRepository
+	invoice entity.Invoice
+}
+
+func (r invoiceRepo) GetByID(id, userID uint) (*entity.Invoice, error) {
+	inv := r.invoice
+	return &inv, nil
+}
+
+func TestMissingUser(t *testing.T) {
+	u := &UseCase{
+		InvoiceRepo: invoiceRepo{invoice: entity.Invoice{ID: 1, UserID: 7, Status: string(entity.InvoiceStatusSent), Tax: 11000}},
+		AuthRepo:    missingUsers{},
+	}
+
+	if _, err := u.AssignSerial(1, 7); err == nil || err.Error() != "user not found" {
+		t.Errorf("AssignSerial: %v, want user not found", err)
+	}
+
+	if _, err := u.Export(7, time.Now(), entity.EFakturCSV); err == nil || err.Error() != "user not found" {
+		t.Errorf("Export: %v, want user not found", err)
+	}
+}
@@ -22,7 +22,18 @@ func JWTMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 			return httpresp.Response(c, http.StatusUnauthorized, "invalid token", nil)
 		}
 
-		c.Set("user_id", uint(claims["user_id"].(float64)))
+		// Portal tokens carry a typ claim and user tokens none, so a portal
+		// session never passes for the business user it belongs to.
+		if _, typed := claims["typ"]; typed {
+			return httpresp.Response(c, http.StatusUnauthorized, "invalid token", nil)
+		}
+
+		userID, ok := claims["user_id"].(float64)
+		if !ok {
+			return httpresp.Response(c, http.StatusUnauthorized, "invalid token", nil)
+		}
+
+		c.Set("user_id", uint(userID))
 		return next(c)
 	}
 }
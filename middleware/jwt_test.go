@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hutamy/go-invoice-backend/config"
+	"github.com/hutamy/go-invoice-backend/internal/adapter/security"
+	"github.com/labstack/echo/v4"
+)
+
+func TestJWTMiddleware(t *testing.T) {
+	// With one secret for both, portal tokens verify as user tokens and
+	// only their typ claim tells them apart.
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("PORTAL_TOKEN_SECRET", "secret")
+	config.LoadEnv()
+
+	userToken, err := security.JWTTokenService{}.Generate(7, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	portalToken, err := security.PortalTokenService{}.Generate(3, 7, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noUser, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"user token", "Bearer " + userToken, http.StatusOK},
+		{"portal token", "Bearer " + portalToken, http.StatusUnauthorized},
+		{"no user id", "Bearer " + noUser, http.StatusUnauthorized},
+		{"no bearer", userToken, http.StatusUnauthorized},
+		{"garbage", "Bearer x.y.z", http.StatusUnauthorized},
+	}
+
+	e := echo.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/protected/me", nil)
+			req.Header.Set(echo.HeaderAuthorization, tt.header)
+			rec := httptest.NewRecorder()
+
+			var userID any
+			err := JWTMiddleware(func(c echo.Context) error {
+				userID = c.Get("user_id")
+				return c.NoContent(http.StatusOK)
+			})(e.NewContext(req, rec))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if rec.Code != tt.status {
+				t.Errorf("got status %d, want %d", rec.Code, tt.status)
+			}
+
+			if tt.status == http.StatusOK && userID != uint(7) {
+				t.Errorf("got user_id %v, want 7", userID)
+			}
+		})
+	}
+}
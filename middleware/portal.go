@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hutamy/go-invoice-backend/internal/adapter/security"
+	httpresp "github.com/hutamy/go-invoice-backend/internal/transport/http/response"
+	"github.com/labstack/echo/v4"
+)
+
+// PortalMiddleware authenticates client portal requests and sets the
+// client and the user they belong to on the context.
+func PortalMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		authHeader := c.Request().Header.Get("Authorization")
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			return httpresp.Response(c, http.StatusUnauthorized, "invalid token", nil)
+		}
+
+		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
+		clientID, userID, err := security.PortalTokenService{}.Parse(tokenStr)
+		if err != nil {
+			return httpresp.Response(c, http.StatusUnauthorized, "invalid token", nil)
+		}
+
+		c.Set("portal_client_id", clientID)
+		c.Set("portal_user_id", userID)
+		return next(c)
+	}
+}
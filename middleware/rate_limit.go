@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	httpresp "github.com/hutamy/go-invoice-backend/internal/transport/http/response"
+	"github.com/labstack/echo/v4"
+	echomw "github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
+)
+
+// NewRateLimitStore returns an in-memory store allowing each identifier
+// perHour requests an hour, all at once or spread out.
+func NewRateLimitStore(perHour int) echomw.RateLimiterStore {
+	return echomw.NewRateLimiterMemoryStoreWithConfig(echomw.RateLimiterMemoryStoreConfig{
+		Rate:      rate.Every(time.Hour / time.Duration(perHour)),
+		Burst:     perHour,
+		ExpiresIn: time.Hour,
+	})
+}
+
+// RateLimitByIP allows each client IP, as the server's IP extractor sees
+// it, perHour requests an hour and answers the rest with 429.
+func RateLimitByIP(perHour int) echo.MiddlewareFunc {
+	return echomw.RateLimiterWithConfig(echomw.RateLimiterConfig{
+		Store: NewRateLimitStore(perHour),
+		IdentifierExtractor: func(c echo.Context) (string, error) {
+			return c.RealIP(), nil
+		},
+		DenyHandler: func(c echo.Context, identifier string, err error) error {
+			return httpresp.Response(c, http.StatusTooManyRequests, "too many requests, try again later", nil)
+		},
+	})
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestRateLimitByIP(t *testing.T) {
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	e.POST("/magic-link", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, RateLimitByIP(2))
+
+	requests := 0
+	post := func(remoteAddr string) int {
+		requests++
+		req := httptest.NewRequest(http.MethodPost, "/magic-link", nil)
+		req.RemoteAddr = remoteAddr
+		// Ignored, or any client could claim a fresh address per request.
+		req.Header.Set(echo.HeaderXForwardedFor, fmt.Sprintf("192.0.2.%d", requests))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		if got := post("203.0.113.7:4000"); got != want {
+			t.Errorf("request %d: got %d, want %d", i+1, got, want)
+		}
+	}
+
+	if got := post("198.51.100.2:4000"); got != http.StatusOK {
+		t.Errorf("another IP got %d", got)
+	}
+}
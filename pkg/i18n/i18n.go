@@ -0,0 +1,262 @@
+// Package i18n formats invoices in the languages they can be issued in:
+// translated labels, dates, numbers and amounts spelled out in words.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Supported languages.
+const (
+	English    = "en"
+	Indonesian = "id"
+)
+
+var Languages = []string{English, Indonesian}
+
+// IsSupported reports whether lang is one of Languages.
+func IsSupported(lang string) bool {
+	for _, l := range Languages {
+		if l == lang {
+			return true
+		}
+	}
+
+	return false
+}
+
+var indonesianMonths = [12]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+// Locale formats text for one language.
+type Locale struct {
+	Lang string
+	// Currency is the label printed before amounts.
+	Currency string
+
+	printer *message.Printer
+}
+
+var locales = map[string]*Locale{
+	English: {
+		Lang:     English,
+		Currency: "IDR",
+		printer:  message.NewPrinter(language.English, message.Catalog(messages)),
+	},
+	Indonesian: {
+		Lang:     Indonesian,
+		Currency: "Rp",
+		printer:  message.NewPrinter(language.Indonesian, message.Catalog(messages)),
+	},
+}
+
+// For returns the locale for lang, falling back to English.
+func For(lang string) *Locale {
+	if l, ok := locales[lang]; ok {
+		return l
+	}
+
+	return locales[English]
+}
+
+// T translates the English format string key and formats it with args.
+// Keys without a translation are used as they are.
+func (l *Locale) T(key string, args ...any) string {
+	return l.printer.Sprintf(key, args...)
+}
+
+// Number formats v with two decimals and the language's separators, e.g.
+// 1,234,567.00 in English and 1.234.567,00 in Indonesian.
+func (l *Locale) Number(v float64) string {
+	return l.printer.Sprintf("%.2f", v)
+}
+
+// Money formats v as an amount with the currency label.
+func (l *Locale) Money(v float64) string {
+	return l.Currency + " " + l.Number(v)
+}
+
+// Date formats t as a day, month and year, e.g. 02 Jan 2006 in English and
+// 02 Januari 2006 in Indonesian.
+func (l *Locale) Date(t time.Time) string {
+	if l.Lang == Indonesian {
+		return fmt.Sprintf("%02d %s %d", t.Day(), indonesianMonths[t.Month()-1], t.Year())
+	}
+
+	return t.Format("02 Jan 2006")
+}
+
+// Words spells out an amount of rupiah, rounded to the sen, starting with a
+// capital letter ("terbilang").
+func (l *Locale) Words(v float64) string {
+	sen := int64(v*100 + 0.5)
+	if v < 0 {
+		sen = -int64(-v*100 + 0.5)
+	}
+
+	spell := englishWords
+	if l.Lang == Indonesian {
+		spell = indonesianWords
+	}
+
+	abs := sen
+	if abs < 0 {
+		abs = -abs
+	}
+
+	whole, cents := abs/100, abs%100
+
+	s := spell(whole) + " rupiah"
+	if cents > 0 {
+		s += " " + spell(cents) + " sen"
+	}
+
+	if sen < 0 {
+		s = l.T("minus") + " " + s
+	}
+
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+var indonesianUnits = []string{
+	"nol", "satu", "dua", "tiga", "empat", "lima", "enam", "tujuh", "delapan", "sembilan", "sepuluh", "sebelas",
+}
+
+// indonesianWords spells out n in Indonesian.
+func indonesianWords(n int64) string {
+	switch {
+	case n < 12:
+		return indonesianUnits[n]
+	case n < 20:
+		return indonesianUnits[n-10] + " belas"
+	case n < 100:
+		return join(indonesianUnits[n/10]+" puluh", n%10, indonesianWords)
+	case n < 200:
+		return join("seratus", n-100, indonesianWords)
+	case n < 1000:
+		return join(indonesianUnits[n/100]+" ratus", n%100, indonesianWords)
+	case n < 2000:
+		return join("seribu", n-1000, indonesianWords)
+	}
+
+	for _, scale := range []struct {
+		value int64
+		name  string
+	}{
+		{1_000_000_000_000, "triliun"},
+		{1_000_000_000, "miliar"},
+		{1_000_000, "juta"},
+		{1_000, "ribu"},
+	} {
+		if n >= scale.value {
+			return join(indonesianWords(n/scale.value)+" "+scale.name, n%scale.value, indonesianWords)
+		}
+	}
+
+	return ""
+}
+
+var englishUnits = []string{
+	"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+	"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+}
+
+var englishTens = []string{
+	"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety",
+}
+
+// englishWords spells out n in English.
+func englishWords(n int64) string {
+	switch {
+	case n < 20:
+		return englishUnits[n]
+	case n < 100:
+		if n%10 == 0 {
+			return englishTens[n/10]
+		}
+
+		return englishTens[n/10] + "-" + englishUnits[n%10]
+	case n < 1000:
+		return join(englishUnits[n/100]+" hundred", n%100, englishWords)
+	}
+
+	for _, scale := range []struct {
+		value int64
+		name  string
+	}{
+		{1_000_000_000_000, "trillion"},
+		{1_000_000_000, "billion"},
+		{1_000_000, "million"},
+		{1_000, "thousand"},
+	} {
+		if n >= scale.value {
+			return join(englishWords(n/scale.value)+" "+scale.name, n%scale.value, englishWords)
+		}
+	}
+
+	return ""
+}
+
+// join appends the words for rest to prefix unless rest is zero.
+func join(prefix string, rest int64, spell func(int64) string) string {
+	if rest == 0 {
+		return prefix
+	}
+
+	return prefix + " " + spell(rest)
+}
+
+// messages holds the translations of invoice text, keyed by the English
+// format string.
+var messages = func() *catalog.Builder {
+	b := catalog.NewBuilder(catalog.Fallback(language.English))
+	for key, id := range indonesian {
+		if err := b.SetString(language.Indonesian, key, id); err != nil {
+			panic(err)
+		}
+	}
+
+	return b
+}()
+
+var indonesian = map[string]string{
+	"Invoice":               "Faktur",
+	"INVOICE":               "FAKTUR",
+	"Issue Date:":           "Tanggal Terbit:",
+	"Due Date:":             "Jatuh Tempo:",
+	"Payment Terms:":        "Syarat Pembayaran:",
+	"From":                  "Dari",
+	"To":                    "Kepada",
+	"FROM":                  "DARI",
+	"TO":                    "KEPADA",
+	"Description":           "Deskripsi",
+	"Quantity":              "Jumlah",
+	"Unit Price":            "Harga Satuan",
+	"Total":                 "Total",
+	"Subtotal:":             "Subtotal:",
+	"Tax (%s%%):":           "Pajak (%s%%):",
+	"Delivery Fee:":         "Biaya Pengiriman:",
+	"Total:":                "Total:",
+	"Amount in words:":      "Terbilang:",
+	"Terms:":                "Ketentuan:",
+	"Thank you":             "Terima kasih",
+	"for your business!":    "atas kepercayaan Anda!",
+	"Bank Account Details":  "Detail Rekening Bank",
+	"BANK ACCOUNT DETAILS":  "DETAIL REKENING BANK",
+	"Bank Name:":            "Nama Bank:",
+	"Account Name:":         "Nama Rekening:",
+	"Account Number:":       "Nomor Rekening:",
+	"Scan to pay with QRIS": "Pindai untuk membayar dengan QRIS",
+	"Due on receipt":        "Jatuh tempo saat diterima",
+	"Due end of month":      "Jatuh tempo akhir bulan",
+	"Net %d":                "%d hari",
+	"minus":                 "minus",
+}
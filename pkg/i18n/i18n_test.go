@@ -0,0 +1,179 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNumber(t *testing.T) {
+	tests := []struct {
+		lang  string
+		v     float64
+		want  string
+		money string
+	}{
+		{Indonesian, 0, "0,00", "Rp 0,00"},
+		{Indonesian, 999.5, "999,50", "Rp 999,50"},
+		{Indonesian, 1000, "1.000,00", "Rp 1.000,00"},
+		{Indonesian, 1234567.89, "1.234.567,89", "Rp 1.234.567,89"},
+		{Indonesian, -1234.5, "-1.234,50", "Rp -1.234,50"},
+		{English, 0, "0.00", "IDR 0.00"},
+		{English, 1000, "1,000.00", "IDR 1,000.00"},
+		{English, 1234567.89, "1,234,567.89", "IDR 1,234,567.89"},
+		{English, -1234.5, "-1,234.50", "IDR -1,234.50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang+"/"+tt.want, func(t *testing.T) {
+			l := For(tt.lang)
+			if got := l.Number(tt.v); got != tt.want {
+				t.Errorf("Number(%v) = %q, want %q", tt.v, got, tt.want)
+			}
+
+			if got := l.Money(tt.v); got != tt.money {
+				t.Errorf("Money(%v) = %q, want %q", tt.v, got, tt.money)
+			}
+		})
+	}
+}
+
+func TestDate(t *testing.T) {
+	tests := []struct {
+		lang string
+		date time.Time
+		want string
+	}{
+		{Indonesian, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), "02 Januari 2026"},
+		{Indonesian, time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC), "17 Agustus 2026"},
+		{Indonesian, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), "31 Desember 2026"},
+		{English, time.Date(2026, 8, 17, 0, 0, 0, 0, time.UTC), "17 Aug 2026"},
+	}
+
+	for _, tt := range tests {
+		if got := For(tt.lang).Date(tt.date); got != tt.want {
+			t.Errorf("%s: Date = %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestFor(t *testing.T) {
+	if l := For("fr"); l.Lang != English {
+		t.Errorf("For(fr) = %s, want the English fallback", l.Lang)
+	}
+
+	if got := For(Indonesian).T("Net %d", 30); got != "30 hari" {
+		t.Errorf("T = %q, want 30 hari", got)
+	}
+
+	if got := For(Indonesian).T("Not translated"); got != "Not translated" {
+		t.Errorf("T = %q, want the key", got)
+	}
+}
+
+func TestIndonesianWords(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "nol"},
+		{1, "satu"},
+		{10, "sepuluh"},
+		{11, "sebelas"},
+		{12, "dua belas"},
+		{19, "sembilan belas"},
+		{20, "dua puluh"},
+		{21, "dua puluh satu"},
+		{99, "sembilan puluh sembilan"},
+		{100, "seratus"},
+		{101, "seratus satu"},
+		{111, "seratus sebelas"},
+		{200, "dua ratus"},
+		{999, "sembilan ratus sembilan puluh sembilan"},
+		{1000, "seribu"},
+		{1001, "seribu satu"},
+		{1999, "seribu sembilan ratus sembilan puluh sembilan"},
+		{2000, "dua ribu"},
+		{11000, "sebelas ribu"},
+		{100000, "seratus ribu"},
+		{101000, "seratus satu ribu"},
+		{1_000_000, "satu juta"},
+		{1_001_000, "satu juta seribu"},
+		{1_234_567, "satu juta dua ratus tiga puluh empat ribu lima ratus enam puluh tujuh"},
+		{1_000_000_000, "satu miliar"},
+		{2_500_000_000, "dua miliar lima ratus juta"},
+		{1_000_000_000_000, "satu triliun"},
+	}
+
+	for _, tt := range tests {
+		if got := indonesianWords(tt.n); got != tt.want {
+			t.Errorf("indonesianWords(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestEnglishWords(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "zero"},
+		{11, "eleven"},
+		{19, "nineteen"},
+		{20, "twenty"},
+		{21, "twenty-one"},
+		{99, "ninety-nine"},
+		{100, "one hundred"},
+		{101, "one hundred one"},
+		{999, "nine hundred ninety-nine"},
+		{1000, "one thousand"},
+		{1001, "one thousand one"},
+		{11000, "eleven thousand"},
+		{100000, "one hundred thousand"},
+		{1_000_000, "one million"},
+		{1_234_567, "one million two hundred thirty-four thousand five hundred sixty-seven"},
+		{1_000_000_000, "one billion"},
+		{1_000_000_000_000, "one trillion"},
+	}
+
+	for _, tt := range tests {
+		if got := englishWords(tt.n); got != tt.want {
+			t.Errorf("englishWords(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	tests := []struct {
+		lang string
+		v    float64
+		want string
+	}{
+		{Indonesian, 0, "Nol rupiah"},
+		{Indonesian, 11, "Sebelas rupiah"},
+		{Indonesian, 100, "Seratus rupiah"},
+		{Indonesian, 1000, "Seribu rupiah"},
+		{Indonesian, 1_000_000, "Satu juta rupiah"},
+		{Indonesian, 1_500_000.5, "Satu juta lima ratus ribu rupiah lima puluh sen"},
+		{Indonesian, 0.05, "Nol rupiah lima sen"},
+		{Indonesian, 12.994, "Dua belas rupiah sembilan puluh sembilan sen"},
+		{Indonesian, 2.999, "Tiga rupiah"},
+		{Indonesian, -250, "Minus dua ratus lima puluh rupiah"},
+		{Indonesian, -1.25, "Minus satu rupiah dua puluh lima sen"},
+		{Indonesian, -0.5, "Minus nol rupiah lima puluh sen"},
+		{English, 0, "Zero rupiah"},
+		{English, 11, "Eleven rupiah"},
+		{English, 100, "One hundred rupiah"},
+		{English, 1000, "One thousand rupiah"},
+		{English, 1_000_000, "One million rupiah"},
+		{English, 0.01, "Zero rupiah one sen"},
+		{English, 21.75, "Twenty-one rupiah seventy-five sen"},
+		{English, -1_000_000, "Minus one million rupiah"},
+		{English, -0.5, "Minus zero rupiah fifty sen"},
+	}
+
+	for _, tt := range tests {
+		if got := For(tt.lang).Words(tt.v); got != tt.want {
+			t.Errorf("%s: Words(%v) = %q, want %q", tt.lang, tt.v, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"sync"
+)
+
+// srgbProfile builds a version 2 ICC profile for sRGB, the color space of
+// archival documents' output intent, since PDF/A requires the profile to be
+// embedded.
+var srgbProfile = sync.OnceValue(func() []byte {
+	fixed := func(v float64) []byte {
+		return binary.BigEndian.AppendUint32(nil, uint32(int32(math.Round(v*65536))))
+	}
+
+	xyz := func(x, y, z float64) []byte {
+		b := append([]byte("XYZ \x00\x00\x00\x00"), fixed(x)...)
+		b = append(b, fixed(y)...)
+		return append(b, fixed(z)...)
+	}
+
+	desc := []byte("desc\x00\x00\x00\x00")
+	desc = binary.BigEndian.AppendUint32(desc, uint32(len(srgbName)+1))
+	desc = append(desc, srgbName+"\x00"...)
+	// Empty Unicode and ScriptCode descriptions.
+	desc = append(desc, make([]byte, 4+4+2+1+67)...)
+
+	// The sRGB transfer curve, sampled.
+	curve := []byte("curv\x00\x00\x00\x00")
+	curve = binary.BigEndian.AppendUint32(curve, 1024)
+	for i := range 1024 {
+		v := float64(i) / 1023
+		if v <= 0.04045 {
+			v /= 12.92
+		} else {
+			v = math.Pow((v+0.055)/1.055, 2.4)
+		}
+
+		curve = binary.BigEndian.AppendUint16(curve, uint16(math.Round(v*65535)))
+	}
+
+	// Primaries adapted to the D50 white point of the profile connection
+	// space.
+	tags := []struct {
+		sig  string
+		data []byte
+	}{
+		{"desc", desc},
+		{"cprt", []byte("text\x00\x00\x00\x00No copyright, use freely\x00")},
+		{"wtpt", xyz(0.9642, 1, 0.8249)},
+		{"rXYZ", xyz(0.4361, 0.2225, 0.0139)},
+		{"gXYZ", xyz(0.3851, 0.7169, 0.0971)},
+		{"bXYZ", xyz(0.1431, 0.0606, 0.7141)},
+		{"rTRC", curve},
+		{"gTRC", curve},
+		{"bTRC", curve},
+	}
+
+	var table, data bytes.Buffer
+	binary.Write(&table, binary.BigEndian, uint32(len(tags)))
+	start := 128 + 4 + 12*len(tags)
+	offsets := map[string]int{}
+	for _, t := range tags {
+		// Tags with the same data share it.
+		off, ok := offsets[string(t.data)]
+		if !ok {
+			off = start + data.Len()
+			offsets[string(t.data)] = off
+			data.Write(t.data)
+			for data.Len()%4 != 0 {
+				data.WriteByte(0)
+			}
+		}
+
+		table.WriteString(t.sig)
+		binary.Write(&table, binary.BigEndian, [2]uint32{uint32(off), uint32(len(t.data))})
+	}
+
+	header := make([]byte, 128)
+	binary.BigEndian.PutUint32(header[0:], uint32(start+data.Len()))
+	binary.BigEndian.PutUint32(header[8:], 0x02100000)
+	copy(header[12:], "mntrRGB XYZ ")
+	binary.BigEndian.PutUint16(header[24:], 2024)
+	binary.BigEndian.PutUint16(header[26:], 1)
+	binary.BigEndian.PutUint16(header[28:], 1)
+	copy(header[36:], "acsp")
+	copy(header[68:], xyz(0.9642, 1, 0.8249)[8:])
+
+	return append(append(header, table.Bytes()...), data.Bytes()...)
+})
+
+const srgbName = "sRGB IEC61966-2.1"
@@ -0,0 +1,608 @@
+// Package pdf writes simple PDF documents: text in the standard Helvetica
+// fonts, lines, filled rectangles and raster images. Coordinates are in points with the
+// origin at the top-left corner of the page. Documents can carry embedded files and be
+// written as PDF/A-3B for archiving.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/md5"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	// producer names the software in the document information.
+	producer = "go-invoice-backend"
+	// dateLayout formats dates in UTC as PDF date strings.
+	dateLayout = "D:20060102150405Z00'00'"
+)
+
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+var fontNames = map[Font]string{
+	Helvetica:     "Helvetica",
+	HelveticaBold: "Helvetica-Bold",
+}
+
+// Common page sizes in points.
+const (
+	LetterWidth  = 612.0
+	LetterHeight = 792.0
+	A4Width      = 595.28
+	A4Height     = 841.89
+)
+
+// Color is an RGB color.
+type Color struct {
+	R, G, B uint8
+}
+
+// Hex parses a CSS style "#rrggbb" color, returning black when malformed.
+func Hex(s string) Color {
+	var c Color
+	if _, err := fmt.Sscanf(strings.TrimPrefix(s, "#"), "%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		return Color{}
+	}
+
+	return c
+}
+
+func (c Color) String() string {
+	return fmt.Sprintf("%.3f %.3f %.3f", float64(c.R)/255, float64(c.G)/255, float64(c.B)/255)
+}
+
+// Document is a PDF being built.
+type Document struct {
+	Width, Height float64
+	Title         string
+	Author        string
+	CreatedAt     time.Time
+	// Attachments are files embedded in the document.
+	Attachments []Attachment
+	// Archival makes the document conform to PDF/A-3B when set.
+	Archival *Archival
+
+	pages  []*Page
+	images []*Image
+}
+
+// Attachment is a file embedded in a Document.
+type Attachment struct {
+	Name        string
+	Description string
+	MimeType    string
+	// Relationship tells PDF/A-3 readers how the file relates to the
+	// document: Data, Source, Alternative, Supplement or Unspecified.
+	Relationship string
+	Data         []byte
+	ModTime      time.Time
+}
+
+// Archival holds what a PDF/A-3B document needs on top of a plain one.
+type Archival struct {
+	// Fonts are embedded in place of the standard fonts, which PDF/A
+	// does not allow to be left to the viewer. Every Font needs one.
+	Fonts map[Font]*TrueTypeFont
+	// Metadata holds extra rdf:Description elements for the XMP metadata,
+	// along with the extension schemas declaring their properties.
+	Metadata string
+}
+
+func New(width, height float64) *Document {
+	return &Document{
+		Width:     width,
+		Height:    height,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Page is one page of a Document.
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+func (d *Document) AddPage() *Page {
+	p := &Page{doc: d}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Image is a raster image added to a Document. It is stored once and can
+// be drawn on any of the document's pages.
+type Image struct {
+	index         int
+	width, height int
+	rgb, alpha    []byte // alpha is nil for opaque images
+}
+
+// AddImage adds img to the document.
+func (d *Document) AddImage(img image.Image) *Image {
+	b := img.Bounds()
+	im := &Image{
+		index:  len(d.images),
+		width:  b.Dx(),
+		height: b.Dy(),
+		rgb:    make([]byte, 0, 3*b.Dx()*b.Dy()),
+	}
+
+	alpha := make([]byte, 0, b.Dx()*b.Dy())
+	opaque := true
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			// RGBA returns alpha-premultiplied values; undo that so
+			// transparent areas keep their color under the soft mask.
+			r, g, bl, a := img.At(x, y).RGBA()
+			if a > 0 && a < 0xffff {
+				r, g, bl = r*0xffff/a, g*0xffff/a, bl*0xffff/a
+			}
+
+			im.rgb = append(im.rgb, byte(r>>8), byte(g>>8), byte(bl>>8))
+			alpha = append(alpha, byte(a>>8))
+			if a != 0xffff {
+				opaque = false
+			}
+		}
+	}
+
+	if !opaque {
+		im.alpha = alpha
+	}
+
+	d.images = append(d.images, im)
+	return im
+}
+
+// Size returns the image's dimensions in pixels.
+func (im *Image) Size() (width, height int) {
+	return im.width, im.height
+}
+
+// TextWidth returns the width of s set in font at size.
+func TextWidth(font Font, size float64, s string) float64 {
+	w := 0
+	for _, c := range encode(s) {
+		w += glyphWidth(font, c)
+	}
+
+	return float64(w) * size / 1000
+}
+
+// Text draws s with its baseline at y.
+func (p *Page) Text(x, y float64, font Font, size float64, color Color, s string) {
+	fmt.Fprintf(&p.content, "BT /F%d %.2f Tf %s rg %.2f %.2f Td (%s) Tj ET\n",
+		font, size, color, x, p.doc.Height-y, escape(encode(s)))
+}
+
+// Rect fills the rectangle with its top-left corner at x, y.
+func (p *Page) Rect(x, y, w, h float64, fill Color) {
+	fmt.Fprintf(&p.content, "%s rg %.2f %.2f %.2f %.2f re f\n", fill, x, p.doc.Height-y-h, w, h)
+}
+
+// Image draws img scaled to w by h points with its top-left corner at x, y.
+func (p *Page) Image(img *Image, x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", w, h, x, p.doc.Height-y-h, img.index)
+}
+
+func (p *Page) Line(x1, y1, x2, y2, width float64, color Color) {
+	fmt.Fprintf(&p.content, "%s RG %.2f w %.2f %.2f m %.2f %.2f l S\n",
+		color, width, x1, p.doc.Height-y1, x2, p.doc.Height-y2)
+}
+
+// Bytes serializes the document.
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// WriteTo serializes the document to w.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var objs objects
+	catalog := objs.reserve()
+	pageTree := objs.reserve()
+	info := objs.add(d.info())
+
+	var fonts strings.Builder
+	for f := Helvetica; f <= HelveticaBold; f++ {
+		ref, err := d.writeFont(&objs, f)
+		if err != nil {
+			return 0, err
+		}
+
+		fmt.Fprintf(&fonts, "/F%d %d 0 R ", f, ref)
+	}
+
+	var xobjects strings.Builder
+	for i, im := range d.images {
+		rgb, err := deflate(im.rgb)
+		if err != nil {
+			return 0, err
+		}
+
+		mask := ""
+		if im.alpha != nil {
+			alpha, err := deflate(im.alpha)
+			if err != nil {
+				return 0, err
+			}
+
+			mask = fmt.Sprintf(" /SMask %d 0 R", objs.add(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8 /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+				im.width, im.height, len(alpha), alpha)))
+		}
+
+		ref := objs.add(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8%s /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+			im.width, im.height, mask, len(rgb), rgb))
+		fmt.Fprintf(&xobjects, "/Im%d %d 0 R ", i, ref)
+	}
+
+	resources := fmt.Sprintf("/Font << %s>>", fonts.String())
+	if xobjects.Len() > 0 {
+		resources += fmt.Sprintf(" /XObject << %s>>", xobjects.String())
+	}
+
+	kids := make([]string, len(d.pages))
+	for i, p := range d.pages {
+		z, err := deflate(p.content.Bytes())
+		if err != nil {
+			return 0, err
+		}
+
+		contents := objs.add(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", len(z), z))
+		kids[i] = fmt.Sprintf("%d 0 R", objs.add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Resources << %s >> /Contents %d 0 R >>",
+			pageTree, d.Width, d.Height, resources, contents)))
+	}
+
+	objs.set(pageTree, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+
+	root := fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R", pageTree)
+	if len(d.Attachments) > 0 {
+		names, files, err := d.writeAttachments(&objs)
+		if err != nil {
+			return 0, err
+		}
+
+		root += fmt.Sprintf(" /Names << /EmbeddedFiles << /Names [%s] >> >>", names)
+		if d.Archival != nil {
+			root += fmt.Sprintf(" /AF [%s]", files)
+		}
+	}
+
+	version := "1.4"
+	if d.Archival != nil {
+		version = "1.7"
+		xmp := d.xmp()
+		metadata := objs.add(fmt.Sprintf("<< /Type /Metadata /Subtype /XML /Length %d >>\nstream\n%s\nendstream", len(xmp), xmp))
+
+		icc, err := deflate(srgbProfile())
+		if err != nil {
+			return 0, err
+		}
+
+		profile := objs.add(fmt.Sprintf("<< /N 3 /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", len(icc), icc))
+		root += fmt.Sprintf(" /Metadata %d 0 R /OutputIntents [<< /Type /OutputIntent /S /GTS_PDFA1 /OutputConditionIdentifier %s /Info %s /DestOutputProfile %d 0 R >>]",
+			metadata, pdfString(srgbName), pdfString(srgbName), profile)
+	}
+
+	objs.set(catalog, root+" >>")
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "%%PDF-%s\n%%\xe2\xe3\xcf\xd3\n", version)
+	offsets := make([]int, len(objs))
+	for i, body := range objs {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+
+	// PDF/A requires the file identifier; any value unique to the file does.
+	id := md5.Sum(out.Bytes())
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R /ID [<%x> <%x>] >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, catalog, info, id, id, xref)
+
+	n, err := w.Write(out.Bytes())
+	return int64(n), err
+}
+
+// objects holds the bodies of a document's objects; object n is at n-1.
+type objects []string
+
+// reserve allocates an object to be set later, for objects that refer to
+// ones written after them.
+func (o *objects) reserve() int {
+	*o = append(*o, "")
+	return len(*o)
+}
+
+func (o *objects) set(ref int, body string) {
+	(*o)[ref-1] = body
+}
+
+func (o *objects) add(body string) int {
+	ref := o.reserve()
+	o.set(ref, body)
+	return ref
+}
+
+func (d *Document) info() string {
+	info := "<< /Producer " + pdfString(producer)
+	if d.Title != "" {
+		info += " /Title " + pdfString(d.Title)
+	}
+
+	if d.Author != "" {
+		info += " /Author " + pdfString(d.Author)
+	}
+
+	return info + fmt.Sprintf(" /CreationDate (%s) >>", d.CreatedAt.UTC().Format(dateLayout))
+}
+
+// writeFont writes font f, embedding its font program in archival
+// documents, and returns the font's object.
+func (d *Document) writeFont(objs *objects, f Font) (int, error) {
+	if d.Archival == nil {
+		return objs.add(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", fontNames[f])), nil
+	}
+
+	tt := d.Archival.Fonts[f]
+	if tt == nil {
+		return 0, fmt.Errorf("pdf: no font program for %s", fontNames[f])
+	}
+
+	program, err := tt.program()
+	if err != nil {
+		return 0, err
+	}
+
+	name := pdfName(tt.name)
+	if tt.name == "" {
+		name = pdfName(fontNames[f])
+	}
+
+	flags := 32 // nonsymbolic
+	if tt.italicAngle != 0 {
+		flags |= 64
+	}
+
+	file := objs.add(fmt.Sprintf("<< /Length %d /Length1 %d /Filter /FlateDecode >>\nstream\n%s\nendstream", len(program), len(tt.data), program))
+	descriptor := objs.add(fmt.Sprintf("<< /Type /FontDescriptor /FontName %s /Flags %d /FontBBox [%d %d %d %d] /ItalicAngle %g /Ascent %d /Descent %d /CapHeight %d /StemV %d /FontFile2 %d 0 R >>",
+		name, flags, tt.bbox[0], tt.bbox[1], tt.bbox[2], tt.bbox[3], tt.italicAngle, tt.ascent, tt.descent, tt.capHeight, tt.stemV, file))
+
+	widths := make([]string, len(tt.widths))
+	for i, w := range tt.widths {
+		widths[i] = strconv.Itoa(w)
+	}
+
+	return objs.add(fmt.Sprintf("<< /Type /Font /Subtype /TrueType /BaseFont %s /FirstChar 32 /LastChar 255 /Widths [%s] /Encoding /WinAnsiEncoding /FontDescriptor %d 0 R >>",
+		name, strings.Join(widths, " "), descriptor)), nil
+}
+
+// writeAttachments writes the embedded files and returns the entries of
+// the EmbeddedFiles name tree and the file specifications.
+func (d *Document) writeAttachments(objs *objects) (names, files string, err error) {
+	attachments := slices.Clone(d.Attachments)
+	slices.SortFunc(attachments, func(a, b Attachment) int { return strings.Compare(a.Name, b.Name) })
+
+	var n, f []string
+	for _, a := range attachments {
+		z, err := deflate(a.Data)
+		if err != nil {
+			return "", "", err
+		}
+
+		file := objs.add(fmt.Sprintf("<< /Type /EmbeddedFile /Subtype %s /Params << /ModDate (%s) /Size %d >> /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+			pdfName(a.MimeType), a.ModTime.UTC().Format(dateLayout), len(a.Data), len(z), z))
+
+		relationship := a.Relationship
+		if relationship == "" {
+			relationship = "Unspecified"
+		}
+
+		spec := objs.add(fmt.Sprintf("<< /Type /Filespec /F %s /UF %s /Desc %s /AFRelationship %s /EF << /F %d 0 R /UF %d 0 R >> >>",
+			pdfString(a.Name), pdfString(a.Name), pdfString(a.Description), pdfName(relationship), file, file))
+
+		n = append(n, fmt.Sprintf("%s %d 0 R", pdfString(a.Name), spec))
+		f = append(f, fmt.Sprintf("%d 0 R", spec))
+	}
+
+	return strings.Join(n, " "), strings.Join(f, " "), nil
+}
+
+// xmp returns the XMP metadata of an archival document, which repeats the
+// document information and declares the PDF/A conformance.
+func (d *Document) xmp() string {
+	var b strings.Builder
+	b.WriteString("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	b.WriteString(`<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="" xmlns:pdfaid="http://www.aiim.org/pdfa/ns/id/">
+<pdfaid:part>3</pdfaid:part>
+<pdfaid:conformance>B</pdfaid:conformance>
+</rdf:Description>
+<rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:format>application/pdf</dc:format>
+`)
+	if d.Title != "" {
+		fmt.Fprintf(&b, "<dc:title><rdf:Alt><rdf:li xml:lang=\"x-default\">%s</rdf:li></rdf:Alt></dc:title>\n", xmlText(d.Title))
+	}
+
+	if d.Author != "" {
+		fmt.Fprintf(&b, "<dc:creator><rdf:Seq><rdf:li>%s</rdf:li></rdf:Seq></dc:creator>\n", xmlText(d.Author))
+	}
+
+	fmt.Fprintf(&b, `</rdf:Description>
+<rdf:Description rdf:about="" xmlns:pdf="http://ns.adobe.com/pdf/1.3/">
+<pdf:Producer>%s</pdf:Producer>
+</rdf:Description>
+<rdf:Description rdf:about="" xmlns:xmp="http://ns.adobe.com/xap/1.0/">
+<xmp:CreateDate>%s</xmp:CreateDate>
+</rdf:Description>
+`, producer, d.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"))
+	b.WriteString(d.Archival.Metadata)
+	b.WriteString("</rdf:RDF>\n</x:xmpmeta>\n<?xpacket end=\"w\"?>")
+	return b.String()
+}
+
+func deflate(b []byte) ([]byte, error) {
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	if _, err := zw.Write(b); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return z.Bytes(), nil
+}
+
+// encode converts s to WinAnsiEncoding, which matches Latin-1 for the
+// characters it shares. Other characters become '?'.
+func encode(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			b = append(b, ' ')
+		case r >= 32 && r <= 126, r >= 160 && r <= 255:
+			b = append(b, byte(r))
+		case r == '€':
+			b = append(b, 0x80)
+		case r == '–':
+			b = append(b, 0x96)
+		case r == '—':
+			b = append(b, 0x97)
+		case r == '‘', r == '’':
+			b = append(b, '\'')
+		case r == '“', r == '”':
+			b = append(b, '"')
+		case r == '•':
+			b = append(b, 0x95)
+		case r < 32:
+		default:
+			b = append(b, '?')
+		}
+	}
+
+	return b
+}
+
+// pdfString returns s as a PDF text string: literal when it is ASCII and
+// UTF-16 otherwise.
+func pdfString(s string) string {
+	for _, r := range s {
+		if r < 32 || r > 126 {
+			u := utf16.Encode([]rune(s))
+			b := make([]byte, 0, 2+2*len(u))
+			b = append(b, 0xfe, 0xff)
+			for _, c := range u {
+				b = append(b, byte(c>>8), byte(c))
+			}
+
+			return fmt.Sprintf("<%x>", b)
+		}
+	}
+
+	return "(" + escape([]byte(s)) + ")"
+}
+
+// pdfName returns s as a PDF name, escaping delimiters and bytes outside
+// printable ASCII.
+func pdfName(s string) string {
+	var b strings.Builder
+	b.WriteByte('/')
+	for _, c := range []byte(s) {
+		if c < '!' || c > '~' || strings.IndexByte("#()<>[]{}/%", c) >= 0 {
+			fmt.Fprintf(&b, "#%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+func xmlText(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func escape(b []byte) string {
+	var s strings.Builder
+	for _, c := range b {
+		if c == '(' || c == ')' || c == '\\' {
+			s.WriteByte('\\')
+		}
+
+		s.WriteByte(c)
+	}
+
+	return s.String()
+}
+
+// Wrap breaks s into lines no wider than width, keeping its line breaks.
+// Words longer than width are split.
+func Wrap(font Font, size, width float64, s string) []string {
+	var lines []string
+	for _, para := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			for TextWidth(font, size, word) > width && len(word) > 1 {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+
+				n := len(word) - 1
+				for n > 1 && TextWidth(font, size, word[:n]) > width {
+					n--
+				}
+
+				lines = append(lines, word[:n])
+				word = word[n:]
+			}
+
+			switch {
+			case line == "":
+				line = word
+			case TextWidth(font, size, line+" "+word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
@@ -0,0 +1,267 @@
+package pdf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// TrueTypeFont is a TrueType font program that archival documents embed in
+// place of a standard font. Text is still laid out with the Helvetica
+// metrics, so the font should be metric compatible with Helvetica, like
+// Liberation Sans.
+type TrueTypeFont struct {
+	name        string
+	data        []byte
+	bbox        [4]int
+	ascent      int
+	descent     int
+	capHeight   int
+	italicAngle float64
+	stemV       int
+	// widths holds the advance of each WinAnsiEncoding code from 32 to
+	// 255, in thousandths of the font size.
+	widths [224]int
+
+	deflateOnce sync.Once
+	deflated    []byte
+	deflateErr  error
+}
+
+// winAnsiHigh maps the WinAnsiEncoding codes 128-159 to Unicode; zero marks
+// codes without a character. The other codes match Latin-1.
+var winAnsiHigh = [32]rune{
+	0x20ac, 0, 0x201a, 0x0192, 0x201e, 0x2026, 0x2020, 0x2021,
+	0x02c6, 0x2030, 0x0160, 0x2039, 0x0152, 0, 0x017d, 0,
+	0, 0x2018, 0x2019, 0x201c, 0x201d, 0x2022, 0x2013, 0x2014,
+	0x02dc, 0x2122, 0x0161, 0x203a, 0x0153, 0, 0x017e, 0x0178,
+}
+
+// ParseTrueType reads the metrics of a TrueType font program.
+func ParseTrueType(data []byte) (*TrueTypeFont, error) {
+	r := ttfReader{data: data}
+	tables := map[string][]byte{}
+	for i := range int(r.u16(4)) {
+		rec := 12 + 16*i
+		tag := string(r.bytes(rec, 4))
+		off, length := r.u32(rec+8), r.u32(rec+12)
+		tables[tag] = r.bytes(off, length)
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	for _, tag := range []string{"head", "hhea", "hmtx", "cmap", "post"} {
+		if tables[tag] == nil {
+			return nil, fmt.Errorf("truetype: missing %s table", tag)
+		}
+	}
+
+	head := ttfReader{data: tables["head"]}
+	unitsPerEm := int(head.u16(18))
+	if unitsPerEm == 0 {
+		return nil, errors.New("truetype: zero units per em")
+	}
+
+	scale := func(v int) int {
+		if v < 0 {
+			return -((-v*1000 + unitsPerEm/2) / unitsPerEm)
+		}
+
+		return (v*1000 + unitsPerEm/2) / unitsPerEm
+	}
+
+	f := &TrueTypeFont{data: data, stemV: 80}
+	for i := range f.bbox {
+		f.bbox[i] = scale(int(head.i16(36 + 2*i)))
+	}
+
+	hhea := ttfReader{data: tables["hhea"]}
+	f.ascent = scale(int(hhea.i16(4)))
+	f.descent = scale(int(hhea.i16(6)))
+	f.capHeight = f.ascent
+	metrics := int(hhea.u16(34))
+
+	if t := tables["OS/2"]; t != nil {
+		os2 := ttfReader{data: t}
+		// PDF has no weight; the stem width is only a hint for substitution.
+		if os2.u16(4) >= 600 {
+			f.stemV = 140
+		}
+
+		if os2.u16(0) >= 2 {
+			f.capHeight = scale(int(os2.i16(88)))
+		}
+	}
+
+	post := ttfReader{data: tables["post"]}
+	f.italicAngle = float64(int32(post.u32(4))) / 65536
+
+	f.name = fontName(tables["name"])
+
+	glyphs, err := unicodeGlyphs(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+
+	hmtx := ttfReader{data: tables["hmtx"]}
+	for i := range f.widths {
+		c := rune(32 + i)
+		if c >= 128 && c < 160 {
+			c = winAnsiHigh[c-128]
+		}
+
+		g := glyphs(c)
+		f.widths[i] = scale(int(hmtx.u16(4 * min(g, metrics-1))))
+	}
+
+	for _, r := range []ttfReader{head, hhea, hmtx, post} {
+		if r.err != nil {
+			return nil, r.err
+		}
+	}
+
+	return f, nil
+}
+
+// program returns the compressed font program.
+func (f *TrueTypeFont) program() ([]byte, error) {
+	f.deflateOnce.Do(func() {
+		f.deflated, f.deflateErr = deflate(f.data)
+	})
+
+	return f.deflated, f.deflateErr
+}
+
+// fontName returns the PostScript name from the name table.
+func fontName(table []byte) string {
+	r := ttfReader{data: table}
+	storage := int(r.u16(4))
+	for i := range int(r.u16(2)) {
+		rec := 6 + 12*i
+		if r.u16(rec+6) != 6 {
+			continue
+		}
+
+		platform, length, off := r.u16(rec), int(r.u16(rec+8)), storage+int(r.u16(rec+10))
+		name := r.bytes(off, length)
+		if r.err != nil {
+			return ""
+		}
+
+		// Windows names are UTF-16; PostScript names are ASCII.
+		if platform == 3 {
+			ascii := make([]byte, 0, len(name)/2)
+			for j := 1; j < len(name); j += 2 {
+				ascii = append(ascii, name[j])
+			}
+
+			name = ascii
+		}
+
+		return string(name)
+	}
+
+	return ""
+}
+
+// unicodeGlyphs returns a lookup of glyph ids from the font's Windows
+// Unicode (3, 1) format 4 cmap subtable.
+func unicodeGlyphs(table []byte) (func(rune) int, error) {
+	r := ttfReader{data: table}
+	var sub ttfReader
+	for i := range int(r.u16(2)) {
+		rec := 4 + 8*i
+		if r.u16(rec) == 3 && r.u16(rec+2) == 1 {
+			off := r.u32(rec + 4)
+			if off < len(table) {
+				sub = ttfReader{data: table[off:]}
+			}
+		}
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if sub.data == nil || sub.u16(0) != 4 {
+		return nil, errors.New("truetype: no format 4 Unicode cmap")
+	}
+
+	segs := int(sub.u16(6)) / 2
+	ends, starts := 14, 16+2*segs
+	deltas, ranges := starts+2*segs, starts+4*segs
+	return func(c rune) int {
+		if c > 0xffff {
+			return 0
+		}
+
+		for i := range segs {
+			end := rune(sub.u16(ends + 2*i))
+			if c > end {
+				continue
+			}
+
+			start := rune(sub.u16(starts + 2*i))
+			if c < start {
+				return 0
+			}
+
+			delta := int(sub.u16(deltas + 2*i))
+			rangeOff := int(sub.u16(ranges + 2*i))
+			if rangeOff == 0 {
+				return (int(c) + delta) & 0xffff
+			}
+
+			g := int(sub.u16(ranges + 2*i + rangeOff + 2*int(c-start)))
+			if g == 0 {
+				return 0
+			}
+
+			return (g + delta) & 0xffff
+		}
+
+		return 0
+	}, sub.err
+}
+
+// ttfReader reads big-endian values, remembering the first out of range
+// read and returning zeros after it.
+type ttfReader struct {
+	data []byte
+	err  error
+}
+
+func (r *ttfReader) bytes(off, n int) []byte {
+	if r.err != nil || off < 0 || n < 0 || off+n > len(r.data) {
+		if r.err == nil {
+			r.err = errors.New("truetype: malformed font")
+		}
+
+		return nil
+	}
+
+	return r.data[off : off+n]
+}
+
+func (r *ttfReader) u16(off int) uint16 {
+	if b := r.bytes(off, 2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+
+	return 0
+}
+
+func (r *ttfReader) i16(off int) int16 {
+	return int16(r.u16(off))
+}
+
+func (r *ttfReader) u32(off int) int {
+	if b := r.bytes(off, 4); b != nil {
+		return int(binary.BigEndian.Uint32(b))
+	}
+
+	return 0
+}
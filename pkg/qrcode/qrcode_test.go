@@ -0,0 +1,254 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	zxqrcode "github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// formatM is the format information for level M and masks 0 to 7, from
+// table C.1 of ISO/IEC 18004.
+var formatM = [8]int{0x5412, 0x5125, 0x5e7c, 0x5b4b, 0x45f9, 0x40ce, 0x4f97, 0x4aa0}
+
+func TestEncodeDecodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		version int
+	}{
+		{name: "single byte", data: []byte("1"), version: 1},
+		{name: "version 1 full", data: []byte("INV-2024-00001"), version: 1},
+		{name: "version 2", data: []byte("INV-2024-000012"), version: 2},
+		{name: "url", data: []byte("https://invoice.example.com/p/9f86d081884c7d659a2feaa0c55ad015"), version: 4},
+		{
+			name: "qris payload",
+			data: []byte("00020101021226570011ID.DANA.WWW011893600915302259148102090225914810303UMI" +
+				"51440014ID.CO.QRIS.WWW0215ID10200176114730303UMI520458125303360540615000058" +
+				"02ID5910Toko Sinar6013Jakarta Barat6105114706304512D"),
+			version: 10,
+		},
+		{name: "utf-8", data: []byte("Pembayaran faktur — Rp150.000 ✓"), version: 3},
+		{name: "binary", data: []byte{0x00, 0xff, 0x80, 0x7f, 0x0a, 0x0d}, version: 1},
+		{name: "version 10 full", data: bytes.Repeat([]byte{'7'}, 213), version: 10},
+		{name: "version 11", data: bytes.Repeat([]byte{'7'}, 214), version: 11},
+		{name: "version 40", data: bytes.Repeat([]byte{'x'}, 2331), version: 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Encode(tt.data)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+
+			if c.Version != tt.version || c.Size != tt.version*4+17 {
+				t.Errorf("version %d size %d, want version %d", c.Version, c.Size, tt.version)
+			}
+
+			got := decode(t, c)
+			if !bytes.Equal(got, tt.data) {
+				t.Errorf("decoded %q, want %q", got, tt.data)
+			}
+		})
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	if _, err := Encode(bytes.Repeat([]byte{'x'}, 2332)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("err = %v, want %v", err, ErrTooLong)
+	}
+}
+
+func TestEncodeFunctionPatterns(t *testing.T) {
+	for _, version := range []int{1, 2, 7, 8, 40} {
+		t.Run(fmt.Sprint(version), func(t *testing.T) {
+			data := bytes.Repeat([]byte{'a'}, numDataCodewords(version)-3)
+			c, err := Encode(data)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+
+			if c.Version != version {
+				t.Fatalf("version = %d, want %d", c.Version, version)
+			}
+
+			n := c.Size
+			for _, corner := range [][2]int{{0, 0}, {n - 7, 0}, {0, n - 7}} {
+				if got := finderAt(c, corner[0], corner[1]); got != finder {
+					t.Errorf("finder at %v =\n%s", corner, got)
+				}
+			}
+
+			for i := 8; i < n-8; i++ {
+				if c.Modules[6][i] != (i%2 == 0) || c.Modules[i][6] != (i%2 == 0) {
+					t.Errorf("timing pattern broken at %d", i)
+				}
+			}
+
+			if !c.Modules[n-8][8] {
+				t.Error("dark module is light")
+			}
+
+			first, second := 0, 0
+			for i := 0; i < 15; i++ {
+				first |= bit(c, firstFormat(i)) << i
+				second |= bit(c, secondFormat(n, i)) << i
+			}
+
+			if first != second {
+				t.Errorf("format copies differ: %015b and %015b", first, second)
+			}
+
+			if !contains(formatM[:], first) {
+				t.Errorf("format bits %015b are not level M", first)
+			}
+
+			if version >= 7 {
+				want := map[int]int{7: 0x07c94, 8: 0x085bc, 40: 0x28c69}[version]
+				top, left := 0, 0
+				for i := 0; i < 18; i++ {
+					top |= bit(c, [2]int{n - 11 + i%3, i / 3}) << i
+					left |= bit(c, [2]int{i / 3, n - 11 + i%3}) << i
+				}
+
+				if top != want || left != want {
+					t.Errorf("version bits %018b and %018b, want %018b", top, left, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatBits(t *testing.T) {
+	for mask, want := range formatM {
+		if got := formatBits(mask); got != want {
+			t.Errorf("formatBits(%d) = %015b, want %015b", mask, got, want)
+		}
+	}
+}
+
+func TestPNG(t *testing.T) {
+	out, err := PNG([]byte("INV-2024-00001"), 4)
+	if err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("png.Decode: %v", err)
+	}
+
+	// Version 1 is 21 modules wide, plus a quiet zone of 4 on each side.
+	if side := (21 + 2*quietZone) * 4; img.Bounds().Dx() != side || img.Bounds().Dy() != side {
+		t.Errorf("image is %v, want %dx%d", img.Bounds(), side, side)
+	}
+}
+
+const finder = "#######\n" +
+	"#.....#\n" +
+	"#.###.#\n" +
+	"#.###.#\n" +
+	"#.###.#\n" +
+	"#.....#\n" +
+	"#######\n"
+
+func finderAt(c *Code, x0, y0 int) string {
+	var b strings.Builder
+	for y := y0; y < y0+7; y++ {
+		for x := x0; x < x0+7; x++ {
+			if c.Modules[y][x] {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+// firstFormat and secondFormat give the module, as x and y, holding bit i
+// of the format information in the copy around the top-left finder and in
+// the copy split between the other two.
+func firstFormat(i int) [2]int {
+	switch {
+	case i <= 5:
+		return [2]int{8, i}
+	case i == 6:
+		return [2]int{8, 7}
+	case i == 7:
+		return [2]int{8, 8}
+	case i == 8:
+		return [2]int{7, 8}
+	default:
+		return [2]int{14 - i, 8}
+	}
+}
+
+func secondFormat(n, i int) [2]int {
+	if i < 8 {
+		return [2]int{n - 1 - i, 8}
+	}
+
+	return [2]int{8, n - 15 + i}
+}
+
+func bit(c *Code, at [2]int) int {
+	if c.Modules[at[1]][at[0]] {
+		return 1
+	}
+
+	return 0
+}
+
+func contains(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// decode reads c back with an independent decoder from its PNG rendering
+// and returns the bytes it carries.
+func decode(t *testing.T, c *Code) []byte {
+	t.Helper()
+	out, err := c.PNG(2)
+	if err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("png.Decode: %v", err)
+	}
+
+	bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		t.Fatalf("NewBinaryBitmapFromImage: %v", err)
+	}
+
+	res, err := zxqrcode.NewQRCodeReader().Decode(bmp, map[gozxing.DecodeHintType]interface{}{
+		gozxing.DecodeHintType_PURE_BARCODE: true,
+	})
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if level := fmt.Sprint(res.GetResultMetadata()[gozxing.ResultMetadataType_ERROR_CORRECTION_LEVEL]); level != "M" {
+		t.Errorf("error correction level = %s, want M", level)
+	}
+
+	segments, _ := res.GetResultMetadata()[gozxing.ResultMetadataType_BYTE_SEGMENTS].([][]byte)
+	return bytes.Join(segments, nil)
+}
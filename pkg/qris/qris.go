@@ -0,0 +1,171 @@
+// Package qris reads and writes QRIS payloads, the Indonesian profile of
+// the EMVCo merchant-presented QR code specification.
+package qris
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	tagPayloadFormat    = "00"
+	tagInitiationMethod = "01"
+	tagAmount           = "54"
+	tagCountry          = "58"
+	tagMerchantName     = "59"
+	tagMerchantCity     = "60"
+	tagCRC              = "63"
+
+	initiationStatic  = "11"
+	initiationDynamic = "12"
+)
+
+var (
+	ErrInvalidPayload = errors.New("qris: invalid payload")
+	ErrInvalidCRC     = errors.New("qris: checksum mismatch")
+)
+
+// Field is one top-level ID, length, value data object.
+type Field struct {
+	ID    string
+	Value string
+}
+
+// Merchant is the merchant information carried by a payload.
+type Merchant struct {
+	Name string
+	City string
+}
+
+// Parse splits payload into its top-level data objects and verifies the
+// trailing CRC.
+func Parse(payload string) ([]Field, error) {
+	payload = strings.TrimSpace(payload)
+	var fields []Field
+	for i := 0; i < len(payload); {
+		if i+4 > len(payload) {
+			return nil, ErrInvalidPayload
+		}
+
+		id := payload[i : i+2]
+		n, err := strconv.Atoi(payload[i+2 : i+4])
+		if err != nil || n < 0 || i+4+n > len(payload) {
+			return nil, ErrInvalidPayload
+		}
+
+		fields = append(fields, Field{ID: id, Value: payload[i+4 : i+4+n]})
+		i += 4 + n
+	}
+
+	if len(fields) < 2 || fields[0].ID != tagPayloadFormat || fields[len(fields)-1].ID != tagCRC {
+		return nil, ErrInvalidPayload
+	}
+
+	crc := fields[len(fields)-1].Value
+	if len(crc) != 4 || !strings.EqualFold(crc, checksum(payload[:len(payload)-4])) {
+		return nil, ErrInvalidCRC
+	}
+
+	return fields, nil
+}
+
+// Validate checks that payload is a well-formed Indonesian QRIS payload
+// and returns its merchant details.
+func Validate(payload string) (*Merchant, error) {
+	fields, err := Parse(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Merchant{}
+	var country string
+	for _, f := range fields {
+		switch f.ID {
+		case tagCountry:
+			country = f.Value
+		case tagMerchantName:
+			m.Name = f.Value
+		case tagMerchantCity:
+			m.City = f.Value
+		}
+	}
+
+	if country != "ID" {
+		return nil, fmt.Errorf("%w: country code must be ID", ErrInvalidPayload)
+	}
+
+	if m.Name == "" {
+		return nil, fmt.Errorf("%w: merchant name is missing", ErrInvalidPayload)
+	}
+
+	return m, nil
+}
+
+// Dynamic turns a static merchant payload into a dynamic one for a single
+// payment of amount rupiah: it marks the initiation method dynamic, sets
+// the transaction amount and recomputes the CRC.
+func Dynamic(static string, amount float64) (string, error) {
+	if amount <= 0 {
+		return "", errors.New("qris: amount must be positive")
+	}
+
+	if _, err := Validate(static); err != nil {
+		return "", err
+	}
+
+	fields, _ := Parse(static)
+	out := make([]Field, 0, len(fields)+1)
+	for _, f := range fields {
+		switch f.ID {
+		case tagAmount, tagCRC:
+			continue
+		case tagInitiationMethod:
+			f.Value = initiationDynamic
+		}
+
+		out = append(out, f)
+	}
+
+	out = append(out, Field{ID: tagAmount, Value: formatAmount(amount)})
+	sort.SliceStable(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+
+	var b strings.Builder
+	for _, f := range out {
+		if len(f.Value) > 99 {
+			return "", ErrInvalidPayload
+		}
+
+		fmt.Fprintf(&b, "%s%02d%s", f.ID, len(f.Value), f.Value)
+	}
+
+	b.WriteString(tagCRC + "04")
+	return b.String() + checksum(b.String()), nil
+}
+
+// formatAmount writes amount in whole rupiah, the only precision QRIS
+// issuers accept for IDR.
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(math.Round(amount), 'f', 0, 64)
+}
+
+// checksum is the CRC-16/CCITT-FALSE of data as four uppercase hex digits.
+// For a full payload data runs up to and including the "6304" CRC header.
+func checksum(data string) string {
+	crc := uint16(0xffff)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return fmt.Sprintf("%04X", crc)
+}
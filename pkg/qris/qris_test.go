@@ -0,0 +1,209 @@
+package qris
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// static is a static QRIS payload as printed on a merchant sticker.
+const static = "00020101021126570011ID.DANA.WWW011893600915302259148102090225914810303UMI" +
+	"51440014ID.CO.QRIS.WWW0215ID10200176114730303UMI5204581253033605802ID" +
+	"5910Toko Sinar6013Jakarta Barat61051147063042F47"
+
+// dynamic is static for a payment of Rp150.000, with its CRC worked out
+// independently of checksum.
+const dynamic = "00020101021226570011ID.DANA.WWW011893600915302259148102090225914810303UMI" +
+	"51440014ID.CO.QRIS.WWW0215ID10200176114730303UMI520458125303360540615000058" +
+	"02ID5910Toko Sinar6013Jakarta Barat6105114706304512D"
+
+func TestChecksum(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "check value", data: "123456789", want: "29B1"},
+		{
+			// The sample payload from the EMVCo merchant-presented QR
+			// specification, CRC A13A.
+			name: "emvco sample",
+			data: "00020101021229300012D156000000000510A93FO3230Q31280012D15600000001030812345678" +
+				"520441115802CN5914BEST TRANSPORT6007BEIJING64200002ZH0104最佳运输0202北京" +
+				"540523.7253031565502016233030412340603***0708A60086670902ME91320016A0112233449988770708123456786304",
+			want: "A13A",
+		},
+		{name: "empty", data: "", want: "FFFF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checksum(tt.data); got != tt.want {
+				t.Errorf("checksum = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	fields, err := Parse(static)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if fields[0] != (Field{ID: "00", Value: "01"}) || fields[1] != (Field{ID: "01", Value: "11"}) {
+		t.Errorf("leading fields = %+v", fields[:2])
+	}
+
+	if last := fields[len(fields)-1]; last != (Field{ID: "63", Value: "2F47"}) {
+		t.Errorf("last field = %+v", last)
+	}
+
+	if _, err := Parse(" " + static[:len(static)-4] + "2f47\n"); err != nil {
+		t.Errorf("lowercase CRC and surrounding space: %v", err)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	body := static[:len(static)-4]
+	tests := []struct {
+		name    string
+		payload string
+		want    error
+	}{
+		{name: "empty", payload: "", want: ErrInvalidPayload},
+		{name: "short header", payload: "000", want: ErrInvalidPayload},
+		{name: "length not a number", payload: "00ab01", want: ErrInvalidPayload},
+		{name: "length past the end", payload: "000201010211630499", want: ErrInvalidPayload},
+		{name: "only a CRC", payload: "6304" + checksum("6304"), want: ErrInvalidPayload},
+		{name: "missing payload format", payload: withCRC("010211" + "5802ID"), want: ErrInvalidPayload},
+		{name: "missing CRC", payload: "000201010211", want: ErrInvalidPayload},
+		{name: "CRC not last", payload: "0002016304ABCD5802ID", want: ErrInvalidPayload},
+		{name: "wrong CRC", payload: body + "0000", want: ErrInvalidCRC},
+		{name: "short CRC", payload: "0002016303ABC", want: ErrInvalidCRC},
+		{name: "tampered", payload: strings.Replace(static, "Toko Sinar", "Toko Bulan", 1), want: ErrInvalidCRC},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.payload); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	m, err := Validate(static)
+	if err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	if m.Name != "Toko Sinar" || m.City != "Jakarta Barat" {
+		t.Errorf("merchant = %+v", m)
+	}
+
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{name: "foreign country", payload: withCRC("000201010211" + "5802SG" + "5910Toko Sinar")},
+		{name: "no country", payload: withCRC("000201010211" + "5910Toko Sinar")},
+		{name: "no merchant name", payload: withCRC("000201010211" + "5802ID" + "6007Jakarta")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Validate(tt.payload); !errors.Is(err, ErrInvalidPayload) {
+				t.Errorf("err = %v, want %v", err, ErrInvalidPayload)
+			}
+		})
+	}
+}
+
+func TestDynamic(t *testing.T) {
+	got, err := Dynamic(static, 150000.4)
+	if err != nil {
+		t.Fatalf("Dynamic: %v", err)
+	}
+
+	if got != dynamic {
+		t.Errorf("Dynamic =\n%s\nwant\n%s", got, dynamic)
+	}
+
+	fields, err := Parse(got)
+	if err != nil {
+		t.Fatalf("Parse(Dynamic): %v", err)
+	}
+
+	values := map[string]string{}
+	for i, f := range fields {
+		if i > 0 && f.ID < fields[i-1].ID {
+			t.Errorf("field %s follows %s", f.ID, fields[i-1].ID)
+		}
+
+		if _, ok := values[f.ID]; ok {
+			t.Errorf("field %s appears twice", f.ID)
+		}
+
+		values[f.ID] = f.Value
+	}
+
+	if values["01"] != "12" {
+		t.Errorf("initiation method = %q, want 12", values["01"])
+	}
+
+	if values["54"] != "150000" {
+		t.Errorf("amount = %q, want 150000", values["54"])
+	}
+
+	if values["63"] != checksum(got[:len(got)-4]) || values["63"] == "2F47" {
+		t.Errorf("CRC = %q was not recomputed", values["63"])
+	}
+
+	for _, id := range []string{"00", "26", "51", "52", "53", "58", "59", "60", "61"} {
+		if values[id] == "" {
+			t.Errorf("field %s was dropped", id)
+		}
+	}
+}
+
+func TestDynamicReplacesAmount(t *testing.T) {
+	first, err := Dynamic(static, 10000)
+	if err != nil {
+		t.Fatalf("Dynamic: %v", err)
+	}
+
+	second, err := Dynamic(first, 25000)
+	if err != nil {
+		t.Fatalf("Dynamic: %v", err)
+	}
+
+	fresh, err := Dynamic(static, 25000)
+	if err != nil {
+		t.Fatalf("Dynamic: %v", err)
+	}
+
+	if second != fresh {
+		t.Errorf("Dynamic of a dynamic payload =\n%s\nwant\n%s", second, fresh)
+	}
+}
+
+func TestDynamicRejects(t *testing.T) {
+	if _, err := Dynamic(static, 0); err == nil {
+		t.Error("zero amount: want error")
+	}
+
+	if _, err := Dynamic(static, -5); err == nil {
+		t.Error("negative amount: want error")
+	}
+
+	if _, err := Dynamic(static[:len(static)-4]+"0000", 1000); !errors.Is(err, ErrInvalidCRC) {
+		t.Errorf("bad CRC: err = %v, want %v", err, ErrInvalidCRC)
+	}
+}
+
+func withCRC(body string) string {
+	body += "6304"
+	return body + checksum(body)
+}